@@ -4,10 +4,13 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,14 +18,38 @@ import (
 	orchestrators "github.com/ochairo/potions/internal/domain-orchestrators"
 	"github.com/ochairo/potions/internal/domain/entities"
 	"github.com/ochairo/potions/internal/domain/interfaces"
+	domainGateways "github.com/ochairo/potions/internal/domain/interfaces/gateways"
 	"github.com/ochairo/potions/internal/domain/services"
 	"github.com/ochairo/potions/internal/external-adapters/yaml"
 )
 
 // PackageBuildInput represents a package to build
 type PackageBuildInput struct {
-	Package string `json:"package"`
-	Version string `json:"version"`
+	Package   string   `json:"package"`
+	Version   string   `json:"version"`
+	Platforms []string `json:"platforms,omitempty"` // Platforms to build; defaults to --platform
+}
+
+// buildTarget is one package entry built for one platform
+type buildTarget struct {
+	PackageBuildInput
+	Platform string
+}
+
+// expandBuildTargets pairs each package with each of its own platforms, falling back to
+// defaultPlatform for entries that list none
+func expandBuildTargets(packages []PackageBuildInput, defaultPlatform string) []buildTarget {
+	var targets []buildTarget
+	for _, pkg := range packages {
+		platforms := pkg.Platforms
+		if len(platforms) == 0 {
+			platforms = []string{defaultPlatform}
+		}
+		for _, platform := range platforms {
+			targets = append(targets, buildTarget{PackageBuildInput: pkg, Platform: resolvePlatform(platform)})
+		}
+	}
+	return targets
 }
 
 // BuildReport represents the output of building packages
@@ -31,30 +58,280 @@ type BuildReport struct {
 	SuccessfulBuilds  int            `json:"successful_builds"`
 	FailedBuilds      int            `json:"failed_builds"`
 	TimeoutBuilds     int            `json:"timeout_builds"`
+	UpToDateBuilds    int            `json:"up_to_date_builds"` // Skipped by --only-if-updated
+	DeprecatedBuilds  int            `json:"deprecated_builds"` // Skipped by --skip-deprecated
 	SuccessDetails    []BuildResult  `json:"success_details"`
 	FailureDetails    []BuildResult  `json:"failure_details"`
 	TimeoutDetails    []BuildResult  `json:"timeout_details"`
+	UpToDateDetails   []BuildResult  `json:"up_to_date_details"`
+	DeprecatedDetails []BuildResult  `json:"deprecated_details"`
 	PlatformBreakdown map[string]int `json:"platform_breakdown"`
 	DurationSeconds   float64        `json:"duration_seconds"`
+	OutputBytes       int64          `json:"output_bytes"`     // Tarballs and security artifacts written
+	OutputDirBytes    int64          `json:"output_dir_bytes"` // Total output directory usage
 }
 
 // BuildResult represents the outcome of a single build
 type BuildResult struct {
-	Package  string `json:"package"`
-	Version  string `json:"version"`
-	Platform string `json:"platform"`
-	Status   string `json:"status"`
-	Message  string `json:"message,omitempty"`
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	Platform     string `json:"platform"`
+	Status       string `json:"status"`
+	Message      string `json:"message,omitempty"`
+	OutputBytes  int64  `json:"output_bytes,omitempty"`
+	Cached       bool   `json:"cached,omitempty"`
+	SourcePath   string `json:"source_path,omitempty"`   // Set when --keep-source preserved it
+	DownloadPath string `json:"download_path,omitempty"` // Set when --keep-download preserved it
+	Phase        string `json:"phase,omitempty"`         // Phase whose --timeout-per-phase deadline expired
+}
+
+// BuildEvent is one line of the NDJSON progress stream written by --events/--events-output
+type BuildEvent struct {
+	Event    string    `json:"event"`
+	Time     time.Time `json:"time"`
+	Package  string    `json:"package"`
+	Version  string    `json:"version,omitempty"`
+	Platform string    `json:"platform"`
+	Status   string    `json:"status,omitempty"`  // Set on package_result
+	Message  string    `json:"message,omitempty"` // Set on package_result
+}
+
+// Build progress event names
+const (
+	eventPackageStarted = "package_started"
+	eventPackageResult  = "package_result"
+)
+
+// buildEventStream writes build progress events as newline-delimited JSON; a nil stream discards events
+type buildEventStream struct {
+	encoder *json.Encoder
+	now     func() time.Time
+}
+
+// newBuildEventStream streams events to w
+func newBuildEventStream(w io.Writer) *buildEventStream {
+	return &buildEventStream{encoder: json.NewEncoder(w), now: func() time.Time { return time.Now().UTC() }}
+}
+
+// emit writes one event, stamping its time
+func (s *buildEventStream) emit(event BuildEvent) {
+	if s == nil {
+		return
+	}
+	event.Time = s.now()
+	if err := s.encoder.Encode(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write build event: %v\n", err)
+	}
+}
+
+// result emits the package_result event for a finished build
+func (s *buildEventStream) result(result BuildResult) {
+	s.emit(BuildEvent{
+		Event:    eventPackageResult,
+		Package:  result.Package,
+		Version:  result.Version,
+		Platform: result.Platform,
+		Status:   result.Status,
+		Message:  result.Message,
+	})
+}
+
+// StageDone emits download_done, scan_done and build_done events as the orchestrator progresses
+func (s *buildEventStream) StageDone(packageName, version, platform, stage string) {
+	s.emit(BuildEvent{Event: stage + "_done", Package: packageName, Version: version, Platform: platform})
+}
+
+// keepBuildInputs selects which intermediate build inputs are preserved and reported for debugging
+type keepBuildInputs struct {
+	Source   bool
+	Download bool
+}
+
+// keptPaths returns the selected source and download paths that still exist on disk
+func (k keepBuildInputs) keptPaths(result *orchestrators.BuildResult) (source, download string) {
+	if result == nil {
+		return "", ""
+	}
+	if k.Source && result.SourcePath != "" {
+		if _, err := os.Stat(result.SourcePath); err == nil {
+			source = result.SourcePath
+		}
+	}
+	if k.Download && result.DownloadPath != "" {
+		if _, err := os.Stat(result.DownloadPath); err == nil {
+			download = result.DownloadPath
+		}
+	}
+	return source, download
+}
+
+// releaseLister lists published releases of the distribution repository
+type releaseLister interface {
+	ListReleases(ctx context.Context, owner, repo string) ([]*domainGateways.GitHubRelease, error)
+}
+
+// upToDateGate implements --only-if-updated: it skips builds whose target version
+// is already the highest released version of the package
+type upToDateGate struct {
+	fetcher orchestrators.VersionFetcher
+	tags    []string // Published release tags ("<package>-<version>")
+}
+
+// newUpToDateGate lists the repository's releases once for all subsequent checks
+func newUpToDateGate(ctx context.Context, lister releaseLister, owner, repo string, fetcher orchestrators.VersionFetcher) (*upToDateGate, error) {
+	releases, err := lister.ListReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	gate := &upToDateGate{fetcher: fetcher}
+	for _, release := range releases {
+		if !release.Draft {
+			gate.tags = append(gate.tags, release.TagName)
+		}
+	}
+	return gate, nil
+}
+
+// check resolves the version to build (the latest upstream version when version is empty,
+// "latest" or an "@channel") and reports whether it matches the highest released version
+func (g *upToDateGate) check(def *entities.Recipe, version string) (string, bool, error) {
+	if orchestrators.IsVersionLookup(version) {
+		latest, err := orchestrators.ResolveLatestVersion(g.fetcher, def, version)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to resolve latest version: %w", err)
+		}
+		version = latest
+	}
+
+	released := highestReleasedVersion(g.tags, def.Name)
+	upToDate := released != "" && entities.CanonicalVersion(released) == entities.CanonicalVersion(version)
+	return version, upToDate, nil
+}
+
+// highestReleasedVersion returns the highest version among "<pkg>-<version>" tags, or "" if none
+func highestReleasedVersion(tags []string, pkg string) string {
+	var highest string
+	for _, tag := range tags {
+		version, ok := strings.CutPrefix(tag, pkg+"-")
+		// Require a version-looking suffix so "tool-extra-1.0" is not read as a "tool" release
+		if !ok || !startsWithVersion(version) {
+			continue
+		}
+		if highest == "" || entities.CompareVersions(version, highest) > 0 {
+			highest = version
+		}
+	}
+	return highest
+}
+
+// startsWithVersion reports whether s begins with a digit, optionally after a "v" prefix
+func startsWithVersion(s string) bool {
+	s = entities.CanonicalVersion(s)
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// versionLock implements --lockfile: packages listed in it build their pinned version
+// instead of looking up the latest one
+type versionLock struct {
+	versions map[string]string
+	require  bool // --require-lock: packages missing from the lockfile are an error
+}
+
+// loadVersionLock reads a lockfile of "<package> <version>" lines; blank lines and
+// lines starting with # are ignored
+func loadVersionLock(path string, require bool) (*versionLock, error) {
+	//nolint:gosec // G304: Lockfile path is provided by the user via --lockfile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	lock := &versionLock{versions: make(map[string]string), require: require}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"<package> <version>\", got %q", path, i+1, line)
+		}
+		if previous, ok := lock.versions[fields[0]]; ok && previous != fields[1] {
+			return nil, fmt.Errorf("%s:%d: %s is pinned twice (%s and %s)", path, i+1, fields[0], previous, fields[1])
+		}
+		lock.versions[fields[0]] = fields[1]
+	}
+	return lock, nil
+}
+
+// resolve returns the version to build: an explicit version as given, otherwise the pinned
+// one in place of a latest-version lookup; a nil lock leaves the version unchanged
+func (l *versionLock) resolve(pkg, version string) (string, error) {
+	if l == nil {
+		return version, nil
+	}
+	pinned, ok := l.versions[pkg]
+	if !ok {
+		if l.require {
+			return "", fmt.Errorf("%s is not pinned in the lockfile (--require-lock)", pkg)
+		}
+		return version, nil
+	}
+	if orchestrators.IsVersionLookup(version) {
+		return pinned, nil
+	}
+	return version, nil
+}
+
+// applyVersionLock resolves every package list entry against the lockfile
+func applyVersionLock(packages []PackageBuildInput, lock *versionLock) error {
+	for i := range packages {
+		version, err := lock.resolve(packages[i].Package, packages[i].Version)
+		if err != nil {
+			return err
+		}
+		packages[i].Version = version
+	}
+	return nil
+}
+
+// printKeptBuildInputs reports preserved build inputs
+func printKeptBuildInputs(w io.Writer, indent, source, download string) {
+	if source != "" {
+		fmt.Fprintf(w, "%s🔧 Kept source directory: %s\n", indent, source)
+	}
+	if download != "" {
+		fmt.Fprintf(w, "%s📦 Kept downloaded file: %s\n", indent, download)
+	}
 }
 
 func runBuild(ctx context.Context, args []string) {
 	fs := flag.NewFlagSet("build", flag.ExitOnError)
 	var (
 		// Common flags
-		platform       = fs.String("platform", "", "Target platform (e.g., darwin-arm64)")
+		platform       = fs.String("platform", "", "Target platform (e.g., darwin-arm64, or 'auto' to honor TARGETPLATFORM)")
 		enableSecurity = fs.Bool("enable-security-scan", true, "Enable security vulnerability scanning (default: true)")
+		strictSBOM     = fs.Bool("strict-sbom", false, "Fail the build when a scanned binary's SBOM resolves no dependencies")
 		recipesDir     = fs.String("recipes-dir", "recipes", "Path to recipes directory")
+		recipeFormat   = fs.String("recipe-format", "", "Only read recipes in this format: yaml, toml or json (default: detect by extension)")
 		outputDir      = fs.String("output-dir", "dist", "Output directory for built binaries")
+		outputLayout   = fs.String("output-layout", string(entities.LayoutFlat), "Artifact layout under --output-dir: flat, by-package or by-package-version")
+		ifExistsFlag   = fs.String("if-exists", string(entities.IfExistsOverwrite), "When a target tarball already exists in --output-dir: overwrite, skip (keep it, report success) or error")
+		ifLocked       = fs.String("if-locked", "error", "When another potions build holds --output-dir: error, or wait for it to finish")
+		checksumRel    = fs.Bool("checksum-relative", false, "Name files in .sha256/.sha512 by their path relative to --output-dir instead of basename")
+		cacheDir       = fs.String("cache-dir", "", "Build cache directory (default: user cache dir/potions/builds)")
+		noCache        = fs.Bool("no-cache", false, "Always rebuild, bypassing the build cache")
+		keepSource     = fs.Bool("keep-source", false, "Preserve and print the extracted source directory for debugging")
+		keepDownload   = fs.Bool("keep-download", false, "Preserve and print the downloaded archive for debugging")
+		onlyIfUpdated  = fs.Bool("only-if-updated", false, "Skip packages whose latest version is already released (needs GITHUB_TOKEN)")
+		repoOwner      = fs.String("repo-owner", "ochairo", "GitHub repository owner checked by --only-if-updated")
+		repoName       = fs.String("repo-name", "potions", "GitHub repository name checked by --only-if-updated")
+		dlTimeout      = fs.Duration("download-timeout", gateways.DefaultDownloadTimeout, "Deadline per artifact download request (0 disables)")
+		versionTimeout = fs.Duration("version-timeout", gateways.DefaultVersionTimeout, "Deadline per version lookup request (0 disables)")
+		lockfile       = fs.String("lockfile", "", "Build the versions pinned in this file (\"<package> <version>\" lines) instead of the latest")
+		requireLock    = fs.Bool("require-lock", false, "Fail packages that are not pinned in --lockfile")
+		skipDeprecated = fs.Bool("skip-deprecated", false, "Skip recipes marked deprecated or past their eol_date")
 
 		// Single package flags
 		allPlatforms = fs.Bool("all-platforms", false, "Build for all platforms defined in recipe")
@@ -62,11 +339,16 @@ func runBuild(ctx context.Context, args []string) {
 		// Multiple packages flags
 		packages       = fs.String("packages", "", "JSON array of packages to build")
 		timeoutMinutes = fs.Int("timeout", 20, "Timeout per package build in minutes")
+		phaseTimeouts  = fs.String("timeout-per-phase", "", "Per-phase deadlines within --timeout (e.g., 'download=5m,scan=2m,build=15m')")
 		successFile    = fs.String("successes", "build-successes.txt", "File to write successful builds")
 		failureFile    = fs.String("failures", "build-failures.txt", "File to write failed builds")
 		timeoutFile    = fs.String("timeouts", "build-failures-timeout.txt", "File to write timeout builds")
 		errorFile      = fs.String("errors", "build-failures-error.txt", "File to write error builds")
 		jsonOutput     = fs.String("json-output", "", "Optional JSON file for detailed report")
+		reportFormat   = fs.String("report-format", "json", "Format of the --json-output report: json, or junit (JUnit XML for CI test reports)")
+		historyFile    = fs.String("history", "", "Append one JSON line per build outcome to this file (kept across runs; requires --packages)")
+		eventsOutput   = fs.String("events-output", "", "Stream NDJSON progress events to this file as packages build")
+		eventsStdout   = fs.Bool("events", false, "Stream NDJSON progress events to stdout (implies --quiet)")
 		quiet          = fs.Bool("quiet", false, "Quiet mode - minimal output")
 	)
 
@@ -80,37 +362,174 @@ Examples:
   # Single package
   potions build kubectl                                # Build latest version, auto-detect platform
   potions build kubectl v1.28.0                        # Build specific version
+  potions build node @lts                              # Build latest version from the recipe's lts channel
   potions build kubectl v1.28.0 --platform darwin-arm64
   potions build kubectl v1.28.0 --all-platforms        # Build for all platforms
+  potions build kubectl v1.28.0 --no-cache             # Rebuild even if cached
+  potions build kubectl v1.28.0 --keep-source          # Report source dir to debug a failed build
+  potions build kubectl v1.28.0 --download-timeout 30m # Allow slow links to finish large downloads
+  potions build kubectl v1.28.0 --strict-sbom          # Fail if the binary's dependencies cannot be resolved
+  potions build kubectl --output-layout by-package-version  # Write dist/kubectl/<version>/kubectl-<version>-<platform>.tar.gz
+  potions build kubectl --output-layout by-package --checksum-relative  # Checksums verify with "cd dist && sha256sum -c"
+  potions build kubectl --lockfile versions.lock       # Build the version pinned in versions.lock
+  potions build kubectl v1.28.0 --if-exists error      # Never replace an existing tarball
 
   # Multiple packages from JSON
   potions build --packages '[{"package":"curl","version":"8.11.1"}]' --platform linux-x86_64
   potions build --packages @packages.json --platform darwin-arm64
+  potions build --packages '[{"package":"jq","platforms":["linux-x86_64","darwin-arm64"]}]'   # Per-package platforms
   potions build --packages "$PACKAGES" --platform linux-arm64 --quiet
+  potions build --packages @packages.json --platform auto   # Use buildx TARGETPLATFORM
+  potions build --packages @packages.json --platform linux-x86_64 --only-if-updated
+  potions build --packages @packages.json --platform linux-x86_64 --skip-deprecated   # Leave EOL tools out
+  potions build --packages @packages.json --platform linux-x86_64 --history build-history.jsonl
+  potions build --packages @packages.json --platform linux-x86_64 --lockfile versions.lock --require-lock
+  potions build --packages @packages.json --platform linux-x86_64 --events | dashboard   # Live progress
+  potions build --packages @packages.json --platform linux-x86_64 --json-output build.xml --report-format junit
 
 Options:
 `)
 		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Environment Variables:
+  SOURCE_DATE_EPOCH    Unix timestamp used for SBOM/provenance times (reproducible builds)
+  GITHUB_TOKEN         GitHub token for --only-if-updated (GH_TOKEN is also accepted)
+  TARGETPLATFORM       Target platform for --platform auto (e.g., linux/arm64)
+  TARGETOS/TARGETARCH  Used for --platform auto when TARGETPLATFORM is unset
+`)
 	}
 
+	applyFlagDefaults(fs, flagDefaults)
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(1)
 	}
 
+	layout, err := parseOutputLayout(*outputLayout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	ifExists, err := parseIfExistsPolicy(*ifExistsFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	format, err := yaml.ParseRecipeFormat(*recipeFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --recipe-format: %v\n", err)
+		os.Exit(2)
+	}
+
+	checksumBaseDir := ""
+	if *checksumRel {
+		checksumBaseDir = *outputDir
+	}
+	if *ifLocked != "error" && *ifLocked != "wait" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --if-locked %q (valid: error, wait)\n", *ifLocked)
+		os.Exit(2)
+	}
+	if *reportFormat != "json" && *reportFormat != "junit" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --report-format %q (valid: json, junit)\n", *reportFormat)
+		os.Exit(2)
+	}
+	phases, err := parsePhaseTimeouts(*phaseTimeouts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --timeout-per-phase: %v\n", err)
+		os.Exit(2)
+	}
+	timeouts := httpTimeouts{Download: *dlTimeout, Version: *versionTimeout, Phases: phases}
+	*platform = resolvePlatform(*platform)
+
+	if *requireLock && *lockfile == "" {
+		fmt.Fprintf(os.Stderr, "Error: --require-lock requires --lockfile\n")
+		os.Exit(2)
+	}
+	var lock *versionLock
+	if *lockfile != "" {
+		if lock, err = loadVersionLock(*lockfile, *requireLock); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	var gate *upToDateGate
+	if *onlyIfUpdated {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			token = os.Getenv("GH_TOKEN")
+		}
+		if token == "" && !githubAppConfigured() {
+			fmt.Fprintf(os.Stderr, "Error: --only-if-updated requires GITHUB_TOKEN to list existing releases\n")
+			os.Exit(2)
+		}
+		githubGW, err := newGitHubGateway(token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		gate, err = newUpToDateGate(ctx, githubGW, *repoOwner, *repoName,
+			gateways.NewVersionFetcher().WithTimeout(timeouts.Version))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	opts := buildOptions{
+		RecipesDir:      *recipesDir,
+		RecipeFormat:    format,
+		OutputDir:       *outputDir,
+		Layout:          layout,
+		IfExists:        ifExists,
+		ChecksumBaseDir: checksumBaseDir,
+		CacheDir:        resolveCacheDir(*cacheDir, *noCache, "builds"),
+		Keep:            keepBuildInputs{Source: *keepSource, Download: *keepDownload},
+		Timeouts:        timeouts,
+		Gate:            gate,
+		SkipDeprecated:  *skipDeprecated,
+		EnableSecurity:  *enableSecurity,
+		StrictSBOM:      *strictSBOM,
+		WaitForLock:     *ifLocked == "wait",
+		TimeoutMinutes:  *timeoutMinutes,
+	}
+
 	// Build multiple packages from JSON input
 	if *packages != "" {
-		if *platform == "" {
-			fmt.Fprintf(os.Stderr, "Error: --platform is required when using --packages\n")
-			fs.Usage()
-			os.Exit(1)
+		var events *buildEventStream
+		switch {
+		case *eventsStdout:
+			events = newBuildEventStream(os.Stdout)
+			*quiet = true
+		case *eventsOutput != "":
+			//nolint:gosec // G304: Events path is provided by the user via --events-output
+			f, err := os.OpenFile(*eventsOutput, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to open events output: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() { _ = f.Close() }()
+			events = newBuildEventStream(f)
 		}
-		buildFromPackageList(ctx, *packages, *platform, *recipesDir, *outputDir, *enableSecurity,
-			*timeoutMinutes, *successFile, *failureFile, *timeoutFile, *errorFile, *jsonOutput, *quiet)
+		opts.Events = events
+		opts.Quiet = *quiet
+		buildFromPackageList(ctx, *packages, *platform, lock, opts, buildReportFiles{
+			Successes:  *successFile,
+			Failures:   *failureFile,
+			Timeouts:   *timeoutFile,
+			Errors:     *errorFile,
+			JSONOutput: *jsonOutput,
+			Format:     *reportFormat,
+			History:    *historyFile,
+		})
 		return
 	}
 
 	// Build single package from CLI args
+	if *historyFile != "" {
+		fmt.Fprintf(os.Stderr, "Error: --history is only supported with --packages\n")
+		os.Exit(2)
+	}
 	if fs.NArg() < 1 {
 		fmt.Fprintf(os.Stderr, "Error: package name is required\n\n")
 		fs.Usage()
@@ -122,13 +541,114 @@ Options:
 	if fs.NArg() >= 2 {
 		version = fs.Arg(1)
 	}
+	if version, err = lock.resolve(packageName, version); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	buildPackage(ctx, packageName, version, *platform, *allPlatforms, opts)
+}
+
+// lockOutputDir takes the output directory lock for the builds about to run, so concurrent
+// builds cannot share extraction directories; it exits when another build holds the lock
+func lockOutputDir(ctx context.Context, outputDir string, wait bool) *gateways.OutputLock {
+	outputLock, err := gateways.AcquireOutputLock(ctx, outputDir, wait)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return outputLock
+}
+
+// httpTimeouts holds the per-request deadlines for network operations during a build,
+// along with the per-phase build deadlines set by --timeout-per-phase
+type httpTimeouts struct {
+	Download time.Duration
+	Version  time.Duration
+	Phases   orchestrators.PhaseTimeouts
+}
+
+// parsePhaseTimeouts parses a --timeout-per-phase value of comma-separated phase=duration pairs
+func parsePhaseTimeouts(value string) (orchestrators.PhaseTimeouts, error) {
+	var timeouts orchestrators.PhaseTimeouts
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		phase, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return timeouts, fmt.Errorf("invalid entry %q (want phase=duration)", pair)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil || d <= 0 {
+			return timeouts, fmt.Errorf("invalid duration %q for phase %s", raw, phase)
+		}
+		switch strings.TrimSpace(phase) {
+		case orchestrators.StageDownload:
+			timeouts.Download = d
+		case orchestrators.StageScan:
+			timeouts.Scan = d
+		case orchestrators.StageBuild:
+			timeouts.Build = d
+		default:
+			return timeouts, fmt.Errorf("unknown phase %q (valid: download, scan, build)", phase)
+		}
+	}
+	return timeouts, nil
+}
+
+// resolveCacheDir returns a cache directory, defaulting to the user cache dir/potions/<name>,
+// or "" when caching is disabled
+func resolveCacheDir(cacheDir string, noCache bool, name string) string {
+	if noCache {
+		return ""
+	}
+	if cacheDir != "" {
+		return cacheDir
+	}
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(".potions-cache", name)
+	}
+	return filepath.Join(userCacheDir, "potions", name)
+}
 
-	buildPackage(ctx, packageName, version, *platform, *allPlatforms, *recipesDir, *outputDir, *enableSecurity)
+// buildOptions holds the build settings shared by single-package and --packages builds
+type buildOptions struct {
+	RecipesDir      string
+	RecipeFormat    yaml.RecipeFormat
+	OutputDir       string
+	Layout          entities.OutputLayout   // Empty means flat
+	IfExists        entities.IfExistsPolicy // Empty means overwrite
+	ChecksumBaseDir string                  // Checksum files name artifacts relative to this directory when set
+	CacheDir        string                  // Build cache directory; empty disables caching
+	Keep            keepBuildInputs
+	Timeouts        httpTimeouts
+	Gate            *upToDateGate // Skips versions that are already released when set
+	SkipDeprecated  bool
+	EnableSecurity  bool
+	StrictSBOM      bool
+	WaitForLock     bool              // Wait for another build holding OutputDir instead of failing
+	TimeoutMinutes  int               // Deadline per package for --packages builds
+	Events          *buildEventStream // Receives NDJSON progress events for --packages builds when set
+	Quiet           bool
 }
 
-func buildPackage(ctx context.Context, packageName, version, platform string, allPlatforms bool, recipesDir, outputDir string, enableSecurity bool) {
+// buildReportFiles names the report files written after a --packages build; empty names are skipped
+type buildReportFiles struct {
+	Successes  string
+	Failures   string
+	Timeouts   string
+	Errors     string
+	JSONOutput string
+	Format     string // Format of JSONOutput: json or junit
+	History    string // Build outcomes are appended here as JSON Lines
+}
+
+func buildPackage(ctx context.Context, packageName, version, platform string, allPlatforms bool, opts buildOptions) {
 	// Initialize repository
-	defRepo := yaml.NewRecipeRepository(recipesDir)
+	defRepo := yaml.NewRecipeRepository(opts.RecipesDir).WithFormat(opts.RecipeFormat)
 
 	// Load package recipe
 	def, err := defRepo.GetRecipe(ctx, packageName)
@@ -137,18 +657,36 @@ func buildPackage(ctx context.Context, packageName, version, platform string, al
 		os.Exit(1)
 	}
 
+	if now := time.Now(); opts.SkipDeprecated && def.Retired(now) {
+		fmt.Printf("⏭️  %s is %s, skipping build\n", packageName, def.LifecycleNote(now))
+		return
+	}
+
+	if opts.Gate != nil {
+		resolved, upToDate, err := opts.Gate.check(def, version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not check for updates, building anyway: %v\n", err)
+		} else {
+			if upToDate {
+				fmt.Printf("✅ %s %s is up-to-date (already released), skipping build\n", packageName, resolved)
+				return
+			}
+			version = resolved
+		}
+	}
+
 	// Determine platforms to build
 	var platforms []string
 	//nolint:gocritic // ifElseChain: checking different boolean conditions, not suitable for switch
 	if allPlatforms {
 		// Build for all platforms in recipe
 		for p := range def.Download.Platforms {
-			platforms = append(platforms, p)
+			platforms = append(platforms, def.Download.CanonicalPlatform(p))
 		}
 		fmt.Printf("Building for all platforms: %v\n", platforms)
 	} else if platform != "" {
 		// Build for specified platform
-		if _, exists := def.Download.Platforms[platform]; !exists {
+		if _, exists := def.Download.Platform(platform); !exists {
 			fmt.Fprintf(os.Stderr, "Error: platform %s not supported by %s\n", platform, packageName)
 			fmt.Fprintf(os.Stderr, "Available platforms: ")
 			for p := range def.Download.Platforms {
@@ -165,21 +703,21 @@ func buildPackage(ctx context.Context, packageName, version, platform string, al
 	}
 
 	// Initialize security components
-	securityGateway := gateways.NewCompositeSecurityGateway()
+	securityGateway := newSecurityGateway(opts.StrictSBOM)
 	var securityOrch *orchestrators.SecurityOrchestrator
-	if enableSecurity && def.Security.ScanVulnerabilities {
+	if opts.EnableSecurity {
 		securityService := services.NewSecurityService(securityGateway)
 		securityOrch = orchestrators.NewSecurityOrchestrator(securityService)
 	}
 
 	// Initialize version fetcher and downloader
-	versionFetcher := gateways.NewVersionFetcher()
-	downloader := gateways.NewDownloader()
+	versionFetcher := gateways.NewVersionFetcher().WithTimeout(opts.Timeouts.Version)
+	logger := &interfaces.StdoutLogger{}
+	downloader := gateways.NewDownloader().WithLogger(logger).WithTimeout(opts.Timeouts.Download)
 	scriptExecutor := gateways.NewScriptExecutor()
 	packager := gateways.NewPackager()
 
 	// Initialize build orchestrator
-	logger := &interfaces.StdoutLogger{}
 	buildOrch := orchestrators.NewBuildOrchestrator(
 		defRepo,
 		securityOrch,
@@ -189,11 +727,16 @@ func buildPackage(ctx context.Context, packageName, version, platform string, al
 		scriptExecutor,
 		packager,
 		orchestrators.BuildOrchestratorConfig{
-			EnableSecurityScan: enableSecurity,
-			OutputDir:          outputDir,
+			EnableSecurityScan: opts.EnableSecurity,
+			OutputDir:          opts.OutputDir,
 		},
 		logger,
-	)
+	).WithBuildVerifier(gateways.NewBuildVerifier()).WithURLProber(gateways.NewURLProber()).
+		WithToolchainChecker(gateways.NewToolchainChecker()).WithHostPlatform(detectPlatform()).
+		WithIfExists(opts.IfExists, opts.Layout).WithPhaseTimeouts(opts.Timeouts.Phases)
+	if opts.CacheDir != "" {
+		buildOrch.WithBuildCache(gateways.NewFileBuildCache(opts.CacheDir))
+	}
 
 	// Build for each platform
 	fmt.Printf("\nBuilding %s", packageName)
@@ -201,33 +744,55 @@ func buildPackage(ctx context.Context, packageName, version, platform string, al
 		fmt.Printf(" version %s", version)
 	}
 	fmt.Println()
-	if enableSecurity {
+	if opts.EnableSecurity {
 		fmt.Println("🔒 Security scanning: enabled")
 	}
 	fmt.Println()
 
 	// Initialize security artifacts service
-	securityArtifactsService := services.NewSecurityArtifactsService(logger)
+	securityArtifactsService := services.NewSecurityArtifactsService(logger).WithClock(buildClock()).WithChecksumBaseDir(opts.ChecksumBaseDir)
 
-	successCount := 0
+	outputLock := lockOutputDir(ctx, opts.OutputDir, opts.WaitForLock)
+	successCount, skippedCount := 0, 0
 	for _, plat := range platforms {
 		fmt.Printf("=== Building for %s ===\n", plat)
 
 		result, err := buildOrch.BuildPackage(ctx, packageName, version, plat)
+		if err == nil && result.Skipped {
+			fmt.Printf("⏭️  Skipping %s: %s (running on %s)\n\n", plat, result.SkipReason, detectPlatform())
+			skippedCount++
+			continue
+		}
+		keptSource, keptDownload := opts.Keep.keptPaths(result)
 		if err != nil {
+			fmt.Fprintf(os.Stderr, "Build failed for %s: %v\n", plat, err)
+			printKeptBuildInputs(os.Stderr, "", keptSource, keptDownload)
+			fmt.Fprintln(os.Stderr)
+			continue
+		}
+
+		if result.KeptExisting {
+			fmt.Printf("%s\n\n", result.GetBuildSummary())
+			successCount++
+			continue
+		}
+
+		if err := arrangeArtifact(opts.Layout, opts.OutputDir, result.Artifact); err != nil {
 			fmt.Fprintf(os.Stderr, "Build failed for %s: %v\n\n", plat, err)
 			continue
 		}
 
 		fmt.Println(result.GetBuildSummary())
+		printKeptBuildInputs(os.Stdout, "", keptSource, keptDownload)
 
 		// Generate security artifacts if enabled
-		if enableSecurity && result.Artifact != nil && result.Artifact.Path != "" {
+		if opts.EnableSecurity && result.Artifact != nil && result.Artifact.Path != "" {
 			fmt.Printf("\n🔒 Generating security artifacts for %s...\n", filepath.Base(result.Artifact.Path))
 
-			artifacts, err := securityArtifactsService.GenerateAllArtifacts(ctx, result.Artifact.Path)
+			artifacts, err := securityArtifactsService.GenerateAllArtifacts(ctx, result.Artifact.Path, result.Artifact.SourceURL)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "⚠️  Security artifacts generation failed: %v\n", err)
+				fmt.Printf("💾 Output size: %s\n", formatBytes(sumFileSizes([]string{result.Artifact.Path})))
 			} else {
 				fmt.Printf("✅ Security artifacts generated:\n")
 				if artifacts.SHA256Path != "" {
@@ -242,22 +807,28 @@ func buildPackage(ctx context.Context, packageName, version, platform string, al
 				if artifacts.ProvenancePath != "" {
 					fmt.Printf("  - %s\n", filepath.Base(artifacts.ProvenancePath))
 				}
+				outputPaths := append([]string{result.Artifact.Path}, artifacts.Paths()...)
+				fmt.Printf("💾 Output size: %s\n", formatBytes(sumFileSizes(outputPaths)))
 			}
 		}
 
 		fmt.Println()
 		successCount++
 	}
+	_ = outputLock.Release()
 
 	// Summary
-	fmt.Printf("\n✅ Build complete: %d/%d platforms successful\n", successCount, len(platforms))
-	if successCount < len(platforms) {
+	fmt.Printf("\n✅ Build complete: %d/%d platforms successful", successCount, len(platforms)-skippedCount)
+	if skippedCount > 0 {
+		fmt.Printf(" (%d skipped on this host)", skippedCount)
+	}
+	fmt.Println()
+	if successCount < len(platforms)-skippedCount {
 		os.Exit(1)
 	}
 }
 
-func buildFromPackageList(ctx context.Context, packagesInput, targetPlatform, recipesDir, outputDir string,
-	enableSecurity bool, timeoutMinutes int, successFile, failureFile, timeoutFile, errorFile, jsonOutput string, quiet bool) {
+func buildFromPackageList(ctx context.Context, packagesInput, targetPlatform string, lock *versionLock, opts buildOptions, files buildReportFiles) {
 
 	// Parse packages input
 	var packagesJSON string
@@ -282,47 +853,77 @@ func buildFromPackageList(ctx context.Context, packagesInput, targetPlatform, re
 	}
 
 	if len(packages) == 0 {
-		if !quiet {
+		if !opts.Quiet {
 			fmt.Println("No packages to build")
 		}
 		os.Exit(0)
 	}
 
+	if err := applyVersionLock(packages, lock); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	if targetPlatform == "" {
+		for _, pkg := range packages {
+			if len(pkg.Platforms) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: --platform is required for packages without a \"platforms\" list (%s)\n", pkg.Package)
+				os.Exit(2)
+			}
+		}
+	}
+
 	// Build all packages
-	report := buildPackages(ctx, packages, targetPlatform, recipesDir, outputDir, enableSecurity, timeoutMinutes, quiet)
+	outputLock := lockOutputDir(ctx, opts.OutputDir, opts.WaitForLock)
+	report := buildPackages(ctx, packages, targetPlatform, opts)
+	_ = outputLock.Release()
 
 	// Write report files
-	if err := writeSuccessFile(successFile, report.SuccessDetails); err != nil {
+	if err := writeSuccessFile(files.Successes, report.SuccessDetails); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to write success file: %v\n", err)
 	}
 
-	if err := writeFailureFile(failureFile, report.FailureDetails, report.TimeoutDetails); err != nil {
+	if err := writeFailureFile(files.Failures, report.FailureDetails, report.TimeoutDetails); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to write failure file: %v\n", err)
 	}
 
-	if err := writeTimeoutFile(timeoutFile, report.TimeoutDetails); err != nil {
+	if err := writeTimeoutFile(files.Timeouts, report.TimeoutDetails); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to write timeout file: %v\n", err)
 	}
 
-	if err := writeErrorFile(errorFile, report.FailureDetails); err != nil {
+	if err := writeErrorFile(files.Errors, report.FailureDetails); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to write error file: %v\n", err)
 	}
 
-	// Write JSON report if requested
-	if jsonOutput != "" {
-		reportData, err := json.MarshalIndent(report, "", "  ")
+	if err := appendHistory(files.History, buildHistoryRecords(report, time.Now().UTC())); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write history: %v\n", err)
+	}
+
+	// Write JSON (or JUnit) report if requested
+	if files.JSONOutput != "" {
+		var reportData []byte
+		var err error
+		if files.Format == "junit" {
+			reportData, err = marshalJUnitReport(report)
+		} else {
+			reportData, err = json.MarshalIndent(report, "", "  ")
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to marshal JSON report: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to marshal %s report: %v\n", files.Format, err)
 		} else {
-			if err := os.WriteFile(jsonOutput, reportData, 0600); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to write JSON report: %v\n", err)
+			if err := os.WriteFile(files.JSONOutput, reportData, 0600); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to write %s report: %v\n", files.Format, err)
 			}
 		}
 	}
 
 	// Print summary
-	if !quiet {
-		printBuildSummary(report, targetPlatform)
+	if !opts.Quiet {
+		summaryPlatform := targetPlatform
+		if summaryPlatform == "" {
+			summaryPlatform = "per-package platforms"
+		}
+		printBuildSummary(report, summaryPlatform)
 	}
 
 	// Exit with error if all builds failed
@@ -331,7 +932,7 @@ func buildFromPackageList(ctx context.Context, packagesInput, targetPlatform, re
 	}
 }
 
-func buildPackages(ctx context.Context, packages []PackageBuildInput, targetPlatform, recipesDir, outputDir string, enableSecurity bool, timeoutMinutes int, quiet bool) BuildReport {
+func buildPackages(ctx context.Context, packages []PackageBuildInput, defaultPlatform string, opts buildOptions) BuildReport {
 	startTime := time.Now()
 
 	report := BuildReport{
@@ -339,28 +940,30 @@ func buildPackages(ctx context.Context, packages []PackageBuildInput, targetPlat
 		SuccessDetails:    []BuildResult{},
 		FailureDetails:    []BuildResult{},
 		TimeoutDetails:    []BuildResult{},
+		UpToDateDetails:   []BuildResult{},
+		DeprecatedDetails: []BuildResult{},
 		PlatformBreakdown: make(map[string]int),
 	}
 
 	// Initialize dependencies following architecture pattern
-	recipeRepo := yaml.NewRecipeRepository(recipesDir)
+	recipeRepo := yaml.NewRecipeRepository(opts.RecipesDir).WithFormat(opts.RecipeFormat)
 
 	// Initialize security components
-	securityGateway := gateways.NewCompositeSecurityGateway()
+	securityGateway := newSecurityGateway(opts.StrictSBOM)
 	var securityOrch *orchestrators.SecurityOrchestrator
-	if enableSecurity {
+	if opts.EnableSecurity {
 		securityService := services.NewSecurityService(securityGateway)
 		securityOrch = orchestrators.NewSecurityOrchestrator(securityService)
 	}
 
 	// Initialize other gateways
-	versionFetcher := gateways.NewVersionFetcher()
-	downloader := gateways.NewDownloader()
+	versionFetcher := gateways.NewVersionFetcher().WithTimeout(opts.Timeouts.Version)
+	logger := &interfaces.StdoutLogger{}
+	downloader := gateways.NewDownloader().WithLogger(logger).WithTimeout(opts.Timeouts.Download)
 	scriptExecutor := gateways.NewScriptExecutor()
 	packager := gateways.NewPackager()
 
 	// Create build orchestrator following architecture
-	logger := &interfaces.StdoutLogger{}
 	buildOrchestrator := orchestrators.NewBuildOrchestrator(
 		recipeRepo,
 		securityOrch,
@@ -370,49 +973,106 @@ func buildPackages(ctx context.Context, packages []PackageBuildInput, targetPlat
 		scriptExecutor,
 		packager,
 		orchestrators.BuildOrchestratorConfig{
-			EnableSecurityScan: enableSecurity,
-			OutputDir:          outputDir,
+			EnableSecurityScan: opts.EnableSecurity,
+			OutputDir:          opts.OutputDir,
 		},
 		logger,
-	)
+	).WithBuildVerifier(gateways.NewBuildVerifier()).WithURLProber(gateways.NewURLProber()).
+		WithToolchainChecker(gateways.NewToolchainChecker()).WithHostPlatform(detectPlatform()).
+		WithIfExists(opts.IfExists, opts.Layout).WithPhaseTimeouts(opts.Timeouts.Phases)
+	if opts.Events != nil {
+		buildOrchestrator.WithProgress(opts.Events)
+	}
+	if opts.CacheDir != "" {
+		buildOrchestrator.WithBuildCache(gateways.NewFileBuildCache(opts.CacheDir))
+	}
 
 	// Initialize security artifacts service
-	securityArtifactsService := services.NewSecurityArtifactsService(logger)
+	securityArtifactsService := services.NewSecurityArtifactsService(logger).WithClock(buildClock()).WithChecksumBaseDir(opts.ChecksumBaseDir)
 
-	for _, pkg := range packages {
-		if !quiet {
+	for _, target := range expandBuildTargets(packages, defaultPlatform) {
+		pkg, targetPlatform := target.PackageBuildInput, target.Platform
+		if !opts.Quiet {
 			fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-			fmt.Printf("📦 Processing package: %s v%s\n", pkg.Package, pkg.Version)
+			fmt.Printf("📦 Processing package: %s v%s (%s)\n", pkg.Package, pkg.Version, targetPlatform)
 			fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 		}
 
 		// Load recipe to check platform support
 		recipe, err := recipeRepo.GetRecipe(ctx, pkg.Package)
 		if err != nil {
-			if !quiet {
+			if !opts.Quiet {
 				fmt.Printf("  ❌ Failed to load recipe: %v\n\n", err)
 			}
-			report.FailureDetails = append(report.FailureDetails, BuildResult{
+			failure := BuildResult{
 				Package:  pkg.Package,
 				Version:  pkg.Version,
 				Platform: targetPlatform,
 				Status:   "error",
 				Message:  fmt.Sprintf("Recipe not found: %v", err),
-			})
+			}
+			report.FailureDetails = append(report.FailureDetails, failure)
 			report.FailedBuilds++
+			opts.Events.result(failure)
 			continue
 		}
 
 		// Check if package supports the target platform
 		if !packageSupportsPlatform(recipe, targetPlatform) {
-			if !quiet {
+			if !opts.Quiet {
 				fmt.Printf("  ⏭️  Skipping %s - platform %s not supported\n\n", pkg.Package, targetPlatform)
 			}
 			continue
 		}
 
+		// Skip recipes whose upstream is deprecated or past end of life
+		if opts.SkipDeprecated && recipe.Retired(startTime) {
+			note := recipe.LifecycleNote(startTime)
+			if !opts.Quiet {
+				fmt.Printf("  ⏭️  Skipping %s - %s\n\n", pkg.Package, note)
+			}
+			deprecatedResult := BuildResult{
+				Package:  pkg.Package,
+				Version:  pkg.Version,
+				Platform: targetPlatform,
+				Status:   "deprecated",
+				Message:  note,
+			}
+			report.DeprecatedBuilds++
+			report.DeprecatedDetails = append(report.DeprecatedDetails, deprecatedResult)
+			opts.Events.result(deprecatedResult)
+			continue
+		}
+
+		// Skip packages whose target version is already released
+		if opts.Gate != nil {
+			resolved, upToDate, err := opts.Gate.check(recipe, pkg.Version)
+			switch {
+			case err != nil:
+				if !opts.Quiet {
+					fmt.Printf("  ⚠️  Could not check for updates, building anyway: %v\n", err)
+				}
+			case upToDate:
+				if !opts.Quiet {
+					fmt.Printf("  ✅ %s %s is up-to-date (already released), skipping\n\n", pkg.Package, resolved)
+				}
+				upToDateResult := BuildResult{
+					Package:  pkg.Package,
+					Version:  resolved,
+					Platform: targetPlatform,
+					Status:   "up-to-date",
+				}
+				report.UpToDateBuilds++
+				report.UpToDateDetails = append(report.UpToDateDetails, upToDateResult)
+				opts.Events.result(upToDateResult)
+				continue
+			default:
+				pkg.Version = resolved
+			}
+		}
+
 		// Build the package using orchestrator
-		if !quiet {
+		if !opts.Quiet {
 			fmt.Printf("  🔨 Building %s v%s for %s\n", pkg.Package, pkg.Version, targetPlatform)
 		}
 
@@ -423,58 +1083,91 @@ func buildPackages(ctx context.Context, packages []PackageBuildInput, targetPlat
 			pkg.Package,
 			pkg.Version,
 			targetPlatform,
-			enableSecurity,
-			timeoutMinutes,
-			quiet,
+			opts.OutputDir,
+			opts.Layout,
+			opts.Keep,
+			opts.EnableSecurity,
+			opts.TimeoutMinutes,
+			opts.Events,
+			opts.Quiet,
 		)
 
 		switch result.Status {
 		case "success":
 			report.SuccessfulBuilds++
 			report.SuccessDetails = append(report.SuccessDetails, result)
+			report.OutputBytes += result.OutputBytes
 			report.PlatformBreakdown[targetPlatform]++
-			if !quiet {
-				fmt.Printf("  ✅ Built %s %s successfully\n", pkg.Package, targetPlatform)
+			if !opts.Quiet {
+				if result.Cached {
+					fmt.Printf("  ✅ Restored %s %s from build cache\n", pkg.Package, targetPlatform)
+				} else {
+					fmt.Printf("  ✅ Built %s %s successfully\n", pkg.Package, targetPlatform)
+				}
 			}
 		case "timeout":
 			report.TimeoutBuilds++
 			report.TimeoutDetails = append(report.TimeoutDetails, result)
 			report.FailedBuilds++
-			if !quiet {
-				fmt.Printf("  ⏱️  Build timeout (%d min) for %s (%s)\n", timeoutMinutes, pkg.Package, targetPlatform)
+			if !opts.Quiet {
+				if result.Phase != "" {
+					fmt.Printf("  ⏱️  Build timeout for %s (%s): %s\n", pkg.Package, targetPlatform, result.Message)
+				} else {
+					fmt.Printf("  ⏱️  Build timeout (%d min) for %s (%s)\n", opts.TimeoutMinutes, pkg.Package, targetPlatform)
+				}
 			}
 		case "error":
 			report.FailedBuilds++
 			report.FailureDetails = append(report.FailureDetails, result)
-			if !quiet {
+			if !opts.Quiet {
 				fmt.Printf("  ❌ Build failed for %s (%s): %s\n", pkg.Package, targetPlatform, result.Message)
 			}
+		case "skipped":
+			if !opts.Quiet {
+				fmt.Printf("  ⏭️  Skipped %s (%s): %s\n", pkg.Package, targetPlatform, result.Message)
+			}
 		}
 
-		if !quiet {
+		if !opts.Quiet {
 			fmt.Println()
 		}
 	}
 
+	if usage, err := estimateDirUsage(opts.OutputDir); err == nil {
+		report.OutputDirBytes = usage
+	}
+
 	report.DurationSeconds = time.Since(startTime).Seconds()
 	return report
 }
 
+// buildClock returns the time source for security artifacts, honoring
+// SOURCE_DATE_EPOCH for reproducible builds
+func buildClock() interfaces.Clock {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return interfaces.RealClock{}
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid SOURCE_DATE_EPOCH %q: %v\n", epoch, err)
+		return interfaces.RealClock{}
+	}
+	return interfaces.FixedClock{Time: time.Unix(seconds, 0).UTC()}
+}
+
 func packageSupportsPlatform(recipe *entities.Recipe, platform string) bool {
 	if len(recipe.Download.Platforms) == 0 {
 		return false
 	}
 
-	// Check both the standard platform name and alternate naming conventions
-	platformName := convertPlatformName(platform)
-
-	for pname := range recipe.Download.Platforms {
-		if pname == platform || pname == platformName {
-			return true
-		}
+	// Check the recipe's own platform map, then alternate naming conventions
+	if _, ok := recipe.Download.Platform(platform); ok {
+		return true
 	}
-
-	return false
+	_, ok := recipe.Download.Platform(convertPlatformName(platform))
+	return ok
 }
 
 // convertPlatformName converts between different platform naming conventions
@@ -495,8 +1188,12 @@ func buildPackageWithOrchestrator(
 	buildOrch *orchestrators.BuildOrchestrator,
 	securityService *services.SecurityArtifactsService,
 	packageName, version, platform string,
+	outputDir string,
+	layout entities.OutputLayout,
+	keep keepBuildInputs,
 	enableSecurity bool,
 	timeoutMinutes int,
+	events *buildEventStream,
 	quiet bool,
 ) BuildResult {
 	result := BuildResult{
@@ -504,6 +1201,8 @@ func buildPackageWithOrchestrator(
 		Version:  version,
 		Platform: platform,
 	}
+	events.emit(BuildEvent{Event: eventPackageStarted, Package: packageName, Version: version, Platform: platform})
+	defer func() { events.result(result) }()
 
 	// Create context with timeout
 	buildCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMinutes)*time.Minute)
@@ -511,8 +1210,16 @@ func buildPackageWithOrchestrator(
 
 	// Execute build using orchestrator
 	buildResult, err := buildOrch.BuildPackage(buildCtx, packageName, version, platform)
+	result.SourcePath, result.DownloadPath = keep.keptPaths(buildResult)
+	if !quiet {
+		printKeptBuildInputs(os.Stdout, "    ", result.SourcePath, result.DownloadPath)
+	}
 	if err != nil {
-		if buildCtx.Err() == context.DeadlineExceeded {
+		if buildResult != nil && buildResult.TimedOutPhase != "" {
+			result.Status = "timeout"
+			result.Phase = buildResult.TimedOutPhase
+			result.Message = err.Error()
+		} else if buildCtx.Err() == context.DeadlineExceeded {
 			result.Status = "timeout"
 			result.Message = fmt.Sprintf("Build exceeded %d minute timeout", timeoutMinutes)
 		} else {
@@ -521,21 +1228,196 @@ func buildPackageWithOrchestrator(
 		}
 		return result
 	}
+	if buildResult.Skipped {
+		result.Status = "skipped"
+		result.Message = buildResult.SkipReason
+		return result
+	}
+	if buildResult.KeptExisting {
+		// The tarball and its security artifacts are left exactly as they were
+		result.Status = "success"
+		result.Message = "kept existing " + filepath.Base(buildResult.Artifact.Path)
+		return result
+	}
+
+	if err := arrangeArtifact(layout, outputDir, buildResult.Artifact); err != nil {
+		result.Status = "error"
+		result.Message = err.Error()
+		return result
+	}
+
+	var outputPaths []string
+	if buildResult.Artifact != nil && buildResult.Artifact.Path != "" {
+		outputPaths = append(outputPaths, buildResult.Artifact.Path)
+	}
 
 	// Generate security artifacts if enabled and artifact was created
 	if enableSecurity && buildResult.Artifact != nil && buildResult.Artifact.Path != "" {
-		_, err := securityService.GenerateAllArtifacts(buildCtx, buildResult.Artifact.Path)
+		artifacts, err := securityService.GenerateAllArtifacts(buildCtx, buildResult.Artifact.Path, buildResult.Artifact.SourceURL)
 		if err != nil {
 			if !quiet {
 				fmt.Printf("    ⚠️  Warning: Failed to generate security artifacts: %v\n", err)
 			}
+		} else {
+			outputPaths = append(outputPaths, artifacts.Paths()...)
 		}
 	}
 
+	result.OutputBytes = sumFileSizes(outputPaths)
+	result.Cached = buildResult.CacheHit
+
 	result.Status = "success"
 	return result
 }
 
+// newSecurityGateway returns the composite security gateway, with strict SBOM generation
+// failing binaries whose dependencies cannot be resolved when strictSBOM is set
+func newSecurityGateway(strictSBOM bool) domainGateways.SecurityGateway {
+	if !strictSBOM {
+		return gateways.NewCompositeSecurityGateway()
+	}
+	return gateways.NewCompositeSecurityGatewayWithDeps(
+		gateways.NewOSVGateway(),
+		gateways.NewSBOMGenerator().WithStrict(true),
+		gateways.NewBinaryAnalyzerGateway(),
+		gateways.NewChecksumVerifier(),
+		gateways.NewGPGVerifier(),
+	)
+}
+
+// parseOutputLayout validates an --output-layout value
+func parseOutputLayout(value string) (entities.OutputLayout, error) {
+	names := make([]string, 0, len(entities.OutputLayouts))
+	for _, layout := range entities.OutputLayouts {
+		if string(layout) == value {
+			return layout, nil
+		}
+		names = append(names, string(layout))
+	}
+	return "", fmt.Errorf("invalid output layout %q (valid: %s)", value, strings.Join(names, ", "))
+}
+
+// parseIfExistsPolicy validates an --if-exists value
+func parseIfExistsPolicy(value string) (entities.IfExistsPolicy, error) {
+	names := make([]string, 0, len(entities.IfExistsPolicies))
+	for _, policy := range entities.IfExistsPolicies {
+		if string(policy) == value {
+			return policy, nil
+		}
+		names = append(names, string(policy))
+	}
+	return "", fmt.Errorf("invalid --if-exists policy %q (valid: %s)", value, strings.Join(names, ", "))
+}
+
+// arrangeArtifact moves a packaged tarball into its layout directory under outputDir
+// Security artifacts are generated next to the moved tarball afterwards
+func arrangeArtifact(layout entities.OutputLayout, outputDir string, artifact *entities.Artifact) error {
+	if artifact == nil || artifact.Path == "" {
+		return nil
+	}
+
+	dir := layout.Dir(outputDir, artifact.Name, artifact.Version)
+	target := filepath.Join(dir, filepath.Base(artifact.Path))
+	if target == artifact.Path {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.Rename(artifact.Path, target); err != nil {
+		return fmt.Errorf("failed to move artifact into %s: %w", dir, err)
+	}
+	artifact.Path = target
+	return nil
+}
+
+// buildHistoryRecords converts every outcome in a build report into history records
+func buildHistoryRecords(report BuildReport, now time.Time) []HistoryRecord {
+	var records []HistoryRecord
+	for _, details := range [][]BuildResult{report.SuccessDetails, report.UpToDateDetails, report.DeprecatedDetails, report.FailureDetails, report.TimeoutDetails} {
+		for _, result := range details {
+			records = append(records, HistoryRecord{
+				Timestamp: now,
+				Command:   "build",
+				Package:   result.Package,
+				Version:   result.Version,
+				Platform:  result.Platform,
+				Status:    result.Status,
+				Message:   result.Message,
+			})
+		}
+	}
+	return records
+}
+
+// junitTestSuite is the JUnit XML form of a build report: one testcase per package and platform
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// marshalJUnitReport renders a build report as a JUnit XML suite; failures and timeouts fail
+// their testcase with the build's message, up-to-date and deprecated packages are skipped
+func marshalJUnitReport(report BuildReport) ([]byte, error) {
+	suite := junitTestSuite{Name: "potions build", Time: strconv.FormatFloat(report.DurationSeconds, 'f', 3, 64)}
+	addCases := func(results []BuildResult, outcome func(*junitTestCase, BuildResult)) {
+		for _, result := range results {
+			testCase := junitTestCase{ClassName: result.Package, Name: result.Platform}
+			if result.Version != "" {
+				testCase.ClassName += "@" + result.Version
+			}
+			if outcome != nil {
+				outcome(&testCase, result)
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+	}
+	fail := func(testCase *junitTestCase, result BuildResult) {
+		suite.Failures++
+		testCase.Failure = &junitFailure{Type: result.Status, Message: result.Message, Text: result.Message}
+	}
+	skip := func(testCase *junitTestCase, result BuildResult) {
+		suite.Skipped++
+		testCase.Skipped = &junitSkipped{Message: result.Message}
+	}
+
+	addCases(report.SuccessDetails, nil)
+	addCases(report.FailureDetails, fail)
+	addCases(report.TimeoutDetails, fail)
+	addCases(report.UpToDateDetails, skip)
+	addCases(report.DeprecatedDetails, skip)
+	suite.Tests = len(suite.TestCases)
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
 func writeSuccessFile(filename string, successes []BuildResult) error {
 	if len(successes) == 0 {
 		return os.WriteFile(filename, []byte{}, 0600)
@@ -616,6 +1498,20 @@ func printBuildSummary(report BuildReport, platform string) {
 		}
 	}
 
+	if report.UpToDateBuilds > 0 {
+		fmt.Printf("⏭️  Up-to-date (skipped): %d\n", report.UpToDateBuilds)
+		for _, u := range report.UpToDateDetails {
+			fmt.Printf("  - %s:%s\n", u.Package, u.Version)
+		}
+	}
+
+	if report.DeprecatedBuilds > 0 {
+		fmt.Printf("⏭️  Deprecated (skipped): %d\n", report.DeprecatedBuilds)
+		for _, d := range report.DeprecatedDetails {
+			fmt.Printf("  - %s (%s)\n", d.Package, d.Message)
+		}
+	}
+
 	if report.FailedBuilds > 0 {
 		fmt.Println()
 
@@ -643,5 +1539,59 @@ func printBuildSummary(report BuildReport, platform string) {
 	}
 
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("💾 Output written: %s (tarballs and security artifacts)\n", formatBytes(report.OutputBytes))
+	if report.OutputDirBytes > 0 {
+		fmt.Printf("📁 Output directory usage: %s\n", formatBytes(report.OutputDirBytes))
+	}
 	fmt.Printf("⏱️  Duration: %.2f seconds\n", report.DurationSeconds)
 }
+
+// sumFileSizes returns the combined size of the given files, ignoring missing ones
+func sumFileSizes(paths []string) int64 {
+	var total int64
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		total += info.Size()
+	}
+	return total
+}
+
+// estimateDirUsage returns the total size of regular files under dir
+func estimateDirUsage(dir string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return total, nil
+}
+
+// formatBytes renders a byte count in human-readable units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,668 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	orchestrators "github.com/ochairo/potions/internal/domain-orchestrators"
+	"github.com/ochairo/potions/internal/domain/entities"
+	"github.com/ochairo/potions/internal/domain/interfaces"
+	"github.com/ochairo/potions/internal/domain/services"
+	"github.com/ochairo/potions/internal/testsupport"
+)
+
+// Test reported output size matches on-disk size of generated files
+func TestSumFileSizes_MatchesGeneratedArtifacts(t *testing.T) {
+	outputDir := t.TempDir()
+	tarball := filepath.Join(outputDir, "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(tarball, make([]byte, 4096), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	securityService := services.NewSecurityArtifactsService(&interfaces.NoOpLogger{})
+	artifacts, err := securityService.GenerateAllArtifacts(context.Background(), tarball, "")
+	if err != nil {
+		t.Fatalf("GenerateAllArtifacts() error = %v", err)
+	}
+
+	paths := append([]string{tarball}, artifacts.Paths()...)
+	if len(paths) != 5 {
+		t.Fatalf("expected tarball plus 4 security artifacts, got %v", paths)
+	}
+
+	var want int64
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want += info.Size()
+	}
+
+	if got := sumFileSizes(paths); got != want {
+		t.Errorf("sumFileSizes() = %d, want %d", got, want)
+	}
+
+	// The output directory only contains the generated files
+	usage, err := estimateDirUsage(outputDir)
+	if err != nil {
+		t.Fatalf("estimateDirUsage() error = %v", err)
+	}
+	if usage != want {
+		t.Errorf("estimateDirUsage() = %d, want %d", usage, want)
+	}
+}
+
+// Test missing files are ignored when summing sizes
+func TestSumFileSizes_MissingFiles(t *testing.T) {
+	if got := sumFileSizes([]string{"/nonexistent/file.tar.gz"}); got != 0 {
+		t.Errorf("sumFileSizes() = %d, want 0", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+// Test SOURCE_DATE_EPOCH selects a fixed clock for reproducible builds
+func TestBuildClock_SourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	if got := buildClock().Now(); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("buildClock().Now() = %v, want %v", got, time.Unix(1700000000, 0).UTC())
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	if _, ok := buildClock().(interfaces.RealClock); !ok {
+		t.Error("buildClock() should return RealClock when SOURCE_DATE_EPOCH is unset")
+	}
+}
+
+// stubBuildDeps provides minimal orchestrator dependencies for cmd-level build tests
+type stubBuildDeps struct {
+	recipe    *entities.Recipe
+	sourceDir string
+	download  string
+	buildErr  error
+}
+
+func (s *stubBuildDeps) GetRecipe(_ context.Context, _ string) (*entities.Recipe, error) {
+	return s.recipe, nil
+}
+
+func (s *stubBuildDeps) ListRecipes(_ context.Context) ([]*entities.Recipe, error) {
+	return []*entities.Recipe{s.recipe}, nil
+}
+
+func (s *stubBuildDeps) GetRecipesByPlatform(_ context.Context, _ string) ([]*entities.Recipe, error) {
+	return []*entities.Recipe{s.recipe}, nil
+}
+
+func (s *stubBuildDeps) FetchLatestVersion(_ *entities.Recipe) (string, error) {
+	return "1.0.0", nil
+}
+
+func (s *stubBuildDeps) DownloadArtifact(_ context.Context, _ *entities.Recipe, version, platform, _ string) (*entities.Artifact, error) {
+	return &entities.Artifact{Name: s.recipe.Name, Version: version, Platform: platform, Path: s.sourceDir, DownloadPath: s.download}, nil
+}
+
+func (s *stubBuildDeps) ExecuteBuildScripts(_ context.Context, _ *entities.Recipe, _ *entities.Artifact, _ string) error {
+	return s.buildErr
+}
+
+func (s *stubBuildDeps) PackageArtifact(_ context.Context, _ *entities.Recipe, artifact *entities.Artifact, _, _, _ string) (*entities.Artifact, error) {
+	return artifact, nil
+}
+
+func (s *stubBuildDeps) VerifyGPGSignature(_ context.Context, _, _ string) error { return nil }
+
+func (s *stubBuildDeps) VerifyGPGSignedChecksums(_ context.Context, _, _, _ string) error { return nil }
+
+func (s *stubBuildDeps) ImportGPGKeys(_ context.Context, _ []string, _ entities.GPGKeyLookup) error {
+	return nil
+}
+
+func (s *stubBuildDeps) ImportGPGKeysFromURL(_ context.Context, _ string) error { return nil }
+
+// newStubBuildOrchestrator wires stub dependencies into a real build orchestrator
+func newStubBuildOrchestrator(deps *stubBuildDeps) *orchestrators.BuildOrchestrator {
+	return orchestrators.NewBuildOrchestrator(deps, nil, deps, deps, deps, deps, deps,
+		orchestrators.BuildOrchestratorConfig{}, &interfaces.NoOpLogger{})
+}
+
+// Test --keep-source preserves and reports the extraction directory after a failed build
+func TestBuildPackageWithOrchestrator_KeepSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceDir := filepath.Join(tmpDir, "tool-1.0.0-extracted")
+	if err := os.MkdirAll(sourceDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	download := filepath.Join(tmpDir, "tool-1.0.0.tar.gz")
+	if err := os.WriteFile(download, []byte("archive"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	deps := &stubBuildDeps{
+		recipe: &entities.Recipe{
+			Name: "tool",
+			Download: entities.RecipeDownload{
+				Platforms: map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}},
+			},
+		},
+		sourceDir: sourceDir,
+		download:  download,
+		buildErr:  fmt.Errorf("make: *** [all] Error 2"),
+	}
+	orch := newStubBuildOrchestrator(deps)
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	result := buildPackageWithOrchestrator(context.Background(), orch, nil, "tool", "1.0.0", "linux-amd64",
+		t.TempDir(), entities.LayoutFlat, keepBuildInputs{Source: true}, false, 1, nil, false)
+	_ = w.Close()
+	os.Stdout = stdout
+	output, _ := io.ReadAll(r)
+
+	if result.Status != "error" {
+		t.Fatalf("Status = %q, want error", result.Status)
+	}
+	if result.SourcePath != sourceDir {
+		t.Errorf("SourcePath = %q, want %q", result.SourcePath, sourceDir)
+	}
+	if result.DownloadPath != "" {
+		t.Errorf("DownloadPath = %q, want empty without --keep-download", result.DownloadPath)
+	}
+	if _, err := os.Stat(sourceDir); err != nil {
+		t.Errorf("source directory should still exist: %v", err)
+	}
+	if !strings.Contains(string(output), "Kept source directory: "+sourceDir) {
+		t.Errorf("output should report kept source directory, got:\n%s", output)
+	}
+}
+
+// Test --if-exists error fails on an existing tarball and skip keeps it untouched while reporting success
+func TestBuildPackageWithOrchestrator_IfExists(t *testing.T) {
+	sourceDir := filepath.Join(t.TempDir(), "tool-1.0.0")
+	if err := os.MkdirAll(sourceDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	deps := &stubBuildDeps{
+		recipe: &entities.Recipe{
+			Name: "tool",
+			Download: entities.RecipeDownload{
+				Platforms: map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}},
+			},
+		},
+		sourceDir: sourceDir,
+		buildErr:  fmt.Errorf("build should not run"),
+	}
+
+	outputDir := t.TempDir()
+	existing := entities.LayoutByPackage.ArtifactPath(outputDir, "tool", "v1.0.0", "linux-amd64")
+	if err := os.MkdirAll(filepath.Dir(existing), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("good artifact"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(policy entities.IfExistsPolicy) BuildResult {
+		orch := orchestrators.NewBuildOrchestrator(deps, nil, deps, deps, deps, deps, deps,
+			orchestrators.BuildOrchestratorConfig{OutputDir: outputDir}, &interfaces.NoOpLogger{}).
+			WithIfExists(policy, entities.LayoutByPackage)
+		return buildPackageWithOrchestrator(context.Background(), orch, nil, "tool", "v1.0.0", "linux-amd64",
+			outputDir, entities.LayoutByPackage, keepBuildInputs{}, true, 1, nil, true)
+	}
+
+	if result := build(entities.IfExistsError); result.Status != "error" || !strings.Contains(result.Message, "already exists") {
+		t.Errorf("error policy: Status = %q (%s), want an already-exists error", result.Status, result.Message)
+	}
+
+	result := build(entities.IfExistsSkip)
+	if result.Status != "success" || !strings.Contains(result.Message, "kept existing tool-1.0.0-linux-amd64.tar.gz") {
+		t.Errorf("skip policy: Status = %q (%s), want success keeping the tarball", result.Status, result.Message)
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "good artifact" {
+		t.Errorf("existing tarball = %q (%v), want it untouched", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(existing)); len(entries) != 1 {
+		t.Errorf("skip policy wrote %d files next to the tarball, want none", len(entries)-1)
+	}
+
+	if result := build(entities.IfExistsOverwrite); result.Status != "error" || !strings.Contains(result.Message, "build should not run") {
+		t.Errorf("overwrite policy: Status = %q (%s), want the rebuild to be attempted", result.Status, result.Message)
+	}
+}
+
+// Test the event stream reports each stage of a package build in order, ending with its result
+func TestBuildPackageWithOrchestrator_Events(t *testing.T) {
+	sourceDir := filepath.Join(t.TempDir(), "tool-1.0.0")
+	if err := os.MkdirAll(sourceDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	deps := &stubBuildDeps{
+		recipe: &entities.Recipe{
+			Name: "tool",
+			Download: entities.RecipeDownload{
+				Platforms: map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}},
+			},
+		},
+		sourceDir: sourceDir,
+	}
+
+	var buf bytes.Buffer
+	events := newBuildEventStream(&buf)
+	orch := newStubBuildOrchestrator(deps).WithProgress(events)
+
+	result := buildPackageWithOrchestrator(context.Background(), orch, nil, "tool", "1.0.0", "linux-amd64",
+		t.TempDir(), entities.LayoutFlat, keepBuildInputs{}, false, 1, events, true)
+	if result.Status != "success" {
+		t.Fatalf("Status = %q (%s), want success", result.Status, result.Message)
+	}
+
+	var got []BuildEvent
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var event BuildEvent
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("invalid event line: %v", err)
+		}
+		if event.Package != "tool" || event.Platform != "linux-amd64" || event.Time.IsZero() {
+			t.Errorf("event = %+v, want tool linux-amd64 with a timestamp", event)
+		}
+		got = append(got, event)
+	}
+
+	var names []string
+	for _, event := range got {
+		names = append(names, event.Event)
+	}
+	want := "package_started,download_done,build_done,package_result"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("events = %v, want %s", names, want)
+	}
+	if final := got[len(got)-1]; final.Status != "success" || final.Version != "1.0.0" {
+		t.Errorf("package_result = %+v, want success for 1.0.0", final)
+	}
+}
+
+// Test keep flags default to reporting nothing
+func TestKeepBuildInputs_Default(t *testing.T) {
+	dir := t.TempDir()
+	result := &orchestrators.BuildResult{SourcePath: dir, DownloadPath: dir}
+
+	if source, download := (keepBuildInputs{}).keptPaths(result); source != "" || download != "" {
+		t.Errorf("keptPaths() = (%q, %q), want nothing kept by default", source, download)
+	}
+	if source, download := (keepBuildInputs{Source: true, Download: true}).keptPaths(nil); source != "" || download != "" {
+		t.Errorf("keptPaths(nil) = (%q, %q), want empty", source, download)
+	}
+}
+
+// Test --only-if-updated skips a package whose latest version is already released
+func TestBuildPackages_OnlyIfUpdated_Skips(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "tool")
+	outputDir := filepath.Join(tmpDir, "dist")
+
+	gw := testsupport.NewFakeGitHubGateway("tool-0.9.0", "tool-1.0.0")
+	gate, err := newUpToDateGate(context.Background(), gw, "o", "r", &stubBuildDeps{})
+	if err != nil {
+		t.Fatalf("newUpToDateGate() error = %v", err)
+	}
+
+	report := buildPackages(context.Background(), []PackageBuildInput{{Package: "tool"}}, "linux-amd64",
+		buildOptions{RecipesDir: tmpDir, OutputDir: outputDir, Gate: gate, TimeoutMinutes: 1, Quiet: true})
+
+	if report.UpToDateBuilds != 1 || report.SuccessfulBuilds != 0 || report.FailedBuilds != 0 {
+		t.Fatalf("report = %+v, want one up-to-date skip and no builds", report)
+	}
+	if got := report.UpToDateDetails[0]; got.Status != "up-to-date" || got.Version != "1.0.0" {
+		t.Errorf("up-to-date detail = %+v, want tool 1.0.0", got)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("output directory should not be created for a skipped build, stat error = %v", err)
+	}
+}
+
+// Test each manifest entry is built for exactly its own platforms, falling back to --platform
+func TestBuildPackages_PerPackagePlatforms(t *testing.T) {
+	tmpDir := t.TempDir()
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		recipe := fmt.Sprintf(`name: %s
+version:
+  source: "static:1.0.0"
+download:
+  download_url: "%s/%s-{version}-{os}-{arch}.tar.gz"
+  platforms:
+    linux-amd64: {os: linux, arch: amd64}
+    linux-arm64: {os: linux, arch: arm64}
+    darwin-arm64: {os: darwin, arch: arm64}
+`, name, server.URL, name)
+		if err := os.WriteFile(filepath.Join(tmpDir, name+".yml"), []byte(recipe), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	packages := []PackageBuildInput{
+		{Package: "alpha", Version: "1.0.0", Platforms: []string{"linux-amd64", "darwin-arm64"}},
+		{Package: "beta", Version: "1.0.0", Platforms: []string{"linux-arm64"}},
+		{Package: "gamma", Version: "1.0.0"},
+	}
+	report := buildPackages(context.Background(), packages, "linux-amd64",
+		buildOptions{RecipesDir: tmpDir, OutputDir: filepath.Join(tmpDir, "dist"), TimeoutMinutes: 1, Quiet: true})
+
+	var built []string
+	for _, result := range report.FailureDetails {
+		built = append(built, result.Package+"/"+result.Platform)
+	}
+	want := "alpha/linux-amd64,alpha/darwin-arm64,beta/linux-arm64,gamma/linux-amd64"
+	if strings.Join(built, ",") != want {
+		t.Errorf("built targets = %v, want %s", built, want)
+	}
+}
+
+// Test a recipe keyed by upstream platform names is built, not skipped, for its mapped potions platform
+func TestBuildPackages_PlatformMap(t *testing.T) {
+	tmpDir := t.TempDir()
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	recipe := fmt.Sprintf(`name: tool
+version:
+  source: "static:1.0.0"
+download:
+  download_url: "%s/tool-{version}-{os}-{arch}.tar.gz"
+  platforms:
+    macos-aarch64: {}
+  platform_map:
+    darwin-arm64: macos-aarch64
+`, server.URL)
+	if err := os.WriteFile(filepath.Join(tmpDir, "tool.yml"), []byte(recipe), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	report := buildPackages(context.Background(), []PackageBuildInput{{Package: "tool", Version: "1.0.0"}}, "darwin-arm64",
+		buildOptions{RecipesDir: tmpDir, OutputDir: filepath.Join(tmpDir, "dist"), TimeoutMinutes: 1, Quiet: true})
+
+	if report.FailedBuilds != 1 || report.FailureDetails[0].Platform != "darwin-arm64" {
+		t.Fatalf("report = %+v, want the darwin-arm64 build attempted", report)
+	}
+	if len(requested) == 0 || requested[0] != "/tool-1.0.0-macos-aarch64.tar.gz" {
+		t.Errorf("requested %v, want the upstream macos-aarch64 download", requested)
+	}
+}
+
+// Test locked packages build their pinned version without a latest-version lookup
+func TestBuildPackages_VersionLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/VERSION" {
+			_, _ = w.Write([]byte("9.9.9"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	recipe := fmt.Sprintf(`name: tool
+version:
+  source: "url:%s/VERSION"
+download:
+  download_url: "%s/tool-{version}-{os}-{arch}.tar.gz"
+  platforms:
+    linux-amd64: {os: linux, arch: amd64}
+`, server.URL, server.URL)
+	if err := os.WriteFile(filepath.Join(tmpDir, "tool.yml"), []byte(recipe), 0600); err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(tmpDir, "versions.lock")
+	if err := os.WriteFile(lockPath, []byte("# pinned builds\ntool 1.2.3\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := loadVersionLock(lockPath, true)
+	if err != nil {
+		t.Fatalf("loadVersionLock() error = %v", err)
+	}
+	packages := []PackageBuildInput{{Package: "tool"}}
+	if err := applyVersionLock(packages, lock); err != nil {
+		t.Fatalf("applyVersionLock() error = %v", err)
+	}
+	buildPackages(context.Background(), packages, "linux-amd64",
+		buildOptions{RecipesDir: tmpDir, OutputDir: filepath.Join(tmpDir, "dist"), TimeoutMinutes: 1, Quiet: true})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) == 0 || paths[0] != "/tool-1.2.3-linux-amd64.tar.gz" {
+		t.Errorf("requests = %v, want the pinned 1.2.3 download", paths)
+	}
+	for _, path := range paths {
+		if path == "/VERSION" {
+			t.Errorf("latest version was fetched for a locked package: %v", paths)
+		}
+	}
+
+	if err := applyVersionLock([]PackageBuildInput{{Package: "other"}}, lock); err == nil ||
+		!strings.Contains(err.Error(), "not pinned") {
+		t.Errorf("applyVersionLock() error = %v, want unpinned package rejected by --require-lock", err)
+	}
+	if version, err := lock.resolve("tool", "2.0.0"); err != nil || version != "2.0.0" {
+		t.Errorf("resolve() = %q, %v, want an explicit version kept", version, err)
+	}
+}
+
+// Test the highest released version is parsed from tags and compared against the target
+func TestUpToDateGate_Check(t *testing.T) {
+	gw := testsupport.NewFakeGitHubGateway("tool-1.9.0", "tool-1.10.0", "tool-extra-3.0.0", "other-2.0.0")
+	gate, err := newUpToDateGate(context.Background(), gw, "o", "r", &stubBuildDeps{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := highestReleasedVersion(gate.tags, "tool"); got != "1.10.0" {
+		t.Errorf("highestReleasedVersion() = %q, want 1.10.0", got)
+	}
+
+	def := &entities.Recipe{Name: "tool"}
+	tests := []struct {
+		version      string
+		wantUpToDate bool
+	}{
+		{"1.10.0", true},
+		{"v1.10.0", true},
+		{"1.11.0", false},
+		{"", false}, // latest from the fetcher (1.0.0) differs from the highest release
+	}
+	for _, tt := range tests {
+		if _, upToDate, err := gate.check(def, tt.version); err != nil || upToDate != tt.wantUpToDate {
+			t.Errorf("check(%q) = %v, %v; want %v", tt.version, upToDate, err, tt.wantUpToDate)
+		}
+	}
+}
+
+// Test packaged tarballs are moved into the --output-layout directory
+func TestArrangeArtifact(t *testing.T) {
+	for _, layout := range entities.OutputLayouts {
+		t.Run(string(layout), func(t *testing.T) {
+			outputDir := t.TempDir()
+			tarball := filepath.Join(outputDir, "tool-1.0.0-linux-amd64.tar.gz")
+			if err := os.WriteFile(tarball, []byte("x"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			artifact := &entities.Artifact{Name: "tool", Version: "v1.0.0", Platform: "linux-amd64", Path: tarball}
+
+			if err := arrangeArtifact(layout, outputDir, artifact); err != nil {
+				t.Fatalf("arrangeArtifact() error = %v", err)
+			}
+
+			want := filepath.Join(layout.Dir(outputDir, "tool", "v1.0.0"), "tool-1.0.0-linux-amd64.tar.gz")
+			if artifact.Path != want {
+				t.Errorf("Path = %s, want %s", artifact.Path, want)
+			}
+			if _, err := os.Stat(want); err != nil {
+				t.Errorf("artifact not at %s: %v", want, err)
+			}
+		})
+	}
+}
+
+// Test unknown --output-layout values are rejected with the valid choices
+func TestParseOutputLayout(t *testing.T) {
+	if layout, err := parseOutputLayout("by-package"); err != nil || layout != entities.LayoutByPackage {
+		t.Errorf("parseOutputLayout(by-package) = %q, %v", layout, err)
+	}
+	if _, err := parseOutputLayout("nested"); err == nil || !strings.Contains(err.Error(), "by-package-version") {
+		t.Errorf("parseOutputLayout(nested) error = %v, want valid layouts listed", err)
+	}
+}
+
+// Test --timeout-per-phase values are parsed per phase and unknown phases are rejected
+func TestParsePhaseTimeouts(t *testing.T) {
+	got, err := parsePhaseTimeouts("download=5m, build=15m")
+	if err != nil {
+		t.Fatalf("parsePhaseTimeouts() error = %v", err)
+	}
+	want := orchestrators.PhaseTimeouts{Download: 5 * time.Minute, Build: 15 * time.Minute}
+	if got != want {
+		t.Errorf("parsePhaseTimeouts() = %+v, want %+v", got, want)
+	}
+	for _, value := range []string{"install=5m", "scan", "scan=soon", "scan=-1m"} {
+		if _, err := parsePhaseTimeouts(value); err == nil {
+			t.Errorf("parsePhaseTimeouts(%q) error = nil, want error", value)
+		}
+	}
+}
+
+// Test build reports are flattened into one history record per outcome
+func TestBuildHistoryRecords(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	report := BuildReport{
+		SuccessDetails: []BuildResult{{Package: "a", Version: "1.0.0", Platform: "linux-amd64", Status: "success"}},
+		FailureDetails: []BuildResult{{Package: "b", Version: "2.0.0", Platform: "linux-amd64", Status: "error", Message: "boom"}},
+		TimeoutDetails: []BuildResult{{Package: "c", Version: "3.0.0", Platform: "linux-amd64", Status: "timeout"}},
+	}
+
+	records := buildHistoryRecords(report, now)
+	if len(records) != 3 {
+		t.Fatalf("records = %d, want 3", len(records))
+	}
+	if records[1].Package != "b" || records[1].Status != "error" || records[1].Message != "boom" || !records[1].Timestamp.Equal(now) {
+		t.Errorf("records[1] = %+v", records[1])
+	}
+}
+
+// Test --skip-deprecated skips deprecated and end-of-life recipes without building them
+func TestBuildPackages_SkipDeprecated(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, lifecycle := range map[string]string{
+		"legacy":  "deprecated: true",
+		"expired": `eol_date: "2020-01-01"`,
+	} {
+		recipe := fmt.Sprintf(`name: %s
+%s
+version:
+  source: "static:1.0.0"
+download:
+  download_url: "https://example.com/%s-{version}.tar.gz"
+  platforms:
+    linux-amd64: {os: linux, arch: amd64}
+`, name, lifecycle, name)
+		if err := os.WriteFile(filepath.Join(tmpDir, name+".yml"), []byte(recipe), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outputDir := filepath.Join(tmpDir, "dist")
+
+	report := buildPackages(context.Background(), []PackageBuildInput{{Package: "legacy"}, {Package: "expired"}}, "linux-amd64",
+		buildOptions{RecipesDir: tmpDir, OutputDir: outputDir, SkipDeprecated: true, TimeoutMinutes: 1, Quiet: true})
+
+	if report.DeprecatedBuilds != 2 || report.SuccessfulBuilds != 0 || report.FailedBuilds != 0 {
+		t.Fatalf("report = %+v, want both recipes skipped as deprecated", report)
+	}
+	if got := report.DeprecatedDetails[1]; got.Package != "expired" || got.Status != "deprecated" ||
+		got.Message != "end of life since 2020-01-01" {
+		t.Errorf("deprecated detail = %+v, want expired skipped for its eol_date", got)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("output directory should not be created for skipped builds, stat error = %v", err)
+	}
+}
+
+// Test the JUnit report parses back with one testcase per build, failing failures and timeouts
+func TestMarshalJUnitReport(t *testing.T) {
+	report := BuildReport{
+		SuccessDetails:  []BuildResult{{Package: "jq", Version: "1.7.1", Platform: "linux-x86_64", Status: "success"}},
+		FailureDetails:  []BuildResult{{Package: "curl", Version: "8.11.1", Platform: "linux-x86_64", Status: "error", Message: "build/install failed: exit status 2"}},
+		TimeoutDetails:  []BuildResult{{Package: "llvm", Version: "19.1.0", Platform: "linux-x86_64", Status: "timeout", Message: "build phase timed out after 15m0s"}},
+		UpToDateDetails: []BuildResult{{Package: "kubectl", Version: "1.31.0", Platform: "linux-x86_64", Status: "up-to-date"}},
+		DurationSeconds: 12.5,
+	}
+
+	data, err := marshalJUnitReport(report)
+	if err != nil {
+		t.Fatalf("marshalJUnitReport() error = %v", err)
+	}
+	var suite junitTestSuite
+	if err := xml.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("JUnit report does not parse: %v\n%s", err, data)
+	}
+
+	if suite.Tests != 4 || suite.Failures != 2 || suite.Skipped != 1 || suite.Time != "12.500" {
+		t.Errorf("suite = tests %d, failures %d, skipped %d, time %s; want 4, 2, 1, 12.500", suite.Tests, suite.Failures, suite.Skipped, suite.Time)
+	}
+	failures := make(map[string]*junitFailure)
+	for _, testCase := range suite.TestCases {
+		if testCase.Failure != nil {
+			failures[testCase.ClassName+" "+testCase.Name] = testCase.Failure
+		}
+	}
+	curl := failures["curl@8.11.1 linux-x86_64"]
+	if curl == nil || curl.Message != "build/install failed: exit status 2" || curl.Type != "error" {
+		t.Errorf("curl failure = %+v, want its error message", curl)
+	}
+	if llvm := failures["llvm@19.1.0 linux-x86_64"]; llvm == nil || llvm.Type != "timeout" {
+		t.Errorf("llvm failure = %+v, want a timeout failure", llvm)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+func runGraph(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	var (
+		recipesDir   = fs.String("recipes-dir", "recipes", "Path to recipes directory")
+		recipeFormat = fs.String("recipe-format", "", "Only read recipes in this format: yaml, toml or json (default: detect by extension)")
+		platform     = fs.String("platform", "", "Only graph packages targeting this platform (e.g., darwin-arm64)")
+		include      = fs.String("include", "", "Comma-separated recipe name globs to include (e.g., 'k8s-*,kube*')")
+		exclude      = fs.String("exclude", "", "Comma-separated recipe name globs to exclude")
+		format       = fs.String("format", "dot", "Output format: dot (Graphviz) or mermaid")
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: potions graph [options]
+
+Print packages, the platforms they target and their dependencies on other
+recipes as a graph for rendering with Graphviz or Mermaid.
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Examples:
+  potions graph | dot -Tsvg > recipes.svg
+  potions graph --format mermaid > recipes.mmd
+  potions graph --platform linux-arm64 --include 'k8s-*'
+`)
+	}
+
+	applyFlagDefaults(fs, flagDefaults)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *format != "dot" && *format != "mermaid" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q (valid: dot, mermaid)\n", *format)
+		os.Exit(1)
+	}
+
+	defRepo, err := newFilteredRecipeRepository(*recipesDir, *recipeFormat, *include, *exclude)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var defs []*entities.Recipe
+	if *platform != "" {
+		defs, err = defRepo.GetRecipesByPlatform(ctx, *platform)
+	} else {
+		defs, err = defRepo.ListRecipes(ctx)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing packages: %v\n", err)
+		os.Exit(1)
+	}
+
+	graph := newRecipeGraph(defs, *platform)
+	if *format == "mermaid" {
+		writeMermaidGraph(os.Stdout, graph)
+		return
+	}
+	writeDOTGraph(os.Stdout, graph)
+}
+
+// recipeGraph holds the sorted nodes and edges shared by every output format
+type recipeGraph struct {
+	Packages     []string
+	Platforms    []string
+	Targets      map[string][]string // Package -> platforms it targets
+	Dependencies map[string][]string // Package -> graphed recipes it depends on
+}
+
+// newRecipeGraph collects packages, their platforms (only platformFilter when set) and their
+// dependencies on other graphed recipes; dependencies on build tools without a recipe are left out
+func newRecipeGraph(defs []*entities.Recipe, platformFilter string) *recipeGraph {
+	graph := &recipeGraph{
+		Targets:      make(map[string][]string),
+		Dependencies: make(map[string][]string),
+	}
+	for _, def := range defs {
+		graph.Packages = append(graph.Packages, def.Name)
+	}
+	sort.Strings(graph.Packages)
+
+	for _, def := range defs {
+		for platform := range def.Download.Platforms {
+			if platformFilter != "" && platform != platformFilter {
+				continue
+			}
+			graph.Targets[def.Name] = append(graph.Targets[def.Name], platform)
+			if !slices.Contains(graph.Platforms, platform) {
+				graph.Platforms = append(graph.Platforms, platform)
+			}
+		}
+		sort.Strings(graph.Targets[def.Name])
+
+		for _, dep := range def.Dependencies {
+			if _, found := slices.BinarySearch(graph.Packages, dep); found && !slices.Contains(graph.Dependencies[def.Name], dep) {
+				graph.Dependencies[def.Name] = append(graph.Dependencies[def.Name], dep)
+			}
+		}
+		sort.Strings(graph.Dependencies[def.Name])
+	}
+	sort.Strings(graph.Platforms)
+	return graph
+}
+
+// writeDOTGraph renders the graph in Graphviz DOT: packages are boxes, platforms ellipses,
+// and dependencies dashed edges between packages
+func writeDOTGraph(w io.Writer, graph *recipeGraph) {
+	fmt.Fprintln(w, "digraph potions {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+	for _, pkg := range graph.Packages {
+		fmt.Fprintf(w, "  %s [label=%s];\n", dotID("pkg:"+pkg), dotID(pkg))
+	}
+	for _, platform := range graph.Platforms {
+		fmt.Fprintf(w, "  %s [label=%s, shape=ellipse];\n", dotID("platform:"+platform), dotID(platform))
+	}
+	for _, pkg := range graph.Packages {
+		for _, platform := range graph.Targets[pkg] {
+			fmt.Fprintf(w, "  %s -> %s;\n", dotID("pkg:"+pkg), dotID("platform:"+platform))
+		}
+		for _, dep := range graph.Dependencies[pkg] {
+			fmt.Fprintf(w, "  %s -> %s [style=dashed, label=\"depends on\"];\n", dotID("pkg:"+pkg), dotID("pkg:"+dep))
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// dotID quotes a DOT identifier
+func dotID(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// writeMermaidGraph renders the graph as a Mermaid flowchart; node IDs are positional because
+// Mermaid IDs cannot contain the dots and dashes found in package names
+func writeMermaidGraph(w io.Writer, graph *recipeGraph) {
+	ids := make(map[string]string, len(graph.Packages)+len(graph.Platforms))
+	fmt.Fprintln(w, "graph LR")
+	for i, pkg := range graph.Packages {
+		ids["pkg:"+pkg] = fmt.Sprintf("p%d", i)
+		fmt.Fprintf(w, "  p%d[%q]\n", i, pkg)
+	}
+	for i, platform := range graph.Platforms {
+		ids["platform:"+platform] = fmt.Sprintf("t%d", i)
+		fmt.Fprintf(w, "  t%d([%q])\n", i, platform)
+	}
+	for _, pkg := range graph.Packages {
+		for _, platform := range graph.Targets[pkg] {
+			fmt.Fprintf(w, "  %s --> %s\n", ids["pkg:"+pkg], ids["platform:"+platform])
+		}
+		for _, dep := range graph.Dependencies[pkg] {
+			fmt.Fprintf(w, "  %s -.->|depends on| %s\n", ids["pkg:"+pkg], ids["pkg:"+dep])
+		}
+	}
+}
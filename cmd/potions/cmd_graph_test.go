@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// graphTestRecipes are two packages where kubectl-plugin depends on kubectl and a build tool
+func graphTestRecipes() []*entities.Recipe {
+	return []*entities.Recipe{
+		{Name: "kubectl-plugin", Dependencies: []string{"kubectl", "make"}, Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{"linux-amd64": {}}}},
+		{Name: "kubectl", Download: entities.RecipeDownload{Platforms: map[string]entities.PlatformConfig{
+			"linux-amd64": {}, "darwin-arm64": {}}}},
+	}
+}
+
+// Test the DOT graph has a node per package and an edge to each declared platform
+func TestWriteDOTGraph(t *testing.T) {
+	var out bytes.Buffer
+	writeDOTGraph(&out, newRecipeGraph(graphTestRecipes(), ""))
+	dot := out.String()
+
+	for _, want := range []string{
+		`"pkg:kubectl" [label="kubectl"];`,
+		`"pkg:kubectl-plugin" [label="kubectl-plugin"];`,
+		`"platform:darwin-arm64" [label="darwin-arm64", shape=ellipse];`,
+		`"pkg:kubectl" -> "platform:darwin-arm64";`,
+		`"pkg:kubectl" -> "platform:linux-amd64";`,
+		`"pkg:kubectl-plugin" -> "platform:linux-amd64";`,
+		`"pkg:kubectl-plugin" -> "pkg:kubectl" [style=dashed, label="depends on"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %s:\n%s", want, dot)
+		}
+	}
+	if strings.Count(dot, " -> ") != 4 {
+		t.Errorf("DOT should have 3 platform edges and 1 dependency edge (no build tools):\n%s", dot)
+	}
+	if !strings.HasPrefix(dot, "digraph potions {") || !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("DOT is not a complete digraph:\n%s", dot)
+	}
+}
+
+// Test the Mermaid graph uses safe node IDs and honors the platform filter
+func TestWriteMermaidGraph(t *testing.T) {
+	var out bytes.Buffer
+	writeMermaidGraph(&out, newRecipeGraph(graphTestRecipes(), "linux-amd64"))
+
+	want := `graph LR
+  p0["kubectl"]
+  p1["kubectl-plugin"]
+  t0(["linux-amd64"])
+  p0 --> t0
+  p1 --> t0
+  p1 -.->|depends on| p0
+`
+	if out.String() != want {
+		t.Errorf("Mermaid =\n%s\nwant\n%s", out.String(), want)
+	}
+}
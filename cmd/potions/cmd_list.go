@@ -2,20 +2,33 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain/entities"
-	"github.com/ochairo/potions/internal/external-adapters/yaml"
+	"github.com/ochairo/potions/internal/domain/services"
 )
 
 func runList(ctx context.Context, args []string) {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
 	var (
 		recipesDir   = fs.String("recipes-dir", "recipes", "Path to recipes directory")
+		recipeFormat = fs.String("recipe-format", "", "Only read recipes in this format: yaml, toml or json (default: detect by extension)")
 		platform     = fs.String("platform", "", "Filter by platform (e.g., darwin-arm64)")
 		securityOnly = fs.Bool("security-enabled", false, "Only show packages with security scanning enabled")
+		include      = fs.String("include", "", "Comma-separated recipe name globs to include (e.g., 'k8s-*,kube*')")
+		exclude      = fs.String("exclude", "", "Comma-separated recipe name globs to exclude")
+		groupBy      = fs.String("group-by", "", "Group the output; 'platform' lists packages under each platform")
+		tree         = fs.Bool("tree", false, "Shorthand for --group-by platform")
+		format       = fs.String("format", "text", "Output format: text or csv (package, platforms, security_enabled, version_source)")
 	)
 
 	fs.Usage = func() {
@@ -31,20 +44,44 @@ Examples:
   potions list
   potions list --platform darwin-arm64
   potions list --security-enabled
+  potions list --include 'k8s-*' --exclude 'k8s-legacy-*'
+  potions list --tree                          # Packages per platform, flagging incomplete coverage
+  potions list --group-by platform --platform linux-arm64
+  potions list --format csv > recipes.csv     # Spreadsheet export
 `)
 	}
 
+	applyFlagDefaults(fs, flagDefaults)
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(1)
 	}
 
+	if *tree {
+		*groupBy = "platform"
+	}
+	if *groupBy != "" && *groupBy != "platform" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --group-by %q (valid: platform)\n", *groupBy)
+		os.Exit(1)
+	}
+	if *format != "text" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q (valid: text, csv)\n", *format)
+		os.Exit(1)
+	}
+	if *format == "csv" && *groupBy != "" {
+		fmt.Fprintf(os.Stderr, "Error: --format csv cannot be combined with --group-by\n")
+		os.Exit(1)
+	}
+
 	// Initialize repository
-	defRepo := yaml.NewRecipeRepository(*recipesDir)
+	defRepo, err := newFilteredRecipeRepository(*recipesDir, *recipeFormat, *include, *exclude)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Load recipes
 	var defs []*entities.Recipe
-	var err error
 
 	if *platform != "" {
 		defs, err = defRepo.GetRecipesByPlatform(ctx, *platform)
@@ -68,30 +105,144 @@ Examples:
 		defs = filtered
 	}
 
+	if *groupBy == "platform" {
+		writePlatformTree(os.Stdout, defs, *platform)
+		return
+	}
+	if *format == "csv" {
+		if err := writeRecipesCSV(os.Stdout, defs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Display results
 	if *platform != "" {
 		fmt.Printf("Packages for platform %s (%d total):\n\n", *platform, len(defs))
 	} else {
 		fmt.Printf("Available packages (%d total):\n\n", len(defs))
 	}
+	writeRecipeList(os.Stdout, defs, time.Now())
+}
 
+// writeRecipeList prints one block per recipe, flagging deprecated and end-of-life recipes
+func writeRecipeList(w io.Writer, defs []*entities.Recipe, now time.Time) {
 	for _, def := range defs {
 		platforms := make([]string, 0, len(def.Download.Platforms))
 		for p := range def.Download.Platforms {
 			platforms = append(platforms, p)
 		}
 
-		fmt.Printf("  %-20s %s\n", def.Name, def.Description)
-		fmt.Printf("  %-20s Version source: %s\n", "", def.Version.Source)
-		fmt.Printf("  %-20s Platforms: %v\n", "", platforms)
+		fmt.Fprintf(w, "  %-20s %s\n", def.Name, def.Description)
+		if note := def.LifecycleNote(now); note != "" {
+			fmt.Fprintf(w, "  %-20s ⚠️  Lifecycle: %s\n", "", note)
+		}
+		fmt.Fprintf(w, "  %-20s Version source: %s\n", "", def.Version.Source)
+		fmt.Fprintf(w, "  %-20s Platforms: %v\n", "", platforms)
 
 		if def.Security.ScanVulnerabilities {
-			fmt.Printf("  %-20s 🔒 Security: vulnerability scanning enabled\n", "")
+			fmt.Fprintf(w, "  %-20s 🔒 Security: vulnerability scanning enabled\n", "")
 		}
 		if def.Security.VerifySignature {
-			fmt.Printf("  %-20s 🔐 Security: GPG signature verification enabled\n", "")
+			fmt.Fprintf(w, "  %-20s 🔐 Security: GPG signature verification enabled\n", "")
 		}
 
-		fmt.Println()
+		fmt.Fprintln(w)
+	}
+}
+
+// writeRecipesCSV writes one RFC 4180 row per recipe after a header row; platforms are
+// sorted and space-separated within their cell
+func writeRecipesCSV(w io.Writer, defs []*entities.Recipe) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"package", "platforms", "security_enabled", "version_source"})
+	for _, def := range defs {
+		platforms := make([]string, 0, len(def.Download.Platforms))
+		for platform := range def.Download.Platforms {
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+		_ = writer.Write([]string{def.Name, strings.Join(platforms, " "),
+			strconv.FormatBool(def.Security.ScanVulnerabilities), def.Version.Source})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// standardPlatforms are the platforms every recipe is expected to cover
+var standardPlatforms = []string{
+	string(services.PlatformLinuxAMD64),
+	string(services.PlatformLinuxARM64),
+	string(services.PlatformDarwinAMD64),
+	string(services.PlatformDarwinARM64),
+}
+
+// writePlatformTree prints packages grouped under each platform they declare, flagging
+// packages missing a standard platform; platformFilter limits the output to one platform
+func writePlatformTree(w io.Writer, defs []*entities.Recipe, platformFilter string) {
+	groups := make(map[string][]*entities.Recipe)
+	for _, def := range defs {
+		for platform := range def.Download.Platforms {
+			if platformFilter == "" || platform == platformFilter {
+				groups[platform] = append(groups[platform], def)
+			}
+		}
+	}
+
+	// Standard platforms first, in their usual order, then any extra architectures
+	platforms := make([]string, 0, len(groups))
+	for _, platform := range standardPlatforms {
+		if _, ok := groups[platform]; ok {
+			platforms = append(platforms, platform)
+		}
+	}
+	var extra []string
+	for platform := range groups {
+		if !slices.Contains(standardPlatforms, platform) {
+			extra = append(extra, platform)
+		}
+	}
+	sort.Strings(extra)
+	platforms = append(platforms, extra...)
+
+	incomplete := 0
+	for _, def := range defs {
+		if len(missingStandardPlatforms(def)) > 0 {
+			incomplete++
+		}
+	}
+
+	fmt.Fprintf(w, "Packages by platform (%d packages, %d missing a standard platform):\n", len(defs), incomplete)
+	for _, platform := range platforms {
+		recipes := groups[platform]
+		sort.Slice(recipes, func(i, j int) bool { return recipes[i].Name < recipes[j].Name })
+
+		fmt.Fprintf(w, "\n%s (%d)\n", platform, len(recipes))
+		for i, def := range recipes {
+			branch := "├──"
+			if i == len(recipes)-1 {
+				branch = "└──"
+			}
+			line := fmt.Sprintf("%s %s", branch, def.Name)
+			if missing := missingStandardPlatforms(def); len(missing) > 0 {
+				line += fmt.Sprintf("  ⚠️  missing %s", strings.Join(missing, ", "))
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// missingStandardPlatforms returns the standard platforms a recipe does not declare
+func missingStandardPlatforms(def *entities.Recipe) []string {
+	var missing []string
+	for _, platform := range standardPlatforms {
+		if _, ok := def.Download.Platform(platform); !ok {
+			missing = append(missing, platform)
+		}
 	}
+	return missing
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// Test the platform tree lists a package only under the platforms it declares
+func TestWritePlatformTree(t *testing.T) {
+	defs := []*entities.Recipe{
+		{Name: "everywhere", Download: entities.RecipeDownload{Platforms: map[string]entities.PlatformConfig{
+			"linux-amd64": {}, "linux-arm64": {}, "darwin-x86_64": {}, "darwin-arm64": {},
+		}}},
+		{Name: "linux-only", Download: entities.RecipeDownload{Platforms: map[string]entities.PlatformConfig{
+			"linux-amd64": {}, "linux-arm64": {},
+		}}},
+	}
+
+	var out bytes.Buffer
+	writePlatformTree(&out, defs, "")
+	sections := platformSections(out.String())
+
+	if !strings.Contains(sections["linux-amd64 (2)"], "linux-only") {
+		t.Errorf("linux-amd64 section = %q, want linux-only listed", sections["linux-amd64 (2)"])
+	}
+	if darwin := sections["darwin-arm64 (1)"]; darwin == "" || strings.Contains(darwin, "linux-only") {
+		t.Errorf("darwin-arm64 section = %q, want only everywhere", darwin)
+	}
+	if !strings.Contains(out.String(), "linux-only  ⚠️  missing darwin-x86_64, darwin-arm64") {
+		t.Errorf("output should flag missing standard platforms:\n%s", out.String())
+	}
+
+	out.Reset()
+	writePlatformTree(&out, defs, "darwin-arm64")
+	if strings.Contains(out.String(), "linux-amd64") {
+		t.Errorf("--platform darwin-arm64 output should only show that platform:\n%s", out.String())
+	}
+}
+
+// Test the CSV export parses back into one row per recipe with sorted platforms
+func TestWriteRecipesCSV(t *testing.T) {
+	defs := []*entities.Recipe{
+		{Name: "kubectl", Version: entities.VersionConfig{Source: "github-release:kubernetes/kubernetes"},
+			Security: entities.RecipeSecurity{ScanVulnerabilities: true},
+			Download: entities.RecipeDownload{Platforms: map[string]entities.PlatformConfig{"linux-arm64": {}, "darwin-arm64": {}}}},
+		{Name: "jq", Version: entities.VersionConfig{Source: `json:https://example.com/index.json#"latest, stable"`},
+			Download: entities.RecipeDownload{Platforms: map[string]entities.PlatformConfig{"linux-amd64": {}}}},
+	}
+
+	var out bytes.Buffer
+	if err := writeRecipesCSV(&out, defs); err != nil {
+		t.Fatalf("writeRecipesCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+
+	want := [][]string{
+		{"package", "platforms", "security_enabled", "version_source"},
+		{"kubectl", "darwin-arm64 linux-arm64", "true", "github-release:kubernetes/kubernetes"},
+		{"jq", "linux-amd64", "false", `json:https://example.com/index.json#"latest, stable"`},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+// platformSections splits tree output into its platform headers and entries
+func platformSections(output string) map[string]string {
+	sections := make(map[string]string)
+	for _, block := range strings.Split(output, "\n\n")[1:] {
+		header, body, _ := strings.Cut(block, "\n")
+		sections[header] = body
+	}
+	return sections
+}
+
+// Test deprecated and end-of-life recipes carry a warning marker in the list output
+func TestWriteRecipeList_Lifecycle(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	defs := []*entities.Recipe{
+		{Name: "kubectl"},
+		{Name: "legacy", Deprecated: true, EOLDate: time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{Name: "aging", EOLDate: time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var out bytes.Buffer
+	writeRecipeList(&out, defs, now)
+	output := out.String()
+
+	for _, want := range []string{
+		"⚠️  Lifecycle: deprecated, end of life since 2026-06-30",
+		"⚠️  Lifecycle: end of life on 2027-01-31",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "⚠️") != 2 {
+		t.Errorf("only the deprecated and end-of-life recipes should be flagged:\n%s", output)
+	}
+}
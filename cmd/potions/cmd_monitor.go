@@ -2,13 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	orchestrators "github.com/ochairo/potions/internal/domain-orchestrators"
+	"github.com/ochairo/potions/internal/domain/entities"
+	domainGateways "github.com/ochairo/potions/internal/domain/interfaces/gateways"
 	"github.com/ochairo/potions/internal/external-adapters/yaml"
 )
 
@@ -20,16 +28,40 @@ type UpdateInfo struct {
 	UpdateNeeded   bool   `json:"update_needed"`
 	RecipeFile     string `json:"recipe_file"`
 	Error          string `json:"error,omitempty"`
+	Deprecated     bool   `json:"deprecated,omitempty"`
+	EOL            bool   `json:"eol,omitempty"`
+	EOLDate        string `json:"eol_date,omitempty"`
+	Deferred       bool   `json:"deferred,omitempty"`         // Not checked because the GitHub rate limit was exhausted
+	RateLimitReset string `json:"rate_limit_reset,omitempty"` // When a deferred check can be retried (RFC 3339)
+}
+
+// MonitorPlan describes the lookups monitor would make for a package, reported by --dry-run
+type MonitorPlan struct {
+	Package       string   `json:"package"`
+	RecipeFile    string   `json:"recipe_file"`
+	VersionSource string   `json:"version_source,omitempty"`
+	Requests      []string `json:"requests"`
+	ReleaseCheck  string   `json:"release_check,omitempty"`
+	Error         string   `json:"error,omitempty"`
 }
 
 func runMonitor(ctx context.Context, args []string) {
 	fs := flag.NewFlagSet("monitor", flag.ExitOnError)
 	var (
-		all        = fs.Bool("all", false, "Check all packages for updates")
-		jsonOutput = fs.Bool("json", true, "Output results as JSON (default)")
-		recipesDir = fs.String("recipes-dir", "recipes", "Path to recipes directory")
-		repoOwner  = fs.String("repo-owner", "ochairo", "GitHub repository owner")
-		repoName   = fs.String("repo-name", "potions", "GitHub repository name")
+		all          = fs.Bool("all", false, "Check all packages for updates")
+		jsonOutput   = fs.Bool("json", true, "Output results as JSON (default)")
+		format       = fs.String("format", "", "Output format: json, text or csv (package, current, latest, update_available, error); overrides --json")
+		recipesDir   = fs.String("recipes-dir", "recipes", "Path to recipes directory")
+		recipeFormat = fs.String("recipe-format", "", "Only read recipes in this format: yaml, toml or json (default: detect by extension)")
+		repoOwner    = fs.String("repo-owner", "ochairo", "GitHub repository owner")
+		repoName     = fs.String("repo-name", "potions", "GitHub repository name")
+		include      = fs.String("include", "", "Comma-separated recipe name globs to check with --all (e.g., 'k8s-*')")
+		exclude      = fs.String("exclude", "", "Comma-separated recipe name globs to skip with --all")
+		timeout      = fs.Duration("version-timeout", gateways.DefaultVersionTimeout, "Deadline per version lookup request (0 disables)")
+		dryRun       = fs.Bool("dry-run", false, "Print the version sources and requests that would be made without contacting the network")
+		snapshot     = fs.String("snapshot", "", "JSON file of package versions from the previous run: report packages updated since, then save the current versions")
+		urlCacheDir  = fs.String("url-cache-dir", "", "Cache for url: version sources, revalidated with ETag/If-Modified-Since (default: user cache dir/potions/versions)")
+		noURLCache   = fs.Bool("no-url-cache", false, "Always re-fetch url: version sources in full")
 	)
 
 	fs.Usage = func() {
@@ -47,28 +79,57 @@ Examples:
   potions monitor --all                    # Check all packages
   potions monitor kubectl helm age         # Check specific packages
   potions monitor kubectl --json=false     # Human-readable output
+  potions monitor --all --format csv       # Spreadsheet export
+  potions monitor --all --include 'k8s-*'  # Check only matching packages
+  potions monitor --all --dry-run          # Validate recipes offline and list planned requests
+  potions monitor --all --json=false --snapshot monitor-snapshot.json  # Report what changed since the last run
 `)
 	}
 
+	applyFlagDefaults(fs, flagDefaults)
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(1)
 	}
 
+	switch *format {
+	case "":
+	case "json", "text":
+		*jsonOutput = *format == "json"
+	case "csv":
+		if *dryRun {
+			fmt.Fprintf(os.Stderr, "Error: --format csv is not supported with --dry-run\n")
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --format %q (valid: json, text, csv)\n", *format)
+		os.Exit(1)
+	}
+
 	// Initialize repository
-	defRepo := yaml.NewRecipeRepository(*recipesDir)
+	defRepo, err := newFilteredRecipeRepository(*recipesDir, *recipeFormat, *include, *exclude)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Initialize version fetcher
-	versionFetcher := gateways.NewVersionFetcher()
+	versionFetcher := gateways.NewVersionFetcher().WithTimeout(*timeout).
+		WithURLCache(resolveCacheDir(*urlCacheDir, *noURLCache, "versions"))
 
 	// Initialize GitHub gateway for release checking
 	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
 		token = os.Getenv("GH_TOKEN")
 	}
-	var githubGW *gateways.HTTPGitHubGateway
-	if token != "" {
-		githubGW = gateways.NewHTTPGitHubGateway(token)
+	var githubGW domainGateways.GitHubGateway
+	if token != "" || githubAppConfigured() {
+		httpGW, err := newGitHubGateway(token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		githubGW = httpGW
 	}
 
 	// Determine which packages to check
@@ -93,53 +154,107 @@ Examples:
 		os.Exit(1)
 	}
 
-	// Check each package for updates with timeout protection
-	var updates []UpdateInfo
-	for _, pkgName := range packagesToCheck {
-		// Check if context already cancelled (timeout or user interrupt)
-		select {
-		case <-ctx.Done():
-			// Context cancelled - output what we have so far
-			if *jsonOutput {
-				outputJSON(updates)
-			} else {
-				outputHuman(updates)
-				fmt.Fprintf(os.Stderr, "\n⚠️  Stopped checking packages: %v\n", ctx.Err())
-				fmt.Fprintf(os.Stderr, "Checked %d of %d packages.\n", len(updates), len(packagesToCheck))
-			}
+	if *dryRun {
+		plans := planMonitorChecks(ctx, defRepo, versionFetcher, packagesToCheck, *recipesDir, *repoOwner, *repoName)
+		if err := printMonitorPlans(os.Stdout, plans, *jsonOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
-		default:
-			// Continue checking
 		}
+		return
+	}
 
-		update := checkPackageUpdate(ctx, defRepo, versionFetcher, githubGW, pkgName, *recipesDir, *repoOwner, *repoName)
-		updates = append(updates, update)
+	// Check each package for updates with timeout protection
+	checker := &updateChecker{
+		defRepo:        defRepo,
+		versionFetcher: versionFetcher,
+		githubGW:       githubGW,
+		recipesDir:     *recipesDir,
+		repoOwner:      *repoOwner,
+		repoName:       *repoName,
+	}
+	updates, err := checker.checkAll(ctx, packagesToCheck)
+	if err != nil {
+		// Context cancelled (timeout or user interrupt) - output what we have so far
+		switch {
+		case *format == "csv":
+			outputCSV(updates)
+		case *jsonOutput:
+			outputJSON(updates)
+		default:
+			outputHuman(updates)
+			fmt.Fprintf(os.Stderr, "\n⚠️  Stopped checking packages: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Checked %d of %d packages.\n", len(updates), len(packagesToCheck))
+		}
+		os.Exit(1)
 	}
 
 	// Output all results
-	if *jsonOutput {
+	switch {
+	case *format == "csv":
+		outputCSV(updates)
+	case *jsonOutput:
 		outputJSON(updates)
-	} else {
+	default:
 		outputHuman(updates)
 	}
 
+	if *snapshot != "" {
+		// Keep stdout parseable for JSON and CSV consumers
+		out := io.Writer(os.Stderr)
+		if *format != "csv" && !*jsonOutput {
+			out = os.Stdout
+		}
+		if err := updateMonitorSnapshot(out, *snapshot, updates); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Always exit with code 0 - errors are documented in JSON and human-readable output
 	// Individual package errors don't cause failure of the entire monitoring operation
 	// The workflow script should parse the JSON to determine if there are updates
 }
 
-func checkPackageUpdate(ctx context.Context, defRepo *yaml.RecipeRepository, versionFetcher *gateways.VersionFetcher, githubGW *gateways.HTTPGitHubGateway, pkgName, recipesDir, repoOwner, repoName string) UpdateInfo {
+// updateChecker checks packages for updates one at a time; once a check exhausts the GitHub
+// rate limit, the remaining packages are deferred without further network requests
+type updateChecker struct {
+	defRepo        *yaml.RecipeRepository
+	versionFetcher orchestrators.VersionFetcher
+	githubGW       domainGateways.GitHubGateway // nil when no token is available
+	recipesDir     string
+	repoOwner      string
+	repoName       string
+	rateLimit      *gateways.RateLimitError // Set by the first check that hit an exhausted rate limit
+}
+
+// checkAll checks packages in order, returning the results so far and ctx's error if it is done first
+func (c *updateChecker) checkAll(ctx context.Context, packages []string) ([]UpdateInfo, error) {
+	updates := make([]UpdateInfo, 0, len(packages))
+	for _, pkgName := range packages {
+		if err := ctx.Err(); err != nil {
+			return updates, err
+		}
+		updates = append(updates, c.checkPackageUpdate(ctx, pkgName))
+	}
+	return updates, nil
+}
+
+func (c *updateChecker) checkPackageUpdate(ctx context.Context, pkgName string) UpdateInfo {
 	update := UpdateInfo{
 		Package:    pkgName,
-		RecipeFile: fmt.Sprintf("%s/%s.yml", recipesDir, pkgName),
+		RecipeFile: fmt.Sprintf("%s/%s.yml", c.recipesDir, pkgName),
 	}
 
 	// Load recipe
-	def, err := defRepo.GetRecipe(ctx, pkgName)
+	def, err := c.defRepo.GetRecipe(ctx, pkgName)
 	if err != nil {
 		update.Error = fmt.Sprintf("failed to load recipe: %v", err)
 		return update
 	}
+	if recipePath, err := c.defRepo.RecipePath(pkgName); err == nil {
+		update.RecipeFile = recipePath
+	}
+	setRecipeLifecycle(&update, def, time.Now())
 
 	// Check if version source is configured
 	if def.Version.Source == "" {
@@ -147,9 +262,18 @@ func checkPackageUpdate(ctx context.Context, defRepo *yaml.RecipeRepository, ver
 		return update
 	}
 
+	// Don't spend requests that are bound to fail until the rate limit resets
+	if c.rateLimit != nil {
+		deferUpdate(&update, c.rateLimit)
+		return update
+	}
+
 	// Fetch latest version
-	latestVersion, err := versionFetcher.FetchLatestVersion(def)
+	latestVersion, err := c.versionFetcher.FetchLatestVersion(def)
 	if err != nil {
+		if c.deferOnRateLimit(&update, err) {
+			return update
+		}
 		update.Error = fmt.Sprintf("failed to fetch version: %v", err)
 		return update
 	}
@@ -157,17 +281,18 @@ func checkPackageUpdate(ctx context.Context, defRepo *yaml.RecipeRepository, ver
 	update.LatestVersion = latestVersion
 
 	// Check if this version is already released on GitHub
-	if githubGW != nil {
-		releaseTag := fmt.Sprintf("%s-%s", pkgName, latestVersion)
-		_, err := githubGW.GetRelease(ctx, repoOwner, repoName, releaseTag)
+	if c.githubGW != nil {
+		err := findPackageRelease(ctx, c.githubGW, c.repoOwner, c.repoName, pkgName, latestVersion)
 		switch {
 		case err == nil:
 			// Release exists - no update needed
 			update.UpdateNeeded = false
 			update.CurrentVersion = latestVersion
-		case strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found"):
+		case isReleaseNotFound(err):
 			// Release doesn't exist - update needed
 			update.UpdateNeeded = true
+		case c.deferOnRateLimit(&update, err):
+			// Release status unknown until the rate limit resets
 		default:
 			// Error checking release (e.g., rate limit, network issue)
 			// Be conservative: assume update is NOT needed to avoid duplicate releases
@@ -185,6 +310,192 @@ func checkPackageUpdate(ctx context.Context, defRepo *yaml.RecipeRepository, ver
 	return update
 }
 
+// deferOnRateLimit defers update when err is an exhausted rate limit and remembers the limit so
+// later checks are deferred too
+func (c *updateChecker) deferOnRateLimit(update *UpdateInfo, err error) bool {
+	var rateLimitErr *gateways.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return false
+	}
+	c.rateLimit = rateLimitErr
+	deferUpdate(update, rateLimitErr)
+	return true
+}
+
+// deferUpdate marks update as not checked until the rate limit resets
+func deferUpdate(update *UpdateInfo, rateLimit *gateways.RateLimitError) {
+	update.Deferred = true
+	if !rateLimit.ResetAt.IsZero() {
+		update.RateLimitReset = rateLimit.ResetAt.UTC().Format(time.RFC3339)
+	}
+}
+
+// snapshotChange is a package whose latest version differs from the previous snapshot
+type snapshotChange struct {
+	Package  string
+	Previous string
+	Current  string
+}
+
+// updateMonitorSnapshot reports the packages updated since the snapshot at path was written,
+// then saves the current versions to it; a missing snapshot is a first run with nothing to report
+func updateMonitorSnapshot(w io.Writer, path string, updates []UpdateInfo) error {
+	previous, err := loadMonitorSnapshot(path)
+	if err != nil {
+		return err
+	}
+	changes, current := diffMonitorSnapshot(previous, updates)
+
+	if previous == nil {
+		fmt.Fprintf(w, "\n📸 No snapshot at %s yet, recording %d packages\n", path, len(current))
+	} else if len(changes) == 0 {
+		fmt.Fprintf(w, "\n📸 No packages updated since last check\n")
+	} else {
+		fmt.Fprintf(w, "\n📸 %d packages updated since last check:\n", len(changes))
+		for _, change := range changes {
+			fmt.Fprintf(w, "   %-20s %s → %s\n", change.Package, change.Previous, change.Current)
+		}
+	}
+
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadMonitorSnapshot reads a package to version snapshot, returning nil when none exists yet
+func loadMonitorSnapshot(path string) (map[string]string, error) {
+	//nolint:gosec // G304: path is the user's snapshot file
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	snapshot := make(map[string]string)
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// diffMonitorSnapshot returns the checked packages whose latest version changed since previous,
+// and the snapshot to save; packages that failed or were not checked keep their previous version
+// so a later run still reports their update, and packages new to the snapshot are only recorded
+func diffMonitorSnapshot(previous map[string]string, updates []UpdateInfo) ([]snapshotChange, map[string]string) {
+	current := make(map[string]string, len(previous)+len(updates))
+	for pkg, version := range previous {
+		current[pkg] = version
+	}
+
+	var changes []snapshotChange
+	for _, update := range updates {
+		if update.LatestVersion == "" {
+			continue
+		}
+		current[update.Package] = update.LatestVersion
+		before, ok := previous[update.Package]
+		if ok && entities.CanonicalVersion(before) != entities.CanonicalVersion(update.LatestVersion) {
+			changes = append(changes, snapshotChange{Package: update.Package, Previous: before, Current: update.LatestVersion})
+		}
+	}
+	return changes, current
+}
+
+// findPackageRelease looks up the release of a package version under each tag it may carry
+// It stops at the first error other than not found, so a rate limit is never read as a missing release
+func findPackageRelease(ctx context.Context, githubGW domainGateways.GitHubGateway, owner, repo, pkgName, version string) error {
+	var err error
+	for _, tag := range entities.ReleaseTagCandidates(pkgName, version) {
+		if _, err = githubGW.GetRelease(ctx, owner, repo, tag); err == nil || !isReleaseNotFound(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// isReleaseNotFound reports whether a release lookup failed because the tag does not exist
+func isReleaseNotFound(err error) bool {
+	return strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found")
+}
+
+// setRecipeLifecycle copies the recipe's deprecation and end-of-life status into the update
+func setRecipeLifecycle(update *UpdateInfo, def *entities.Recipe, now time.Time) {
+	update.Deprecated = def.Deprecated
+	if !def.EOLDate.IsZero() {
+		update.EOLDate = def.EOLDate.Format(time.DateOnly)
+		update.EOL = !now.Before(def.EOLDate)
+	}
+}
+
+// planMonitorChecks resolves each package's recipe and version source into the requests
+// checkPackageUpdate would make, without sending any of them
+func planMonitorChecks(ctx context.Context, defRepo *yaml.RecipeRepository, versionFetcher *gateways.VersionFetcher, packages []string, recipesDir, repoOwner, repoName string) []MonitorPlan {
+	plans := make([]MonitorPlan, 0, len(packages))
+	for _, pkgName := range packages {
+		plan := MonitorPlan{
+			Package:    pkgName,
+			RecipeFile: fmt.Sprintf("%s/%s.yml", recipesDir, pkgName),
+			Requests:   []string{},
+		}
+		if recipePath, err := defRepo.RecipePath(pkgName); err == nil {
+			plan.RecipeFile = recipePath
+		}
+
+		def, err := defRepo.GetRecipe(ctx, pkgName)
+		if err != nil {
+			plan.Error = fmt.Sprintf("failed to load recipe: %v", err)
+			plans = append(plans, plan)
+			continue
+		}
+		plan.VersionSource = def.Version.Source
+
+		requests, err := versionFetcher.PlannedRequests(def)
+		if err != nil {
+			plan.Error = err.Error()
+			plans = append(plans, plan)
+			continue
+		}
+		plan.Requests = requests
+		plan.ReleaseCheck = fmt.Sprintf("%s/%s release %s-<latest>", repoOwner, repoName, pkgName)
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// printMonitorPlans writes the --dry-run plan as JSON or one block per package
+func printMonitorPlans(w io.Writer, plans []MonitorPlan, jsonOutput bool) error {
+	if jsonOutput {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(plans); err != nil {
+			return fmt.Errorf("failed to encode plan: %w", err)
+		}
+		return nil
+	}
+
+	errors := 0
+	for _, plan := range plans {
+		if plan.Error != "" {
+			fmt.Fprintf(w, "❌ %-20s ERROR: %s\n", plan.Package, plan.Error)
+			errors++
+			continue
+		}
+		fmt.Fprintf(w, "🔍 %-20s %s\n", plan.Package, plan.VersionSource)
+		for _, request := range plan.Requests {
+			fmt.Fprintf(w, "   GET %s\n", request)
+		}
+		fmt.Fprintf(w, "   check %s\n", plan.ReleaseCheck)
+	}
+	fmt.Fprintf(w, "\nDry run: %d packages planned, %d errors, no requests sent\n", len(plans), errors)
+	return nil
+}
+
 func outputJSON(updates []UpdateInfo) {
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
@@ -194,6 +505,31 @@ func outputJSON(updates []UpdateInfo) {
 	}
 }
 
+func outputCSV(updates []UpdateInfo) {
+	if err := writeUpdatesCSV(os.Stdout, updates); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeUpdatesCSV writes one RFC 4180 row per checked package after a header row
+func writeUpdatesCSV(w io.Writer, updates []UpdateInfo) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"package", "current", "latest", "update_available", "error", "deprecated", "eol",
+		"deferred", "rate_limit_reset"})
+	for _, update := range updates {
+		_ = writer.Write([]string{update.Package, update.CurrentVersion, update.LatestVersion,
+			strconv.FormatBool(update.UpdateNeeded), update.Error,
+			strconv.FormatBool(update.Deprecated), strconv.FormatBool(update.EOL),
+			strconv.FormatBool(update.Deferred), update.RateLimitReset})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
 func outputHuman(updates []UpdateInfo) {
 	fmt.Println("Package Update Check Results")
 	fmt.Println(strings.Repeat("=", 60))
@@ -202,7 +538,16 @@ func outputHuman(updates []UpdateInfo) {
 	updatesAvailable := 0
 	errors := 0
 
+	retired := 0
+
+	var deferred []UpdateInfo
+
 	for _, update := range updates {
+		if update.Deferred {
+			// Listed apart from real errors below
+			deferred = append(deferred, update)
+			continue
+		}
 		//nolint:gocritic // ifElseChain: checking different struct fields, not suitable for switch
 		if update.Error != "" {
 			fmt.Printf("❌ %-20s ERROR: %s\n", update.Package, update.Error)
@@ -213,9 +558,37 @@ func outputHuman(updates []UpdateInfo) {
 		} else {
 			fmt.Printf("✅ %-20s %s (up to date)\n", update.Package, update.CurrentVersion)
 		}
+		if note := updateLifecycleNote(update); note != "" {
+			fmt.Printf("   %-20s ⚠️  %s\n", "", note)
+			retired++
+		}
+	}
+
+	if len(deferred) > 0 {
+		reset := "an unknown time"
+		if deferred[0].RateLimitReset != "" {
+			reset = deferred[0].RateLimitReset
+		}
+		fmt.Println()
+		fmt.Printf("⏸️  Deferred %d packages: GitHub API rate limit exhausted, resets at %s\n", len(deferred), reset)
+		for _, update := range deferred {
+			fmt.Printf("   %s\n", update.Package)
+		}
 	}
 
 	fmt.Println()
-	fmt.Printf("Summary: %d packages checked, %d updates available, %d errors\n",
-		len(updates), updatesAvailable, errors)
+	fmt.Printf("Summary: %d packages checked, %d updates available, %d errors, %d deferred, %d deprecated or end of life\n",
+		len(updates)-len(deferred), updatesAvailable, errors, len(deferred), retired)
+}
+
+// updateLifecycleNote describes a checked package's deprecation and end-of-life status, or ""
+func updateLifecycleNote(update UpdateInfo) string {
+	var notes []string
+	if update.Deprecated {
+		notes = append(notes, "deprecated")
+	}
+	if update.EOL {
+		notes = append(notes, "end of life since "+update.EOLDate)
+	}
+	return strings.Join(notes, ", ")
 }
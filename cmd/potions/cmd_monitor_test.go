@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/domain/entities"
+	"github.com/ochairo/potions/internal/testsupport"
+)
+
+// Test --dry-run lists each recipe's planned version requests without sending any
+func TestPlanMonitorChecks(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte("9.9.9"))
+	}))
+	defer server.Close()
+
+	recipesDir := t.TempDir()
+	sources := map[string]string{
+		"plain":   "url:" + server.URL + "/VERSION",
+		"feed":    "json:" + server.URL + "/index.json#latest.version",
+		"tagged":  "github-tag:example/tagged",
+		"pinned":  "static:1.0.0",
+		"unknown": "ftp:example.com",
+	}
+	for name, source := range sources {
+		recipe := fmt.Sprintf(`name: %s
+version:
+  source: %q
+download:
+  download_url: "https://example.com/%s-{version}.tar.gz"
+  platforms:
+    linux-amd64: {os: linux, arch: amd64}
+`, name, source, name)
+		if err := os.WriteFile(filepath.Join(recipesDir, name+".yml"), []byte(recipe), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defRepo, err := newFilteredRecipeRepository(recipesDir, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plans := planMonitorChecks(context.Background(), defRepo, gateways.NewVersionFetcher(),
+		[]string{"plain", "feed", "tagged", "pinned", "unknown", "missing"}, recipesDir, "ochairo", "potions")
+	var buf bytes.Buffer
+	if err := printMonitorPlans(&buf, plans, false); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"GET " + server.URL + "/VERSION",
+		"GET " + server.URL + "/index.json\n",
+		"GET https://api.github.com/repos/example/tagged/tags",
+		"check ochairo/potions release pinned-<latest>",
+		"unsupported version.source format: ftp:example.com",
+		"missing              ERROR: failed to load recipe",
+		"6 packages planned, 2 errors",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("plan output missing %q:\n%s", want, output)
+		}
+	}
+	if plans[3].Package != "pinned" || len(plans[3].Requests) != 0 {
+		t.Errorf("static source plan = %+v, want no requests", plans[3])
+	}
+	if hits.Load() != 0 {
+		t.Errorf("dry run sent %d HTTP requests, want none", hits.Load())
+	}
+}
+
+// Test the monitor CSV export parses back into one row per result, quoting errors with commas
+func TestWriteUpdatesCSV(t *testing.T) {
+	updates := []UpdateInfo{
+		{Package: "kubectl", CurrentVersion: "1.28.0", LatestVersion: "1.29.0", UpdateNeeded: true},
+		{Package: "helm", CurrentVersion: "3.14.0", LatestVersion: "3.14.0", Deprecated: true, EOL: true, EOLDate: "2026-01-31"},
+		{Package: "broken", Error: "failed to fetch latest version: status 404, \"not found\""},
+		{Package: "later", Deferred: true, RateLimitReset: "2026-01-01T00:00:00Z"},
+	}
+
+	var out bytes.Buffer
+	if err := writeUpdatesCSV(&out, updates); err != nil {
+		t.Fatalf("writeUpdatesCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+
+	want := [][]string{
+		{"package", "current", "latest", "update_available", "error", "deprecated", "eol", "deferred", "rate_limit_reset"},
+		{"kubectl", "1.28.0", "1.29.0", "true", "", "false", "false", "false", ""},
+		{"helm", "3.14.0", "3.14.0", "false", "", "true", "true", "false", ""},
+		{"broken", "", "", "false", "failed to fetch latest version: status 404, \"not found\"", "false", "false", "false", ""},
+		{"later", "", "", "false", "", "false", "false", "true", "2026-01-01T00:00:00Z"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+// Test deprecated and end-of-life recipes are flagged in monitor results
+func TestSetRecipeLifecycle(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		def  *entities.Recipe
+		want UpdateInfo
+	}{
+		{"maintained", &entities.Recipe{Name: "kubectl"}, UpdateInfo{}},
+		{"deprecated", &entities.Recipe{Name: "old", Deprecated: true}, UpdateInfo{Deprecated: true}},
+		{"past eol", &entities.Recipe{Name: "legacy", EOLDate: now.AddDate(0, -1, 0)},
+			UpdateInfo{EOL: true, EOLDate: "2026-09-01"}},
+		{"eol ahead", &entities.Recipe{Name: "aging", EOLDate: now.AddDate(1, 0, 0)},
+			UpdateInfo{EOLDate: "2027-10-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update UpdateInfo
+			setRecipeLifecycle(&update, tt.def, now)
+			if update != tt.want {
+				t.Errorf("update = %+v, want %+v", update, tt.want)
+			}
+		})
+	}
+
+	data, err := json.Marshal(UpdateInfo{Package: "old", Deprecated: true, EOL: true, EOLDate: "2026-09-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"deprecated":true,"eol":true,"eol_date":"2026-09-01"`) {
+		t.Errorf("JSON = %s, want deprecated and eol flags", data)
+	}
+}
+
+// Test --snapshot reports only packages whose version changed since the previous run
+func TestUpdateMonitorSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	previous := `{"kubectl": "1.28.0", "helm": "3.14.0", "ripgrep": "v1.1.1", "flaky": "2.0.0", "unchecked": "0.9.0"}`
+	if err := os.WriteFile(path, []byte(previous), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := []UpdateInfo{
+		{Package: "kubectl", LatestVersion: "1.29.0"},
+		{Package: "helm", LatestVersion: "3.14.0"},
+		{Package: "ripgrep", LatestVersion: "1.1.1"},
+		{Package: "flaky", Error: "failed to fetch version: timeout"},
+		{Package: "newcomer", LatestVersion: "0.1.0"},
+	}
+	var out bytes.Buffer
+	if err := updateMonitorSnapshot(&out, path, updates); err != nil {
+		t.Fatalf("updateMonitorSnapshot() error = %v", err)
+	}
+
+	if !strings.Contains(out.String(), "1 packages updated since last check") || !strings.Contains(out.String(), "1.28.0 → 1.29.0") {
+		t.Errorf("output = %q, want only kubectl reported", out.String())
+	}
+	for _, pkg := range []string{"helm", "ripgrep", "flaky", "newcomer", "unchecked"} {
+		if strings.Contains(out.String(), pkg) {
+			t.Errorf("output = %q, should not report %s", out.String(), pkg)
+		}
+	}
+
+	saved, err := loadMonitorSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"kubectl": "1.29.0", "helm": "3.14.0", "ripgrep": "1.1.1", "flaky": "2.0.0", "unchecked": "0.9.0", "newcomer": "0.1.0"}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved snapshot = %v, want %v", saved, want)
+	}
+
+	// The next run compares against the saved state
+	out.Reset()
+	if err := updateMonitorSnapshot(&out, path, updates); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "No packages updated since last check") {
+		t.Errorf("second run output = %q, want no updates", out.String())
+	}
+}
+
+// rateLimitedFetcher returns a version until its limit of calls is spent, then a rate limit error
+type rateLimitedFetcher struct {
+	limit   int
+	resetAt time.Time
+	calls   int
+}
+
+func (f *rateLimitedFetcher) FetchLatestVersion(_ *entities.Recipe) (string, error) {
+	f.calls++
+	if f.calls > f.limit {
+		return "", fmt.Errorf("failed to fetch releases: %w", &gateways.RateLimitError{ResetAt: f.resetAt})
+	}
+	return "2.0.0", nil
+}
+
+// Test packages after a rate limit error are deferred without further lookups, apart from real errors
+func TestUpdateChecker_RateLimitDefersRemaining(t *testing.T) {
+	recipesDir := t.TempDir()
+	for _, name := range []string{"alpha", "beta", "gamma", "delta"} {
+		recipe := fmt.Sprintf("name: %s\nversion:\n  source: github-release:example/%s\n", name, name)
+		if err := os.WriteFile(filepath.Join(recipesDir, name+".yml"), []byte(recipe), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defRepo, err := newFilteredRecipeRepository(recipesDir, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resetAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	fetcher := &rateLimitedFetcher{limit: 1, resetAt: resetAt}
+	checker := &updateChecker{
+		defRepo:        defRepo,
+		versionFetcher: fetcher,
+		githubGW:       testsupport.NewFakeGitHubGateway(),
+		recipesDir:     recipesDir,
+		repoOwner:      "ochairo",
+		repoName:       "potions",
+	}
+
+	updates, err := checker.checkAll(context.Background(), []string{"alpha", "missing", "beta", "gamma", "delta"})
+	if err != nil {
+		t.Fatalf("checkAll() error = %v", err)
+	}
+	if fetcher.calls != 2 {
+		t.Errorf("version lookups = %d, want 2 (none after the rate limit)", fetcher.calls)
+	}
+
+	if updates[0].Deferred || !updates[0].UpdateNeeded || updates[0].LatestVersion != "2.0.0" {
+		t.Errorf("alpha = %+v, want checked with an update available", updates[0])
+	}
+	if updates[1].Deferred || !strings.Contains(updates[1].Error, "failed to load recipe") {
+		t.Errorf("missing = %+v, want a real error", updates[1])
+	}
+	for _, update := range updates[2:] {
+		if !update.Deferred || update.Error != "" || update.RateLimitReset != "2026-01-01T12:00:00Z" {
+			t.Errorf("%s = %+v, want deferred until the reset without an error", update.Package, update)
+		}
+		if update.RecipeFile != filepath.Join(recipesDir, update.Package+".yml") {
+			t.Errorf("%s recipe file = %q", update.Package, update.RecipeFile)
+		}
+	}
+}
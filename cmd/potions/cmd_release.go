@@ -1,17 +1,27 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/domain/entities"
+	"github.com/ochairo/potions/internal/domain/interfaces"
 	domainGateways "github.com/ochairo/potions/internal/domain/interfaces/gateways"
 	"github.com/ochairo/potions/internal/domain/services"
+	"github.com/ochairo/potions/internal/external-adapters/gpg"
 	"github.com/ochairo/potions/internal/external-adapters/yaml"
 )
 
@@ -30,6 +40,28 @@ type ReleaseReport struct {
 	SuccessRate float64  `json:"success_rate"`
 }
 
+// PackageReleaseReport is the per-package report written to --report-dir
+type PackageReleaseReport struct {
+	Package      string                   `json:"package"`
+	Version      string                   `json:"version"`
+	Status       string                   `json:"status"`
+	Error        string                   `json:"error,omitempty"`
+	ReleaseURL   string                   `json:"release_url,omitempty"`
+	Assets       []string                 `json:"assets"`
+	FailedAssets []string                 `json:"failed_assets,omitempty"`
+	Validation   *PackageValidationReport `json:"validation,omitempty"`
+}
+
+// PackageValidationReport summarizes platform validation for a package release
+type PackageValidationReport struct {
+	Status             string   `json:"status"`
+	ExpectedCount      int      `json:"expected_count"`
+	AvailableCount     int      `json:"available_count"`
+	AvailablePlatforms []string `json:"available_platforms"`
+	MissingPlatforms   []string `json:"missing_platforms,omitempty"`
+	OptionalMissing    []string `json:"optional_missing_platforms,omitempty"`
+}
+
 // RateLimitInfo contains GitHub API rate limit information
 type RateLimitInfo struct {
 	Limit     int
@@ -55,15 +87,28 @@ func runRelease(ctx context.Context, args []string) {
 		dryRun      = fs.Bool("dry-run", false, "Show what would be released without actually releasing")
 		draft       = fs.Bool("draft", false, "Create as draft release")
 		prerelease  = fs.Bool("prerelease", false, "Mark as pre-release")
+		waitPublish = fs.Bool("wait-publish", false, "Create as draft, upload assets, then publish only if all critical assets uploaded")
+		atomic      = fs.Bool("atomic", false, "Create as draft and publish only if every asset uploaded; otherwise delete the release")
+		signKey     = fs.String("sign-manifest-key", "", "GPG private key file used to sign a SHA256SUMS manifest (uploads SHA256SUMS and SHA256SUMS.asc)")
+		verifyAfter = fs.Bool("verify-after-release", false, "Download each published asset and compare its SHA256 with the local file")
+		provenance  = fs.Bool("include-checksums-in-provenance", false, "Upload one SLSA provenance whose subjects are every release asset with its digests")
+		pruneOld    = fs.Int("prune-old", 0, "After a stable release, delete the package's prereleases except the N most recent (lists them unless --yes)")
+		confirm     = fs.Bool("yes", false, "Confirm --prune-old deletions")
 
 		// Multiple packages flags
 		packages      = fs.String("packages", "", "JSON array of packages to release")
 		artifactsDir  = fs.String("artifacts", "current-artifacts", "Directory containing artifacts")
 		recipesDir    = fs.String("recipes", "recipes", "Directory containing recipe files")
 		reportFile    = fs.String("report", "", "Write JSON report to file")
+		reportDir     = fs.String("report-dir", "", "Write a <package>-<version>.json report per package to directory")
 		failuresFile  = fs.String("failures", "release-failures.txt", "Write failures to file")
 		successesFile = fs.String("successes", "release-successes.txt", "Write successes to file")
 		maxReleases   = fs.Int("max-releases", 50, "Maximum releases to process per run (for rate limit safety)")
+		concurrency   = fs.Int("concurrency", 1, "Number of packages to release in parallel within a batch")
+		batchDelay    = fs.Duration("batch-delay", 0, "Pause between starting packages to avoid GitHub secondary rate limits (e.g. 2s)")
+		historyFile   = fs.String("history", "", "Append one JSON line per release outcome to this file (kept across runs; requires --packages)")
+		outputLayout  = fs.String("output-layout", string(entities.LayoutFlat), "Layout the artifacts were built with: flat, by-package or by-package-version")
+		notesTemplate = fs.String("notes-template", "", "Go text/template file rendered as the release body instead of the built-in notes")
 	)
 
 	fs.Usage = func() {
@@ -78,34 +123,109 @@ Examples:
   potions release kubectl v1.28.0 --binaries ./dist
   potions release kubectl v1.28.0 --dry-run
   potions release kubectl v1.28.0 --draft --prerelease
+  potions release kubectl v1.28.0 --wait-publish
+  potions release kubectl v1.28.0 --atomic               # All assets or no release at all
+  potions release kubectl v1.28.0 --sign-manifest-key release-key.asc
+  potions release kubectl v1.28.0 --output-layout by-package-version
+  potions release kubectl v1.28.0 --verify-after-release
+  potions release kubectl v1.28.0 --include-checksums-in-provenance
+  potions release kubectl v1.28.0 --prune-old 3          # List prereleases beyond the 3 most recent
+  potions release kubectl v1.28.0 --prune-old 3 --yes    # ...and delete them
+  potions release kubectl v1.28.0 --notes-template .github/release-notes.tmpl
 
   # Multiple packages from JSON
   potions release --packages '[{"package":"kubectl","version":"v1.28.0"}]'
   potions release --packages @packages.json --artifacts ./dist
   potions release --packages "$PACKAGES_JSON" --report report.json
+  potions release --packages @packages.json --report-dir reports/
+  potions release --packages @packages.json --concurrency 4
+  potions release --packages @packages.json --atomic
+  potions release --packages @packages.json --batch-delay 2s
+  potions release --packages @packages.json --history release-history.jsonl
 
 Options:
 `)
 		fs.PrintDefaults()
 		fmt.Fprintf(os.Stderr, `
 Environment Variables:
-  GITHUB_TOKEN    GitHub personal access token (required)
+  GITHUB_TOKEN              GitHub personal access token (required unless a GitHub App is set)
+  POTIONS_GITHUB_APP_ID     GitHub App ID; authenticates as the App installation instead of GITHUB_TOKEN
+  POTIONS_GITHUB_APP_INSTALLATION_ID
+                            Installation ID of the GitHub App (required with POTIONS_GITHUB_APP_ID)
+  POTIONS_GITHUB_APP_PRIVATE_KEY_FILE
+                            PEM private key of the GitHub App (required with POTIONS_GITHUB_APP_ID)
+  POTIONS_GPG_PASSPHRASE    Passphrase for --sign-manifest-key (if the key is encrypted)
 `)
 	}
 
+	applyFlagDefaults(fs, flagDefaults)
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(1)
 	}
 
+	layout, err := parseOutputLayout(*outputLayout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	var manifestSigner *gpg.Signer
+	if *signKey != "" {
+		signer, err := gpg.NewSignerFromFile(*signKey, os.Getenv("POTIONS_GPG_PASSPHRASE"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		manifestSigner = signer
+	}
+
+	if *pruneOld < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --prune-old must not be negative\n")
+		os.Exit(2)
+	}
+
+	var notes *template.Template
+	if *notesTemplate != "" {
+		if notes, err = loadNotesTemplate(*notesTemplate); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
 	// Release multiple packages from JSON input
 	if *packages != "" {
+		if *pruneOld > 0 {
+			fmt.Fprintf(os.Stderr, "Error: --prune-old is only supported for single package releases\n")
+			os.Exit(2)
+		}
 		token := os.Getenv("GITHUB_TOKEN")
-		if token == "" {
+		if token == "" && !githubAppConfigured() {
 			fmt.Fprintf(os.Stderr, "Error: GITHUB_TOKEN environment variable is required\n")
 			os.Exit(2)
 		}
-		if err := releaseFromPackageList(ctx, *packages, *artifactsDir, *recipesDir, *owner, *repo, token, *reportFile, *failuresFile, *successesFile, *maxReleases); err != nil {
+		opts := BatchReleaseOptions{
+			ArtifactsDir:  *artifactsDir,
+			RecipesDir:    *recipesDir,
+			Owner:         *owner,
+			Repo:          *repo,
+			ReportFile:    *reportFile,
+			ReportDir:     *reportDir,
+			FailuresFile:  *failuresFile,
+			SuccessesFile: *successesFile,
+			MaxReleases:   *maxReleases,
+			Concurrency:   *concurrency,
+			BatchDelay:    *batchDelay,
+			OutputLayout:  layout,
+			HistoryFile:   *historyFile,
+			WaitPublish:   *waitPublish,
+			Atomic:        *atomic,
+			VerifyAfter:   *verifyAfter,
+			Provenance:    *provenance,
+			Signer:        manifestSigner,
+			NotesTemplate: notes,
+		}
+		if err := releaseFromPackageList(ctx, *packages, token, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -113,6 +233,10 @@ Environment Variables:
 	}
 
 	// Release single package from CLI args
+	if *historyFile != "" {
+		fmt.Fprintf(os.Stderr, "Error: --history is only supported with --packages\n")
+		os.Exit(2)
+	}
 	if fs.NArg() < 2 {
 		fmt.Fprintf(os.Stderr, "Error: package name and version are required\n\n")
 		fs.Usage()
@@ -124,31 +248,72 @@ Environment Variables:
 
 	// Get GitHub token (only required for non-dry-run)
 	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" && !*dryRun {
+	if token == "" && !githubAppConfigured() && !*dryRun {
 		fmt.Fprintf(os.Stderr, "Error: GITHUB_TOKEN environment variable is required (not needed for --dry-run)\n")
 		os.Exit(1)
 	}
 
-	if err := releasePackage(ctx, packageName, version, *binariesDir, *owner, *repo, token, *dryRun, *draft, *prerelease); err != nil {
+	if err := releasePackage(ctx, packageName, version, token, releaseOptions{
+		BatchReleaseOptions: BatchReleaseOptions{
+			ArtifactsDir:  *binariesDir,
+			Owner:         *owner,
+			Repo:          *repo,
+			OutputLayout:  layout,
+			WaitPublish:   *waitPublish,
+			Atomic:        *atomic,
+			VerifyAfter:   *verifyAfter,
+			Provenance:    *provenance,
+			Signer:        manifestSigner,
+			NotesTemplate: notes,
+		},
+		DryRun:     *dryRun,
+		Draft:      *draft,
+		Prerelease: *prerelease,
+	}); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+
+	if *pruneOld > 0 {
+		switch {
+		case *prerelease || *draft:
+			fmt.Println("⚠️  Skipping --prune-old: prereleases are only pruned after a stable release")
+		case token == "" && !githubAppConfigured():
+			fmt.Printf("🔍 Dry-run mode - would prune %s prereleases beyond the %d most recent (set GITHUB_TOKEN to list them)\n", packageName, *pruneOld)
+		default:
+			githubGW, err := newGitHubGateway(token)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if _, err := pruneOldPrereleases(ctx, os.Stdout, githubGW, *owner, *repo, packageName, *pruneOld, *confirm && !*dryRun); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+}
+
+// releaseOptions configures a single package release; ArtifactsDir holds the --binaries directory
+type releaseOptions struct {
+	BatchReleaseOptions
+	DryRun     bool // Print the release that would be created without calling GitHub
+	Draft      bool // Leave the release as a draft
+	Prerelease bool // Mark the release as a prerelease
 }
 
-func releasePackage(ctx context.Context, packageName, version, binariesDir, owner, repo, token string, dryRun, draft, prerelease bool) error {
+func releasePackage(ctx context.Context, packageName, version, token string, opts releaseOptions) error {
 	fmt.Printf("🚀 Releasing %s %s\n", packageName, version)
-	fmt.Printf("📁 Binaries directory: %s\n", binariesDir)
+	fmt.Printf("📁 Binaries directory: %s\n", opts.ArtifactsDir)
 
 	// Initialize artifact finder
-	artifactFinder := gateways.NewArtifactFinder()
+	artifactFinder := gateways.NewArtifactFinder().WithLayout(opts.OutputLayout)
 
-	// Normalize version to ensure it starts with 'v'
-	if !strings.HasPrefix(version, "v") {
-		version = "v" + version
-	}
+	// Releases display the version with a leading 'v'; file names use the canonical form
+	version = entities.DisplayVersion(version)
 
 	// Tag format: packageName-version (e.g., kubectl-v1.28.0)
-	tagName := fmt.Sprintf("%s-%s", packageName, version)
+	tagName := entities.ReleaseTag(packageName, version)
 
 	// Load recipe to validate expected platforms
 	recipeRepo := yaml.NewRecipeRepository("recipes")
@@ -159,13 +324,13 @@ func releasePackage(ctx context.Context, packageName, version, binariesDir, owne
 	}
 
 	// Find all artifacts for this package
-	artifacts, err := artifactFinder.FindByGlob(binariesDir, packageName, version)
+	artifacts, err := artifactFinder.FindByGlob(opts.ArtifactsDir, packageName, version)
 	if err != nil {
 		return fmt.Errorf("failed to find artifacts: %w", err)
 	}
 
 	if len(artifacts) == 0 {
-		return fmt.Errorf("no artifacts found in %s for %s %s", binariesDir, packageName, version)
+		return fmt.Errorf("no artifacts found in %s for %s %s", opts.ArtifactsDir, packageName, version)
 	}
 
 	fmt.Printf("📦 Found %d artifacts:\n", len(artifacts))
@@ -184,7 +349,7 @@ func releasePackage(ctx context.Context, packageName, version, binariesDir, owne
 
 	// Validate platform coverage if recipe is available
 	if recipe != nil {
-		releaseService := services.NewReleaseService()
+		releaseService := services.NewReleaseService().WithOutputLayout(opts.OutputLayout)
 		validation := releaseService.ValidateRelease(recipe, packageName, version, artifacts)
 
 		fmt.Printf("\n🔍 Platform Validation:\n")
@@ -200,29 +365,78 @@ func releasePackage(ctx context.Context, packageName, version, binariesDir, owne
 		fmt.Println("  ✅ All expected platforms present")
 	}
 
-	if dryRun {
+	if opts.DryRun {
 		fmt.Println("\n🔍 Dry-run mode - no release will be created")
 		fmt.Printf("Would create release:\n")
-		fmt.Printf("  Repository: %s/%s\n", owner, repo)
+		fmt.Printf("  Repository: %s/%s\n", opts.Owner, opts.Repo)
 		fmt.Printf("  Tag: %s\n", tagName)
 		fmt.Printf("  Name: %s %s\n", packageName, version)
-		fmt.Printf("  Draft: %v\n", draft)
-		fmt.Printf("  Prerelease: %v\n", prerelease)
+		fmt.Printf("  Draft: %v\n", opts.Draft)
+		fmt.Printf("  Wait for uploads before publishing: %v\n", opts.WaitPublish)
+		fmt.Printf("  Atomic (delete on incomplete upload): %v\n", opts.Atomic)
+		fmt.Printf("  Prerelease: %v\n", opts.Prerelease)
+		fmt.Printf("  Signed SHA256SUMS manifest: %v\n", opts.Signer != nil)
+		fmt.Printf("  Release provenance: %v\n", opts.Provenance)
 		fmt.Printf("  Artifacts: %d files\n", len(artifacts))
 		return nil
 	}
 
+	mergedDir, err := os.MkdirTemp("", "potions-sbom-*")
+	if err != nil {
+		return fmt.Errorf("failed to create SBOM directory: %w", err)
+	}
+	//nolint:errcheck // Best effort cleanup of temp directory
+	defer os.RemoveAll(mergedDir)
+
+	if mergedSBOM, err := mergedSBOMAsset(mergedDir, packageName, version, artifacts); err != nil {
+		fmt.Printf("⚠️  Warning: could not merge SBOMs: %v\n", err)
+	} else if mergedSBOM != "" {
+		fmt.Printf("📋 Merged SBOMs into %s\n", filepath.Base(mergedSBOM))
+		artifacts = append(artifacts, mergedSBOM)
+	}
+
+	if opts.Provenance {
+		releaseProvenance, err := releaseProvenanceAsset(mergedDir, packageName, version, artifacts)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("📜 Attested %d assets in %s\n", len(artifacts), filepath.Base(releaseProvenance))
+		artifacts = append(artifacts, releaseProvenance)
+	}
+
+	if opts.Signer != nil {
+		manifestDir, err := os.MkdirTemp("", "potions-manifest-*")
+		if err != nil {
+			return fmt.Errorf("failed to create manifest directory: %w", err)
+		}
+		//nolint:errcheck // Best effort cleanup of temp directory
+		defer os.RemoveAll(manifestDir)
+
+		manifestAssets, err := signedManifestAssets(manifestDir, artifacts, opts.Signer)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🔏 Signed %s with key %s\n", services.ChecksumManifestName, opts.Signer.KeyID())
+		artifacts = append(artifacts, manifestAssets...)
+	}
+
 	// Initialize GitHub gateway
-	githubGW := gateways.NewHTTPGitHubGateway(token)
+	githubGW, err := newGitHubGateway(token)
+	if err != nil {
+		return err
+	}
 
 	// Check if release already exists
 	fmt.Printf("\n🔍 Checking if release %s already exists...\n", tagName)
-	existingRelease, err := githubGW.GetRelease(ctx, owner, repo, tagName)
+	existingRelease, err := githubGW.GetRelease(ctx, opts.Owner, opts.Repo, tagName)
 	if err == nil {
 		fmt.Printf("⚠️  Release %s already exists: %s\n", tagName, existingRelease.HTMLURL)
+		if opts.Atomic {
+			return fmt.Errorf("--atomic needs a new release, but %s already exists", tagName)
+		}
 
 		// List existing assets
-		assets, err := githubGW.ListReleaseAssets(ctx, owner, repo, existingRelease.ID)
+		assets, err := githubGW.ListReleaseAssets(ctx, opts.Owner, opts.Repo, existingRelease.ID)
 		if err != nil {
 			return fmt.Errorf("failed to list existing assets: %w", err)
 		}
@@ -233,22 +447,31 @@ func releasePackage(ctx context.Context, packageName, version, binariesDir, owne
 		}
 
 		// Upload new artifacts to existing release
-		return uploadArtifacts(ctx, githubGW, existingRelease.UploadURL, artifacts)
+		if _, err := uploadArtifacts(ctx, os.Stdout, githubGW, existingRelease.UploadURL, artifacts); err != nil {
+			return err
+		}
+		if opts.VerifyAfter && !existingRelease.Draft {
+			return checkReleaseAssets(ctx, os.Stdout, githubGW, opts.Owner, opts.Repo, existingRelease.ID, artifacts)
+		}
+		return nil
 	}
 
 	// Create new release
 	fmt.Printf("\n✨ Creating new release %s...\n", tagName)
-	releaseBody := generateReleaseBody(packageName, version, artifacts)
+	releaseBody, err := renderReleaseBody(opts.NotesTemplate, recipe, packageName, version, artifacts)
+	if err != nil {
+		return err
+	}
 
 	release := &domainGateways.GitHubRelease{
 		TagName:    tagName,
 		Name:       fmt.Sprintf("%s %s", packageName, version),
 		Body:       releaseBody,
-		Draft:      draft,
-		Prerelease: prerelease,
+		Draft:      opts.Draft || opts.WaitPublish || opts.Atomic,
+		Prerelease: opts.Prerelease,
 	}
 
-	createdRelease, err := githubGW.CreateRelease(ctx, owner, repo, release)
+	createdRelease, err := githubGW.CreateRelease(ctx, opts.Owner, opts.Repo, release)
 	if err != nil {
 		return fmt.Errorf("failed to create release: %w", err)
 	}
@@ -256,14 +479,504 @@ func releasePackage(ctx context.Context, packageName, version, binariesDir, owne
 	fmt.Printf("✅ Release created: %s\n", createdRelease.HTMLURL)
 
 	// Upload artifacts
-	return uploadArtifacts(ctx, githubGW, createdRelease.UploadURL, artifacts)
+	failedUploads, uploadErr := uploadArtifacts(ctx, os.Stdout, githubGW, createdRelease.UploadURL, artifacts)
+	if opts.Atomic && len(failedUploads) > 0 {
+		return rollbackRelease(ctx, os.Stdout, githubGW, opts.Owner, opts.Repo, createdRelease, failedUploads)
+	}
+	latestAlias := recipe != nil && recipe.LatestAlias && !opts.Prerelease
+	if !(opts.WaitPublish || opts.Atomic) || opts.Draft {
+		if uploadErr != nil {
+			return uploadErr
+		}
+		if opts.Draft {
+			if opts.VerifyAfter {
+				fmt.Println("⚠️  Skipping --verify-after-release: draft release assets are not publicly downloadable")
+			}
+			return nil
+		}
+		if opts.VerifyAfter {
+			if err := checkReleaseAssets(ctx, os.Stdout, githubGW, opts.Owner, opts.Repo, createdRelease.ID, artifacts); err != nil {
+				return err
+			}
+		}
+		if latestAlias {
+			updateLatestAlias(ctx, os.Stdout, githubGW, opts.Owner, opts.Repo, packageName, version, artifacts)
+		}
+		return nil
+	}
+
+	// Publish the draft only once every critical asset is in place
+	if critical := criticalAssets(failedUploads); uploadErr != nil || len(critical) > 0 {
+		fmt.Printf("\n⚠️  Release left as draft: %s\n", createdRelease.HTMLURL)
+		if uploadErr != nil {
+			return fmt.Errorf("release left as draft: %w", uploadErr)
+		}
+		return fmt.Errorf("release left as draft: critical assets failed to upload: %s", strings.Join(critical, ", "))
+	}
+
+	publishedRelease, err := publishRelease(ctx, githubGW, opts.Owner, opts.Repo, createdRelease)
+	if err != nil {
+		return fmt.Errorf("uploads succeeded but publishing failed (release left as draft): %w", err)
+	}
+
+	fmt.Printf("🚀 Release published: %s\n", publishedRelease.HTMLURL)
+	if opts.VerifyAfter {
+		if err := checkReleaseAssets(ctx, os.Stdout, githubGW, opts.Owner, opts.Repo, publishedRelease.ID, artifacts); err != nil {
+			return err
+		}
+	}
+	if latestAlias {
+		updateLatestAlias(ctx, os.Stdout, githubGW, opts.Owner, opts.Repo, packageName, version, artifacts)
+	}
+	return nil
 }
 
-//nolint:gocyclo // High complexity acceptable for batch release orchestration (CLI handler)
-func releaseFromPackageList(ctx context.Context, packagesJSON, artifactsDir, recipesDir, owner, repo, token, reportFile, failuresFile, successesFile string, maxReleases int) error {
+// rollbackRelease deletes a release whose uploads were incomplete under --atomic so it is
+// never published partially; the returned error says whether the deletion succeeded
+func rollbackRelease(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, owner, repo string, release *domainGateways.GitHubRelease, failedUploads []string) error {
+	reason := fmt.Sprintf("assets failed to upload: %s", strings.Join(failedUploads, ", "))
+	if err := githubGW.DeleteRelease(ctx, owner, repo, release.ID); err != nil {
+		fmt.Fprintf(w, "❌ Rollback failed, release left as draft: %s\n", release.HTMLURL)
+		return fmt.Errorf("%s; rollback failed, release left as draft (%s): %w", reason, release.HTMLURL, err)
+	}
+	fmt.Fprintf(w, "↩️  Deleted release %s\n", release.TagName)
+	return fmt.Errorf("release rolled back: %s", reason)
+}
+
+// latestAliasTag returns the tag of the release mirroring a package's newest tarballs
+func latestAliasTag(packageName string) string {
+	return packageName + "-latest"
+}
+
+// aliasAssetName returns the version-less name of a package tarball or checksum
+// (kubectl-1.28.0-linux-amd64.tar.gz -> kubectl-linux-amd64.tar.gz), or "" for other assets
+func aliasAssetName(packageName, version, filename string) string {
+	rest, ok := strings.CutPrefix(filename, fmt.Sprintf("%s-%s-", packageName, entities.CanonicalVersion(version)))
+	if !ok || !(strings.HasSuffix(rest, ".tar.gz") || strings.HasSuffix(rest, ".tar.gz.sha256")) {
+		return ""
+	}
+	return packageName + "-" + rest
+}
+
+// updateLatestAlias mirrors a published release to <package>-latest, warning instead of failing
+// the release when the mirror cannot be updated
+func updateLatestAlias(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, owner, repo, packageName, version string, artifacts []string) {
+	if err := mirrorLatestRelease(ctx, w, githubGW, owner, repo, packageName, version, artifacts); err != nil {
+		fmt.Fprintf(w, "  ⚠️  Could not update %s: %v\n", latestAliasTag(packageName), err)
+	}
+}
+
+// latestAliasName returns the title of the <package>-latest release mirroring version
+func latestAliasName(packageName, version string) string {
+	return fmt.Sprintf("%s latest (%s)", packageName, entities.DisplayVersion(version))
+}
+
+// latestAliasVersion returns the version a <package>-latest release mirrors, or "" when its title
+// was not written by latestAliasName
+func latestAliasVersion(packageName string, release *domainGateways.GitHubRelease) string {
+	rest, ok := strings.CutPrefix(release.Name, packageName+" latest (")
+	if !ok {
+		return ""
+	}
+	version, ok := strings.CutSuffix(rest, ")")
+	if !ok {
+		return ""
+	}
+	return entities.CanonicalVersion(version)
+}
+
+// mirrorLatestRelease points the package's <package>-latest release at version-less copies of the
+// tarballs and checksums in artifacts, so users can hardcode a stable download URL
+// The replacement is staged as a draft and only takes over the tag once its assets are uploaded;
+// a -latest release already mirroring a newer version is left alone
+func mirrorLatestRelease(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, owner, repo, packageName, version string, artifacts []string) error {
+	tag := latestAliasTag(packageName)
+	existing, err := githubGW.GetRelease(ctx, owner, repo, tag)
+	switch {
+	case err == nil:
+		if current := latestAliasVersion(packageName, existing); current != "" && entities.CompareVersions(version, current) < 0 {
+			fmt.Fprintf(w, "  ⏭️  Keeping %s at %s, newer than %s\n", tag, entities.DisplayVersion(current), entities.DisplayVersion(version))
+			return nil
+		}
+	case isReleaseNotFound(err):
+		existing = nil
+	default:
+		return fmt.Errorf("failed to look up previous release: %w", err)
+	}
+
+	staged, err := githubGW.CreateRelease(ctx, owner, repo, &domainGateways.GitHubRelease{
+		TagName: tag + "-next",
+		Name:    latestAliasName(packageName, version),
+		Body:    fmt.Sprintf("Version-less copies of the %s assets, for stable download links.\n", entities.ReleaseTag(packageName, version)),
+		Draft:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create release: %w", err)
+	}
+
+	for _, artifact := range artifacts {
+		name := filepath.Base(artifact)
+		alias := aliasAssetName(packageName, version, name)
+		if alias == "" {
+			continue
+		}
+		if err := uploadAliasAsset(ctx, githubGW, staged.UploadURL, artifact, name, alias); err != nil {
+			_ = githubGW.DeleteRelease(ctx, owner, repo, staged.ID)
+			return err
+		}
+	}
+
+	if existing != nil {
+		if err := githubGW.DeleteRelease(ctx, owner, repo, existing.ID); err != nil {
+			_ = githubGW.DeleteRelease(ctx, owner, repo, staged.ID)
+			return fmt.Errorf("failed to delete previous release: %w", err)
+		}
+	}
+	publish := *staged
+	publish.TagName = tag
+	publish.Draft = false
+	if _, err := githubGW.UpdateRelease(ctx, owner, repo, staged.ID, &publish); err != nil {
+		return fmt.Errorf("failed to publish release (left as draft): %w", err)
+	}
+	fmt.Fprintf(w, "  🔗 Mirrored version-less assets to %s\n", tag)
+	return nil
+}
+
+// uploadAliasAsset uploads the artifact under alias; checksum files are rewritten to name the
+// aliased tarball so "shasum -c" works on the renamed download
+func uploadAliasAsset(ctx context.Context, githubGW domainGateways.GitHubGateway, uploadURL, artifact, name, alias string) error {
+	var content io.Reader
+	if strings.HasSuffix(alias, ".sha256") {
+		//nolint:gosec // G304: artifact is a release file found under the artifacts directory
+		data, err := os.ReadFile(artifact)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		content = strings.NewReader(strings.ReplaceAll(string(data), strings.TrimSuffix(name, ".sha256"), strings.TrimSuffix(alias, ".sha256")))
+	} else {
+		//nolint:gosec // G304: artifact is a release file found under the artifacts directory
+		file, err := os.Open(artifact)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer func() { _ = file.Close() }()
+		content = file
+	}
+
+	if _, err := githubGW.UploadAsset(ctx, uploadURL, alias, content); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", alias, err)
+	}
+	return nil
+}
+
+// pruneOldPrereleases deletes packageName's published prereleases beyond the keep most recent,
+// newest first by tag version and then publish date; with apply false it only lists them
+func pruneOldPrereleases(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, owner, repo, packageName string, keep int, apply bool) ([]string, error) {
+	releases, err := githubGW.ListReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	prefix := packageName + "-"
+	var prereleases []*domainGateways.GitHubRelease
+	for _, release := range releases {
+		version, ok := strings.CutPrefix(release.TagName, prefix)
+		// Require a version-looking suffix so "tool-extra-1.0" is not pruned with "tool"
+		if ok && startsWithVersion(version) && release.Prerelease && !release.Draft {
+			prereleases = append(prereleases, release)
+		}
+	}
+	sort.SliceStable(prereleases, func(i, j int) bool {
+		a, b := prereleases[i], prereleases[j]
+		if c := entities.CompareVersions(strings.TrimPrefix(a.TagName, prefix), strings.TrimPrefix(b.TagName, prefix)); c != 0 {
+			return c > 0
+		}
+		if a.PublishedAt != b.PublishedAt {
+			return a.PublishedAt > b.PublishedAt
+		}
+		return a.TagName > b.TagName
+	})
+
+	if len(prereleases) <= keep {
+		fmt.Fprintf(w, "\n🧹 %s has %d prerelease(s); nothing to prune (keeping %d)\n", packageName, len(prereleases), keep)
+		return nil, nil
+	}
+
+	stale := prereleases[keep:]
+	fmt.Fprintf(w, "\n🧹 Pruning %d of %d %s prerelease(s), keeping the %d most recent\n", len(stale), len(prereleases), packageName, keep)
+	var pruned []string
+	for _, release := range stale {
+		if !apply {
+			fmt.Fprintf(w, "  - would delete %s\n", release.TagName)
+			continue
+		}
+		if err := githubGW.DeleteRelease(ctx, owner, repo, release.ID); err != nil {
+			return pruned, fmt.Errorf("failed to delete release %s: %w", release.TagName, err)
+		}
+		fmt.Fprintf(w, "  🗑️  Deleted %s\n", release.TagName)
+		pruned = append(pruned, release.TagName)
+	}
+	if !apply {
+		fmt.Fprintln(w, "  Nothing deleted: pass --yes (without --dry-run) to delete these releases")
+	}
+	return pruned, nil
+}
+
+// publishRelease marks a draft release as published
+func publishRelease(ctx context.Context, githubGW domainGateways.GitHubGateway, owner, repo string, release *domainGateways.GitHubRelease) (*domainGateways.GitHubRelease, error) {
+	update := *release
+	update.Draft = false
+	return githubGW.UpdateRelease(ctx, owner, repo, release.ID, &update)
+}
+
+// criticalAssets filters asset filenames down to those a release cannot ship without
+// (tarballs, their checksums and the signed manifest)
+func criticalAssets(filenames []string) []string {
+	var critical []string
+	for _, name := range filenames {
+		switch {
+		case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tar.gz.sha256"),
+			name == services.ChecksumManifestName, name == services.ChecksumManifestName+".asc":
+			critical = append(critical, name)
+		}
+	}
+	return critical
+}
+
+// signedManifestAssets writes a SHA256SUMS manifest covering artifacts into dir and signs it,
+// returning the manifest and detached signature paths to upload alongside the artifacts
+func signedManifestAssets(dir string, artifacts []string, signer *gpg.Signer) ([]string, error) {
+	securityService := services.NewSecurityArtifactsService(&interfaces.NoOpLogger{})
+	manifestPath, err := securityService.GenerateChecksumManifest(dir, artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate checksum manifest: %w", err)
+	}
+
+	sigPath := manifestPath + ".asc"
+	if err := signer.SignDetachedFile(manifestPath, sigPath); err != nil {
+		return nil, fmt.Errorf("failed to sign checksum manifest: %w", err)
+	}
+
+	return []string{manifestPath, sigPath}, nil
+}
+
+// mergedSBOMAsset merges the per-artifact SBOMs among artifacts into one release SBOM in dir,
+// returning "" when no artifact has an SBOM
+func mergedSBOMAsset(dir, packageName, version string, artifacts []string) (string, error) {
+	var sboms []string
+	for _, artifact := range artifacts {
+		if strings.HasSuffix(artifact, ".sbom.json") {
+			sboms = append(sboms, artifact)
+		}
+	}
+	if len(sboms) == 0 {
+		return "", nil
+	}
+
+	securityService := services.NewSecurityArtifactsService(&interfaces.NoOpLogger{}).WithClock(buildClock())
+	return securityService.MergeSBOMs(dir, packageName, version, sboms)
+}
+
+// releaseProvenanceAsset writes a provenance statement into dir whose subjects are all of artifacts
+func releaseProvenanceAsset(dir, packageName, version string, artifacts []string) (string, error) {
+	securityService := services.NewSecurityArtifactsService(&interfaces.NoOpLogger{}).WithClock(buildClock())
+	provenancePath, err := securityService.GenerateReleaseProvenance(dir, packageName, version, artifacts)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate release provenance: %w", err)
+	}
+	return provenancePath, nil
+}
+
+// checkReleaseAssets verifies a release's published assets and fails when any differ from the local files
+func checkReleaseAssets(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, owner, repo string, releaseID int64, artifacts []string) error {
+	mismatched, err := verifyReleaseAssets(ctx, w, githubGW, gateways.NewDownloader(), owner, repo, releaseID, artifacts)
+	if err != nil {
+		return fmt.Errorf("release verification failed: %w", err)
+	}
+	if len(mismatched) > 0 {
+		return fmt.Errorf("published assets differ from local files: %s", strings.Join(mismatched, ", "))
+	}
+	return nil
+}
+
+// verifyReleaseAssets downloads each uploaded artifact back from its browser download URL and
+// compares its SHA256 with the local file, returning the names of assets that are missing or differ
+func verifyReleaseAssets(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, downloader *gateways.Downloader, owner, repo string, releaseID int64, artifacts []string) ([]string, error) {
+	assets, err := githubGW.ListReleaseAssets(ctx, owner, repo, releaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release assets: %w", err)
+	}
+	byName := make(map[string]*domainGateways.GitHubAsset, len(assets))
+	for _, asset := range assets {
+		byName[asset.Name] = asset
+	}
+
+	downloadDir, err := os.MkdirTemp("", "potions-verify-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	//nolint:errcheck // Best effort cleanup of temp directory
+	defer os.RemoveAll(downloadDir)
+
+	fmt.Fprintf(w, "\n🔍 Verifying %d published assets...\n", len(artifacts))
+	verifier := gateways.NewChecksumVerifier()
+	var mismatched []string
+	for _, artifact := range artifacts {
+		name := filepath.Base(artifact)
+		asset, ok := byName[name]
+		if !ok || asset.BrowserDownloadURL == "" {
+			fmt.Fprintf(w, "  ❌ %s: not found in release\n", name)
+			mismatched = append(mismatched, name)
+			continue
+		}
+
+		expected, err := verifier.CalculateChecksum(artifact)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", name, err)
+		}
+		downloaded := filepath.Join(downloadDir, name)
+		if err := downloader.DownloadFile(ctx, asset.BrowserDownloadURL, downloaded); err != nil {
+			fmt.Fprintf(w, "  ❌ %s: %v\n", name, err)
+			mismatched = append(mismatched, name)
+			continue
+		}
+		if err := verifier.VerifyChecksum(ctx, downloaded, expected); err != nil {
+			fmt.Fprintf(w, "  ❌ %s: %v\n", name, err)
+			mismatched = append(mismatched, name)
+			continue
+		}
+		fmt.Fprintf(w, "  ✅ %s\n", name)
+	}
+	return mismatched, nil
+}
+
+// sleepContext waits for d or until ctx is cancelled, returning ctx's error in that case
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// BatchReleaseOptions contains options for releasing multiple packages
+type BatchReleaseOptions struct {
+	ArtifactsDir  string
+	RecipesDir    string
+	Owner         string
+	Repo          string
+	ReportFile    string
+	ReportDir     string // Per-package JSON reports are written here when set
+	FailuresFile  string
+	SuccessesFile string
+	MaxReleases   int
+	Concurrency   int                   // Packages processed in parallel within a batch
+	BatchDelay    time.Duration         // Pause before starting each package after the first
+	WaitPublish   bool                  // Create drafts and publish only after critical assets upload
+	Atomic        bool                  // Create drafts, publish only after every asset uploads, delete otherwise
+	VerifyAfter   bool                  // Download published assets and compare them with the local files
+	Provenance    bool                  // Upload one provenance statement covering every asset of each release
+	Signer        *gpg.Signer           // Signs a per-release SHA256SUMS manifest when set
+	OutputLayout  entities.OutputLayout // Directory layout of ArtifactsDir; empty means flat
+	HistoryFile   string                // Per-package outcomes are appended here as JSON Lines when set
+	NotesTemplate *template.Template    // Renders release bodies instead of generateReleaseBody when set
+}
+
+// releaseOutcome is the result of releasing a single package within a batch
+type releaseOutcome int
+
+const (
+	outcomeCreated releaseOutcome = iota
+	outcomeSkipped
+	outcomeFailed
+)
+
+// String returns the status name used in per-package reports
+func (o releaseOutcome) String() string {
+	switch o {
+	case outcomeCreated:
+		return "created"
+	case outcomeSkipped:
+		return "skipped"
+	default:
+		return "failed"
+	}
+}
+
+// batchPackageOutput holds one package's buffered log block and outcome until it is its turn to print
+type batchPackageOutput struct {
+	log     bytes.Buffer
+	outcome releaseOutcome
+	detail  string
+	ran     bool
+	done    chan struct{} // Closed once the package has finished or will never start
+}
+
+// releaseResults tracks batch release results and is safe for concurrent use
+type releaseResults struct {
+	mu             sync.Mutex
+	created        []string
+	skipped        []string
+	failed         []string
+	failureDetails []string
+	history        []HistoryRecord
+}
+
+// record stores the outcome of a single package release
+func (r *releaseResults) record(pkg PackageRelease, outcome releaseOutcome, detail string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	label := fmt.Sprintf("%s %s", pkg.Package, entities.DisplayVersion(pkg.Version))
+	r.history = append(r.history, HistoryRecord{
+		Timestamp: time.Now().UTC(),
+		Command:   "release",
+		Package:   pkg.Package,
+		Version:   pkg.Version,
+		Status:    outcome.String(),
+		Message:   detail,
+	})
+
+	switch outcome {
+	case outcomeCreated:
+		r.created = append(r.created, label)
+	case outcomeSkipped:
+		r.skipped = append(r.skipped, label)
+	case outcomeFailed:
+		r.failed = append(r.failed, label)
+		r.failureDetails = append(r.failureDetails, detail)
+	}
+}
+
+func releaseFromPackageList(ctx context.Context, packagesJSON, token string, opts BatchReleaseOptions) error {
 	fmt.Println("🔍 Processing releases...")
 
-	// Parse packages JSON
+	packages, err := parsePackageList(packagesJSON)
+	if err != nil {
+		return err
+	}
+
+	if len(packages) == 0 {
+		fmt.Println("ℹ️  No packages to release")
+		return nil
+	}
+
+	fmt.Printf("📦 Processing %d package(s)\n\n", len(packages))
+
+	// Initialize GitHub gateway early to check rate limits
+	githubGW, err := newGitHubGateway(token)
+	if err != nil {
+		return err
+	}
+
+	return releaseBatches(ctx, githubGW, packages, opts)
+}
+
+// parsePackageList parses packages from inline JSON, a file path, or @file syntax
+func parsePackageList(packagesJSON string) ([]PackageRelease, error) {
 	var packages []PackageRelease
 
 	// Handle @file syntax
@@ -272,42 +985,52 @@ func releaseFromPackageList(ctx context.Context, packagesJSON, artifactsDir, rec
 		//nolint:gosec // G304: User explicitly provides file path for packages input
 		data, err := os.ReadFile(filename)
 		if err != nil {
-			return fmt.Errorf("failed to read packages file: %w", err)
+			return nil, fmt.Errorf("failed to read packages file: %w", err)
 		}
 		if err := json.Unmarshal(data, &packages); err != nil {
-			return fmt.Errorf("failed to parse packages JSON from file: %w", err)
+			return nil, fmt.Errorf("failed to parse packages JSON from file: %w", err)
 		}
-	} else {
-		// Check if it's a file path
-		if fileInfo, err := os.Stat(packagesJSON); err == nil && !fileInfo.IsDir() {
-			//nolint:gosec // G304: User explicitly provides file path for packages JSON
-			data, err := os.ReadFile(packagesJSON)
-			if err != nil {
-				return fmt.Errorf("failed to read packages file: %w", err)
-			}
-			if err := json.Unmarshal(data, &packages); err != nil {
-				return fmt.Errorf("failed to parse packages JSON from file: %w", err)
-			}
-		} else {
-			// Parse as direct JSON
-			if err := json.Unmarshal([]byte(packagesJSON), &packages); err != nil {
-				return fmt.Errorf("failed to parse packages JSON: %w", err)
-			}
+		return packages, nil
+	}
+
+	// Check if it's a file path
+	if fileInfo, err := os.Stat(packagesJSON); err == nil && !fileInfo.IsDir() {
+		//nolint:gosec // G304: User explicitly provides file path for packages JSON
+		data, err := os.ReadFile(packagesJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read packages file: %w", err)
+		}
+		if err := json.Unmarshal(data, &packages); err != nil {
+			return nil, fmt.Errorf("failed to parse packages JSON from file: %w", err)
 		}
+		return packages, nil
 	}
 
-	if len(packages) == 0 {
-		fmt.Println("ℹ️  No packages to release")
-		return nil
+	// Parse as direct JSON
+	if err := json.Unmarshal([]byte(packagesJSON), &packages); err != nil {
+		return nil, fmt.Errorf("failed to parse packages JSON: %w", err)
 	}
 
-	fmt.Printf("📦 Processing %d package(s)\n\n", len(packages))
+	return packages, nil
+}
 
-	// Initialize GitHub gateway early to check rate limits
-	githubGW := gateways.NewHTTPGitHubGateway(token)
+// canonicalPackageVersions returns a copy of packages with versions in canonical form
+func canonicalPackageVersions(packages []PackageRelease) []PackageRelease {
+	canonical := make([]PackageRelease, len(packages))
+	for i, pkg := range packages {
+		pkg.Version = entities.CanonicalVersion(pkg.Version)
+		canonical[i] = pkg
+	}
+	return canonical
+}
+
+//nolint:gocyclo // High complexity acceptable for batch release orchestration (CLI handler)
+func releaseBatches(ctx context.Context, githubGW domainGateways.GitHubGateway, packages []PackageRelease, opts BatchReleaseOptions) error {
+	// Versions are handled in canonical form; tags and labels add the "v" back
+	packages = canonicalPackageVersions(packages)
 
 	// Split into batches based on rate limit
-	batches := splitPackagesIntoBatches(ctx, packages, githubGW, maxReleases)
+	batches := splitPackagesIntoBatches(ctx, packages, githubGW, opts.MaxReleases)
 
 	if len(batches) > 1 {
 		fmt.Printf("📊 Splitting into %d batch(es) for rate limit safety\n", len(batches))
@@ -315,183 +1038,109 @@ func releaseFromPackageList(ctx context.Context, packagesJSON, artifactsDir, rec
 		fmt.Println()
 	}
 
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
 	// Initialize services
-	recipeRepo := yaml.NewRecipeRepository(recipesDir)
-	releaseService := services.NewReleaseService()
+	recipeRepo := yaml.NewRecipeRepository(opts.RecipesDir)
+	releaseService := services.NewReleaseService().WithOutputLayout(opts.OutputLayout)
 
 	// Get existing releases
 	fmt.Println("🔍 Fetching existing releases...")
-	existingReleases, err := fetchExistingReleases(ctx, githubGW, owner, repo)
+	existingReleases, err := fetchExistingReleases(ctx, githubGW, opts.Owner, opts.Repo)
 	if err != nil {
 		return fmt.Errorf("failed to fetch existing releases: %w", err)
 	}
 	fmt.Printf("   Found %d existing releases\n\n", len(existingReleases))
 
+	if opts.ReportDir != "" {
+		if err := os.MkdirAll(opts.ReportDir, 0750); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+
 	// Track results across all batches
-	var created, skipped, failed []string
-	var failureDetails []string
+	results := &releaseResults{}
 
 	// Process batches
+	started := 0
+	interrupted := false
 	for batchNum, batch := range batches {
+		if interrupted {
+			break
+		}
 		if len(batches) > 1 {
 			fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 			fmt.Printf("📦 Batch %d of %d (%d package(s))\n", batchNum+1, len(batches), len(batch))
 			fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 		}
 
-		// Process each package in this batch
-		for i, pkg := range batch {
-			fmt.Printf("[%d/%d] Processing %s v%s\n", i+1, len(batch), pkg.Package, pkg.Version)
-
-			releaseTag := fmt.Sprintf("%s-%s", pkg.Package, pkg.Version)
-
-			// Check if already exists
-			if existingReleases[releaseTag] {
-				fmt.Printf("  ⏭️  Release already exists, skipping\n\n")
-				skipped = append(skipped, fmt.Sprintf("%s v%s", pkg.Package, pkg.Version))
-				continue
-			}
-
-			// Load recipe
-			recipe, err := recipeRepo.GetRecipe(ctx, pkg.Package)
-			if err != nil {
-				errMsg := fmt.Sprintf("%s v%s - NO_RECIPE: %v", pkg.Package, pkg.Version, err)
-				fmt.Printf("  ❌ %s\n\n", errMsg)
-				failed = append(failed, fmt.Sprintf("%s v%s", pkg.Package, pkg.Version))
-				failureDetails = append(failureDetails, errMsg)
-				continue
-			}
-
-			// Initialize artifact finder
-			artifactFinder := gateways.NewArtifactFinder()
-
-			// Find artifacts
-			artifacts, err := artifactFinder.FindRecursive(artifactsDir, pkg.Package, pkg.Version)
-			if err != nil {
-				errMsg := fmt.Sprintf("%s v%s - FIND_ERROR: %v", pkg.Package, pkg.Version, err)
-				fmt.Printf("  ❌ %s\n\n", errMsg)
-				failed = append(failed, fmt.Sprintf("%s v%s", pkg.Package, pkg.Version))
-				failureDetails = append(failureDetails, errMsg)
-				continue
-			}
-
-			// Debug: Show found artifacts by type
-			fmt.Printf("  📦 Found %d artifacts:\n", len(artifacts))
-			tarballCount := 0
-			checksumCount := 0
-			sbomCount := 0
-			for _, a := range artifacts {
-				basename := filepath.Base(a)
-				switch {
-				case strings.HasSuffix(basename, ".tar.gz"):
-					tarballCount++
-					fmt.Printf("     - %s (tarball)\n", basename)
-				case strings.HasSuffix(basename, ".sha256"):
-					checksumCount++
-				case strings.HasSuffix(basename, ".sbom.json"):
-					sbomCount++
-				case strings.HasSuffix(basename, ".provenance.json"):
-					// Don't log individually
-				default:
-					fmt.Printf("     - %s (other)\n", basename)
-				}
-			}
-			if checksumCount > 0 || sbomCount > 0 {
-				fmt.Printf("     + %d checksums, %d SBOMs, etc.\n", checksumCount, sbomCount)
-			}
-
-			// Early validation: must have at least one tarball
-			if tarballCount == 0 {
-				errMsg := fmt.Sprintf("%s v%s - NO_TARBALLS: Found %d artifacts but no .tar.gz files",
-					pkg.Package, pkg.Version, len(artifacts))
-				fmt.Printf("  ❌ %s\n", errMsg)
-				fmt.Printf("     Possible causes:\n")
-				fmt.Printf("     - Build artifacts exceeded 500MB size limit and were filtered\n")
-				fmt.Printf("     - Build job failed or was skipped\n")
-				fmt.Printf("     - Artifacts were not uploaded correctly\n")
-				fmt.Printf("     Check build job logs for size warnings\n\n")
-				failed = append(failed, fmt.Sprintf("%s v%s", pkg.Package, pkg.Version))
-				failureDetails = append(failureDetails, errMsg)
-				continue
-			} // Validate platforms
-			validation := releaseService.ValidateRelease(recipe, pkg.Package, pkg.Version, artifacts)
-			if !validation.IsReady() {
-				errMsg := fmt.Sprintf("%s v%s - VALIDATION: %s", pkg.Package, pkg.Version, validation.ErrorMessage(pkg.Package, pkg.Version))
-				fmt.Printf("  ❌ %s\n", errMsg)
-				fmt.Printf("     Expected: %d, Available: %d\n", validation.ExpectedCount, validation.AvailableCount)
-				if len(validation.MissingPlatforms) > 0 {
-					fmt.Printf("     Missing: %v\n", validation.MissingPlatforms)
-				}
-				if len(validation.AvailablePlatforms) > 0 {
-					fmt.Printf("     Available: %v\n", validation.AvailablePlatforms)
+		// Each package's log block and outcome are printed and recorded in input order,
+		// as soon as every package before it has finished
+		outputs := make([]batchPackageOutput, len(batch))
+		for i := range outputs {
+			outputs[i].done = make(chan struct{})
+		}
+		printed := make(chan struct{})
+		go func() {
+			defer close(printed)
+			for i := range outputs {
+				<-outputs[i].done
+				if outputs[i].ran {
+					results.record(batch[i], outputs[i].outcome, outputs[i].detail)
+					_, _ = os.Stdout.Write(outputs[i].log.Bytes())
 				}
-				fmt.Println()
-				failed = append(failed, fmt.Sprintf("%s v%s", pkg.Package, pkg.Version))
-				failureDetails = append(failureDetails, errMsg)
-				continue
 			}
+		}()
 
-			// Warn if not all platforms are present
-			if validation.AvailableCount < validation.ExpectedCount {
-				fmt.Printf("  ⚠️  Partial release: %d/%d platforms\n", validation.AvailableCount, validation.ExpectedCount)
-				if len(validation.MissingPlatforms) > 0 {
-					fmt.Printf("     Missing: %v\n", validation.MissingPlatforms)
+		// Process packages in this batch with bounded concurrency
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		launched := 0
+		for i, pkg := range batch {
+			if started > 0 && opts.BatchDelay > 0 {
+				if err := sleepContext(ctx, opts.BatchDelay); err != nil {
+					interrupted = true
+					break
 				}
-			} else {
-				fmt.Printf("  ✅ Validation passed (%d platforms)\n", validation.AvailableCount)
 			}
-
-			// Create release
-			releaseBody := generateReleaseBody(pkg.Package, pkg.Version, artifacts)
-
-			// Add warning if not all platforms are available
-			if validation.AvailableCount < validation.ExpectedCount {
-				warningNote := fmt.Sprintf("\n> ⚠️ **Note**: This release is missing some platforms. Available: %d/%d\n",
-					validation.AvailableCount, validation.ExpectedCount)
-				if len(validation.MissingPlatforms) > 0 {
-					missing := make([]string, len(validation.MissingPlatforms))
-					for i, p := range validation.MissingPlatforms {
-						missing[i] = string(p)
+			started++
+			launched++
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(i int, pkg PackageRelease) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				out := &outputs[i]
+				defer close(out.done)
+
+				fmt.Fprintf(&out.log, "[%d/%d] Processing %s v%s\n", i+1, len(batch), pkg.Package, pkg.Version)
+				report := &PackageReleaseReport{Package: pkg.Package, Version: pkg.Version, Assets: []string{}}
+				out.outcome, out.detail = releaseBatchPackage(ctx, &out.log, githubGW, recipeRepo, releaseService, existingReleases, pkg, opts, report)
+				out.ran = true
+
+				if opts.ReportDir != "" {
+					report.Status = out.outcome.String()
+					report.Error = out.detail
+					if err := writePackageReleaseReport(opts.ReportDir, report); err != nil {
+						fmt.Fprintf(&out.log, "  ⚠️  Failed to write package report: %v\n\n", err)
 					}
-					warningNote += fmt.Sprintf("> Missing: %s\n", strings.Join(missing, ", "))
 				}
-				releaseBody = warningNote + "\n" + releaseBody
-			}
-
-			release := &domainGateways.GitHubRelease{
-				TagName:    releaseTag,
-				Name:       fmt.Sprintf("%s %s", pkg.Package, pkg.Version),
-				Body:       releaseBody,
-				Draft:      false,
-				Prerelease: false,
-			}
-
-			fmt.Printf("  🚀 Creating release...\n")
-			createdRelease, err := githubGW.CreateRelease(ctx, owner, repo, release)
-			if err != nil {
-				errMsg := fmt.Sprintf("%s v%s - CREATE_FAILED: %v", pkg.Package, pkg.Version, err)
-				fmt.Printf("  ❌ %s\n\n", errMsg)
-				failed = append(failed, fmt.Sprintf("%s v%s", pkg.Package, pkg.Version))
-				failureDetails = append(failureDetails, errMsg)
-				continue
-			}
-
-			// Upload artifacts
-			fmt.Printf("  📤 Uploading %d artifact(s)...\n", len(artifacts))
-			if err := uploadArtifacts(ctx, githubGW, createdRelease.UploadURL, artifacts); err != nil {
-				errMsg := fmt.Sprintf("%s v%s - UPLOAD_FAILED: %v", pkg.Package, pkg.Version, err)
-				fmt.Printf("  ⚠️  %s\n", errMsg)
-				// Don't mark as completely failed if release was created
-				fmt.Printf("  ⚠️  Release created but uploads incomplete: %s\n", createdRelease.HTMLURL)
-			} else {
-				fmt.Printf("  ✅ Release created successfully\n")
-				fmt.Printf("     %s\n", createdRelease.HTMLURL)
-			}
-
-			created = append(created, fmt.Sprintf("%s v%s", pkg.Package, pkg.Version))
-			fmt.Println()
+			}(i, pkg)
 		}
+		for i := launched; i < len(outputs); i++ {
+			close(outputs[i].done)
+		}
+		wg.Wait()
+		<-printed
+	}
+	if err := appendHistory(opts.HistoryFile, results.history); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write history: %v\n", err)
 	}
+	created, skipped, failed, failureDetails := results.created, results.skipped, results.failed, results.failureDetails
 
 	// Print summary
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -524,14 +1173,18 @@ func releaseFromPackageList(ctx context.Context, packagesJSON, artifactsDir, rec
 	if total < len(packages) {
 		unprocessed := len(packages) - total
 		fmt.Printf("\n⏳ Unprocessed packages: %d (will be processed in next workflow run)\n", unprocessed)
-		fmt.Printf("   Reason: Rate limit safety threshold reached\n")
+		if interrupted {
+			fmt.Printf("   Reason: Release interrupted\n")
+		} else {
+			fmt.Printf("   Reason: Rate limit safety threshold reached\n")
+		}
 	}
 
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	// Write failures file
-	if len(failureDetails) > 0 && failuresFile != "" {
-		if err := os.WriteFile(failuresFile, []byte(strings.Join(failureDetails, "\n")+"\n"), 0600); err != nil {
+	if len(failureDetails) > 0 && opts.FailuresFile != "" {
+		if err := os.WriteFile(opts.FailuresFile, []byte(strings.Join(failureDetails, "\n")+"\n"), 0600); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to write failures file: %v\n", err)
 		}
 		if len(failureDetails) > 0 {
@@ -543,14 +1196,14 @@ func releaseFromPackageList(ctx context.Context, packagesJSON, artifactsDir, rec
 	}
 
 	// Write successes file
-	if len(created) > 0 && successesFile != "" {
-		if err := os.WriteFile(successesFile, []byte(strings.Join(created, "\n")+"\n"), 0600); err != nil {
+	if len(created) > 0 && opts.SuccessesFile != "" {
+		if err := os.WriteFile(opts.SuccessesFile, []byte(strings.Join(created, "\n")+"\n"), 0600); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to write successes file: %v\n", err)
 		}
 	}
 
 	// Write JSON report
-	if reportFile != "" {
+	if opts.ReportFile != "" {
 		report := ReleaseReport{
 			Created: created,
 			Skipped: skipped,
@@ -566,11 +1219,16 @@ func releaseFromPackageList(ctx context.Context, packagesJSON, artifactsDir, rec
 			return fmt.Errorf("failed to marshal report: %w", err)
 		}
 
-		if err := os.WriteFile(reportFile, data, 0600); err != nil {
+		if err := os.WriteFile(opts.ReportFile, data, 0600); err != nil {
 			return fmt.Errorf("failed to write report file: %w", err)
 		}
 	}
 
+	// Reports above cover the packages finished before the interruption
+	if interrupted {
+		return fmt.Errorf("release interrupted after %d of %d package(s): %w", started, len(packages), ctx.Err())
+	}
+
 	// Exit with error if all releases failed
 	if len(created) == 0 && len(failed) > 0 {
 		fmt.Println("\n⚠️  Warning: No releases were created")
@@ -580,66 +1238,408 @@ func releaseFromPackageList(ctx context.Context, packagesJSON, artifactsDir, rec
 	return nil
 }
 
-func uploadArtifacts(ctx context.Context, githubGW *gateways.HTTPGitHubGateway, uploadURL string, artifacts []string) error {
-	fmt.Printf("\n📤 Uploading %d artifacts...\n", len(artifacts))
+// writePackageReleaseReport writes report to <dir>/<package>-<version>.json
+func writePackageReleaseReport(dir string, report *PackageReleaseReport) error {
+	name := fmt.Sprintf("%s-%s.json", report.Package, report.Version)
+	if name != filepath.Base(name) || strings.Contains(name, "..") {
+		return fmt.Errorf("security: invalid report name %q", name)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal package report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return fmt.Errorf("failed to write package report: %w", err)
+	}
+	return nil
+}
+
+// releaseBatchPackage releases a single package from a batch, writing its log block to w
+// and recording validation, assets and the release URL in report
+//
+//nolint:gocyclo // Sequential validation steps for a single release
+func releaseBatchPackage(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, recipeRepo *yaml.RecipeRepository, releaseService *services.ReleaseService, existingReleases map[string]bool, pkg PackageRelease, opts BatchReleaseOptions, report *PackageReleaseReport) (releaseOutcome, string) {
+	releaseTag := entities.ReleaseTag(pkg.Package, pkg.Version)
+
+	// Check if already exists, under either the current or the legacy unprefixed tag
+	if releaseExists(existingReleases, pkg.Package, pkg.Version) {
+		fmt.Fprintf(w, "  ⏭️  Release already exists, skipping\n\n")
+		return outcomeSkipped, ""
+	}
+
+	// Load recipe
+	recipe, err := recipeRepo.GetRecipe(ctx, pkg.Package)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - NO_RECIPE: %v", pkg.Package, pkg.Version, err)
+		fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+
+	// Initialize artifact finder
+	artifactFinder := gateways.NewArtifactFinder().WithLayout(opts.OutputLayout)
+
+	// Find artifacts
+	artifacts, err := artifactFinder.FindRecursive(opts.ArtifactsDir, pkg.Package, pkg.Version)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - FIND_ERROR: %v", pkg.Package, pkg.Version, err)
+		fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+
+	// Debug: Show found artifacts by type
+	fmt.Fprintf(w, "  📦 Found %d artifacts:\n", len(artifacts))
+	tarballCount := 0
+	checksumCount := 0
+	sbomCount := 0
+	for _, a := range artifacts {
+		basename := filepath.Base(a)
+		switch {
+		case strings.HasSuffix(basename, ".tar.gz"):
+			tarballCount++
+			fmt.Fprintf(w, "     - %s (tarball)\n", basename)
+		case strings.HasSuffix(basename, ".sha256"):
+			checksumCount++
+		case strings.HasSuffix(basename, ".sbom.json"):
+			sbomCount++
+		case strings.HasSuffix(basename, ".provenance.json"):
+			// Don't log individually
+		default:
+			fmt.Fprintf(w, "     - %s (other)\n", basename)
+		}
+	}
+	if checksumCount > 0 || sbomCount > 0 {
+		fmt.Fprintf(w, "     + %d checksums, %d SBOMs, etc.\n", checksumCount, sbomCount)
+	}
+
+	// Early validation: must have at least one tarball
+	if tarballCount == 0 {
+		errMsg := fmt.Sprintf("%s v%s - NO_TARBALLS: Found %d artifacts but no .tar.gz files",
+			pkg.Package, pkg.Version, len(artifacts))
+		fmt.Fprintf(w, "  ❌ %s\n", errMsg)
+		fmt.Fprintf(w, "     Possible causes:\n")
+		fmt.Fprintf(w, "     - Build artifacts exceeded 500MB size limit and were filtered\n")
+		fmt.Fprintf(w, "     - Build job failed or was skipped\n")
+		fmt.Fprintf(w, "     - Artifacts were not uploaded correctly\n")
+		fmt.Fprintf(w, "     Check build job logs for size warnings\n\n")
+		return outcomeFailed, errMsg
+	}
+
+	// Validate platforms
+	validation := releaseService.ValidateRelease(recipe, pkg.Package, pkg.Version, artifacts)
+	report.Validation = newPackageValidationReport(validation)
+	if !validation.IsReady() {
+		errMsg := fmt.Sprintf("%s v%s - VALIDATION: %s", pkg.Package, pkg.Version, validation.ErrorMessage(pkg.Package, pkg.Version))
+		fmt.Fprintf(w, "  ❌ %s\n", errMsg)
+		fmt.Fprintf(w, "     Expected: %d, Available: %d\n", validation.ExpectedCount, validation.AvailableCount)
+		if len(validation.MissingPlatforms) > 0 {
+			fmt.Fprintf(w, "     Missing: %v\n", validation.MissingPlatforms)
+		}
+		if len(validation.AvailablePlatforms) > 0 {
+			fmt.Fprintf(w, "     Available: %v\n", validation.AvailablePlatforms)
+		}
+		fmt.Fprintln(w)
+		return outcomeFailed, errMsg
+	}
+
+	// Warn if not all platforms are present
+	if validation.AvailableCount < validation.ExpectedCount {
+		fmt.Fprintf(w, "  ⚠️  Partial release: %d/%d platforms\n", validation.AvailableCount, validation.ExpectedCount)
+		if len(validation.MissingPlatforms) > 0 {
+			fmt.Fprintf(w, "     Missing: %v\n", validation.MissingPlatforms)
+		}
+		if len(validation.OptionalMissingPlatforms) > 0 {
+			fmt.Fprintf(w, "     Missing (optional): %v\n", validation.OptionalMissingPlatforms)
+		}
+	} else {
+		fmt.Fprintf(w, "  ✅ Validation passed (%d platforms)\n", validation.AvailableCount)
+	}
+
+	// Create release
+	releaseBody, err := renderReleaseBody(opts.NotesTemplate, recipe, pkg.Package, pkg.Version, artifacts)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - NOTES_TEMPLATE: %v", pkg.Package, pkg.Version, err)
+		fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+
+	// Add warning if not all platforms are available
+	if validation.AvailableCount < validation.ExpectedCount {
+		warningNote := fmt.Sprintf("\n> ⚠️ **Note**: This release is missing some platforms. Available: %d/%d\n",
+			validation.AvailableCount, validation.ExpectedCount)
+		if len(validation.MissingPlatforms) > 0 {
+			missing := make([]string, len(validation.MissingPlatforms))
+			for i, p := range validation.MissingPlatforms {
+				missing[i] = string(p)
+			}
+			warningNote += fmt.Sprintf("> Missing: %s\n", strings.Join(missing, ", "))
+		}
+		if len(validation.OptionalMissingPlatforms) > 0 {
+			optional := make([]string, len(validation.OptionalMissingPlatforms))
+			for i, p := range validation.OptionalMissingPlatforms {
+				optional[i] = string(p)
+			}
+			warningNote += fmt.Sprintf("> Missing (optional): %s\n", strings.Join(optional, ", "))
+		}
+		releaseBody = warningNote + "\n" + releaseBody
+	}
+
+	mergedDir, err := os.MkdirTemp("", "potions-sbom-*")
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - SBOM_FAILED: %v", pkg.Package, pkg.Version, err)
+		fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+	//nolint:errcheck // Best effort cleanup of temp directory
+	defer os.RemoveAll(mergedDir)
+
+	if mergedSBOM, err := mergedSBOMAsset(mergedDir, pkg.Package, pkg.Version, artifacts); err != nil {
+		fmt.Fprintf(w, "  ⚠️  Could not merge SBOMs: %v\n", err)
+	} else if mergedSBOM != "" {
+		fmt.Fprintf(w, "  📋 Merged SBOMs into %s\n", filepath.Base(mergedSBOM))
+		artifacts = append(artifacts, mergedSBOM)
+	}
+
+	if opts.Provenance {
+		releaseProvenance, err := releaseProvenanceAsset(mergedDir, pkg.Package, pkg.Version, artifacts)
+		if err != nil {
+			errMsg := fmt.Sprintf("%s v%s - PROVENANCE_FAILED: %v", pkg.Package, pkg.Version, err)
+			fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+			return outcomeFailed, errMsg
+		}
+		fmt.Fprintf(w, "  📜 Attested %d assets in %s\n", len(artifacts), filepath.Base(releaseProvenance))
+		artifacts = append(artifacts, releaseProvenance)
+	}
+
+	if opts.Signer != nil {
+		manifestDir, err := os.MkdirTemp("", "potions-manifest-*")
+		if err != nil {
+			errMsg := fmt.Sprintf("%s v%s - MANIFEST_FAILED: %v", pkg.Package, pkg.Version, err)
+			fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+			return outcomeFailed, errMsg
+		}
+		//nolint:errcheck // Best effort cleanup of temp directory
+		defer os.RemoveAll(manifestDir)
+
+		manifestAssets, err := signedManifestAssets(manifestDir, artifacts, opts.Signer)
+		if err != nil {
+			errMsg := fmt.Sprintf("%s v%s - MANIFEST_FAILED: %v", pkg.Package, pkg.Version, err)
+			fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+			return outcomeFailed, errMsg
+		}
+		fmt.Fprintf(w, "  🔏 Signed %s\n", services.ChecksumManifestName)
+		artifacts = append(artifacts, manifestAssets...)
+	}
+
+	release := &domainGateways.GitHubRelease{
+		TagName:    releaseTag,
+		Name:       fmt.Sprintf("%s %s", pkg.Package, entities.DisplayVersion(pkg.Version)),
+		Body:       releaseBody,
+		Draft:      opts.WaitPublish || opts.Atomic,
+		Prerelease: false,
+	}
+
+	fmt.Fprintf(w, "  🚀 Creating release...\n")
+	createdRelease, err := githubGW.CreateRelease(ctx, opts.Owner, opts.Repo, release)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - CREATE_FAILED: %v", pkg.Package, pkg.Version, err)
+		fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+
+	// Upload artifacts
+	fmt.Fprintf(w, "  📤 Uploading %d artifact(s)...\n", len(artifacts))
+	failedUploads, err := uploadArtifacts(ctx, w, githubGW, createdRelease.UploadURL, artifacts)
+	report.ReleaseURL = createdRelease.HTMLURL
+	report.Assets = uploadedAssetNames(artifacts, failedUploads)
+	report.FailedAssets = failedUploads
+
+	if opts.Atomic && len(failedUploads) > 0 {
+		rollbackErr := rollbackRelease(ctx, w, githubGW, opts.Owner, opts.Repo, createdRelease, failedUploads)
+		errMsg := fmt.Sprintf("%s v%s - ATOMIC_FAILED: %v", pkg.Package, pkg.Version, rollbackErr)
+		fmt.Fprintf(w, "  ⚠️  %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+	if opts.WaitPublish || opts.Atomic {
+		outcome, errMsg := publishBatchRelease(ctx, w, githubGW, createdRelease, pkg, artifacts, failedUploads, err, opts)
+		if outcome == outcomeCreated && recipe.LatestAlias {
+			updateLatestAlias(ctx, w, githubGW, opts.Owner, opts.Repo, pkg.Package, pkg.Version, artifacts)
+		}
+		return outcome, errMsg
+	}
+
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - UPLOAD_FAILED: %v", pkg.Package, pkg.Version, err)
+		fmt.Fprintf(w, "  ⚠️  %s\n", errMsg)
+		// Don't mark as completely failed if release was created
+		fmt.Fprintf(w, "  ⚠️  Release created but uploads incomplete: %s\n", createdRelease.HTMLURL)
+	} else {
+		fmt.Fprintf(w, "  ✅ Release created successfully\n")
+		fmt.Fprintf(w, "     %s\n", createdRelease.HTMLURL)
+		if opts.VerifyAfter {
+			if outcome, errMsg := verifyBatchRelease(ctx, w, githubGW, createdRelease, pkg, artifacts, opts); outcome != outcomeCreated {
+				return outcome, errMsg
+			}
+		}
+		if recipe.LatestAlias && len(failedUploads) == 0 {
+			updateLatestAlias(ctx, w, githubGW, opts.Owner, opts.Repo, pkg.Package, pkg.Version, artifacts)
+		}
+	}
+
+	fmt.Fprintln(w)
+	return outcomeCreated, ""
+}
+
+// newPackageValidationReport converts a release validation into its report form
+func newPackageValidationReport(validation *services.ReleaseValidation) *PackageValidationReport {
+	report := &PackageValidationReport{
+		Status:             string(validation.Status),
+		ExpectedCount:      validation.ExpectedCount,
+		AvailableCount:     validation.AvailableCount,
+		AvailablePlatforms: []string{},
+	}
+	for _, p := range validation.AvailablePlatforms {
+		report.AvailablePlatforms = append(report.AvailablePlatforms, string(p))
+	}
+	for _, p := range validation.MissingPlatforms {
+		report.MissingPlatforms = append(report.MissingPlatforms, string(p))
+	}
+	for _, p := range validation.OptionalMissingPlatforms {
+		report.OptionalMissing = append(report.OptionalMissing, string(p))
+	}
+	return report
+}
+
+// uploadedAssetNames returns the basenames of artifacts that uploaded successfully
+func uploadedAssetNames(artifacts, failedUploads []string) []string {
+	names := []string{}
+	failed := make(map[string]bool, len(failedUploads))
+	for _, name := range failedUploads {
+		failed[name] = true
+	}
+	for _, artifact := range artifacts {
+		if name := filepath.Base(artifact); !failed[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// publishBatchRelease publishes a draft created with --wait-publish, leaving it as a
+// draft (and reporting a failure) when critical uploads failed
+func publishBatchRelease(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, draft *domainGateways.GitHubRelease, pkg PackageRelease, artifacts, failedUploads []string, uploadErr error, opts BatchReleaseOptions) (releaseOutcome, string) {
+	if critical := criticalAssets(failedUploads); uploadErr != nil || len(critical) > 0 {
+		reason := fmt.Sprintf("critical assets failed to upload: %s", strings.Join(critical, ", "))
+		if uploadErr != nil {
+			reason = uploadErr.Error()
+		}
+		errMsg := fmt.Sprintf("%s v%s - LEFT_AS_DRAFT: %s (%s)", pkg.Package, pkg.Version, reason, draft.HTMLURL)
+		fmt.Fprintf(w, "  ⚠️  %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+
+	published, err := publishRelease(ctx, githubGW, opts.Owner, opts.Repo, draft)
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - PUBLISH_FAILED: %v (%s)", pkg.Package, pkg.Version, err, draft.HTMLURL)
+		fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+
+	fmt.Fprintf(w, "  ✅ Release published successfully\n")
+	fmt.Fprintf(w, "     %s\n\n", published.HTMLURL)
+	if opts.VerifyAfter {
+		return verifyBatchRelease(ctx, w, githubGW, published, pkg, artifacts, opts)
+	}
+	return outcomeCreated, ""
+}
+
+// verifyBatchRelease checks a batch package's published assets, failing the package on any mismatch
+func verifyBatchRelease(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, release *domainGateways.GitHubRelease, pkg PackageRelease, artifacts []string, opts BatchReleaseOptions) (releaseOutcome, string) {
+	mismatched, err := verifyReleaseAssets(ctx, w, githubGW, gateways.NewDownloader(), opts.Owner, opts.Repo, release.ID, artifacts)
+	if err == nil && len(mismatched) > 0 {
+		err = fmt.Errorf("assets differ from local files: %s", strings.Join(mismatched, ", "))
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("%s v%s - VERIFY_FAILED: %v (%s)", pkg.Package, pkg.Version, err, release.HTMLURL)
+		fmt.Fprintf(w, "  ❌ %s\n\n", errMsg)
+		return outcomeFailed, errMsg
+	}
+	return outcomeCreated, ""
+}
+
+// uploadArtifacts uploads artifacts to a release and returns the filenames that failed
+func uploadArtifacts(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, uploadURL string, artifacts []string) ([]string, error) {
+	fmt.Fprintf(w, "\n📤 Uploading %d artifacts...\n", len(artifacts))
 
 	var uploadErrors []error
+	var failed []string
 	successCount := 0
 
 	for i, artifactPath := range artifacts {
 		filename := filepath.Base(artifactPath)
-		fmt.Printf("  [%d/%d] Uploading %s... ", i+1, len(artifacts), filename)
+		fmt.Fprintf(w, "  [%d/%d] Uploading %s... ", i+1, len(artifacts), filename)
 
 		//nolint:gosec // G304: artifactPath is from glob pattern for release uploads
 		file, err := os.Open(artifactPath)
 		if err != nil {
-			fmt.Printf("❌\n")
+			fmt.Fprintf(w, "❌\n")
 			uploadErrors = append(uploadErrors, fmt.Errorf("failed to open %s: %w", filename, err))
+			failed = append(failed, filename)
 			continue
 		}
 
 		asset, err := githubGW.UploadAsset(ctx, uploadURL, filename, file)
 		if closeErr := file.Close(); closeErr != nil {
-			fmt.Printf("❌\n")
+			fmt.Fprintf(w, "❌\n")
 			uploadErrors = append(uploadErrors, fmt.Errorf("failed to close %s: %w", filename, closeErr))
+			failed = append(failed, filename)
 			continue
 		}
 
 		if err != nil {
-			fmt.Printf("❌\n")
+			fmt.Fprintf(w, "❌\n")
 			uploadErrors = append(uploadErrors, fmt.Errorf("failed to upload %s: %w", filename, err))
+			failed = append(failed, filename)
 			continue
 		}
 
-		fmt.Printf("✅ (%d bytes)\n", asset.Size)
+		fmt.Fprintf(w, "✅ (%d bytes)\n", asset.Size)
 		successCount++
 	}
 
 	if len(uploadErrors) > 0 {
-		fmt.Printf("\n⚠️  Upload summary: %d succeeded, %d failed\n", successCount, len(uploadErrors))
+		fmt.Fprintf(w, "\n⚠️  Upload summary: %d succeeded, %d failed\n", successCount, len(uploadErrors))
 		for _, err := range uploadErrors {
-			fmt.Printf("  ❌ %v\n", err)
+			fmt.Fprintf(w, "  ❌ %v\n", err)
 		}
 
 		// Only return error if ALL uploads failed
 		if successCount == 0 {
-			return fmt.Errorf("all %d artifact uploads failed", len(uploadErrors))
+			return failed, fmt.Errorf("all %d artifact uploads failed", len(uploadErrors))
 		}
 
 		// Partial success - warn but don't fail the release
-		fmt.Printf("⚠️  Warning: Partial upload - continuing with %d successful artifacts\n", successCount)
-		return nil
+		fmt.Fprintf(w, "⚠️  Warning: Partial upload - continuing with %d successful artifacts\n", successCount)
+		return failed, nil
 	}
 
-	fmt.Println("\n🎉 All artifacts uploaded successfully!")
-	return nil
+	fmt.Fprintln(w, "\n🎉 All artifacts uploaded successfully!")
+	return nil, nil
 }
 
-func generateReleaseBody(packageName, version string, artifacts []string) string {
+// generateReleaseBody renders the release notes, including a per-platform checksum table
+// and, when upstreamURL is set, a link to the upstream release
+func generateReleaseBody(packageName, version string, artifacts []string, upstreamURL string) string {
 	var body strings.Builder
 
-	body.WriteString(fmt.Sprintf("# %s %s\n\n", packageName, version))
+	tag := entities.ReleaseTag(packageName, version)
+	version = entities.CanonicalVersion(version)
+
+	body.WriteString(fmt.Sprintf("# %s v%s\n\n", packageName, version))
 	body.WriteString("Prebuilt binaries with security scanning and attestations.\n\n")
+	if upstreamURL != "" {
+		body.WriteString(fmt.Sprintf("Upstream release: %s\n\n", upstreamURL))
+	}
 
 	// Group artifacts by platform
 	platformArtifacts := make(map[string][]string)
@@ -684,17 +1684,19 @@ func generateReleaseBody(packageName, version string, artifacts []string) string
 		}
 	}
 
+	writeChecksumTable(&body, packageName, version, artifacts)
+
 	body.WriteString("## Installation\n\n")
 	body.WriteString("```bash\n")
 	body.WriteString("# Download for your platform\n")
-	body.WriteString(fmt.Sprintf("curl -LO https://github.com/ochairo/potions/releases/download/%s-%s/%s-%s-<platform>.tar.gz\n\n",
-		packageName, version, packageName, strings.TrimPrefix(version, "v")))
+	body.WriteString(fmt.Sprintf("curl -LO https://github.com/ochairo/potions/releases/download/%s/%s-%s-<platform>.tar.gz\n\n",
+		tag, packageName, version))
 	body.WriteString("# Verify checksum\n")
-	body.WriteString(fmt.Sprintf("curl -LO https://github.com/ochairo/potions/releases/download/%s-%s/%s-%s-<platform>.tar.gz.sha256\n",
-		packageName, version, packageName, strings.TrimPrefix(version, "v")))
-	body.WriteString(fmt.Sprintf("shasum -a 256 -c %s-%s-<platform>.tar.gz.sha256\n\n", packageName, strings.TrimPrefix(version, "v")))
+	body.WriteString(fmt.Sprintf("curl -LO https://github.com/ochairo/potions/releases/download/%s/%s-%s-<platform>.tar.gz.sha256\n",
+		tag, packageName, version))
+	body.WriteString(fmt.Sprintf("shasum -a 256 -c %s-%s-<platform>.tar.gz.sha256\n\n", packageName, version))
 	body.WriteString("# Extract and install\n")
-	body.WriteString(fmt.Sprintf("tar xzf %s-%s-<platform>.tar.gz\n", packageName, strings.TrimPrefix(version, "v")))
+	body.WriteString(fmt.Sprintf("tar xzf %s-%s-<platform>.tar.gz\n", packageName, version))
 	body.WriteString("```\n\n")
 
 	body.WriteString("## Security\n\n")
@@ -707,8 +1709,193 @@ func generateReleaseBody(packageName, version string, artifacts []string) string
 	return body.String()
 }
 
+// releaseNotesData is what --notes-template is rendered with
+type releaseNotesData struct {
+	Package     string
+	Version     string // Canonical version, without a leading 'v'
+	Tag         string
+	UpstreamURL string // Upstream GitHub release, empty when the recipe's version is not from GitHub
+	Platforms   []string
+	Artifacts   []releaseNotesArtifact
+	Security    releaseNotesSecurity
+}
+
+// releaseNotesArtifact describes one release asset
+type releaseNotesArtifact struct {
+	Name     string
+	Platform string // Empty for assets not tied to a platform (e.g., SHA256SUMS)
+	Size     int64
+	SHA256   string // From the sibling .sha256 file, empty when there is none
+}
+
+// releaseNotesSecurity reports the recipe's security checks and the security assets released
+type releaseNotesSecurity struct {
+	VerifySignature     bool
+	ScanVulnerabilities bool
+	Checksums           bool
+	SBOM                bool
+	Provenance          bool
+	SignedManifest      bool
+}
+
+// loadNotesTemplate parses a release notes template so syntax errors stop the release before it starts;
+// templates can use join (strings.Join) besides the text/template builtins
+func loadNotesTemplate(path string) (*template.Template, error) {
+	//nolint:gosec // G304: path is the user's notes template
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(template.FuncMap{"join": strings.Join}).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid notes template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderReleaseBody renders notes with the release's data, or the built-in notes when notes is nil
+func renderReleaseBody(notes *template.Template, recipe *entities.Recipe, packageName, version string, artifacts []string) (string, error) {
+	if notes == nil {
+		return generateReleaseBody(packageName, version, artifacts, upstreamReleaseURL(recipe, version)), nil
+	}
+	var body strings.Builder
+	if err := notes.Execute(&body, newReleaseNotesData(recipe, packageName, version, artifacts)); err != nil {
+		return "", fmt.Errorf("failed to render notes template: %w", err)
+	}
+	return body.String(), nil
+}
+
+// newReleaseNotesData collects the template data for a release's artifacts; recipe may be nil
+func newReleaseNotesData(recipe *entities.Recipe, packageName, version string, artifacts []string) releaseNotesData {
+	data := releaseNotesData{
+		Package:     packageName,
+		Version:     entities.CanonicalVersion(version),
+		Tag:         entities.ReleaseTag(packageName, version),
+		UpstreamURL: upstreamReleaseURL(recipe, version),
+		Platforms:   []string{},
+		Artifacts:   []releaseNotesArtifact{},
+	}
+	if recipe != nil {
+		data.Security.VerifySignature = recipe.Security.VerifySignature
+		data.Security.ScanVulnerabilities = recipe.Security.ScanVulnerabilities
+	}
+
+	prefix := fmt.Sprintf("%s-%s-", packageName, data.Version)
+	for _, artifact := range artifacts {
+		name := filepath.Base(artifact)
+		entry := releaseNotesArtifact{Name: name}
+		if info, err := os.Stat(artifact); err == nil {
+			entry.Size = info.Size()
+		}
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			entry.Platform, _, _ = strings.Cut(rest, ".")
+			if !slices.Contains(data.Platforms, entry.Platform) {
+				data.Platforms = append(data.Platforms, entry.Platform)
+			}
+		}
+		//nolint:gosec // G304: Checksum file sits next to a release artifact
+		if checksum, err := os.ReadFile(artifact + ".sha256"); err == nil {
+			if fields := strings.Fields(string(checksum)); len(fields) > 0 {
+				entry.SHA256 = fields[0]
+			}
+		}
+		data.Artifacts = append(data.Artifacts, entry)
+
+		switch {
+		case strings.HasSuffix(name, ".sha256") || strings.HasSuffix(name, ".sha512"):
+			data.Security.Checksums = true
+		case strings.HasSuffix(name, ".sbom.json"):
+			data.Security.SBOM = true
+		case strings.HasSuffix(name, ".provenance.json"):
+			data.Security.Provenance = true
+		case name == services.ChecksumManifestName+".asc":
+			data.Security.SignedManifest = true
+		}
+	}
+	sort.Strings(data.Platforms)
+	sort.Slice(data.Artifacts, func(i, j int) bool { return data.Artifacts[i].Name < data.Artifacts[j].Name })
+	return data
+}
+
+// writeChecksumTable writes a platform/file/SHA256 table for tarballs with readable sibling .sha256 files
+func writeChecksumTable(body *strings.Builder, packageName, version string, artifacts []string) {
+	prefix := fmt.Sprintf("%s-%s-", packageName, entities.CanonicalVersion(version))
+
+	var rows []string
+	for _, artifact := range artifacts {
+		basename := filepath.Base(artifact)
+		if !strings.HasSuffix(basename, ".tar.gz") {
+			continue
+		}
+
+		//nolint:gosec // G304: Checksum file sits next to a release artifact
+		data, err := os.ReadFile(artifact + ".sha256")
+		if err != nil {
+			continue
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) == 0 {
+			continue
+		}
+
+		platform := strings.TrimSuffix(strings.TrimPrefix(basename, prefix), ".tar.gz")
+		rows = append(rows, fmt.Sprintf("| %s | `%s` | `%s` |\n", platform, basename, fields[0]))
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	sort.Strings(rows)
+	body.WriteString("## Checksums\n\n")
+	body.WriteString("| Platform | File | SHA256 |\n")
+	body.WriteString("|----------|------|--------|\n")
+	for _, row := range rows {
+		body.WriteString(row)
+	}
+	body.WriteString("\n")
+}
+
+// upstreamReleaseURL links to the upstream GitHub release for recipes whose version comes from GitHub
+// The tag is best-effort: git_tag_prefix is honored, and a "v" is restored when cleanup strips it
+func upstreamReleaseURL(recipe *entities.Recipe, version string) string {
+	if recipe == nil {
+		return ""
+	}
+
+	var repo string
+	for _, prefix := range []string{"github-release:", "github-tag:"} {
+		if strings.HasPrefix(recipe.Version.Source, prefix) {
+			repo = strings.TrimPrefix(recipe.Version.Source, prefix)
+		}
+	}
+	if repo == "" {
+		return ""
+	}
+
+	tag := entities.CanonicalVersion(version)
+	switch {
+	case recipe.Download.GitTagPrefix != "":
+		tag = recipe.Download.GitTagPrefix + tag
+	case recipe.Version.Cleanup == "v:" || recipe.Version.Cleanup == "s/^v//":
+		tag = "v" + tag
+	}
+
+	return fmt.Sprintf("https://github.com/%s/releases/tag/%s", repo, tag)
+}
+
+// releaseExists reports whether any tag the package version may carry is in existing
+func releaseExists(existing map[string]bool, packageName, version string) bool {
+	for _, tag := range entities.ReleaseTagCandidates(packageName, version) {
+		if existing[tag] {
+			return true
+		}
+	}
+	return false
+}
+
 // fetchExistingReleases gets a map of existing release tags
-func fetchExistingReleases(ctx context.Context, githubGW *gateways.HTTPGitHubGateway, owner, repo string) (map[string]bool, error) {
+func fetchExistingReleases(ctx context.Context, githubGW domainGateways.GitHubGateway, owner, repo string) (map[string]bool, error) {
 	releases, err := githubGW.ListReleases(ctx, owner, repo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list releases: %w", err)
@@ -744,7 +1931,7 @@ func calculateMaxSafeReleases(remaining, maxConfigured int) int {
 }
 
 // splitPackagesIntoBatches splits the packages into batches based on rate limit
-func splitPackagesIntoBatches(_ context.Context, packages []PackageRelease, _ *gateways.HTTPGitHubGateway, maxReleases int) [][]PackageRelease {
+func splitPackagesIntoBatches(_ context.Context, packages []PackageRelease, _ domainGateways.GitHubGateway, maxReleases int) [][]PackageRelease {
 	if len(packages) == 0 {
 		return nil
 	}
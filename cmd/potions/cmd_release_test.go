@@ -0,0 +1,1042 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+	domainGateways "github.com/ochairo/potions/internal/domain/interfaces/gateways"
+	"github.com/ochairo/potions/internal/testsupport"
+)
+
+// writeTestRecipe writes a minimal recipe supporting linux-amd64
+func writeTestRecipe(t *testing.T, recipesDir, name string) {
+	t.Helper()
+	content := fmt.Sprintf(`name: %s
+version:
+  source: "static:1.0.0"
+build_type: custom
+download:
+  download_url: "https://example.com/%s-{version}.tar.gz"
+  platforms:
+    linux-amd64:
+      os: linux
+      arch: amd64
+`, name, name)
+	if err := os.WriteFile(filepath.Join(recipesDir, name+".yml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeTestArtifact writes a tarball and checksum for a package
+func writeTestArtifact(t *testing.T, artifactsDir, name, version string) {
+	t.Helper()
+	base := filepath.Join(artifactsDir, fmt.Sprintf("%s-%s-linux-amd64.tar.gz", name, version))
+	if err := os.WriteFile(base, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(base+".sha256", []byte("checksum"), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test concurrent batch release processing keeps accounting accurate
+func TestReleaseBatches_Concurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	recipesDir := filepath.Join(tmpDir, "recipes")
+	artifactsDir := filepath.Join(tmpDir, "artifacts")
+	for _, dir := range []string{recipesDir, artifactsDir} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var packages []PackageRelease
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("pkg%d", i)
+		writeTestRecipe(t, recipesDir, name)
+		writeTestArtifact(t, artifactsDir, name, "1.0.0")
+		packages = append(packages, PackageRelease{Package: name, Version: "1.0.0"})
+	}
+	// One package already released, one without a recipe
+	packages = append(packages,
+		PackageRelease{Package: "existing", Version: "1.0.0"},
+		PackageRelease{Package: "norecipe", Version: "1.0.0"},
+	)
+
+	gw := testsupport.NewFakeGitHubGateway("existing-1.0.0")
+	gw.DelayCreate(20 * time.Millisecond)
+
+	reportFile := filepath.Join(tmpDir, "report.json")
+	opts := BatchReleaseOptions{
+		ArtifactsDir: artifactsDir,
+		RecipesDir:   recipesDir,
+		Owner:        "owner",
+		Repo:         "repo",
+		ReportFile:   reportFile,
+		FailuresFile: filepath.Join(tmpDir, "failures.txt"),
+		MaxReleases:  5,
+		Concurrency:  4,
+	}
+
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report ReleaseReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Created) != 8 {
+		t.Errorf("created = %d, want 8", len(report.Created))
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0] != "existing v1.0.0" {
+		t.Errorf("skipped = %v, want [existing v1.0.0]", report.Skipped)
+	}
+	if len(report.Failed) != 1 || report.Failed[0] != "norecipe v1.0.0" {
+		t.Errorf("failed = %v, want [norecipe v1.0.0]", report.Failed)
+	}
+	if report.Total != len(packages) {
+		t.Errorf("total = %d, want %d", report.Total, len(packages))
+	}
+
+	// Every created release should have received both of its assets
+	for i := 0; i < 8; i++ {
+		tag := fmt.Sprintf("pkg%d-v1.0.0", i)
+		if got := len(gw.UploadsFor(tag)); got != 2 {
+			t.Errorf("uploads for %s = %d, want 2", tag, got)
+		}
+	}
+
+	if maxSeen := gw.MaxConcurrentCreates(); maxSeen < 2 {
+		t.Errorf("max concurrent releases = %d, want > 1", maxSeen)
+	} else if maxSeen > opts.Concurrency {
+		t.Errorf("max concurrent releases = %d, exceeds concurrency %d", maxSeen, opts.Concurrency)
+	}
+}
+
+// Test sequential processing remains the default
+func TestReleaseBatches_DefaultSequential(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "alpha")
+	writeTestRecipe(t, tmpDir, "beta")
+	writeTestArtifact(t, tmpDir, "alpha", "2.0.0")
+	writeTestArtifact(t, tmpDir, "beta", "2.0.0")
+
+	gw := testsupport.NewFakeGitHubGateway()
+	gw.DelayCreate(5 * time.Millisecond)
+
+	packages := []PackageRelease{
+		{Package: "alpha", Version: "2.0.0"},
+		{Package: "beta", Version: "2.0.0"},
+	}
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r"}
+
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	if maxSeen := gw.MaxConcurrentCreates(); maxSeen != 1 {
+		t.Errorf("max concurrent releases = %d, want 1", maxSeen)
+	}
+	if created := gw.CreatedReleases(); len(created) != 2 {
+		t.Errorf("releases created = %d, want 2", len(created))
+	}
+}
+
+// slowReleaseGateway delays creating the release tagged slowTag so it finishes after later packages
+type slowReleaseGateway struct {
+	*testsupport.FakeGitHubGateway
+	slowTag string
+}
+
+func (g *slowReleaseGateway) CreateRelease(ctx context.Context, owner, repo string, release *domainGateways.GitHubRelease) (*domainGateways.GitHubRelease, error) {
+	if release.TagName == g.slowTag {
+		time.Sleep(50 * time.Millisecond)
+	}
+	return g.FakeGitHubGateway.CreateRelease(ctx, owner, repo, release)
+}
+
+// Test concurrent releases are printed and reported in input order, not completion order
+func TestReleaseBatches_OrderedOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	names := []string{"first", "second", "third"}
+	var packages []PackageRelease
+	for _, name := range names {
+		writeTestRecipe(t, tmpDir, name)
+		writeTestArtifact(t, tmpDir, name, "1.0.0")
+		packages = append(packages, PackageRelease{Package: name, Version: "1.0.0"})
+	}
+
+	gw := &slowReleaseGateway{FakeGitHubGateway: testsupport.NewFakeGitHubGateway(), slowTag: "first-v1.0.0"}
+	reportFile := filepath.Join(tmpDir, "report.json")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", ReportFile: reportFile, Concurrency: 3}
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- data
+	}()
+	os.Stdout = w
+	err = releaseBatches(context.Background(), gw, packages, opts)
+	_ = w.Close()
+	os.Stdout = stdout
+	out := string(<-output)
+	if err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	if created := gw.CreatedReleases(); len(created) != 3 || created[0].TagName == "first-v1.0.0" {
+		t.Fatalf("created = %+v, want the first package to finish last", created)
+	}
+	last := -1
+	for i, name := range names {
+		pos := strings.Index(out, fmt.Sprintf("[%d/3] Processing %s", i+1, name))
+		if pos < 0 || pos < last {
+			t.Errorf("log block for %s printed out of order:\n%s", name, out)
+		}
+		last = pos
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report ReleaseReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(report.Created, ","); got != "first v1.0.0,second v1.0.0,third v1.0.0" {
+		t.Errorf("created = %s, want input order", got)
+	}
+}
+
+// Test an interrupted batch still writes the report and failures files for finished packages
+func TestReleaseBatches_InterruptedWritesReports(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "first")
+	writeTestRecipe(t, tmpDir, "second")
+	writeTestArtifact(t, tmpDir, "first", "1.0.0")
+	writeTestArtifact(t, tmpDir, "second", "1.0.0")
+
+	packages := []PackageRelease{
+		{Package: "first", Version: "1.0.0"},
+		{Package: "missing", Version: "1.0.0"},
+		{Package: "second", Version: "1.0.0"},
+	}
+	reportFile := filepath.Join(tmpDir, "report.json")
+	failuresFile := filepath.Join(tmpDir, "failures.txt")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r",
+		ReportFile: reportFile, FailuresFile: failuresFile, BatchDelay: 200 * time.Millisecond}
+
+	// The delay before the third package outlasts the context
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err := releaseBatches(ctx, testsupport.NewFakeGitHubGateway(), packages, opts)
+	if err == nil || !strings.Contains(err.Error(), "release interrupted after 2 of 3 package(s)") {
+		t.Fatalf("releaseBatches() error = %v, want interruption", err)
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("report should be written on interruption: %v", err)
+	}
+	var report ReleaseReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Created) != 1 || report.Created[0] != "first v1.0.0" || len(report.Failed) != 1 || report.Total != 2 {
+		t.Errorf("report = %+v, want first created and missing failed", report)
+	}
+	if _, err := os.Stat(failuresFile); err != nil {
+		t.Errorf("failures file should be written on interruption: %v", err)
+	}
+}
+
+// Test --wait-publish creates a draft, uploads, then publishes
+func TestReleaseBatches_WaitPublish(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "good")
+	writeTestRecipe(t, tmpDir, "broken")
+	writeTestArtifact(t, tmpDir, "good", "1.0.0")
+	writeTestArtifact(t, tmpDir, "broken", "1.0.0")
+
+	gw := testsupport.NewFakeGitHubGateway()
+	gw.FailUpload("broken-1.0.0-linux-amd64.tar.gz", errors.New("upload failed"))
+
+	packages := []PackageRelease{
+		{Package: "good", Version: "1.0.0"},
+		{Package: "broken", Version: "1.0.0"},
+	}
+	reportFile := filepath.Join(tmpDir, "report.json")
+	opts := BatchReleaseOptions{
+		ArtifactsDir: tmpDir,
+		RecipesDir:   tmpDir,
+		Owner:        "o",
+		Repo:         "r",
+		ReportFile:   reportFile,
+		WaitPublish:  true,
+	}
+
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	var goodCalls, brokenCalls []string
+	for _, call := range gw.Calls() {
+		switch {
+		case strings.Contains(call, "good-"):
+			goodCalls = append(goodCalls, call)
+		case strings.Contains(call, "broken-"):
+			brokenCalls = append(brokenCalls, call)
+		}
+	}
+
+	wantGood := []string{
+		"create good-v1.0.0 draft=true",
+		"upload good-1.0.0-linux-amd64.tar.gz",
+		"upload good-1.0.0-linux-amd64.tar.gz.sha256",
+		"update good-v1.0.0 draft=false",
+	}
+	if strings.Join(goodCalls, "\n") != strings.Join(wantGood, "\n") {
+		t.Errorf("good calls =\n%s\nwant\n%s", strings.Join(goodCalls, "\n"), strings.Join(wantGood, "\n"))
+	}
+
+	for _, call := range brokenCalls {
+		if strings.HasPrefix(call, "update ") {
+			t.Errorf("broken release should not be published, got %q", call)
+		}
+	}
+	broken, err := gw.GetRelease(context.Background(), "o", "r", "broken-v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !broken.Draft {
+		t.Error("release with failed critical upload should remain a draft")
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report ReleaseReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Created) != 1 || report.Created[0] != "good v1.0.0" {
+		t.Errorf("created = %v, want [good v1.0.0]", report.Created)
+	}
+	if len(report.Failed) != 1 || report.Failed[0] != "broken v1.0.0" {
+		t.Errorf("failed = %v, want [broken v1.0.0]", report.Failed)
+	}
+}
+
+// Test --atomic deletes a release with any failed upload instead of publishing it
+func TestReleaseBatches_Atomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "good")
+	writeTestRecipe(t, tmpDir, "broken")
+	writeTestArtifact(t, tmpDir, "good", "1.0.0")
+	writeTestArtifact(t, tmpDir, "broken", "1.0.0")
+
+	gw := testsupport.NewFakeGitHubGateway()
+	// A checksum is not critical for --wait-publish, but --atomic requires every asset
+	gw.FailUpload("broken-1.0.0-linux-amd64.tar.gz.sha256", errors.New("upload failed"))
+
+	packages := []PackageRelease{
+		{Package: "good", Version: "1.0.0"},
+		{Package: "broken", Version: "1.0.0"},
+	}
+	reportFile := filepath.Join(tmpDir, "report.json")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", ReportFile: reportFile, Atomic: true}
+
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	var brokenCalls []string
+	for _, call := range gw.Calls() {
+		if strings.Contains(call, "broken-") {
+			brokenCalls = append(brokenCalls, call)
+		}
+	}
+	wantBroken := []string{
+		"create broken-v1.0.0 draft=true",
+		"upload broken-1.0.0-linux-amd64.tar.gz",
+		"upload broken-1.0.0-linux-amd64.tar.gz.sha256 failed",
+		"delete broken-v1.0.0",
+	}
+	if strings.Join(brokenCalls, "\n") != strings.Join(wantBroken, "\n") {
+		t.Errorf("broken calls =\n%s\nwant\n%s", strings.Join(brokenCalls, "\n"), strings.Join(wantBroken, "\n"))
+	}
+	if _, err := gw.GetRelease(context.Background(), "o", "r", "broken-v1.0.0"); err == nil {
+		t.Error("incomplete release should be rolled back")
+	}
+	good, err := gw.GetRelease(context.Background(), "o", "r", "good-v1.0.0")
+	if err != nil || good.Draft {
+		t.Errorf("complete release should be published, got %+v, %v", good, err)
+	}
+
+	report := readReleaseReport(t, reportFile)
+	if len(report.Created) != 1 || report.Created[0] != "good v1.0.0" {
+		t.Errorf("created = %v, want [good v1.0.0]", report.Created)
+	}
+	if len(report.Failed) != 1 || report.Failed[0] != "broken v1.0.0" {
+		t.Errorf("failed = %v, want [broken v1.0.0]", report.Failed)
+	}
+}
+
+// Test only tarballs and their checksums are critical for publishing
+func TestCriticalAssets(t *testing.T) {
+	got := criticalAssets([]string{
+		"tool-1.0.0-linux-amd64.tar.gz",
+		"tool-1.0.0-linux-amd64.tar.gz.sha256",
+		"tool-1.0.0-linux-amd64.tar.gz.sbom.json",
+		"tool-1.0.0-linux-amd64.tar.gz.sha512",
+	})
+	want := []string{"tool-1.0.0-linux-amd64.tar.gz", "tool-1.0.0-linux-amd64.tar.gz.sha256"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("criticalAssets() = %v, want %v", got, want)
+	}
+}
+
+// readReleaseReport reads the JSON report written by releaseBatches
+func readReleaseReport(t *testing.T, path string) ReleaseReport {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report ReleaseReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// Test the batch flow skips existing releases and creates and uploads new ones
+func TestReleaseBatches_FakeGateway(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"fresh", "existing"} {
+		writeTestRecipe(t, tmpDir, name)
+		writeTestArtifact(t, tmpDir, name, "1.0.0")
+	}
+
+	gw := testsupport.NewFakeGitHubGateway("existing-1.0.0")
+	reportFile := filepath.Join(tmpDir, "report.json")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", ReportFile: reportFile}
+	packages := []PackageRelease{
+		{Package: "fresh", Version: "1.0.0"},
+		{Package: "existing", Version: "1.0.0"},
+	}
+
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	created := gw.CreatedReleases()
+	if len(created) != 1 || created[0].TagName != "fresh-v1.0.0" {
+		t.Fatalf("created releases = %+v, want only fresh-v1.0.0", created)
+	}
+
+	wantSizes := map[string]int64{
+		"fresh-1.0.0-linux-amd64.tar.gz":        int64(len("tarball")),
+		"fresh-1.0.0-linux-amd64.tar.gz.sha256": int64(len("checksum")),
+	}
+	uploads := gw.UploadsFor("fresh-v1.0.0")
+	if len(uploads) != len(wantSizes) {
+		t.Fatalf("uploads = %+v, want %d assets", uploads, len(wantSizes))
+	}
+	for _, upload := range uploads {
+		if want, ok := wantSizes[upload.Name]; !ok || upload.Size != want {
+			t.Errorf("upload %s size = %d, want %d", upload.Name, upload.Size, want)
+		}
+	}
+	if got := gw.UploadsFor("existing-1.0.0"); len(got) != 0 {
+		t.Errorf("existing release should not receive uploads, got %+v", got)
+	}
+
+	report := readReleaseReport(t, reportFile)
+	if len(report.Created) != 1 || report.Created[0] != "fresh v1.0.0" {
+		t.Errorf("created = %v, want [fresh v1.0.0]", report.Created)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0] != "existing v1.0.0" {
+		t.Errorf("skipped = %v, want [existing v1.0.0]", report.Skipped)
+	}
+}
+
+// Test latest_alias uploads version-less copies to a replaced <package>-latest release
+func TestReleaseBatches_LatestAlias(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "tool")
+	recipe, err := os.OpenFile(filepath.Join(tmpDir, "tool.yml"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recipe.WriteString("latest_alias: true\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := recipe.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeTestArtifact(t, tmpDir, "tool", "1.0.0")
+	checksum := filepath.Join(tmpDir, "tool-1.0.0-linux-amd64.tar.gz.sha256")
+	if err := os.WriteFile(checksum, []byte("abc123  tool-1.0.0-linux-amd64.tar.gz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	gw := testsupport.NewFakeGitHubGateway("tool-latest")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r"}
+	if err := releaseBatches(context.Background(), gw, []PackageRelease{{Package: "tool", Version: "1.0.0"}}, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	assetNames := func(tag string) string {
+		var names []string
+		for _, upload := range gw.UploadsFor(tag) {
+			names = append(names, upload.Name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+	if got := assetNames("tool-v1.0.0"); got != "tool-1.0.0-linux-amd64.tar.gz,tool-1.0.0-linux-amd64.tar.gz.sha256" {
+		t.Errorf("versioned assets = %s", got)
+	}
+	if got := assetNames("tool-latest-next"); got != "tool-linux-amd64.tar.gz,tool-linux-amd64.tar.gz.sha256" {
+		t.Errorf("aliased assets = %s", got)
+	}
+	if got := gw.DeletedReleases(); len(got) != 1 || got[0] != "tool-latest" {
+		t.Errorf("deleted releases = %v, want the previous tool-latest replaced", got)
+	}
+	latest, err := gw.GetRelease(context.Background(), "o", "r", "tool-latest")
+	if err != nil {
+		t.Fatalf("tool-latest missing after mirroring: %v", err)
+	}
+	if latest.Draft || latest.Name != "tool latest (v1.0.0)" {
+		t.Errorf("tool-latest = %+v, want the published v1.0.0 mirror", latest)
+	}
+	if assets, _ := gw.ListReleaseAssets(context.Background(), "o", "r", latest.ID); len(assets) != 2 {
+		t.Errorf("tool-latest has %d assets, want 2", len(assets))
+	}
+	for _, upload := range gw.UploadsFor("tool-latest-next") {
+		if upload.Name == "tool-linux-amd64.tar.gz.sha256" && upload.Size != int64(len("abc123  tool-linux-amd64.tar.gz\n")) {
+			t.Errorf("aliased checksum size = %d, want it rewritten to name the aliased tarball", upload.Size)
+		}
+	}
+}
+
+// Test latest_alias keeps a -latest release mirroring a newer version, and never mirrors an unpublished draft
+func TestReleaseBatches_LatestAliasSkipped(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "tool")
+	recipe, err := os.OpenFile(filepath.Join(tmpDir, "tool.yml"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recipe.WriteString("latest_alias: true\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := recipe.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeTestArtifact(t, tmpDir, "tool", "1.0.0")
+
+	t.Run("older version", func(t *testing.T) {
+		gw := testsupport.NewFakeGitHubGateway()
+		if _, err := gw.CreateRelease(context.Background(), "o", "r", &domainGateways.GitHubRelease{TagName: "tool-latest", Name: latestAliasName("tool", "2.0.0")}); err != nil {
+			t.Fatal(err)
+		}
+		opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r"}
+		if err := releaseBatches(context.Background(), gw, []PackageRelease{{Package: "tool", Version: "1.0.0"}}, opts); err != nil {
+			t.Fatalf("releaseBatches() error = %v", err)
+		}
+		if got := gw.DeletedReleases(); len(got) != 0 {
+			t.Errorf("deleted releases = %v, want the newer tool-latest kept", got)
+		}
+		if got := gw.UploadsFor("tool-latest-next"); len(got) != 0 {
+			t.Errorf("aliased uploads = %v, want none", got)
+		}
+	})
+
+	t.Run("publish failed", func(t *testing.T) {
+		gw := testsupport.NewFakeGitHubGateway()
+		gw.FailUpdate(errors.New("publish failed"))
+		opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", WaitPublish: true}
+		_ = releaseBatches(context.Background(), gw, []PackageRelease{{Package: "tool", Version: "1.0.0"}}, opts)
+		for _, release := range gw.CreatedReleases() {
+			if strings.HasPrefix(release.TagName, "tool-latest") {
+				t.Errorf("created %s for a release left as draft", release.TagName)
+			}
+		}
+	})
+}
+
+// Test versions given with or without a "v" get the same tag, artifact matching and file names
+func TestReleaseBatches_MixedVersionPrefix(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"plain", "prefixed", "legacy"} {
+		writeTestRecipe(t, tmpDir, name)
+		writeTestArtifact(t, tmpDir, name, "1.0.0")
+	}
+
+	gw := testsupport.NewFakeGitHubGateway("legacy-1.0.0")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r"}
+	packages := []PackageRelease{
+		{Package: "plain", Version: "1.0.0"},
+		{Package: "prefixed", Version: "v1.0.0"},
+		{Package: "legacy", Version: "v1.0.0"},
+	}
+
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	created := gw.CreatedReleases()
+	if len(created) != 2 {
+		t.Fatalf("created releases = %+v, want plain and prefixed only", created)
+	}
+	for _, release := range created {
+		name := strings.TrimSuffix(release.TagName, "-v1.0.0")
+		if release.TagName != entities.ReleaseTag(name, "1.0.0") || release.Name != name+" v1.0.0" {
+			t.Errorf("release tag = %q, name = %q, want %s-v1.0.0 and %q", release.TagName, release.Name, name, name+" v1.0.0")
+		}
+		if !strings.Contains(release.Body, "releases/download/"+name+"-v1.0.0/"+name+"-1.0.0-<platform>.tar.gz") {
+			t.Errorf("%s release body install command does not use the tag and canonical file name:\n%s", name, release.Body)
+		}
+		uploads := gw.UploadsFor(release.TagName)
+		if len(uploads) != 2 || uploads[0].Name != entities.ArtifactFileName(name, "v1.0.0", "linux-amd64") {
+			t.Errorf("%s uploads = %+v, want its 1.0.0 artifacts", name, uploads)
+		}
+	}
+
+	for _, version := range []string{"1.0.0", "v1.0.0", " v1.0.0 "} {
+		if got := entities.ReleaseTag("tool", version); got != "tool-v1.0.0" {
+			t.Errorf("ReleaseTag(tool, %q) = %q, want tool-v1.0.0", version, got)
+		}
+		if got := entities.ArtifactFileName("tool", version, "linux-amd64"); got != "tool-1.0.0-linux-amd64.tar.gz" {
+			t.Errorf("ArtifactFileName(tool, %q) = %q", version, got)
+		}
+	}
+}
+
+// Test --verify-after-release downloads published assets and fails the package on a corrupted one
+func TestReleaseBatches_VerifyAfterRelease(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "tool")
+	writeTestArtifact(t, tmpDir, "tool", "1.0.0")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := filepath.Base(r.URL.Path)
+		if strings.HasSuffix(name, ".sha256") {
+			_, _ = w.Write([]byte("corrupted"))
+			return
+		}
+		http.ServeFile(w, r, filepath.Join(tmpDir, name))
+	}))
+	defer server.Close()
+
+	gw := testsupport.NewFakeGitHubGateway()
+	gw.ServeDownloadsFrom(server.URL)
+	reportDir := filepath.Join(tmpDir, "reports")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", ReportDir: reportDir, VerifyAfter: true}
+
+	if err := releaseBatches(context.Background(), gw, []PackageRelease{{Package: "tool", Version: "1.0.0"}}, opts); err == nil {
+		t.Fatal("releaseBatches() should fail when a published asset is corrupted")
+	}
+
+	data, err := os.ReadFile(filepath.Join(reportDir, "tool-1.0.0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report PackageReleaseReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != "failed" || !strings.Contains(report.Error, "VERIFY_FAILED") {
+		t.Fatalf("report = %+v, want a VERIFY_FAILED failure", report)
+	}
+	if !strings.HasSuffix(report.Error, "assets differ from local files: tool-1.0.0-linux-amd64.tar.gz.sha256 (https://github.example/releases/tag/tool-v1.0.0)") {
+		t.Errorf("error = %q, want only the corrupted checksum asset reported", report.Error)
+	}
+}
+
+// Test injected create errors fail the package without uploading
+func TestReleaseBatches_FakeGateway_CreateError(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "tool")
+	writeTestArtifact(t, tmpDir, "tool", "1.0.0")
+
+	gw := testsupport.NewFakeGitHubGateway()
+	gw.FailCreate(fmt.Errorf("validation failed"))
+
+	failuresFile := filepath.Join(tmpDir, "failures.txt")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", FailuresFile: failuresFile}
+
+	err := releaseBatches(context.Background(), gw, []PackageRelease{{Package: "tool", Version: "1.0.0"}}, opts)
+	if err == nil {
+		t.Fatal("releaseBatches() should fail when every release fails")
+	}
+
+	if len(gw.Uploads()) != 0 {
+		t.Errorf("uploads = %+v, want none", gw.Uploads())
+	}
+	failures, readErr := os.ReadFile(failuresFile)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	if !strings.Contains(string(failures), "CREATE_FAILED: validation failed") {
+		t.Errorf("failures = %q, want CREATE_FAILED detail", failures)
+	}
+}
+
+// Test injected upload errors leave the release created with the remaining assets
+func TestReleaseBatches_FakeGateway_UploadError(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "tool")
+	writeTestArtifact(t, tmpDir, "tool", "1.0.0")
+
+	gw := testsupport.NewFakeGitHubGateway()
+	gw.FailUpload("tool-1.0.0-linux-amd64.tar.gz.sha256", fmt.Errorf("connection reset"))
+
+	reportFile := filepath.Join(tmpDir, "report.json")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", ReportFile: reportFile}
+
+	if err := releaseBatches(context.Background(), gw, []PackageRelease{{Package: "tool", Version: "1.0.0"}}, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	uploads := gw.UploadsFor("tool-v1.0.0")
+	if len(uploads) != 1 || uploads[0].Name != "tool-1.0.0-linux-amd64.tar.gz" {
+		t.Errorf("uploads = %+v, want only the tarball", uploads)
+	}
+	if report := readReleaseReport(t, reportFile); len(report.Created) != 1 {
+		t.Errorf("created = %v, want partial upload to still count as created", report.Created)
+	}
+}
+
+// Test the release body lists a checksum row per platform from sibling .sha256 files
+func TestGenerateReleaseBody_ChecksumTable(t *testing.T) {
+	tmpDir := t.TempDir()
+	sums := map[string]string{
+		"linux-amd64":  strings.Repeat("a", 64),
+		"darwin-arm64": strings.Repeat("b", 64),
+	}
+
+	var artifacts []string
+	for platform, sum := range sums {
+		tarball := filepath.Join(tmpDir, fmt.Sprintf("tool-1.2.3-%s.tar.gz", platform))
+		if err := os.WriteFile(tarball, []byte("tarball"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		checksum := fmt.Sprintf("%s  %s\n", sum, filepath.Base(tarball))
+		if err := os.WriteFile(tarball+".sha256", []byte(checksum), 0600); err != nil {
+			t.Fatal(err)
+		}
+		artifacts = append(artifacts, tarball, tarball+".sha256")
+	}
+	// A tarball without a checksum file is left out of the table
+	orphan := filepath.Join(tmpDir, "tool-1.2.3-linux-arm64.tar.gz")
+	if err := os.WriteFile(orphan, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	artifacts = append(artifacts, orphan)
+
+	body := generateReleaseBody("tool", "v1.2.3", artifacts, "https://github.com/org/tool/releases/tag/v1.2.3")
+
+	for _, want := range []string{
+		"## Checksums",
+		"| Platform | File | SHA256 |",
+		"| darwin-arm64 | `tool-1.2.3-darwin-arm64.tar.gz` | `" + sums["darwin-arm64"] + "` |",
+		"| linux-amd64 | `tool-1.2.3-linux-amd64.tar.gz` | `" + sums["linux-amd64"] + "` |",
+		"Upstream release: https://github.com/org/tool/releases/tag/v1.2.3",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "| linux-arm64 |") {
+		t.Error("tarball without a .sha256 file should not appear in the checksum table")
+	}
+	if strings.Index(body, "| darwin-arm64 |") > strings.Index(body, "| linux-amd64 |") {
+		t.Error("checksum rows should be sorted by platform")
+	}
+}
+
+// Test --notes-template renders release data in place of the built-in notes
+func TestRenderReleaseBody_NotesTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	tarball := filepath.Join(tmpDir, "tool-1.2.3-linux-x86_64.tar.gz")
+	if err := os.WriteFile(tarball, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum := strings.Repeat("c", 64)
+	if err := os.WriteFile(tarball+".sha256", []byte(sum+"  tool-1.2.3-linux-x86_64.tar.gz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sbom := filepath.Join(tmpDir, "tool-1.2.3-linux-x86_64.tar.gz.sbom.json")
+	if err := os.WriteFile(sbom, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	templatePath := filepath.Join(tmpDir, "notes.tmpl")
+	notes := `# Acme {{.Package}} {{.Version}} ({{.Tag}})
+Platforms: {{join .Platforms ", "}}
+{{range .Artifacts}}{{if .SHA256}}- {{.Name}} {{.Size}}B {{.SHA256}}
+{{end}}{{end}}SBOM: {{.Security.SBOM}} Provenance: {{.Security.Provenance}} Scanned: {{.Security.ScanVulnerabilities}}
+`
+	if err := os.WriteFile(templatePath, []byte(notes), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := loadNotesTemplate(templatePath)
+	if err != nil {
+		t.Fatalf("loadNotesTemplate() error = %v", err)
+	}
+
+	recipe := &entities.Recipe{Name: "tool", Security: entities.RecipeSecurity{ScanVulnerabilities: true}}
+	body, err := renderReleaseBody(tmpl, recipe, "tool", "v1.2.3", []string{sbom, tarball + ".sha256", tarball})
+	if err != nil {
+		t.Fatalf("renderReleaseBody() error = %v", err)
+	}
+	want := "# Acme tool 1.2.3 (tool-v1.2.3)\n" +
+		"Platforms: linux-x86_64\n" +
+		"- tool-1.2.3-linux-x86_64.tar.gz 7B " + sum + "\n" +
+		"SBOM: true Provenance: false Scanned: true\n"
+	if body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+
+	// Templates are parsed before any release work starts
+	if err := os.WriteFile(templatePath, []byte("{{.Package"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadNotesTemplate(templatePath); err == nil || !strings.Contains(err.Error(), "invalid notes template") {
+		t.Errorf("loadNotesTemplate() error = %v, want a parse error", err)
+	}
+}
+
+// Test upstream release links are derived from GitHub version sources
+func TestUpstreamReleaseURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe *entities.Recipe
+		want   string
+	}{
+		{"no recipe", nil, ""},
+		{"url source", &entities.Recipe{Version: entities.VersionConfig{Source: "url:https://example.com"}}, ""},
+		{"github release", &entities.Recipe{Version: entities.VersionConfig{Source: "github-release:org/tool"}},
+			"https://github.com/org/tool/releases/tag/1.2.3"},
+		{"cleanup strips v", &entities.Recipe{Version: entities.VersionConfig{Source: "github-release:org/tool", Cleanup: "v:"}},
+			"https://github.com/org/tool/releases/tag/v1.2.3"},
+		{"git tag prefix", &entities.Recipe{
+			Version:  entities.VersionConfig{Source: "github-tag:llvm/llvm-project"},
+			Download: entities.RecipeDownload{GitTagPrefix: "llvmorg-"},
+		}, "https://github.com/llvm/llvm-project/releases/tag/llvmorg-1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := upstreamReleaseURL(tt.recipe, "v1.2.3"); got != tt.want {
+				t.Errorf("upstreamReleaseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test --report-dir writes one report per package with its status, assets and validation
+func TestReleaseBatches_ReportDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"fresh", "existing"} {
+		writeTestRecipe(t, tmpDir, name)
+		writeTestArtifact(t, tmpDir, name, "1.0.0")
+	}
+	writeTestRecipe(t, tmpDir, "empty")
+
+	gw := testsupport.NewFakeGitHubGateway("existing-1.0.0")
+	reportDir := filepath.Join(tmpDir, "reports")
+	reportFile := filepath.Join(tmpDir, "report.json")
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", ReportFile: reportFile, ReportDir: reportDir}
+	packages := []PackageRelease{
+		{Package: "fresh", Version: "1.0.0"},
+		{Package: "existing", Version: "1.0.0"},
+		{Package: "empty", Version: "1.0.0"},
+	}
+
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+
+	readPackageReport := func(name string) PackageReleaseReport {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(reportDir, name+"-1.0.0.json"))
+		if err != nil {
+			t.Fatalf("package report for %s not written: %v", name, err)
+		}
+		var report PackageReleaseReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatal(err)
+		}
+		return report
+	}
+
+	fresh := readPackageReport("fresh")
+	if fresh.Status != "created" || fresh.ReleaseURL == "" {
+		t.Errorf("fresh report = %+v, want created with release URL", fresh)
+	}
+	if got := strings.Join(fresh.Assets, ","); got != "fresh-1.0.0-linux-amd64.tar.gz,fresh-1.0.0-linux-amd64.tar.gz.sha256" {
+		t.Errorf("fresh assets = %v", fresh.Assets)
+	}
+	if fresh.Validation == nil || fresh.Validation.Status != "ready" || fresh.Validation.AvailableCount != 1 {
+		t.Errorf("fresh validation = %+v, want ready with 1 platform", fresh.Validation)
+	}
+
+	if existing := readPackageReport("existing"); existing.Status != "skipped" || len(existing.Assets) != 0 {
+		t.Errorf("existing report = %+v, want skipped without assets", existing)
+	}
+
+	empty := readPackageReport("empty")
+	if empty.Status != "failed" || !strings.Contains(empty.Error, "NO_TARBALLS") {
+		t.Errorf("empty report = %+v, want NO_TARBALLS failure", empty)
+	}
+
+	if report := readReleaseReport(t, reportFile); report.Total != 3 {
+		t.Errorf("aggregate report total = %d, want 3", report.Total)
+	}
+}
+
+// Test --batch-delay pauses between packages and stops early when cancelled
+func TestReleaseBatches_BatchDelay(t *testing.T) {
+	tmpDir := t.TempDir()
+	names := []string{"alpha", "beta", "gamma"}
+	var packages []PackageRelease
+	for _, name := range names {
+		writeTestRecipe(t, tmpDir, name)
+		writeTestArtifact(t, tmpDir, name, "1.0.0")
+		packages = append(packages, PackageRelease{Package: name, Version: "1.0.0"})
+	}
+
+	delay := 30 * time.Millisecond
+	gw := testsupport.NewFakeGitHubGateway()
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", BatchDelay: delay}
+
+	start := time.Now()
+	if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+		t.Fatalf("releaseBatches() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*delay {
+		t.Errorf("elapsed = %v, want at least %v for two inter-package pauses", elapsed, 2*delay)
+	}
+	if got := len(gw.CreatedReleases()); got != len(names) {
+		t.Errorf("releases created = %d, want %d", got, len(names))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), delay/2)
+	defer cancel()
+	cancelled := testsupport.NewFakeGitHubGateway()
+	opts.BatchDelay = time.Hour
+	if err := releaseBatches(ctx, cancelled, packages, opts); err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("releaseBatches() error = %v, want interruption during the delay", err)
+	}
+	if got := len(cancelled.CreatedReleases()); got != 1 {
+		t.Errorf("releases created before cancellation = %d, want 1", got)
+	}
+}
+
+// Test successive runs append release outcomes to the --history JSON Lines file
+func TestReleaseBatches_HistoryAppends(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestRecipe(t, tmpDir, "tool")
+	writeTestArtifact(t, tmpDir, "tool", "1.0.0")
+	historyFile := filepath.Join(tmpDir, "history.jsonl")
+
+	gw := testsupport.NewFakeGitHubGateway()
+	packages := []PackageRelease{{Package: "tool", Version: "1.0.0"}}
+	opts := BatchReleaseOptions{ArtifactsDir: tmpDir, RecipesDir: tmpDir, Owner: "o", Repo: "r", HistoryFile: historyFile}
+
+	for run := 0; run < 2; run++ {
+		if err := releaseBatches(context.Background(), gw, packages, opts); err != nil {
+			t.Fatalf("run %d: releaseBatches() error = %v", run+1, err)
+		}
+	}
+
+	data, err := os.ReadFile(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("history has %d lines, want 2:\n%s", len(lines), data)
+	}
+
+	var statuses []string
+	for _, line := range lines {
+		var record HistoryRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", line, err)
+		}
+		if record.Command != "release" || record.Package != "tool" || record.Version != "1.0.0" || record.Timestamp.IsZero() {
+			t.Errorf("record = %+v, want release of tool 1.0.0 with a timestamp", record)
+		}
+		statuses = append(statuses, record.Status)
+	}
+	if got := strings.Join(statuses, ","); got != "created,skipped" {
+		t.Errorf("statuses = %s, want created,skipped", got)
+	}
+}
+
+// Test --prune-old keeps the newest prereleases of the package and deletes the rest only when confirmed
+func TestPruneOldPrereleases(t *testing.T) {
+	ctx := context.Background()
+	gw := testsupport.NewFakeGitHubGateway("tool-v1.0.0", "tool-v1.1.0")
+	for _, tag := range []string{"tool-v1.1.0-rc1", "tool-v1.2.0-rc1", "tool-v1.10.0-rc1", "tool-v1.2.0-rc2", "tool-extra-v9.0.0-rc1", "other-v1.0.0-rc1"} {
+		if _, err := gw.CreateRelease(ctx, "o", "r", &domainGateways.GitHubRelease{TagName: tag, Prerelease: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	listed, err := pruneOldPrereleases(ctx, &buf, gw, "o", "r", "tool", 2, false)
+	if err != nil {
+		t.Fatalf("pruneOldPrereleases() error = %v", err)
+	}
+	if len(listed) != 0 || len(gw.DeletedReleases()) != 0 {
+		t.Fatalf("unconfirmed prune deleted %v", gw.DeletedReleases())
+	}
+	if !strings.Contains(buf.String(), "would delete tool-v1.1.0-rc1") || !strings.Contains(buf.String(), "--yes") {
+		t.Errorf("unconfirmed prune should list candidates and hint at --yes, got:\n%s", buf.String())
+	}
+
+	pruned, err := pruneOldPrereleases(ctx, io.Discard, gw, "o", "r", "tool", 2, true)
+	if err != nil {
+		t.Fatalf("pruneOldPrereleases() error = %v", err)
+	}
+	if got := strings.Join(gw.DeletedReleases(), ","); got != "tool-v1.2.0-rc1,tool-v1.1.0-rc1" || strings.Join(pruned, ",") != got {
+		t.Errorf("deleted %v (returned %v), want the two oldest tool prereleases", gw.DeletedReleases(), pruned)
+	}
+	for _, tag := range []string{"tool-v1.10.0-rc1", "tool-v1.2.0-rc2", "tool-v1.1.0", "tool-extra-v9.0.0-rc1", "other-v1.0.0-rc1"} {
+		if _, err := gw.GetRelease(ctx, "o", "r", tag); err != nil {
+			t.Errorf("release %s should be kept: %v", tag, err)
+		}
+	}
+}
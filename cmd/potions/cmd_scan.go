@@ -2,25 +2,98 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain-adapters/gateways"
 	orchestrators "github.com/ochairo/potions/internal/domain-orchestrators"
 	"github.com/ochairo/potions/internal/domain/entities"
+	domainServices "github.com/ochairo/potions/internal/domain/interfaces/services"
 	"github.com/ochairo/potions/internal/domain/services"
 )
 
+// ScanReport is the saved form of a scan, written by --output and read by --compare
+type ScanReport struct {
+	Package         string              `json:"package"`
+	Version         string              `json:"version"`
+	Platform        string              `json:"platform"`
+	Score           float64             `json:"score"`
+	Blocked         bool                `json:"blocked"`
+	Vulnerabilities []ScanVulnerability `json:"vulnerabilities"`
+}
+
+// SavedSecurityReport is a full vulnerability report written by --save and listed by --history
+type SavedSecurityReport struct {
+	Package  string                   `json:"package"`
+	Version  string                   `json:"version"`
+	Platform string                   `json:"platform"`
+	SavedAt  time.Time                `json:"saved_at"`
+	Report   *entities.SecurityReport `json:"report"`
+}
+
+// DirectoryScanReport is the combined report of scanning every binary under a directory
+type DirectoryScanReport struct {
+	Directory string             `json:"directory"`
+	Binaries  []BinaryScanResult `json:"binaries"`
+}
+
+// BinaryScanResult holds the hardening analysis and SBOM summary of one binary in a directory scan
+type BinaryScanResult struct {
+	Path           string  `json:"path"`
+	Platform       string  `json:"platform"`
+	HardeningScore float64 `json:"hardening_score"`
+	ChecksPassed   int     `json:"checks_passed"`
+	ChecksTotal    int     `json:"checks_total"`
+	SBOMComponents int     `json:"sbom_components"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// ScanVulnerability is a vulnerability entry in a saved scan report
+type ScanVulnerability struct {
+	ID        string  `json:"id"`
+	Severity  string  `json:"severity"`
+	Score     float64 `json:"score,omitempty"`
+	Component string  `json:"component,omitempty"`
+}
+
+// ScanDelta describes how vulnerabilities changed between a baseline and the current scan
+type ScanDelta struct {
+	Added         []string
+	Resolved      []string
+	BaselineScore float64
+	CurrentScore  float64
+}
+
+// ScoreChange returns the current score minus the baseline score
+func (d ScanDelta) ScoreChange() float64 {
+	return d.CurrentScore - d.BaselineScore
+}
+
 func runScan(ctx context.Context, args []string) {
 	fs := flag.NewFlagSet("scan", flag.ExitOnError)
 	var (
 		packageName = fs.String("package", "", "Package name to scan")
 		version     = fs.String("version", "", "Package version to scan")
 		platform    = fs.String("platform", "", "Platform (e.g., linux-amd64, darwin-arm64)")
-		binaryPath  = fs.String("binary", "", "Direct path to binary file to scan")
+		binaryPath  = fs.String("binary", "", "Direct path to a binary file, or a directory whose binaries are all analyzed")
 		verbose     = fs.Bool("verbose", false, "Show detailed scan results")
+		outputPath  = fs.String("output", "", "Save the scan report as JSON (usable later as a --compare baseline)")
+		comparePath = fs.String("compare", "", "Compare against a scan report previously saved with --output")
+		saveDir     = fs.String("save", "", "Directory to keep the full security report in, named by package/version/platform/date")
+		history     = fs.String("history", "", "List reports saved under --save DIR for this package with their score trend")
+		failFast    = fs.Bool("fail-fast", true, "Stop a directory scan at the first binary that fails to scan")
+		keepGoing   = fs.Bool("continue-on-error", false, "Scan every binary in a directory, summarizing failures at the end (overrides --fail-fast)")
+		exitCodes   = fs.String("exit-code", "", "Exit status per highest severity found and for a blocked verdict, e.g. 'low=1,medium=1,high=2,critical=3,blocked=3'; unnamed severities use the next lower named one (default: 1 only when blocked)")
 	)
 
 	fs.Usage = func() {
@@ -41,15 +114,44 @@ Options:
 Examples:
   potions scan --package kubectl --version 1.28.0 --platform linux-amd64
   potions scan --binary /path/to/kubectl
+  potions scan --binary ./extracted/llvm --output llvm-binaries.json   # Every binary in a directory
+  potions scan --binary ./extracted/llvm --continue-on-error          # Report all failures, not just the first
   potions scan --package kubectl --version 1.28.0 --platform linux-amd64 --verbose
+
+  # Save a baseline, then diff the next version against it
+  potions scan --package kubectl --version 1.28.0 --platform linux-amd64 --output kubectl-1.28.0.json
+  potions scan --package kubectl --version 1.29.0 --platform linux-amd64 --compare kubectl-1.28.0.json
+
+  # Let CI branch on the worst finding: 0 clean, 1 low/medium, 2 high, 3 critical or blocked
+  potions scan --package kubectl --version 1.28.0 --platform linux-amd64 --exit-code low=1,medium=1,high=2,critical=3,blocked=3
+
+  # Keep every report, then review how the score moved over time
+  potions scan --package kubectl --version 1.29.0 --platform linux-amd64 --save security-reports
+  potions scan --history kubectl --save security-reports
 `)
 	}
 
+	applyFlagDefaults(fs, flagDefaults)
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(1)
 	}
 
+	if *history != "" {
+		if *saveDir == "" {
+			fmt.Fprintf(os.Stderr, "Error: --history requires --save DIR to read saved reports from\n\n")
+			fs.Usage()
+			os.Exit(1)
+		}
+		records, err := loadSecurityHistory(*saveDir, *history)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printSecurityHistory(os.Stdout, *history, records)
+		return
+	}
+
 	// Validate inputs
 	if *packageName == "" && *binaryPath == "" {
 		fmt.Fprintf(os.Stderr, "Error: either --package or --binary is required\n\n")
@@ -63,14 +165,113 @@ Examples:
 		os.Exit(1)
 	}
 
+	exitPolicy, err := parseScanExitPolicy(*exitCodes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	continueOnError := *keepGoing || !*failFast
+
 	// Execute scan following Clean Architecture
-	if err := executeScan(ctx, *packageName, *version, *platform, *binaryPath, *verbose); err != nil {
+	if err := executeScan(ctx, *packageName, *version, *platform, *binaryPath, *verbose, *outputPath, *comparePath, *saveDir, exitPolicy, continueOnError); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var exitErr *scanExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
-func executeScan(ctx context.Context, packageName, version, platform, binaryPath string, verbose bool) error {
+// scanExitPolicy maps the highest vulnerability severity (LOW, MEDIUM, HIGH, CRITICAL) and a
+// blocked verdict (BLOCKED) to exit codes; a severity it does not name takes the code of the
+// highest named severity below it, and a blocked verdict always exits non-zero
+type scanExitPolicy map[string]int
+
+// defaultBlockedExitCode is the exit status of a blocked verdict when --exit-code names no blocked code
+const defaultBlockedExitCode = 1
+
+// scanExitOutcomes are the keys accepted by --exit-code, lowest severity first
+var scanExitOutcomes = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL", "BLOCKED"}
+
+// parseScanExitPolicy parses an --exit-code value such as "high=2,critical=3"; empty means no policy
+func parseScanExitPolicy(value string) (scanExitPolicy, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	policy := make(scanExitPolicy)
+	for _, pair := range strings.Split(value, ",") {
+		key, rawCode, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !ok || !slices.Contains(scanExitOutcomes, key) {
+			return nil, fmt.Errorf("invalid --exit-code entry %q (want <%s>=<code>)", pair,
+				strings.ToLower(strings.Join(scanExitOutcomes, "|")))
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(rawCode))
+		if err != nil || code < 0 || code > 125 {
+			return nil, fmt.Errorf("invalid --exit-code code %q for %s (want 0-125)", rawCode, strings.ToLower(key))
+		}
+		policy[key] = code
+	}
+	return policy, nil
+}
+
+// exitCode returns the larger of the highest severity's code and, when the report would block
+// a build, the blocked code, along with the outcome that decided it
+func (p scanExitPolicy) exitCode(svc domainServices.SecurityService, report *entities.SecurityReport) (int, string) {
+	code, outcome := 0, "clean"
+	severities := scanExitOutcomes[:len(scanExitOutcomes)-1]
+	for i := len(severities) - 1; i >= 0; i-- {
+		if len(svc.FilterVulnerabilities(report.Vulnerabilities, severities[i])) > 0 {
+			code, outcome = p.severityCode(severities[:i+1]), "highest severity "+severities[i]
+			break
+		}
+	}
+	if svc.ShouldBlockBuild(report, entities.DefaultSecurityPolicy()) {
+		blocked, ok := p["BLOCKED"]
+		if !ok || blocked == 0 {
+			blocked = defaultBlockedExitCode
+		}
+		if blocked >= code {
+			code, outcome = blocked, "blocked"
+		}
+	}
+	return code, outcome
+}
+
+// severityCode returns the code of the highest severity the policy names among severities,
+// which run from LOW up to the severity found
+func (p scanExitPolicy) severityCode(severities []string) int {
+	for i := len(severities) - 1; i >= 0; i-- {
+		if code, ok := p[severities[i]]; ok {
+			return code
+		}
+	}
+	return 0
+}
+
+// scanExitError reports a finished scan whose --exit-code policy calls for a non-zero status
+type scanExitError struct {
+	code    int
+	outcome string
+}
+
+func (e *scanExitError) Error() string {
+	return fmt.Sprintf("security scan policy: %s (exit code %d)", e.outcome, e.code)
+}
+
+func executeScan(ctx context.Context, packageName, version, platform, binaryPath string, verbose bool, outputPath, comparePath, saveDir string, exitPolicy scanExitPolicy, continueOnError bool) error {
+	// Load the baseline first so a bad path fails before the scan runs
+	var baseline *ScanReport
+	if comparePath != "" {
+		var err error
+		baseline, err = loadScanReport(comparePath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Layer 1: Create composite gateway (Infrastructure) - handles all gateway creation internally
 	securityGateway := gateways.NewCompositeSecurityGateway()
 
@@ -80,6 +281,14 @@ func executeScan(ctx context.Context, packageName, version, platform, binaryPath
 	// Layer 3: Create orchestrator (Use Case)
 	securityOrch := orchestrators.NewSecurityOrchestrator(securityService)
 
+	// A directory gets hardening analysis and an SBOM for each binary it contains
+	if info, err := os.Stat(binaryPath); err == nil && info.IsDir() {
+		if comparePath != "" || saveDir != "" || exitPolicy != nil {
+			return fmt.Errorf("--compare, --save and --exit-code are not supported when --binary is a directory")
+		}
+		return executeDirectoryScan(ctx, securityService, binaryPath, outputPath, continueOnError)
+	}
+
 	// Create artifact entity
 	var artifact *entities.Artifact
 	if binaryPath != "" {
@@ -109,7 +318,7 @@ func executeScan(ctx context.Context, packageName, version, platform, binaryPath
 	fmt.Printf("🔍 Security Scan: %s@%s (%s)\n\n", artifact.Name, artifact.Version, artifact.Platform)
 
 	// Execute security workflow through orchestrator
-	result, err := securityOrch.PerformSecurityWorkflow(ctx, artifact)
+	result, err := securityOrch.PerformSecurityWorkflow(ctx, artifact, entities.DefaultSecurityPolicy())
 	if err != nil {
 		return fmt.Errorf("security workflow failed: %w", err)
 	}
@@ -117,6 +326,37 @@ func executeScan(ctx context.Context, packageName, version, platform, binaryPath
 	// Display results
 	displayScanResults(result, verbose)
 
+	report := newScanReport(artifact, result)
+	if baseline != nil {
+		displayScanDelta(comparePath, compareScanReports(baseline, report))
+	}
+	if outputPath != "" {
+		if err := saveScanReport(outputPath, report); err != nil {
+			return err
+		}
+		fmt.Printf("💾 Scan report saved to %s\n", outputPath)
+	}
+	if saveDir != "" && result.SecurityReport != nil {
+		path, err := saveSecurityReport(saveDir, SavedSecurityReport{
+			Package:  artifact.Name,
+			Version:  artifact.Version,
+			Platform: artifact.Platform,
+			SavedAt:  time.Now().UTC(),
+			Report:   result.SecurityReport,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("💾 Security report saved to %s\n", path)
+	}
+
+	if exitPolicy != nil && result.SecurityReport != nil {
+		if code, outcome := exitPolicy.exitCode(securityService, result.SecurityReport); code != 0 {
+			return &scanExitError{code: code, outcome: outcome}
+		}
+		return nil
+	}
+
 	// Exit with error if blocked
 	if result.Blocked {
 		return fmt.Errorf("security scan failed: build blocked")
@@ -125,6 +365,141 @@ func executeScan(ctx context.Context, packageName, version, platform, binaryPath
 	return nil
 }
 
+// executeDirectoryScan scans every binary under dir and prints the combined report
+// Without continueOnError the scan stops at the first binary that fails
+func executeDirectoryScan(ctx context.Context, securityService domainServices.SecurityService, dir, outputPath string, continueOnError bool) error {
+	fmt.Printf("🔍 Security Scan: binaries in %s\n\n", dir)
+
+	report, err := scanBinaryDirectory(ctx, securityService, dir, continueOnError)
+	if err != nil {
+		return err
+	}
+	if len(report.Binaries) == 0 {
+		return fmt.Errorf("no binaries found in %s", dir)
+	}
+
+	var failures []BinaryScanResult
+	for _, binary := range report.Binaries {
+		if binary.Error != "" {
+			failures = append(failures, binary)
+			fmt.Printf("   ❌ %s (%s): %s\n", binary.Path, binary.Platform, binary.Error)
+			continue
+		}
+		fmt.Printf("   🛡️  %s (%s): hardening %.1f/10.0 (%d/%d checks), SBOM %d components\n",
+			binary.Path, binary.Platform, binary.HardeningScore, binary.ChecksPassed, binary.ChecksTotal, binary.SBOMComponents)
+	}
+	fmt.Printf("\n📦 Scanned %d binaries (%d failed)\n", len(report.Binaries), len(failures))
+	if continueOnError && len(failures) > 0 {
+		fmt.Println("\n❌ Failed binaries:")
+		for _, binary := range failures {
+			fmt.Printf("   • %s: %s\n", binary.Path, binary.Error)
+		}
+	}
+
+	if outputPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal scan report: %w", err)
+		}
+		if err := os.WriteFile(outputPath, data, 0600); err != nil {
+			return fmt.Errorf("failed to write scan report: %w", err)
+		}
+		fmt.Printf("💾 Scan report saved to %s\n", outputPath)
+	}
+
+	if len(failures) > 0 && !continueOnError {
+		return fmt.Errorf("analysis failed for %s; stopped at the first failure (use --continue-on-error to scan the rest)", failures[0].Path)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("analysis failed for %d of %d binaries", len(failures), len(report.Binaries))
+	}
+	return nil
+}
+
+// scanBinaryDirectory walks dir and runs hardening analysis and SBOM generation on each file
+// detected as an ELF or Mach-O binary; other files are skipped. Without continueOnError the
+// walk ends at the first binary that fails, which is the last one in the report
+func scanBinaryDirectory(ctx context.Context, securityService domainServices.SecurityService, dir string, continueOnError bool) (*DirectoryScanReport, error) {
+	report := &DirectoryScanReport{Directory: dir, Binaries: []BinaryScanResult{}}
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || !gateways.IsBinary(path) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		result := scanBinary(ctx, securityService, path, rel)
+		report.Binaries = append(report.Binaries, result)
+		if result.Error != "" && !continueOnError {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+	return report, nil
+}
+
+// scanBinary runs hardening analysis and SBOM generation on the binary at path, recording
+// the first failure in the result's Error
+func scanBinary(ctx context.Context, securityService domainServices.SecurityService, path, rel string) BinaryScanResult {
+	result := BinaryScanResult{Path: rel, Platform: gateways.DetectBinaryPlatform(path)}
+
+	analysis, err := securityService.AnalyzeBinary(ctx, path, result.Platform)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.HardeningScore = analysis.SecurityScore.Score
+	result.ChecksPassed = analysis.SecurityScore.Passed
+	result.ChecksTotal = analysis.SecurityScore.Total
+
+	sbom, err := securityService.GenerateSBOM(ctx, &entities.Artifact{
+		Name:     rel,
+		Version:  "unknown",
+		Platform: result.Platform,
+		Path:     path,
+		Type:     "binary",
+	})
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.SBOMComponents = len(sbom.Components)
+	}
+	return result
+}
+
+// printVulnerabilitiesByComponent lists vulnerabilities under the component they were found in,
+// components in order of first appearance
+func printVulnerabilitiesByComponent(w io.Writer, vulnerabilities []entities.Vulnerability) {
+	var components []string
+	byComponent := make(map[string][]entities.Vulnerability)
+	for _, vuln := range vulnerabilities {
+		component := vuln.Component
+		if component == "" {
+			component = "unknown component"
+		}
+		if _, ok := byComponent[component]; !ok {
+			components = append(components, component)
+		}
+		byComponent[component] = append(byComponent[component], vuln)
+	}
+
+	for _, component := range components {
+		fmt.Fprintf(w, "   📦 %s (%d)\n", component, len(byComponent[component]))
+		for _, vuln := range byComponent[component] {
+			fmt.Fprintf(w, "      - %s [%s] %s\n", vuln.ID, vuln.Severity, vuln.Description)
+		}
+	}
+}
+
 func displayScanResults(result *orchestrators.SecurityWorkflowResult, verbose bool) {
 	// Security Report
 	if result.SecurityReport != nil {
@@ -164,14 +539,8 @@ func displayScanResults(result *orchestrators.SecurityWorkflowResult, verbose bo
 			}
 
 			if verbose {
-				fmt.Printf("\n   Vulnerabilities:\n")
-				for i, vuln := range report.Vulnerabilities {
-					if i >= 10 && !verbose {
-						fmt.Printf("   ... and %d more\n", len(report.Vulnerabilities)-10)
-						break
-					}
-					fmt.Printf("   - %s [%s] %s\n", vuln.ID, vuln.Severity, vuln.Description)
-				}
+				fmt.Printf("\n   Vulnerabilities by component:\n")
+				printVulnerabilitiesByComponent(os.Stdout, report.Vulnerabilities)
 			}
 		} else {
 			fmt.Printf("   ✅ No vulnerabilities found\n")
@@ -205,6 +574,10 @@ func displayScanResults(result *orchestrators.SecurityWorkflowResult, verbose bo
 		if features.HardenedRuntime {
 			fmt.Printf("   Hardened Runtime: ✅\n")
 		}
+		if analysis.Platform == "windows" {
+			fmt.Printf("   Control Flow Guard: %s\n", formatCheck(features.CFG))
+			fmt.Printf("   SafeSEH: %s\n", formatCheck(features.SafeSEH))
+		}
 		fmt.Printf("\n")
 	}
 
@@ -240,3 +613,168 @@ func formatCheck(enabled bool) string {
 	}
 	return "❌ Disabled"
 }
+
+// newScanReport converts a security workflow result into its saved form
+func newScanReport(artifact *entities.Artifact, result *orchestrators.SecurityWorkflowResult) *ScanReport {
+	report := &ScanReport{
+		Package:         artifact.Name,
+		Version:         artifact.Version,
+		Platform:        artifact.Platform,
+		Blocked:         result.Blocked,
+		Vulnerabilities: []ScanVulnerability{},
+	}
+	if result.SecurityReport != nil {
+		report.Score = result.SecurityReport.Score
+		for _, vuln := range result.SecurityReport.Vulnerabilities {
+			report.Vulnerabilities = append(report.Vulnerabilities, ScanVulnerability{
+				ID:        vuln.ID,
+				Severity:  vuln.Severity,
+				Score:     vuln.Score,
+				Component: vuln.Component,
+			})
+		}
+	}
+	return report
+}
+
+// saveScanReport writes a scan report as indented JSON
+func saveScanReport(path string, report *ScanReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write scan report: %w", err)
+	}
+	return nil
+}
+
+// loadScanReport reads a scan report saved with --output
+func loadScanReport(path string) (*ScanReport, error) {
+	//nolint:gosec // G304: User explicitly provides baseline report path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline report (save one first with --output): %w", err)
+	}
+	var report ScanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// saveSecurityReport writes a security report into dir as <package>-<version>-<platform>-<date>.json
+func saveSecurityReport(dir string, record SavedSecurityReport) (string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal security report: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s-%s-%s.json", record.Package, record.Version, record.Platform, record.SavedAt.UTC().Format("20060102T150405Z"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write security report: %w", err)
+	}
+	return path, nil
+}
+
+// loadSecurityHistory reads the reports saved in dir for a package, oldest first
+func loadSecurityHistory(dir, packageName string) ([]SavedSecurityReport, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved reports: %w", err)
+	}
+
+	var records []SavedSecurityReport
+	for _, path := range paths {
+		//nolint:gosec // G304: path is a report inside the user-provided --save directory
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read saved report: %w", err)
+		}
+		var record SavedSecurityReport
+		if err := json.Unmarshal(data, &record); err != nil || record.Report == nil {
+			continue // Not a report written by --save
+		}
+		if record.Package == packageName {
+			records = append(records, record)
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].SavedAt.Before(records[j].SavedAt)
+	})
+	return records, nil
+}
+
+// printSecurityHistory lists saved reports with each score's change from the previous report
+func printSecurityHistory(w io.Writer, packageName string, records []SavedSecurityReport) {
+	if len(records) == 0 {
+		fmt.Fprintf(w, "No saved security reports for %s\n", packageName)
+		return
+	}
+
+	fmt.Fprintf(w, "📈 Security history for %s (%d reports)\n\n", packageName, len(records))
+	for i, record := range records {
+		trend := ""
+		if i > 0 {
+			trend = fmt.Sprintf(" (%+.1f)", record.Report.Score-records[i-1].Report.Score)
+		}
+		fmt.Fprintf(w, "   %s  %-12s %-14s score %.1f/10.0%s  %d vulnerabilities\n",
+			record.SavedAt.Format("2006-01-02 15:04"), record.Version, record.Platform,
+			record.Report.Score, trend, len(record.Report.Vulnerabilities))
+	}
+}
+
+// compareScanReports computes introduced and resolved vulnerability IDs and the score change
+func compareScanReports(baseline, current *ScanReport) ScanDelta {
+	before := make(map[string]bool, len(baseline.Vulnerabilities))
+	for _, vuln := range baseline.Vulnerabilities {
+		before[vuln.ID] = true
+	}
+	after := make(map[string]bool, len(current.Vulnerabilities))
+	for _, vuln := range current.Vulnerabilities {
+		after[vuln.ID] = true
+	}
+
+	delta := ScanDelta{BaselineScore: baseline.Score, CurrentScore: current.Score}
+	for id := range after {
+		if !before[id] {
+			delta.Added = append(delta.Added, id)
+		}
+	}
+	for id := range before {
+		if !after[id] {
+			delta.Resolved = append(delta.Resolved, id)
+		}
+	}
+	sort.Strings(delta.Added)
+	sort.Strings(delta.Resolved)
+	return delta
+}
+
+// displayScanDelta prints the comparison against a baseline report
+func displayScanDelta(baselinePath string, delta ScanDelta) {
+	fmt.Printf("\n🔀 Comparison with %s\n", baselinePath)
+	fmt.Printf("   Security score: %.1f → %.1f (%+.1f)\n", delta.BaselineScore, delta.CurrentScore, delta.ScoreChange())
+
+	if len(delta.Added) == 0 && len(delta.Resolved) == 0 {
+		fmt.Printf("   ✅ No vulnerability changes\n")
+		return
+	}
+	if len(delta.Added) > 0 {
+		fmt.Printf("   🔴 Introduced (%d):\n", len(delta.Added))
+		for _, id := range delta.Added {
+			fmt.Printf("      + %s\n", id)
+		}
+	}
+	if len(delta.Resolved) > 0 {
+		fmt.Printf("   🟢 Resolved (%d):\n", len(delta.Resolved))
+		for _, id := range delta.Resolved {
+			fmt.Printf("      - %s\n", id)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/domain/entities"
+	domainServices "github.com/ochairo/potions/internal/domain/interfaces/services"
+	"github.com/ochairo/potions/internal/domain/services"
+)
+
+// Test comparing two scan reports yields introduced/resolved IDs and the score change
+func TestCompareScanReports(t *testing.T) {
+	baseline := &ScanReport{
+		Package: "tool",
+		Version: "1.0.0",
+		Score:   7.5,
+		Vulnerabilities: []ScanVulnerability{
+			{ID: "GHSA-aaaa", Severity: "HIGH"},
+			{ID: "CVE-2024-0001", Severity: "MEDIUM"},
+			{ID: "CVE-2024-0002", Severity: "LOW"},
+		},
+	}
+	current := &ScanReport{
+		Package: "tool",
+		Version: "1.1.0",
+		Score:   8.0,
+		Vulnerabilities: []ScanVulnerability{
+			{ID: "CVE-2024-0002", Severity: "LOW"},
+			{ID: "CVE-2025-0100", Severity: "CRITICAL"},
+			{ID: "CVE-2025-0009", Severity: "LOW"},
+		},
+	}
+
+	delta := compareScanReports(baseline, current)
+
+	if got := strings.Join(delta.Added, ","); got != "CVE-2025-0009,CVE-2025-0100" {
+		t.Errorf("Added = %v, want [CVE-2025-0009 CVE-2025-0100]", delta.Added)
+	}
+	if got := strings.Join(delta.Resolved, ","); got != "CVE-2024-0001,GHSA-aaaa" {
+		t.Errorf("Resolved = %v, want [CVE-2024-0001 GHSA-aaaa]", delta.Resolved)
+	}
+	if delta.ScoreChange() != 0.5 {
+		t.Errorf("ScoreChange() = %v, want 0.5", delta.ScoreChange())
+	}
+}
+
+// Test saved reports round-trip and missing baselines point at --output
+func TestScanReport_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	report := &ScanReport{
+		Package:         "tool",
+		Version:         "1.0.0",
+		Platform:        "linux-amd64",
+		Score:           9.1,
+		Vulnerabilities: []ScanVulnerability{{ID: "CVE-2024-0001", Severity: "HIGH", Score: 7.2}},
+	}
+
+	if err := saveScanReport(path, report); err != nil {
+		t.Fatalf("saveScanReport() error = %v", err)
+	}
+	loaded, err := loadScanReport(path)
+	if err != nil {
+		t.Fatalf("loadScanReport() error = %v", err)
+	}
+	if loaded.Score != 9.1 || len(loaded.Vulnerabilities) != 1 || loaded.Vulnerabilities[0].ID != "CVE-2024-0001" {
+		t.Errorf("loadScanReport() = %+v, want round-tripped report", loaded)
+	}
+
+	_, err = loadScanReport(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil || !strings.Contains(err.Error(), "--output") {
+		t.Errorf("loadScanReport() error = %v, want hint to save with --output", err)
+	}
+}
+
+// Test a directory scan analyzes only the files detected as binaries
+func TestScanBinaryDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "bin"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	writeTestELF(t, filepath.Join(dir, "bin", "tool"))
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# tool\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := services.NewSecurityService(gateways.NewCompositeSecurityGateway())
+	report, err := scanBinaryDirectory(context.Background(), svc, dir, false)
+	if err != nil {
+		t.Fatalf("scanBinaryDirectory() error = %v", err)
+	}
+
+	if len(report.Binaries) != 1 {
+		t.Fatalf("Binaries = %+v, want only bin/tool", report.Binaries)
+	}
+	got := report.Binaries[0]
+	if got.Path != filepath.Join("bin", "tool") || got.Platform != "linux" || got.Error != "" {
+		t.Errorf("result = %+v, want analyzed linux binary bin/tool", got)
+	}
+	if got.ChecksTotal == 0 || got.SBOMComponents != 1 {
+		t.Errorf("result = %+v, want hardening checks and an SBOM", got)
+	}
+}
+
+// writeTestELF writes a minimal x86-64 ELF executable header to path
+func writeTestELF(t *testing.T, path string) {
+	t.Helper()
+	header := make([]byte, 64)
+	copy(header, []byte{0x7F, 'E', 'L', 'F', 2, 1, 1})
+	binary.LittleEndian.PutUint16(header[16:], 2)  // ET_EXEC
+	binary.LittleEndian.PutUint16(header[18:], 62) // EM_X86_64
+	binary.LittleEndian.PutUint32(header[20:], 1)  // EV_CURRENT
+	binary.LittleEndian.PutUint16(header[52:], 64) // e_ehsize
+	binary.LittleEndian.PutUint16(header[54:], 56) // e_phentsize
+	binary.LittleEndian.PutUint16(header[58:], 64) // e_shentsize
+	if err := os.WriteFile(path, header, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// failingAnalysisService fails hardening analysis for binaries named in fail and records every attempt
+type failingAnalysisService struct {
+	domainServices.SecurityService
+	fail      map[string]bool
+	attempted []string
+}
+
+func (s *failingAnalysisService) AnalyzeBinary(ctx context.Context, binaryPath, platform string) (*entities.BinaryAnalysis, error) {
+	s.attempted = append(s.attempted, filepath.Base(binaryPath))
+	if s.fail[filepath.Base(binaryPath)] {
+		return nil, errors.New("unsupported binary")
+	}
+	return s.SecurityService.AnalyzeBinary(ctx, binaryPath, platform)
+}
+
+// Test fail-fast stops at the first failing binary while --continue-on-error attempts them all
+func TestExecuteDirectoryScan_ContinueOnError(t *testing.T) {
+	dir := t.TempDir()
+	writeTestELF(t, filepath.Join(dir, "a-broken"))
+	writeTestELF(t, filepath.Join(dir, "b-good"))
+
+	for _, tt := range []struct {
+		continueOnError bool
+		wantAttempted   string
+		wantErr         string
+	}{
+		{false, "a-broken", "stopped at the first failure"},
+		{true, "a-broken,b-good", "analysis failed for 1 of 2 binaries"},
+	} {
+		svc := &failingAnalysisService{
+			SecurityService: services.NewSecurityService(gateways.NewCompositeSecurityGateway()),
+			fail:            map[string]bool{"a-broken": true},
+		}
+		outputPath := filepath.Join(t.TempDir(), "report.json")
+
+		err := executeDirectoryScan(context.Background(), svc, dir, outputPath, tt.continueOnError)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("continueOnError=%v: error = %v, want %q", tt.continueOnError, err, tt.wantErr)
+		}
+		if got := strings.Join(svc.attempted, ","); got != tt.wantAttempted {
+			t.Errorf("continueOnError=%v: attempted %s, want %s", tt.continueOnError, got, tt.wantAttempted)
+		}
+		if _, err := os.Stat(outputPath); err != nil {
+			t.Errorf("continueOnError=%v: report should still be written: %v", tt.continueOnError, err)
+		}
+	}
+}
+
+// Test saved security reports for a package are listed oldest first with their score trend
+func TestSecurityHistory(t *testing.T) {
+	dir := t.TempDir()
+	first := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	records := []SavedSecurityReport{
+		{Package: "tool", Version: "1.1.0", Platform: "linux-amd64", SavedAt: first.AddDate(0, 0, 14),
+			Report: &entities.SecurityReport{Score: 9.0}},
+		{Package: "tool", Version: "1.0.0", Platform: "linux-amd64", SavedAt: first,
+			Report: &entities.SecurityReport{Score: 7.5, Vulnerabilities: []entities.Vulnerability{{ID: "CVE-2026-0001", Severity: "HIGH"}}}},
+		{Package: "other", Version: "2.0.0", Platform: "linux-amd64", SavedAt: first,
+			Report: &entities.SecurityReport{Score: 5.0}},
+	}
+	for _, record := range records {
+		if _, err := saveSecurityReport(dir, record); err != nil {
+			t.Fatalf("saveSecurityReport() error = %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tool-1.0.0-linux-amd64-20260901T120000Z.json")); err != nil {
+		t.Errorf("report should be named by package/version/platform/date: %v", err)
+	}
+
+	history, err := loadSecurityHistory(dir, "tool")
+	if err != nil {
+		t.Fatalf("loadSecurityHistory() error = %v", err)
+	}
+	if len(history) != 2 || history[0].Version != "1.0.0" || history[1].Version != "1.1.0" {
+		t.Fatalf("history = %+v, want tool 1.0.0 then 1.1.0", history)
+	}
+	if len(history[0].Report.Vulnerabilities) != 1 {
+		t.Errorf("saved report lost its vulnerabilities: %+v", history[0].Report)
+	}
+
+	var buf bytes.Buffer
+	printSecurityHistory(&buf, "tool", history)
+	output := buf.String()
+	older := strings.Index(output, "score 7.5/10.0")
+	newer := strings.Index(output, "score 9.0/10.0 (+1.5)")
+	if older < 0 || newer < older {
+		t.Errorf("history output should list 7.5 then 9.0 (+1.5), got:\n%s", output)
+	}
+}
+
+// Test --exit-code maps the highest severity and a blocked verdict to the configured status
+func TestScanExitPolicy(t *testing.T) {
+	policy, err := parseScanExitPolicy("low=1, Medium=1,high=2,critical=3,blocked=4")
+	if err != nil {
+		t.Fatalf("parseScanExitPolicy() error = %v", err)
+	}
+	svc := services.NewSecurityService(gateways.NewCompositeSecurityGateway())
+
+	tests := []struct {
+		name     string
+		report   *entities.SecurityReport
+		wantCode int
+	}{
+		{"clean", &entities.SecurityReport{Score: 10}, 0},
+		{"low only", &entities.SecurityReport{Score: 9, Vulnerabilities: []entities.Vulnerability{{ID: "CVE-1", Severity: "LOW"}}}, 1},
+		{"high wins over low", &entities.SecurityReport{Score: 8, Vulnerabilities: []entities.Vulnerability{
+			{ID: "CVE-1", Severity: "LOW"}, {ID: "CVE-2", Severity: "HIGH"}}}, 2},
+		{"critical and blocked", &entities.SecurityReport{Score: 2, Vulnerabilities: []entities.Vulnerability{{ID: "CVE-3", Severity: "CRITICAL"}}}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, outcome := policy.exitCode(svc, tt.report); code != tt.wantCode {
+				t.Errorf("exitCode() = %d (%s), want %d", code, outcome, tt.wantCode)
+			}
+		})
+	}
+
+	// Severities above the highest named one fall back to it, and a blocked verdict never exits 0
+	highOnly, err := parseScanExitPolicy("high=2")
+	if err != nil {
+		t.Fatalf("parseScanExitPolicy() error = %v", err)
+	}
+	fallbacks := []struct {
+		name     string
+		report   *entities.SecurityReport
+		wantCode int
+	}{
+		{"below the named severity", &entities.SecurityReport{Score: 9, Vulnerabilities: []entities.Vulnerability{{ID: "CVE-1", Severity: "MEDIUM"}}}, 0},
+		{"critical falls back to high", &entities.SecurityReport{Score: 6, Vulnerabilities: []entities.Vulnerability{{ID: "CVE-3", Severity: "CRITICAL"}}}, 2},
+		{"blocked without a blocked code", &entities.SecurityReport{Score: 2}, 1},
+	}
+	for _, tt := range fallbacks {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, outcome := highOnly.exitCode(svc, tt.report); code != tt.wantCode {
+				t.Errorf("exitCode() = %d (%s), want %d", code, outcome, tt.wantCode)
+			}
+		})
+	}
+
+	for _, bad := range []string{"severe=2", "high", "high=abc", "critical=200"} {
+		if _, err := parseScanExitPolicy(bad); err == nil {
+			t.Errorf("parseScanExitPolicy(%q) should fail", bad)
+		}
+	}
+	if policy, err := parseScanExitPolicy(""); err != nil || policy != nil {
+		t.Errorf("parseScanExitPolicy(\"\") = %v, %v, want no policy", policy, err)
+	}
+}
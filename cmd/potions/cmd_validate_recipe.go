@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/external-adapters/yaml"
+)
+
+func runValidateRecipe(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("validate-recipe", flag.ExitOnError)
+	var (
+		recipesDir   = fs.String("recipes-dir", "recipes", "Path to recipes directory")
+		recipeFormat = fs.String("recipe-format", "", "Only read recipes in this format: yaml, toml or json (default: detect by extension)")
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: potions validate-recipe [package...] [options]
+
+Check that recipes parse and that every {placeholder} in their download templates is
+substituted on each platform. Without package names, every recipe is checked.
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Exit Codes:
+  0  All recipes are valid
+  1  At least one recipe is invalid
+  2  Usage error or system error
+
+Examples:
+  potions validate-recipe
+  potions validate-recipe kubectl helm
+  potions validate-recipe --recipes-dir ./recipes --recipe-format toml
+`)
+	}
+
+	applyFlagDefaults(fs, flagDefaults)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(2)
+	}
+
+	format, err := yaml.ParseRecipeFormat(*recipeFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --recipe-format: %v\n", err)
+		os.Exit(2)
+	}
+
+	failed, err := validateRecipes(ctx, os.Stdout, *recipesDir, format, fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// validateRecipes parses the named recipes (every recipe in recipesDir when names is empty) and
+// checks their download templates, printing one line per recipe; it returns how many are invalid
+func validateRecipes(ctx context.Context, w io.Writer, recipesDir string, format yaml.RecipeFormat, names []string) (int, error) {
+	if len(names) == 0 {
+		entries, err := os.ReadDir(recipesDir)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read recipes directory: %w", err)
+		}
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			fileFormat, ok := yaml.RecipeFormatForFile(entry.Name())
+			if entry.IsDir() || !ok || (format != "" && fileFormat != format) {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	repo := yaml.NewRecipeRepository(recipesDir).WithFormat(format)
+	failed := 0
+	for _, name := range names {
+		def, err := repo.GetRecipe(ctx, name)
+		if err == nil {
+			err = gateways.ValidateDownloadTemplates(def)
+		}
+		if err != nil {
+			fmt.Fprintf(w, "❌ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(w, "✅ %s\n", name)
+	}
+
+	fmt.Fprintf(w, "\n%d of %d recipe(s) valid\n", len(names)-failed, len(names))
+	return failed, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test validate-recipe reports recipes with unsubstituted placeholders or parse errors
+func TestValidateRecipes(t *testing.T) {
+	recipesDir := t.TempDir()
+	writeTestRecipe(t, recipesDir, "good")
+	files := map[string]string{
+		"templated.yml": `name: templated
+download:
+  download_url: "https://example.com/templated-{version}-{target}.tar.gz"
+  platforms:
+    linux-amd64:
+      target: x86_64-unknown-linux-gnu
+    darwin-arm64: {}
+`,
+		"broken.yml": "name: broken\neol_date: soon\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(recipesDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	failed, err := validateRecipes(context.Background(), &out, recipesDir, "", nil)
+	if err != nil {
+		t.Fatalf("validateRecipes() error = %v", err)
+	}
+	if failed != 2 {
+		t.Errorf("failed = %d, want 2\n%s", failed, out.String())
+	}
+	for _, want := range []string{
+		"✅ good",
+		"❌ templated: download_url for darwin-arm64: unsubstituted placeholder {target}",
+		"❌ broken: invalid eol_date",
+		"1 of 3 recipe(s) valid",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if failed, err := validateRecipes(context.Background(), &out, recipesDir, "", []string{"good"}); err != nil || failed != 0 {
+		t.Errorf("validateRecipes(good) = %d, %v; want 0 failures\n%s", failed, err, out.String())
+	}
+}
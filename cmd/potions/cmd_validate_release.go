@@ -2,21 +2,45 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/domain/entities"
 	"github.com/ochairo/potions/internal/domain/services"
 	"github.com/ochairo/potions/internal/external-adapters/yaml"
 )
 
+// ReleaseValidationReport is the JSON form of a release validation result
+type ReleaseValidationReport struct {
+	Package             string   `json:"package"`
+	Version             string   `json:"version"`
+	Status              string   `json:"status"`
+	Ready               bool     `json:"ready"`
+	ExpectedPlatforms   []string `json:"expected_platforms"`
+	AvailablePlatforms  []string `json:"available_platforms"`
+	MissingPlatforms    []string `json:"missing_platforms"`
+	OptionalMissing     []string `json:"optional_missing_platforms"`
+	UnexpectedPlatforms []string `json:"unexpected_platforms"`
+	MismatchedVersions  []string `json:"mismatched_versions,omitempty"`
+	ExpectedCount       int      `json:"expected_count"`
+	AvailableCount      int      `json:"available_count"`
+	Message             string   `json:"message,omitempty"`
+}
+
 func runValidateRelease(ctx context.Context, args []string) {
 	fs := flag.NewFlagSet("validate-release", flag.ExitOnError)
 	var (
 		artifactsDir = fs.String("artifacts", "current-artifacts", "Directory containing downloaded artifacts")
 		recipesDir   = fs.String("recipes", "recipes", "Directory containing recipe YAML files")
 		quiet        = fs.Bool("quiet", false, "Only output errors (exit code indicates success/failure)")
+		jsonOutput   = fs.Bool("json", false, "Write the validation result as JSON to stdout (human output goes to stderr)")
+		outputLayout = fs.String("output-layout", string(entities.LayoutFlat), "Layout of the artifacts directory: flat, by-package or by-package-version")
 	)
 
 	fs.Usage = func() {
@@ -41,9 +65,12 @@ Examples:
   potions validate-release kubectl v1.28.0
   potions validate-release kubectl v1.28.0 --artifacts ./dist
   potions validate-release kubectl v1.28.0 --quiet
+  potions validate-release kubectl v1.28.0 --artifacts ./dist --output-layout by-package
+  potions validate-release kubectl v1.28.0 --json --quiet > validation.json
 `)
 	}
 
+	applyFlagDefaults(fs, flagDefaults)
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(2)
@@ -58,7 +85,21 @@ Examples:
 	packageName := fs.Arg(0)
 	version := fs.Arg(1)
 
-	if err := executeValidateRelease(ctx, packageName, version, *artifactsDir, *recipesDir, *quiet); err != nil {
+	layout, err := parseOutputLayout(*outputLayout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	// Keep stdout clean for JSON consumers
+	out := io.Writer(os.Stdout)
+	var jsonOut io.Writer
+	if *jsonOutput {
+		out = os.Stderr
+		jsonOut = os.Stdout
+	}
+
+	if err := executeValidateRelease(ctx, out, jsonOut, packageName, version, *artifactsDir, layout, *recipesDir, *quiet); err != nil {
 		if !*quiet {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
@@ -66,13 +107,15 @@ Examples:
 	}
 }
 
-func executeValidateRelease(ctx context.Context, packageName, version, artifactsDir, recipesDir string, quiet bool) error {
+// executeValidateRelease validates a release, writing human output to out and,
+// when jsonOut is non-nil, the structured result as JSON
+func executeValidateRelease(ctx context.Context, out, jsonOut io.Writer, packageName, version, artifactsDir string, layout entities.OutputLayout, recipesDir string, quiet bool) error {
 	if !quiet {
-		fmt.Printf("🔍 Validating release for %s %s\n", packageName, version)
+		fmt.Fprintf(out, "🔍 Validating release for %s %s\n", packageName, version)
 	}
 
 	// Initialize artifact finder
-	artifactFinder := gateways.NewArtifactFinder()
+	artifactFinder := gateways.NewArtifactFinder().WithLayout(layout)
 
 	// Load recipe
 	recipeRepo := yaml.NewRecipeRepository(recipesDir)
@@ -88,76 +131,138 @@ func executeValidateRelease(ctx context.Context, packageName, version, artifacts
 	}
 
 	if !quiet {
-		fmt.Printf("📦 Found %d artifact files\n", len(artifacts))
+		fmt.Fprintf(out, "📦 Found %d artifact files\n", len(artifacts))
 	}
 
 	// Validate
-	releaseService := services.NewReleaseService()
+	releaseService := services.NewReleaseService().WithOutputLayout(layout)
 	validation := releaseService.ValidateRelease(recipe, packageName, version, artifacts)
 
+	if jsonOut != nil {
+		if err := writeValidationJSON(jsonOut, newReleaseValidationReport(packageName, version, validation)); err != nil {
+			return err
+		}
+	}
+
 	if !quiet {
-		fmt.Printf("\n Platform Validation:\n")
-		fmt.Printf("  Expected: %d platforms\n", validation.ExpectedCount)
-		fmt.Printf("  Available: %d platforms\n", validation.AvailableCount)
+		fmt.Fprintf(out, "\n Platform Validation:\n")
+		fmt.Fprintf(out, "  Expected: %d platforms\n", validation.ExpectedCount)
+		fmt.Fprintf(out, "  Available: %d platforms\n", validation.AvailableCount)
 
 		if len(validation.ExpectedPlatforms) > 0 {
-			fmt.Printf("  Expected platforms: ")
+			fmt.Fprintf(out, "  Expected platforms: ")
 			for i, p := range validation.ExpectedPlatforms {
 				if i > 0 {
-					fmt.Printf(", ")
+					fmt.Fprintf(out, ", ")
 				}
-				fmt.Printf("%s", p)
+				fmt.Fprintf(out, "%s", p)
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 
 		if len(validation.AvailablePlatforms) > 0 {
-			fmt.Printf("  Available platforms: ")
+			fmt.Fprintf(out, "  Available platforms: ")
 			for i, p := range validation.AvailablePlatforms {
 				if i > 0 {
-					fmt.Printf(", ")
+					fmt.Fprintf(out, ", ")
 				}
-				fmt.Printf("%s", p)
+				fmt.Fprintf(out, "%s", p)
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 
 		if len(validation.MissingPlatforms) > 0 {
-			fmt.Printf("  Missing platforms: ")
+			fmt.Fprintf(out, "  Missing platforms: ")
 			for i, p := range validation.MissingPlatforms {
 				if i > 0 {
-					fmt.Printf(", ")
+					fmt.Fprintf(out, ", ")
+				}
+				fmt.Fprintf(out, "%s", p)
+			}
+			fmt.Fprintln(out)
+		}
+
+		if len(validation.OptionalMissingPlatforms) > 0 {
+			fmt.Fprintf(out, "  Missing optional platforms: ")
+			for i, p := range validation.OptionalMissingPlatforms {
+				if i > 0 {
+					fmt.Fprintf(out, ", ")
 				}
-				fmt.Printf("%s", p)
+				fmt.Fprintf(out, "%s", p)
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 
 		if len(validation.UnexpectedPlatforms) > 0 {
-			fmt.Printf("  Unexpected platforms: ")
+			fmt.Fprintf(out, "  Unexpected platforms: ")
 			for i, p := range validation.UnexpectedPlatforms {
 				if i > 0 {
-					fmt.Printf(", ")
+					fmt.Fprintf(out, ", ")
 				}
-				fmt.Printf("%s", p)
+				fmt.Fprintf(out, "%s", p)
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
+		}
+
+		if len(validation.MismatchedVersions) > 0 {
+			fmt.Fprintf(out, "  Other versions found: %s\n", strings.Join(validation.MismatchedVersions, ", "))
 		}
 
-		fmt.Println()
+		fmt.Fprintln(out)
 	}
 
 	if !validation.IsReady() {
 		errMsg := validation.ErrorMessage(packageName, version)
 		if !quiet {
-			fmt.Printf("❌ FAILED: %s\n", errMsg)
+			fmt.Fprintf(out, "❌ FAILED: %s\n", errMsg)
 		}
 		return fmt.Errorf("%s", errMsg)
 	}
 
 	if !quiet {
-		fmt.Println("✅ READY: All expected platforms present")
+		fmt.Fprintln(out, "✅ READY: All expected platforms present")
 	}
 
 	return nil
 }
+
+// newReleaseValidationReport converts a validation result into its JSON report form
+func newReleaseValidationReport(packageName, version string, validation *services.ReleaseValidation) ReleaseValidationReport {
+	return ReleaseValidationReport{
+		Package:             packageName,
+		Version:             version,
+		Status:              string(validation.Status),
+		Ready:               validation.IsReady(),
+		ExpectedPlatforms:   sortedPlatformNames(validation.ExpectedPlatforms),
+		AvailablePlatforms:  sortedPlatformNames(validation.AvailablePlatforms),
+		MissingPlatforms:    sortedPlatformNames(validation.MissingPlatforms),
+		OptionalMissing:     sortedPlatformNames(validation.OptionalMissingPlatforms),
+		UnexpectedPlatforms: sortedPlatformNames(validation.UnexpectedPlatforms),
+		MismatchedVersions:  validation.MismatchedVersions,
+		ExpectedCount:       validation.ExpectedCount,
+		AvailableCount:      validation.AvailableCount,
+		Message:             validation.ErrorMessage(packageName, version),
+	}
+}
+
+// sortedPlatformNames returns platform names in stable order (never nil, so JSON shows [])
+func sortedPlatformNames(platforms []services.Platform) []string {
+	names := make([]string, 0, len(platforms))
+	for _, p := range platforms {
+		names = append(names, string(p))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeValidationJSON writes the validation report as indented JSON
+func writeValidationJSON(w io.Writer, report ReleaseValidationReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal validation result: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, string(data)); err != nil {
+		return fmt.Errorf("failed to write validation result: %w", err)
+	}
+	return nil
+}
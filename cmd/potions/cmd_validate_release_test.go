@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// Test --json reports status and missing platforms for a partial build
+func TestExecuteValidateRelease_JSONPartialBuild(t *testing.T) {
+	dir := t.TempDir()
+	recipe := `name: tool
+version:
+  source: "static:1.0.0"
+build_type: custom
+download:
+  download_url: "https://example.com/tool-{version}.tar.gz"
+  platforms:
+    linux-amd64:
+      os: linux
+      arch: amd64
+    linux-arm64:
+      os: linux
+      arch: arm64
+    darwin-x86_64:
+      os: darwin
+      arch: amd64
+    darwin-arm64:
+      os: darwin
+      arch: arm64
+`
+	if err := os.WriteFile(filepath.Join(dir, "tool.yml"), []byte(recipe), 0600); err != nil {
+		t.Fatal(err)
+	}
+	writeTestArtifact(t, dir, "tool", "1.0.0")
+
+	var human, jsonOut bytes.Buffer
+	err := executeValidateRelease(context.Background(), &human, &jsonOut, "tool", "1.0.0", dir, entities.LayoutFlat, dir, true)
+	if err == nil {
+		t.Fatal("expected validation failure for partial build")
+	}
+	if human.Len() != 0 {
+		t.Errorf("--quiet should suppress human output, got %q", human.String())
+	}
+
+	var report ReleaseValidationReport
+	if err := json.Unmarshal(jsonOut.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, jsonOut.String())
+	}
+
+	if report.Status != "platform_mismatch" || report.Ready {
+		t.Errorf("status = %s (ready=%v), want platform_mismatch", report.Status, report.Ready)
+	}
+	if got := strings.Join(report.MissingPlatforms, ","); got != "darwin-arm64,darwin-x86_64,linux-arm64" {
+		t.Errorf("missing platforms = %s", got)
+	}
+	if report.ExpectedCount != 4 || report.AvailableCount != 1 {
+		t.Errorf("counts = %d/%d, want 4 expected, 1 available", report.ExpectedCount, report.AvailableCount)
+	}
+}
+
+// Test human output is still written when not quiet
+func TestExecuteValidateRelease_HumanOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeTestRecipe(t, dir, "tool")
+	writeTestArtifact(t, dir, "tool", "1.0.0")
+
+	var human bytes.Buffer
+	if err := executeValidateRelease(context.Background(), &human, nil, "tool", "1.0.0", dir, entities.LayoutFlat, dir, false); err != nil {
+		t.Fatalf("executeValidateRelease() error = %v", err)
+	}
+	if !strings.Contains(human.String(), "READY") {
+		t.Errorf("human output = %q, want READY", human.String())
+	}
+}
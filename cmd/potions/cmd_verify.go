@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/domain/services"
 	"github.com/ochairo/potions/internal/external-adapters/attestation"
 	"github.com/ochairo/potions/internal/external-adapters/cosign"
 	"github.com/ochairo/potions/internal/external-adapters/gpg"
@@ -17,29 +22,47 @@ import (
 func runVerify(ctx context.Context, args []string) {
 	fs := flag.NewFlagSet("verify", flag.ExitOnError)
 	var (
-		checksumFile   = fs.String("checksum", "", "Checksum file to verify against (.sha256 or .sha512)")
+		checksumFile   = fs.String("checksum", "", "Checksum file to verify against (.sha256, .sha512 or .sha1)")
+		allowWeak      = fs.Bool("allow-weak-checksum", false, "Accept a SHA1 --checksum, which is rejected by default as a weak hash")
 		gpgSig         = fs.String("gpg-sig", "", "GPG signature file (.asc)")
 		gpgKeyIDs      = fs.String("gpg-key-ids", "", "Comma-separated GPG key IDs to import")
 		gpgKeysURL     = fs.String("gpg-keys-url", "", "URL to KEYS file for GPG verification")
+		gpgKeyFile     = fs.String("gpg-key-file", "", "Local public key file for GPG verification")
+		gpgKeyring     = fs.String("gpg-keyring", "", "Local keyring file that --gpg-key-ids are taken from instead of keyservers")
+		gpgKeyEmail    = fs.String("gpg-key-email", "", "Maintainer email whose Web Key Directory (WKD) is queried for --gpg-key-ids first")
+		keybaseUser    = fs.String("gpg-keybase-user", "", "Keybase user whose public key is queried for --gpg-key-ids first")
+		manifest       = fs.String("manifest", "", "SHA256SUMS manifest to verify files against")
+		manifestSig    = fs.String("manifest-sig", "", "Detached GPG signature for --manifest (default: <manifest>.asc)")
 		cosignSig      = fs.String("cosign-sig", "", "Cosign signature file (.sig)")
 		cosignCert     = fs.String("cosign-cert", "", "Cosign certificate file (.pem)")
+		cosignBundle   = fs.String("cosign-bundle", "", "Cosign bundle file (.bundle), used instead of --cosign-sig/--cosign-cert")
 		cosignIdentity = fs.String("cosign-identity", "", "Expected certificate identity")
 		attestFile     = fs.String("attest-file", "", "Attestation file (.attestation.jsonl)")
 		attestOwner    = fs.String("owner", "", "GitHub repository owner (for attestations)")
 		attestRepo     = fs.String("repo", "", "GitHub repository name (for attestations)")
+		provenanceFile = fs.String("provenance", "", "Provenance file (.provenance.json or DSSE envelope) whose subject must match the file; its signature is not verified")
+		printProv      = fs.Bool("print-provenance", false, "Print the decoded provenance statement after successful verification")
+		slsaFile       = fs.String("slsa-provenance", "", "SLSA v1 provenance (.intoto.jsonl from the SLSA GitHub generator) whose subject, build type and builder are checked; its signature is not verified")
+		slsaBuilderID  = fs.String("slsa-builder-id", "", "Trusted builder.id for --slsa-provenance; a trailing / makes it a prefix (default: SLSA GitHub generator workflows)")
+		slsaBuildType  = fs.String("slsa-build-type", "", "Required buildType for --slsa-provenance (default: the SLSA GitHub generator's build types)")
 		verifyAll      = fs.Bool("all", false, "Verify all available signatures automatically")
+		offline        = fs.Bool("offline", false, "Use only local keys and attestations; fail instead of fetching keys or querying gh")
+		keyservers     stringListFlag
 	)
+	fs.Var(&keyservers, "keyserver", "Keyserver (https:// or hkps://) tried before the defaults for --gpg-key-ids; repeatable")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Usage: potions verify <file> [options]
+       potions verify --manifest SHA256SUMS [--manifest-sig SHA256SUMS.asc] <file>... [options]
 
 Verify checksums, signatures, and attestations for build artifacts.
 
 Supports multiple verification methods:
-  - Checksums: SHA256 and SHA512 verification
+  - Checksums: SHA256, SHA512 (and legacy SHA1 with --allow-weak-checksum) verification
   - GPG: PGP signature verification
   - Cosign: Sigstore keyless signature verification
   - GitHub Attestations: SLSA provenance verification
+  - SLSA v1 provenance: subject digest, build type and builder checks (digest match only)
 
 Options:
 `)
@@ -52,14 +75,37 @@ Examples:
   # Verify GPG signature
   potions verify kubectl.tar.gz --gpg-sig kubectl.tar.gz.asc --gpg-key-ids 7F92E05B31093BEF
 
+  # Look up GPG keys on an internal keyserver first
+  potions verify kubectl.tar.gz --gpg-sig kubectl.tar.gz.asc --gpg-key-ids 7F92E05B31093BEF --keyserver hkps://keys.corp.example
+
+  # Fetch the GPG key from the maintainer's Web Key Directory
+  potions verify curl.tar.xz --gpg-sig curl.tar.xz.asc --gpg-key-ids 27EDEAF22F3ABCEB50DB9A125CC908FDB71E12C2 --gpg-key-email daniel@haxx.se
+
+  # Verify in an air-gapped environment with a local keyring and attestation
+  potions verify kubectl.tar.gz --offline --gpg-sig kubectl.tar.gz.asc --gpg-key-ids 7F92E05B31093BEF \
+    --gpg-keyring release-keys.gpg --attest-file kubectl.tar.gz.attestation.jsonl
+
   # Verify Cosign signature
   potions verify helm.tar.gz --cosign-sig helm.tar.gz.sig --cosign-cert helm.tar.gz.pem
 
+  # Verify a Cosign bundle
+  potions verify helm.tar.gz --cosign-bundle helm.tar.gz.bundle
+
   # Verify all available signatures
   potions verify package.tar.gz --all
+
+  # Verify SLSA v1 provenance from the SLSA GitHub generator
+  potions verify tool.tar.gz --slsa-provenance multiple.intoto.jsonl
+
+  # Verify local provenance and show its builder, subjects and materials
+  potions verify package.tar.gz --provenance package.tar.gz.provenance.json --print-provenance
+
+  # Verify a signed SHA256SUMS manifest, then every file against it
+  potions verify --manifest SHA256SUMS --gpg-key-file release-key.asc tool-1.0.0-*.tar.gz
 `)
 	}
 
+	applyFlagDefaults(fs, flagDefaults)
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		os.Exit(1)
@@ -71,49 +117,125 @@ Examples:
 		os.Exit(1)
 	}
 
+	gpgKeys := gpgKeySources{IDs: *gpgKeyIDs, URL: *gpgKeysURL, File: *gpgKeyFile, Keyring: *gpgKeyring, Offline: *offline,
+		Lookup: gpg.KeyLookup{WKDEmail: *gpgKeyEmail, KeybaseUser: *keybaseUser}}
+	for _, raw := range keyservers {
+		keyserver, err := gpg.ParseKeyserver(raw)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --keyserver: %v\n", err)
+			os.Exit(2)
+		}
+		gpgKeys.Keyservers = append(gpgKeys.Keyservers, keyserver)
+	}
+
+	if *manifest != "" {
+		if err := executeManifestVerify(ctx, *manifest, *manifestSig, gpgKeys, fs.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	filePath := fs.Arg(0)
 
 	// Execute verification following Clean Architecture
-	if err := executeVerify(ctx, filePath, *checksumFile, *gpgSig, *gpgKeyIDs, *gpgKeysURL,
-		*cosignSig, *cosignCert, *cosignIdentity, *attestFile, *attestOwner, *attestRepo, *verifyAll); err != nil {
+	if err := executeVerify(ctx, filePath, verifyOptions{
+		ChecksumFile:      *checksumFile,
+		AllowWeakChecksum: *allowWeak,
+		GPGSig:            *gpgSig,
+		GPGKeys:           gpgKeys,
+		CosignSig:         *cosignSig,
+		CosignCert:        *cosignCert,
+		CosignBundle:      *cosignBundle,
+		CosignIdentity:    *cosignIdentity,
+		AttestFile:        *attestFile,
+		AttestOwner:       *attestOwner,
+		AttestRepo:        *attestRepo,
+		ProvenanceFile:    *provenanceFile,
+		SLSAFile:          *slsaFile,
+		SLSAPolicy:        attestation.SLSAPolicy{BuilderID: *slsaBuilderID, BuildType: *slsaBuildType},
+		VerifyAll:         *verifyAll,
+		ShowProvenance:    *printProv,
+	}); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func executeVerify(ctx context.Context, filePath, checksumFile, gpgSig, gpgKeyIDs, gpgKeysURL,
-	cosignSig, cosignCert, cosignIdentity, attestFile, attestOwner, attestRepo string, verifyAll bool) error {
+// gpgKeySources describes where to load GPG public keys from
+type gpgKeySources struct {
+	IDs     string // Comma-separated key IDs fetched from keyservers
+	URL     string // KEYS file URL
+	File    string // Local key file
+	Keyring string // Local keyring IDs are taken from instead of keyservers
+
+	Keyservers []string      // Keyservers queried for IDs before the defaults
+	Lookup     gpg.KeyLookup // WKD and Keybase sources queried for IDs before the keyservers
+	Offline    bool          // Forbid every network key source
+}
+
+// verifyOptions selects the checks executeVerify runs against a file
+type verifyOptions struct {
+	ChecksumFile      string
+	AllowWeakChecksum bool // Accept a SHA1 ChecksumFile
+	GPGSig            string
+	GPGKeys           gpgKeySources // GPGKeys.Offline also keeps attestation checks from querying gh
+	CosignSig         string
+	CosignCert        string
+	CosignBundle      string
+	CosignIdentity    string
+	AttestFile        string
+	AttestOwner       string
+	AttestRepo        string
+	ProvenanceFile    string
+	SLSAFile          string
+	SLSAPolicy        attestation.SLSAPolicy
+	VerifyAll         bool // Pick up <file>.sha256, .asc, .bundle and other sidecar files that exist
+	ShowProvenance    bool // Print verified provenance statements
+}
 
+func executeVerify(ctx context.Context, filePath string, opts verifyOptions) error {
 	verified := 0
 	failed := 0
+	digestOnly := 0 // Checks that matched an unsigned statement's digest: reported, but not a pass
 
 	// Auto-detect files if --all is specified
-	if verifyAll {
-		if checksumFile == "" {
+	if opts.VerifyAll {
+		if opts.ChecksumFile == "" {
 			if fileExists(filePath + ".sha256") {
-				checksumFile = filePath + ".sha256"
+				opts.ChecksumFile = filePath + ".sha256"
 			} else if fileExists(filePath + ".sha512") {
-				checksumFile = filePath + ".sha512"
+				opts.ChecksumFile = filePath + ".sha512"
+			}
+		}
+		if opts.GPGSig == "" && fileExists(filePath+".asc") {
+			opts.GPGSig = filePath + ".asc"
+		}
+		if opts.CosignSig == "" && opts.CosignBundle == "" {
+			if fileExists(filePath + ".bundle") {
+				opts.CosignBundle = filePath + ".bundle"
+			} else if fileExists(filePath+".sig") && fileExists(filePath+".pem") {
+				opts.CosignSig = filePath + ".sig"
+				opts.CosignCert = filePath + ".pem"
 			}
 		}
-		if gpgSig == "" && fileExists(filePath+".asc") {
-			gpgSig = filePath + ".asc"
+		if opts.AttestFile == "" && fileExists(filePath+".attestation.jsonl") {
+			opts.AttestFile = filePath + ".attestation.jsonl"
 		}
-		if cosignSig == "" && fileExists(filePath+".sig") && fileExists(filePath+".pem") {
-			cosignSig = filePath + ".sig"
-			cosignCert = filePath + ".pem"
+		if opts.ProvenanceFile == "" && fileExists(filePath+".provenance.json") {
+			opts.ProvenanceFile = filePath + ".provenance.json"
 		}
-		if attestFile == "" && fileExists(filePath+".attestation.jsonl") {
-			attestFile = filePath + ".attestation.jsonl"
+		if opts.SLSAFile == "" && fileExists(filePath+".intoto.jsonl") {
+			opts.SLSAFile = filePath + ".intoto.jsonl"
 		}
 	}
 
 	fmt.Printf("🔍 Verifying %s\n\n", filepath.Base(filePath))
 
 	// Verify checksum
-	if checksumFile != "" {
+	if opts.ChecksumFile != "" {
 		fmt.Printf("📋 Verifying checksum...\n")
-		if err := verifyChecksum(ctx, filePath, checksumFile); err != nil {
+		if err := verifyChecksum(ctx, filePath, opts.ChecksumFile, opts.AllowWeakChecksum); err != nil {
 			fmt.Printf("❌ Checksum verification FAILED: %v\n\n", err)
 			failed++
 		} else {
@@ -123,9 +245,9 @@ func executeVerify(ctx context.Context, filePath, checksumFile, gpgSig, gpgKeyID
 	}
 
 	// Verify GPG signature
-	if gpgSig != "" {
+	if opts.GPGSig != "" {
 		fmt.Printf("🔐 Verifying GPG signature...\n")
-		if err := verifyGPGSignature(ctx, filePath, gpgSig, gpgKeyIDs, gpgKeysURL); err != nil {
+		if err := verifyGPGSignature(ctx, filePath, opts.GPGSig, opts.GPGKeys); err != nil {
 			fmt.Printf("❌ GPG signature verification FAILED: %v\n\n", err)
 			failed++
 		} else {
@@ -135,9 +257,9 @@ func executeVerify(ctx context.Context, filePath, checksumFile, gpgSig, gpgKeyID
 	}
 
 	// Verify Cosign signature
-	if cosignSig != "" {
+	if opts.CosignSig != "" || opts.CosignBundle != "" {
 		fmt.Printf("🔏 Verifying Cosign signature...\n")
-		if err := verifyCosignSignature(ctx, filePath, cosignSig, cosignCert, cosignIdentity); err != nil {
+		if err := verifyCosignSignature(ctx, filePath, opts.CosignSig, opts.CosignCert, opts.CosignBundle, opts.CosignIdentity); err != nil {
 			fmt.Printf("❌ Cosign signature verification FAILED: %v\n\n", err)
 			failed++
 		} else {
@@ -147,20 +269,65 @@ func executeVerify(ctx context.Context, filePath, checksumFile, gpgSig, gpgKeyID
 	}
 
 	// Verify GitHub attestation
-	if attestFile != "" {
+	if opts.AttestFile != "" {
 		fmt.Printf("📜 Verifying GitHub attestation...\n")
-		if err := verifyAttestation(ctx, filePath, attestFile, attestOwner, attestRepo); err != nil {
+		if signed, err := verifyAttestation(ctx, filePath, opts.AttestFile, opts.AttestOwner, opts.AttestRepo, opts.GPGKeys.Offline); err != nil {
 			fmt.Printf("❌ Attestation verification FAILED: %v\n\n", err)
 			failed++
 		} else {
-			fmt.Printf("✅ Attestation verified\n\n")
-			verified++
+			if signed {
+				fmt.Printf("✅ Attestation verified\n\n")
+				verified++
+			} else {
+				fmt.Printf("⚠️  Attestation digest match only (signature not verified)\n\n")
+				digestOnly++
+			}
+			if opts.ShowProvenance {
+				printProvenanceFile(os.Stdout, opts.AttestFile)
+			}
+		}
+	}
+
+	// Verify local provenance
+	if opts.ProvenanceFile != "" {
+		fmt.Printf("📜 Verifying provenance...\n")
+		if err := verifyProvenance(filePath, opts.ProvenanceFile); err != nil {
+			fmt.Printf("❌ Provenance verification FAILED: %v\n\n", err)
+			failed++
+		} else {
+			// LoadStatement unwraps a DSSE envelope without checking its signature
+			fmt.Printf("⚠️  Provenance digest match only (signature not verified)\n\n")
+			digestOnly++
+			if opts.ShowProvenance {
+				printProvenanceFile(os.Stdout, opts.ProvenanceFile)
+			}
+		}
+	}
+
+	// Verify SLSA v1 provenance
+	if opts.SLSAFile != "" {
+		fmt.Printf("📜 Verifying SLSA provenance...\n")
+		statement, err := attestation.NewVerifier().VerifySLSAProvenance(ctx, filePath, opts.SLSAFile, opts.SLSAPolicy)
+		if err != nil {
+			fmt.Printf("❌ SLSA provenance verification FAILED: %v\n\n", err)
+			failed++
+		} else {
+			// Only the statement is checked; the DSSE envelope's signature and Rekor entry are not
+			fmt.Printf("⚠️  SLSA provenance digest and policy match only (builder %s, signature not verified)\n\n",
+				statement.Predicate.RunDetails.Builder.ID)
+			digestOnly++
+			if opts.ShowProvenance {
+				printProvenance(os.Stdout, statement)
+			}
 		}
 	}
 
 	// Print summary
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Printf("✅ Verified: %d checks\n", verified)
+	if digestOnly > 0 {
+		fmt.Printf("⚠️  Digest match only: %d checks\n", digestOnly)
+	}
 	if failed > 0 {
 		fmt.Printf("❌ Failed: %d checks\n", failed)
 	}
@@ -170,14 +337,17 @@ func executeVerify(ctx context.Context, filePath, checksumFile, gpgSig, gpgKeyID
 		return fmt.Errorf("%d verification checks failed", failed)
 	}
 
+	if verified == 0 && digestOnly > 0 {
+		return fmt.Errorf("%d checks matched digests only; no signature was verified", digestOnly)
+	}
 	if verified == 0 {
-		return fmt.Errorf("no verification checks performed (specify --checksum, --gpg-sig, --cosign-sig, or --attest-file)")
+		return fmt.Errorf("no verification checks performed (specify --checksum, --gpg-sig, --cosign-sig, --cosign-bundle, --attest-file, --provenance, or --slsa-provenance)")
 	}
 
 	return nil
 }
 
-func verifyChecksum(ctx context.Context, filePath, checksumFile string) error {
+func verifyChecksum(ctx context.Context, filePath, checksumFile string, allowWeak bool) error {
 	// Layer 1: Create gateway (Infrastructure)
 	verifier := gateways.NewChecksumVerifier()
 
@@ -193,49 +363,192 @@ func verifyChecksum(ctx context.Context, filePath, checksumFile string) error {
 	if len(parts) < 1 {
 		return fmt.Errorf("invalid checksum file format")
 	}
-	expectedChecksum := parts[0]
+	expectedChecksum := strings.ToLower(parts[0])
+
+	algorithm, err := detectChecksumAlgorithm(expectedChecksum, checksumFile)
+	if err != nil {
+		return err
+	}
+	if algorithm == gateways.ChecksumSHA1 && !allowWeak {
+		return fmt.Errorf("%s holds a SHA1 checksum, which is weak; pass --allow-weak-checksum to accept it",
+			filepath.Base(checksumFile))
+	}
+
+	// Verify using the gateway (pure Go crypto/sha256, crypto/sha512)
+	if err := verifier.VerifyChecksumWithAlgorithm(ctx, filePath, expectedChecksum, algorithm); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// errMalformedChecksum marks a checksum file whose expected hash is not a well-formed hex digest
+var errMalformedChecksum = errors.New("malformed checksum file")
+
+// detectChecksumAlgorithm infers the hash algorithm from the checksum's hex length
+// and rejects malformed checksums and checksum files whose extension contradicts them
+func detectChecksumAlgorithm(checksum, checksumFile string) (string, error) {
+	if strings.Trim(checksum, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("%w: %s holds %q, which is not a hex checksum",
+			errMalformedChecksum, filepath.Base(checksumFile), checksum)
+	}
+
+	var algorithm string
+	switch len(checksum) {
+	case 40:
+		algorithm = gateways.ChecksumSHA1
+	case 64:
+		algorithm = gateways.ChecksumSHA256
+	case 128:
+		algorithm = gateways.ChecksumSHA512
+	default:
+		return "", fmt.Errorf("%w: invalid checksum length %d in %s: expected 40 (SHA1), 64 (SHA256) or 128 (SHA512) hex characters",
+			errMalformedChecksum, len(checksum), filepath.Base(checksumFile))
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(checksumFile)), ".")
+	if (ext == gateways.ChecksumSHA1 || ext == gateways.ChecksumSHA256 || ext == gateways.ChecksumSHA512) && ext != algorithm {
+		return "", fmt.Errorf("checksum file %s has .%s extension but contains a %s checksum",
+			filepath.Base(checksumFile), ext, strings.ToUpper(algorithm))
+	}
+
+	return algorithm, nil
+}
+
+func verifyGPGSignature(ctx context.Context, filePath, gpgSig string, gpgKeys gpgKeySources) error {
+	gpgVerifier, err := newKeyedGPGVerifier(ctx, gpgKeys)
+	if err != nil {
+		return err
+	}
 
-	// Verify using the gateway (pure Go crypto/sha256)
-	if err := verifier.VerifyChecksum(ctx, filePath, expectedChecksum); err != nil {
+	if err := gpgVerifier.VerifySignatureFromFile(filePath, gpgSig); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func verifyGPGSignature(ctx context.Context, filePath, gpgSig, gpgKeyIDs, gpgKeysURL string) error {
-	gpgVerifier := gpg.NewVerifier()
+// newKeyedGPGVerifier creates a GPG verifier with keys imported from the given sources
+func newKeyedGPGVerifier(ctx context.Context, gpgKeys gpgKeySources) (*gpg.Verifier, error) {
+	if gpgKeys.Offline {
+		if err := checkOfflineKeySources(gpgKeys); err != nil {
+			return nil, err
+		}
+	}
+	gpgVerifier := gpg.NewVerifier().WithHTTPClient(gateways.NewHTTPClient(30 * time.Second)).WithKeyservers(gpgKeys.Keyservers)
 
 	// Import keys if specified
-	if gpgKeyIDs != "" {
-		keyIDList := strings.Split(gpgKeyIDs, ",")
-		if err := gpgVerifier.ImportKeys(ctx, keyIDList); err != nil {
-			return fmt.Errorf("failed to import GPG keys: %w", err)
+	if gpgKeys.IDs != "" && gpgKeys.Keyring != "" {
+		if err := gpgVerifier.ImportKeysFromKeyring(gpgKeys.Keyring, strings.Split(gpgKeys.IDs, ",")); err != nil {
+			return nil, fmt.Errorf("failed to import GPG keys: %w", err)
 		}
-	} else if gpgKeysURL != "" {
-		if err := gpgVerifier.ImportKeysFromURL(ctx, gpgKeysURL); err != nil {
-			return fmt.Errorf("failed to import GPG keys from URL: %w", err)
+	} else if gpgKeys.IDs != "" {
+		keyIDList := strings.Split(gpgKeys.IDs, ",")
+		if err := gpgVerifier.ImportKeysVia(ctx, keyIDList, gpgKeys.Lookup); err != nil {
+			return nil, fmt.Errorf("failed to import GPG keys: %w", err)
+		}
+	} else if gpgKeys.URL != "" {
+		if err := gpgVerifier.ImportKeysFromURL(ctx, gpgKeys.URL); err != nil {
+			return nil, fmt.Errorf("failed to import GPG keys from URL: %w", err)
+		}
+	}
+	if gpgKeys.File != "" {
+		if err := gpgVerifier.ImportKeyFromFile(gpgKeys.File); err != nil {
+			return nil, fmt.Errorf("failed to import GPG key file: %w", err)
 		}
 	}
 
 	if gpgVerifier.GetKeyringSize() == 0 {
-		return fmt.Errorf("no GPG keys imported for verification (use --gpg-key-ids or --gpg-keys-url)")
+		return nil, fmt.Errorf("no GPG keys imported for verification (use --gpg-key-ids, --gpg-keys-url or --gpg-key-file)")
 	}
 
-	if err := gpgVerifier.VerifySignatureFromFile(filePath, gpgSig); err != nil {
+	return gpgVerifier, nil
+}
+
+// checkOfflineKeySources rejects key sources that would need a network fetch
+func checkOfflineKeySources(gpgKeys gpgKeySources) error {
+	switch {
+	case gpgKeys.IDs != "" && gpgKeys.Keyring == "":
+		return fmt.Errorf("--offline: --gpg-key-ids would be fetched from keyservers; pass --gpg-keyring to take them from a local keyring")
+	case gpgKeys.URL != "":
+		return fmt.Errorf("--offline: --gpg-keys-url needs a network fetch; use --gpg-key-file or --gpg-keyring")
+	case gpgKeys.Lookup.WKDEmail != "" || gpgKeys.Lookup.KeybaseUser != "":
+		return fmt.Errorf("--offline: WKD and Keybase lookups need a network fetch")
+	}
+	return nil
+}
+
+// executeManifestVerify checks the manifest's GPG signature, then verifies each file's
+// SHA256 against the manifest entry for its basename
+func executeManifestVerify(ctx context.Context, manifestPath, manifestSig string, gpgKeys gpgKeySources, files []string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("at least one file to verify against the manifest is required")
+	}
+	if manifestSig == "" {
+		manifestSig = manifestPath + ".asc"
+	}
+
+	fmt.Printf("🔍 Verifying manifest %s\n\n", filepath.Base(manifestPath))
+
+	fmt.Printf("🔐 Verifying manifest signature...\n")
+	if err := verifyGPGSignature(ctx, manifestPath, manifestSig, gpgKeys); err != nil {
+		fmt.Printf("❌ Manifest signature verification FAILED: %v\n\n", err)
+		return fmt.Errorf("manifest signature verification failed: %w", err)
+	}
+	fmt.Printf("✅ Manifest signature verified\n\n")
+
+	//nolint:gosec // G304: manifestPath is user-provided for verification
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+	sums, err := services.ParseChecksumManifest(data)
+	if err != nil {
 		return err
 	}
 
+	verifier := gateways.NewChecksumVerifier()
+	failed := 0
+	for _, file := range files {
+		expected, ok := sums[filepath.Base(file)]
+		if !ok {
+			fmt.Printf("❌ %s: not listed in manifest\n", filepath.Base(file))
+			failed++
+			continue
+		}
+		if err := verifier.VerifyChecksum(ctx, file, expected); err != nil {
+			fmt.Printf("❌ %s: %v\n", filepath.Base(file), err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ %s\n", filepath.Base(file))
+	}
+
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("✅ Verified: %d files\n", len(files)-failed)
+	if failed > 0 {
+		fmt.Printf("❌ Failed: %d files\n", failed)
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	if failed > 0 {
+		return fmt.Errorf("%d files failed manifest verification", failed)
+	}
 	return nil
 }
 
-func verifyCosignSignature(ctx context.Context, filePath, cosignSig, cosignCert, cosignIdentity string) error {
+func verifyCosignSignature(ctx context.Context, filePath, cosignSig, cosignCert, cosignBundle, cosignIdentity string) error {
 	if !cosign.IsCosignInstalled() {
 		return fmt.Errorf("cosign not installed (install from https://docs.sigstore.dev/cosign/installation/)")
 	}
 
 	cosignVerifier := cosign.NewVerifier()
 
+	// A bundle carries its own signature and certificate
+	if cosignBundle != "" {
+		return cosignVerifier.VerifyBundle(ctx, filePath, cosignBundle, cosignIdentity)
+	}
+
 	if cosignCert == "" {
 		return fmt.Errorf("cosign certificate required (use --cosign-cert)")
 	}
@@ -254,27 +567,102 @@ func verifyCosignSignature(ctx context.Context, filePath, cosignSig, cosignCert,
 	return nil
 }
 
-func verifyAttestation(ctx context.Context, filePath, attestFile, attestOwner, attestRepo string) error {
+// verifyAttestation checks an attestation with gh, reporting signed; offline, only the attestation
+// file's subject digest is matched against the artifact, which does not verify its signature
+func verifyAttestation(ctx context.Context, filePath, attestFile, attestOwner, attestRepo string, offline bool) (signed bool, err error) {
+	if offline {
+		return false, attestation.NewVerifier().VerifyLocalAttestation(filePath, attestFile)
+	}
+
 	if !attestation.IsGHCLIInstalled() {
-		return fmt.Errorf("gh CLI not installed (install from https://cli.github.com)")
+		return false, fmt.Errorf("gh CLI not installed (install from https://cli.github.com)")
 	}
 
 	attestVerifier := attestation.NewVerifier()
-
-	var err error
 	if attestOwner != "" && attestRepo != "" {
 		err = attestVerifier.VerifyAttestationWithGH(ctx, filePath, attestOwner, attestRepo)
 	} else {
 		err = attestVerifier.VerifyAttestation(ctx, filePath, attestFile)
 	}
+	return err == nil, err
+}
 
+// verifyProvenance checks that a provenance statement names filePath's SHA-256 digest as a subject
+func verifyProvenance(filePath, provenanceFile string) error {
+	statement, err := attestation.LoadStatement(provenanceFile)
 	if err != nil {
 		return err
 	}
 
+	digest, err := gateways.NewChecksumVerifier().CalculateChecksum(filePath)
+	if err != nil {
+		return err
+	}
+	if !statement.MatchesDigest("sha256", digest) {
+		return fmt.Errorf("no provenance subject matches sha256:%s", digest)
+	}
 	return nil
 }
 
+// printProvenanceFile pretty-prints the in-toto statement in path (plain or DSSE-wrapped)
+func printProvenanceFile(w io.Writer, path string) {
+	statement, err := attestation.LoadStatement(path)
+	if err != nil {
+		fmt.Fprintf(w, "⚠️  Cannot display provenance: %v\n\n", err)
+		return
+	}
+	printProvenance(w, statement)
+}
+
+// printProvenance writes the statement's subjects and predicate builder, build type and materials
+func printProvenance(w io.Writer, statement *attestation.Statement) {
+	fmt.Fprintf(w, "📄 Provenance\n")
+	fmt.Fprintf(w, "   Statement:      %s\n", statement.Type)
+	if statement.PredicateType != "" {
+		fmt.Fprintf(w, "   Predicate type: %s\n", statement.PredicateType)
+	}
+	fmt.Fprintf(w, "   Subjects:\n")
+	for _, subject := range statement.Subject {
+		fmt.Fprintf(w, "     - %s\n", subject.Name)
+		for _, algorithm := range sortedKeys(subject.Digest) {
+			fmt.Fprintf(w, "       %s:%s\n", algorithm, subject.Digest[algorithm])
+		}
+	}
+	predicate := statement.Predicate
+	builder, buildType, materials := predicate.Builder.ID, predicate.BuildType, predicate.Materials
+	if statement.PredicateType == attestation.SLSAProvenanceV1 {
+		builder = predicate.RunDetails.Builder.ID
+		buildType = predicate.BuildDefinition.BuildType
+		materials = predicate.BuildDefinition.ResolvedDependencies
+	}
+	if builder != "" {
+		fmt.Fprintf(w, "   Builder:        %s\n", builder)
+	}
+	if buildType != "" {
+		fmt.Fprintf(w, "   Build type:     %s\n", buildType)
+	}
+	if len(materials) > 0 {
+		fmt.Fprintf(w, "   Materials:\n")
+		for _, material := range materials {
+			fmt.Fprintf(w, "     - %s\n", material.URI)
+			for _, algorithm := range sortedKeys(material.Digest) {
+				fmt.Fprintf(w, "       %s:%s\n", algorithm, material.Digest[algorithm])
+			}
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// sortedKeys returns a map's keys in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
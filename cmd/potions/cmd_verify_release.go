@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/domain/entities"
+	domainGateways "github.com/ochairo/potions/internal/domain/interfaces/gateways"
+	"github.com/ochairo/potions/internal/external-adapters/attestation"
+	"github.com/ochairo/potions/internal/external-adapters/cosign"
+)
+
+// releaseSiblingSuffixes are the verification files published next to each release tarball
+var releaseSiblingSuffixes = []string{
+	".sha256", ".sha512", ".asc", ".sig", ".pem", ".bundle",
+	".attestation.jsonl", ".provenance.json", ".intoto.jsonl",
+}
+
+func runVerifyRelease(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("verify-release", flag.ExitOnError)
+	var (
+		owner          = fs.String("owner", "ochairo", "GitHub repository owner")
+		repo           = fs.String("repo", "potions", "GitHub repository name")
+		gpgKeyIDs      = fs.String("gpg-key-ids", "", "Comma-separated GPG key IDs to import for .asc signatures")
+		gpgKeysURL     = fs.String("gpg-keys-url", "", "URL to KEYS file for GPG verification")
+		gpgKeyFile     = fs.String("gpg-key-file", "", "Local public key file for GPG verification")
+		gpgKeyring     = fs.String("gpg-keyring", "", "Local keyring file that --gpg-key-ids are taken from instead of keyservers")
+		cosignIdentity = fs.String("cosign-identity", "", "Expected certificate identity for Cosign signatures")
+		offline        = fs.Bool("offline", false, "Check attestations against the downloaded files instead of querying gh")
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: potions verify-release <package> <version> [options]
+
+Download every tarball of a published release together with its checksum,
+signature and attestation files, and verify each tarball with all of them.
+
+GPG signatures are checked when GPG keys are given, Cosign signatures when
+cosign is installed and attestations when gh is installed; otherwise those
+checks are reported as skipped. With --offline an attestation's digest is
+matched without verifying its signature, which alone does not verify a tarball.
+A tarball backed only by checksums and unsigned provenance from the same
+release is reported as checksum only, since they prove the download is intact
+but not who built it.
+
+Arguments:
+  package    Package name (required)
+  version    Released version (required)
+
+Options:
+`)
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, `
+Environment Variables:
+  GITHUB_TOKEN              GitHub token (optional, raises API rate limits)
+
+Examples:
+  potions verify-release kubectl 1.28.0
+  potions verify-release kubectl 1.28.0 --gpg-key-file release-key.asc
+  potions verify-release tool 2.1.0 --owner myorg --repo binaries --offline
+`)
+	}
+
+	applyFlagDefaults(fs, flagDefaults)
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 2 {
+		fmt.Fprintf(os.Stderr, "Error: package name and version are required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	opts := releaseVerifyOptions{
+		GPGKeys:        gpgKeySources{IDs: *gpgKeyIDs, URL: *gpgKeysURL, File: *gpgKeyFile, Keyring: *gpgKeyring},
+		CosignIdentity: *cosignIdentity,
+		Offline:        *offline,
+	}
+	githubGW, err := newGitHubGateway(os.Getenv("GITHUB_TOKEN"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	_, err = executeVerifyRelease(ctx, os.Stdout, githubGW, gateways.NewDownloader(), *owner, *repo, fs.Arg(0), fs.Arg(1), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// releaseVerifyOptions holds the key material and tool choices for verify-release
+type releaseVerifyOptions struct {
+	GPGKeys        gpgKeySources
+	CosignIdentity string
+	Offline        bool
+}
+
+// assetCheck is the outcome of one verification of a release tarball
+type assetCheck struct {
+	Name       string
+	Err        error
+	Skipped    string // Reason the check could not run
+	DigestOnly bool   // Matched an unsigned statement's digest without verifying its signature; not a pass
+	Integrity  bool   // A checksum published in the same release: proves the download intact, not who built it
+}
+
+// assetVerification collects the checks run against one release tarball
+type assetVerification struct {
+	Asset  string
+	Checks []assetCheck
+}
+
+// Failed reports whether any check failed or none could confirm the tarball
+func (a assetVerification) Failed() bool {
+	passed := 0
+	for _, check := range a.Checks {
+		if check.Err != nil {
+			return true
+		}
+		if check.Skipped == "" && !check.DigestOnly {
+			passed++
+		}
+	}
+	return passed == 0
+}
+
+// ChecksumOnly reports whether the tarball passed on checksums alone, with no signature
+// or signed attestation verified
+func (a assetVerification) ChecksumOnly() bool {
+	if a.Failed() {
+		return false
+	}
+	for _, check := range a.Checks {
+		if check.Skipped == "" && !check.DigestOnly && !check.Integrity {
+			return false
+		}
+	}
+	return true
+}
+
+// executeVerifyRelease downloads each tarball of the package's release with its sibling
+// verification files, runs every applicable check and prints a per-asset report; it fails
+// when any tarball fails a check or has nothing to verify it with
+func executeVerifyRelease(ctx context.Context, w io.Writer, githubGW domainGateways.GitHubGateway, downloader *gateways.Downloader,
+	owner, repo, packageName, version string, opts releaseVerifyOptions) ([]assetVerification, error) {
+	var release *domainGateways.GitHubRelease
+	var err error
+	for _, tag := range entities.ReleaseTagCandidates(packageName, version) {
+		if release, err = githubGW.GetRelease(ctx, owner, repo, tag); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %s: %w", entities.ReleaseTag(packageName, version), err)
+	}
+	tag := release.TagName
+	fmt.Fprintf(w, "🔍 Verifying release %s in %s/%s\n", tag, owner, repo)
+	assets, err := githubGW.ListReleaseAssets(ctx, owner, repo, release.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list release assets: %w", err)
+	}
+	byName := make(map[string]*domainGateways.GitHubAsset, len(assets))
+	var tarballs []string
+	for _, asset := range assets {
+		byName[asset.Name] = asset
+		if strings.HasSuffix(asset.Name, ".tar.gz") {
+			tarballs = append(tarballs, asset.Name)
+		}
+	}
+	sort.Strings(tarballs)
+	if len(tarballs) == 0 {
+		return nil, fmt.Errorf("release %s has no .tar.gz assets", tag)
+	}
+	fmt.Fprintf(w, "📦 Found %d tarballs\n", len(tarballs))
+
+	downloadDir, err := os.MkdirTemp("", "potions-verify-release-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	//nolint:errcheck // Best effort cleanup of temp directory
+	defer os.RemoveAll(downloadDir)
+
+	reports := make([]assetVerification, 0, len(tarballs))
+	for _, name := range tarballs {
+		report := assetVerification{Asset: name}
+		filePath := filepath.Join(downloadDir, name)
+		if err := downloadReleaseAsset(ctx, downloader, byName[name], filePath); err != nil {
+			report.Checks = append(report.Checks, assetCheck{Name: "download", Err: err})
+		} else {
+			siblings := make(map[string]string)
+			for _, suffix := range releaseSiblingSuffixes {
+				sibling, ok := byName[name+suffix]
+				if !ok {
+					continue
+				}
+				if err := downloadReleaseAsset(ctx, downloader, sibling, filePath+suffix); err != nil {
+					report.Checks = append(report.Checks, assetCheck{Name: "download " + sibling.Name, Err: err})
+					continue
+				}
+				siblings[suffix] = filePath + suffix
+			}
+			report.Checks = append(report.Checks, verifyReleaseTarball(ctx, filePath, siblings, owner, repo, opts)...)
+		}
+		printAssetVerification(w, report)
+		reports = append(reports, report)
+	}
+
+	failed, checksumOnly := 0, 0
+	for _, report := range reports {
+		if report.Failed() {
+			failed++
+		} else if report.ChecksumOnly() {
+			checksumOnly++
+		}
+	}
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintf(w, "✅ Verified: %d assets\n", len(reports)-failed-checksumOnly)
+	if checksumOnly > 0 {
+		fmt.Fprintf(w, "⚠️  Checksum only: %d assets\n", checksumOnly)
+	}
+	if failed > 0 {
+		fmt.Fprintf(w, "❌ Failed: %d assets\n", failed)
+	}
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	return reports, releaseVerifyError(reports)
+}
+
+// downloadReleaseAsset downloads asset to dest
+func downloadReleaseAsset(ctx context.Context, downloader *gateways.Downloader, asset *domainGateways.GitHubAsset, dest string) error {
+	if asset.BrowserDownloadURL == "" {
+		return fmt.Errorf("%s has no download URL", asset.Name)
+	}
+	return downloader.DownloadFile(ctx, asset.BrowserDownloadURL, dest)
+}
+
+// verifyReleaseTarball runs the verify checks for which a sibling file was downloaded, keyed by suffix
+func verifyReleaseTarball(ctx context.Context, filePath string, siblings map[string]string, owner, repo string, opts releaseVerifyOptions) []assetCheck {
+	var checks []assetCheck
+	for _, suffix := range []string{".sha256", ".sha512"} {
+		if checksumFile, ok := siblings[suffix]; ok {
+			checks = append(checks, assetCheck{Name: "checksum " + strings.TrimPrefix(suffix, "."),
+				Err: verifyChecksum(ctx, filePath, checksumFile, false), Integrity: true})
+		}
+	}
+
+	if gpgSig, ok := siblings[".asc"]; ok {
+		check := assetCheck{Name: "GPG signature"}
+		if opts.GPGKeys.IDs == "" && opts.GPGKeys.URL == "" && opts.GPGKeys.File == "" {
+			check.Skipped = "no GPG keys given (use --gpg-key-ids, --gpg-keys-url or --gpg-key-file)"
+		} else {
+			check.Err = verifyGPGSignature(ctx, filePath, gpgSig, opts.GPGKeys)
+		}
+		checks = append(checks, check)
+	}
+
+	cosignBundle, hasBundle := siblings[".bundle"]
+	cosignSig, hasSig := siblings[".sig"]
+	if hasBundle || hasSig {
+		check := assetCheck{Name: "Cosign signature"}
+		if !cosign.IsCosignInstalled() {
+			check.Skipped = "cosign not installed"
+		} else {
+			check.Err = verifyCosignSignature(ctx, filePath, cosignSig, siblings[".pem"], cosignBundle, opts.CosignIdentity)
+		}
+		checks = append(checks, check)
+	}
+
+	if attestFile, ok := siblings[".attestation.jsonl"]; ok {
+		check := assetCheck{Name: "attestation"}
+		if !opts.Offline && !attestation.IsGHCLIInstalled() {
+			check.Skipped = "gh CLI not installed (use --offline to check the attestation file)"
+		} else {
+			var signed bool
+			signed, check.Err = verifyAttestation(ctx, filePath, attestFile, owner, repo, opts.Offline)
+			check.DigestOnly = check.Err == nil && !signed
+		}
+		checks = append(checks, check)
+	}
+
+	if provenanceFile, ok := siblings[".provenance.json"]; ok {
+		// The statement is unsigned and uploaded with the tarball, so a match only shows consistency
+		err := verifyProvenance(filePath, provenanceFile)
+		checks = append(checks, assetCheck{Name: "provenance", Err: err, DigestOnly: err == nil})
+	}
+
+	if slsaFile, ok := siblings[".intoto.jsonl"]; ok {
+		_, err := attestation.NewVerifier().VerifySLSAProvenance(ctx, filePath, slsaFile, attestation.SLSAPolicy{})
+		checks = append(checks, assetCheck{Name: "SLSA provenance", Err: err, DigestOnly: err == nil})
+	}
+	return checks
+}
+
+// printAssetVerification writes one tarball's check results
+func printAssetVerification(w io.Writer, report assetVerification) {
+	fmt.Fprintf(w, "\n📦 %s\n", report.Asset)
+	if len(report.Checks) == 0 {
+		fmt.Fprintf(w, "  ❌ no checksum, signature or provenance published\n")
+	}
+	for _, check := range report.Checks {
+		switch {
+		case check.Err != nil:
+			fmt.Fprintf(w, "  ❌ %s: %v\n", check.Name, check.Err)
+		case check.Skipped != "":
+			fmt.Fprintf(w, "  ⏭️  %s: skipped, %s\n", check.Name, check.Skipped)
+		case check.DigestOnly:
+			fmt.Fprintf(w, "  ⚠️  %s: digest match only (signature not verified)\n", check.Name)
+		default:
+			fmt.Fprintf(w, "  ✅ %s\n", check.Name)
+		}
+	}
+	if report.ChecksumOnly() {
+		fmt.Fprintf(w, "  ⚠️  checksum only: no signature or signed attestation verified\n")
+	}
+}
+
+// releaseVerifyError lists the assets that failed verification, or returns nil
+func releaseVerifyError(reports []assetVerification) error {
+	var failed []string
+	for _, report := range reports {
+		if report.Failed() {
+			failed = append(failed, report.Asset)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d assets failed verification: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
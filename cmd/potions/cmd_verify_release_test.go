@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	domainGateways "github.com/ochairo/potions/internal/domain/interfaces/gateways"
+	"github.com/ochairo/potions/internal/external-adapters/gpg"
+	"github.com/ochairo/potions/internal/testsupport"
+)
+
+// Test verify-release checks every tarball with its published siblings and flags a tampered one
+func TestExecuteVerifyRelease(t *testing.T) {
+	ctx := context.Background()
+	sha := func(data string) string {
+		sum := sha256.Sum256([]byte(data))
+		return hex.EncodeToString(sum[:])
+	}
+
+	good := "tool-1.0.0-linux-x86_64.tar.gz"
+	provenanced := "tool-1.0.0-linux-riscv64.tar.gz"
+	tampered := "tool-1.0.0-darwin-arm64.tar.gz"
+	bare := "tool-1.0.0-linux-arm64.tar.gz"
+	checksummed := "tool-1.0.0-darwin-x86_64.tar.gz"
+	// good is GPG-signed; the release's own checksums and unsigned provenance do not verify a tarball
+	keyDir := t.TempDir()
+	privPath, pubPath := writeTestSigningKeys(t, keyDir)
+	signer, err := gpg.NewSignerFromFile(privPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goodPath := filepath.Join(keyDir, good)
+	if err := os.WriteFile(goodPath, []byte("good payload"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.SignDetachedFile(goodPath, goodPath+".asc"); err != nil {
+		t.Fatal(err)
+	}
+	goodSig, err := os.ReadFile(goodPath + ".asc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	published := map[string]string{
+		good:                    "good payload",
+		good + ".sha256":        sha("good payload") + "  " + good + "\n",
+		good + ".asc":           string(goodSig),
+		provenanced:             "provenanced payload",
+		provenanced + ".sha256": sha("provenanced payload") + "  " + provenanced + "\n",
+		provenanced + ".provenance.json": fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":%q,"digest":{"sha256":%q}}]}`,
+			provenanced, sha("provenanced payload")),
+		tampered:                "original payload",
+		tampered + ".sha256":    sha("original payload") + "  " + tampered + "\n",
+		bare:                    "bare payload",
+		checksummed:             "checksummed payload",
+		checksummed + ".sha256": sha("checksummed payload") + "  " + checksummed + "\n",
+	}
+	served := map[string]string{tampered: "tampered payload"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := path.Base(r.URL.Path)
+		content, ok := served[name]
+		if !ok {
+			content, ok = published[name]
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	gw := testsupport.NewFakeGitHubGateway()
+	gw.ServeDownloadsFrom(server.URL)
+	release, err := gw.CreateRelease(ctx, "o", "r", &domainGateways.GitHubRelease{TagName: "tool-v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range published {
+		if _, err := gw.UploadAsset(ctx, release.UploadURL, name, strings.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	reports, err := executeVerifyRelease(ctx, &out, gw, gateways.NewDownloader(), "o", "r", "tool", "1.0.0", releaseVerifyOptions{GPGKeys: gpgKeySources{File: pubPath}})
+	if err == nil {
+		t.Fatal("executeVerifyRelease() should fail when an asset is tampered")
+	}
+	if !strings.Contains(err.Error(), tampered) || !strings.Contains(err.Error(), bare) ||
+		strings.Contains(err.Error(), good) || strings.Contains(err.Error(), provenanced) || strings.Contains(err.Error(), checksummed) {
+		t.Errorf("error = %q, want the tampered and unverifiable assets only", err)
+	}
+
+	if len(reports) != 5 {
+		t.Fatalf("got %d asset reports, want 5:\n%s", len(reports), out.String())
+	}
+	byAsset := make(map[string]assetVerification)
+	for _, report := range reports {
+		byAsset[report.Asset] = report
+	}
+	if report := byAsset[good]; report.Failed() || report.ChecksumOnly() || len(report.Checks) != 2 {
+		t.Errorf("%s checks = %+v, want passing checksum and GPG signature checks", good, report.Checks)
+	}
+	if report := byAsset[provenanced]; report.Failed() || !report.ChecksumOnly() || len(report.Checks) != 2 || !report.Checks[1].DigestOnly {
+		t.Errorf("%s checks = %+v, want a checksum and a digest-only provenance match", provenanced, report.Checks)
+	}
+	if report := byAsset[tampered]; !report.Failed() || report.Checks[0].Err == nil {
+		t.Errorf("%s checks = %+v, want a failed checksum", tampered, report.Checks)
+	}
+	if report := byAsset[bare]; !report.Failed() || len(report.Checks) != 0 {
+		t.Errorf("%s checks = %+v, want no checks", bare, report.Checks)
+	}
+	if report := byAsset[checksummed]; report.Failed() || !report.ChecksumOnly() {
+		t.Errorf("%s checks = %+v, want a checksum-only pass", checksummed, report.Checks)
+	}
+	for _, want := range []string{"✅ Verified: 1 assets", "⚠️  Checksum only: 2 assets", "❌ Failed: 2 assets"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
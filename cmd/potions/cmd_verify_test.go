@@ -0,0 +1,419 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	//nolint:gosec // G505: SHA1 test fixture
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+
+	"github.com/ochairo/potions/internal/domain/interfaces"
+	"github.com/ochairo/potions/internal/domain/services"
+	"github.com/ochairo/potions/internal/external-adapters/attestation"
+	"github.com/ochairo/potions/internal/external-adapters/gpg"
+)
+
+// writeChecksumFixture writes a payload and a checksum file with the given content
+func writeChecksumFixture(t *testing.T, checksumName, checksum string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "tool.tar.gz")
+	if err := os.WriteFile(filePath, []byte("payload"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checksumFile := filepath.Join(dir, checksumName)
+	if err := os.WriteFile(checksumFile, []byte(checksum+"  tool.tar.gz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return filePath, checksumFile
+}
+
+// Test verifying a SHA256 checksum file
+func TestVerifyChecksum_SHA256(t *testing.T) {
+	sum := sha256.Sum256([]byte("payload"))
+	filePath, checksumFile := writeChecksumFixture(t, "tool.tar.gz.sha256", hex.EncodeToString(sum[:]))
+
+	if err := verifyChecksum(context.Background(), filePath, checksumFile, false); err != nil {
+		t.Errorf("verifyChecksum() error = %v", err)
+	}
+}
+
+// Test verifying a SHA512 checksum file
+func TestVerifyChecksum_SHA512(t *testing.T) {
+	sum := sha512.Sum512([]byte("payload"))
+	filePath, checksumFile := writeChecksumFixture(t, "tool.tar.gz.sha512", hex.EncodeToString(sum[:]))
+
+	if err := verifyChecksum(context.Background(), filePath, checksumFile, false); err != nil {
+		t.Errorf("verifyChecksum() error = %v", err)
+	}
+}
+
+// Test a checksum with an unexpected length is rejected clearly
+func TestVerifyChecksum_MalformedLength(t *testing.T) {
+	filePath, checksumFile := writeChecksumFixture(t, "tool.tar.gz.sha256", "abc123")
+
+	err := verifyChecksum(context.Background(), filePath, checksumFile, false)
+	if err == nil {
+		t.Fatal("expected error for malformed checksum length")
+	}
+	if !strings.Contains(err.Error(), "invalid checksum length 6") {
+		t.Errorf("error = %v, want invalid checksum length", err)
+	}
+}
+
+// Test a non-hex checksum is reported as a malformed checksum file rather than a mismatch
+func TestVerifyChecksum_NonHex(t *testing.T) {
+	filePath, checksumFile := writeChecksumFixture(t, "tool.tar.gz.sha256", strings.Repeat("z", 64))
+
+	err := verifyChecksum(context.Background(), filePath, checksumFile, false)
+	if !errors.Is(err, errMalformedChecksum) || !strings.Contains(err.Error(), "not a hex checksum") {
+		t.Errorf("error = %v, want malformed non-hex checksum", err)
+	}
+}
+
+// Test a truncated checksum is reported as a malformed checksum file rather than a mismatch
+func TestVerifyChecksum_WrongLength(t *testing.T) {
+	sum := sha256.Sum256([]byte("payload"))
+	filePath, checksumFile := writeChecksumFixture(t, "tool.tar.gz.sha256", hex.EncodeToString(sum[:])[:63])
+
+	err := verifyChecksum(context.Background(), filePath, checksumFile, false)
+	if !errors.Is(err, errMalformedChecksum) || !strings.Contains(err.Error(), "invalid checksum length 63") {
+		t.Errorf("error = %v, want malformed checksum length", err)
+	}
+}
+
+// Test a SHA1 checksum (40 hex characters) is rejected unless weak checksums are allowed
+func TestVerifyChecksum_SHA1(t *testing.T) {
+	//nolint:gosec // G401: Test fixture for upstreams publishing SHA1 sums
+	sum := sha1.Sum([]byte("payload"))
+	filePath, checksumFile := writeChecksumFixture(t, "tool.tar.gz.sha1", hex.EncodeToString(sum[:]))
+
+	err := verifyChecksum(context.Background(), filePath, checksumFile, false)
+	if err == nil || !strings.Contains(err.Error(), "--allow-weak-checksum") {
+		t.Errorf("verifyChecksum() error = %v, want weak checksum rejection", err)
+	}
+	if err := verifyChecksum(context.Background(), filePath, checksumFile, true); err != nil {
+		t.Errorf("verifyChecksum() with allowWeak error = %v", err)
+	}
+}
+
+// Test a checksum whose length contradicts the file extension is rejected
+func TestVerifyChecksum_ExtensionMismatch(t *testing.T) {
+	sum := sha512.Sum512([]byte("payload"))
+	filePath, checksumFile := writeChecksumFixture(t, "tool.tar.gz.sha256", hex.EncodeToString(sum[:]))
+
+	err := verifyChecksum(context.Background(), filePath, checksumFile, false)
+	if err == nil {
+		t.Fatal("expected error for extension mismatch")
+	}
+	if !strings.Contains(err.Error(), ".sha256 extension but contains a SHA512 checksum") {
+		t.Errorf("error = %v, want extension mismatch", err)
+	}
+}
+
+// writeTestSigningKeys generates a signing key and writes armored private/public key files
+func writeTestSigningKeys(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Potions Release", "", "release@example.com",
+		&packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(name, blockType string, serialize func(w io.Writer) error) string {
+		var buf bytes.Buffer
+		w, err := armor.Encode(&buf, blockType, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := serialize(w); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	privPath := write("private.asc", openpgp.PrivateKeyType, func(w io.Writer) error {
+		return entity.SerializePrivateWithoutSigning(w, nil)
+	})
+	pubPath := write("public.asc", openpgp.PublicKeyType, func(w io.Writer) error {
+		return entity.Serialize(w)
+	})
+	return privPath, pubPath
+}
+
+// Test a signed SHA256SUMS manifest verifies its signature and then each file
+func TestExecuteManifestVerify(t *testing.T) {
+	dir := t.TempDir()
+	privPath, pubPath := writeTestSigningKeys(t, dir)
+
+	var files []string
+	for _, name := range []string{"tool-1.0.0-linux-amd64.tar.gz", "tool-1.0.0-darwin-arm64.tar.gz"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+
+	signer, err := gpg.NewSignerFromFile(privPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets, err := signedManifestAssets(dir, files, signer)
+	if err != nil {
+		t.Fatalf("signedManifestAssets() error = %v", err)
+	}
+	manifestPath := assets[0]
+	if filepath.Base(assets[1]) != "SHA256SUMS.asc" {
+		t.Errorf("signature asset = %s, want SHA256SUMS.asc", filepath.Base(assets[1]))
+	}
+
+	keys := gpgKeySources{File: pubPath}
+	if err := executeManifestVerify(context.Background(), manifestPath, "", keys, files); err != nil {
+		t.Fatalf("executeManifestVerify() error = %v", err)
+	}
+
+	// A modified artifact must fail against the signed manifest
+	if err := os.WriteFile(files[0], []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := executeManifestVerify(context.Background(), manifestPath, "", keys, files); err == nil {
+		t.Error("expected failure for tampered artifact")
+	}
+
+	// A modified manifest must fail signature verification
+	if err := os.WriteFile(manifestPath, []byte(strings.Repeat("0", 64)+"  tool-1.0.0-linux-amd64.tar.gz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = executeManifestVerify(context.Background(), manifestPath, "", keys, files[:1])
+	if err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("expected manifest signature failure, got %v", err)
+	}
+}
+
+// Test generated provenance verifies against its artifact and prints builder and subject digest,
+// both as a plain statement and wrapped in a DSSE envelope
+func TestVerifyProvenance_Print(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "tool-1.0.0-linux-x86_64.tar.gz")
+	if err := os.WriteFile(filePath, []byte("payload"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	provenancePath, err := services.NewSecurityArtifactsService(&interfaces.NoOpLogger{}).
+		GenerateProvenance(context.Background(), filePath, "https://example.com/tool-1.0.0.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement, err := os.ReadFile(provenancePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := json.Marshal(map[string]interface{}{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString(statement),
+		"signatures":  []map[string]string{{"sig": "c2ln"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dssePath := filepath.Join(dir, "tool.dsse.json")
+	if err := os.WriteFile(dssePath, envelope, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte("payload"))
+	wantDigest := "sha256:" + hex.EncodeToString(sum[:])
+
+	for _, path := range []string{provenancePath, dssePath} {
+		if err := verifyProvenance(filePath, path); err != nil {
+			t.Errorf("verifyProvenance(%s) error = %v", filepath.Base(path), err)
+		}
+
+		var out bytes.Buffer
+		printProvenanceFile(&out, path)
+		for _, want := range []string{"Builder:        https://github.com/ochairo/potions", wantDigest, "https://example.com/tool-1.0.0.tar.gz"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("printProvenanceFile(%s) output missing %q:\n%s", filepath.Base(path), want, out.String())
+			}
+		}
+	}
+
+	if err := os.WriteFile(filePath, []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyProvenance(filePath, provenancePath); err == nil {
+		t.Error("verifyProvenance() should fail when no subject matches the file")
+	}
+}
+
+// Test a matching provenance statement alone is a digest match, not a verification
+func TestExecuteVerify_ProvenanceDigestOnly(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "tool-1.0.0-linux-x86_64.tar.gz")
+	if err := os.WriteFile(filePath, []byte("payload"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	provenancePath, err := services.NewSecurityArtifactsService(&interfaces.NoOpLogger{}).
+		GenerateProvenance(context.Background(), filePath, "https://example.com/tool-1.0.0.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = executeVerify(context.Background(), filePath, verifyOptions{ProvenanceFile: provenancePath})
+	if err == nil || !strings.Contains(err.Error(), "no signature was verified") {
+		t.Errorf("executeVerify() with only provenance error = %v, want digest-only failure", err)
+	}
+}
+
+// Test --all picks up a .bundle file and passes it to cosign verify-blob --bundle
+func TestExecuteVerify_CosignBundle(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub cosign is a shell script")
+	}
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	stub := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\n"
+	//nolint:gosec // G306: stub cosign must be executable
+	if err := os.WriteFile(filepath.Join(binDir, "cosign"), []byte(stub), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	dir := t.TempDir()
+	artifact := filepath.Join(dir, "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(artifact, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(artifact+".bundle", []byte(`{"mediaType":"application/vnd.dev.sigstore.bundle+json;version=0.2"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := executeVerify(context.Background(), artifact, verifyOptions{
+		CosignIdentity: "https://github.com/ochairo/potions/.github/workflows/build.yml@refs/heads/main",
+		VerifyAll:      true,
+	}); err != nil {
+		t.Fatalf("executeVerify() error = %v", err)
+	}
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("cosign was not invoked: %v", err)
+	}
+	want := strings.Join([]string{
+		"verify-blob",
+		"--bundle", artifact + ".bundle",
+		"--certificate-oidc-issuer", "https://token.actions.githubusercontent.com",
+		"--certificate-identity", "https://github.com/ochairo/potions/.github/workflows/build.yml@refs/heads/main",
+		artifact,
+	}, "\n") + "\n"
+	if string(data) != want {
+		t.Errorf("cosign args =\n%s\nwant\n%s", data, want)
+	}
+}
+
+// Test --offline verifies a signature and attestation from local files only, and refuses
+// key sources that would need a keyserver without making any request
+func TestExecuteVerify_Offline(t *testing.T) {
+	dir := t.TempDir()
+	privPath, pubPath := writeTestSigningKeys(t, dir)
+	artifact := filepath.Join(dir, "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(artifact, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := gpg.NewSignerFromFile(privPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.SignDetachedFile(artifact, artifact+".asc"); err != nil {
+		t.Fatal(err)
+	}
+	provenancePath, err := services.NewSecurityArtifactsService(&interfaces.NoOpLogger{}).
+		GenerateProvenance(context.Background(), artifact, "https://example.com/tool-1.0.0.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	keyserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		http.NotFound(w, nil)
+	}))
+	defer keyserver.Close()
+
+	t.Setenv("PATH", t.TempDir()) // No gh: attestations must be checked locally
+	keys := gpgKeySources{IDs: signer.KeyID(), Keyring: pubPath, Keyservers: []string{keyserver.URL}, Offline: true}
+	if err := executeVerify(context.Background(), artifact, verifyOptions{GPGSig: artifact + ".asc", GPGKeys: keys, AttestFile: provenancePath}); err != nil {
+		t.Fatalf("executeVerify() offline error = %v", err)
+	}
+
+	keys.Keyring = ""
+	err = verifyGPGSignature(context.Background(), artifact, artifact+".asc", keys)
+	if err == nil || !strings.Contains(err.Error(), "--gpg-keyring") {
+		t.Errorf("verifyGPGSignature() without keyring error = %v, want a --gpg-keyring hint", err)
+	}
+	if requests != 0 {
+		t.Errorf("keyserver received %d requests in offline mode, want 0", requests)
+	}
+
+	if _, err := verifyAttestation(context.Background(), filepath.Join(dir, "private.asc"), provenancePath, "", "", true); err == nil {
+		t.Error("verifyAttestation() offline should fail when no subject matches the file")
+	}
+
+	// A matching but unsigned attestation alone is not a pass
+	err = executeVerify(context.Background(), artifact, verifyOptions{GPGKeys: gpgKeySources{Offline: true}, AttestFile: provenancePath})
+	if err == nil || !strings.Contains(err.Error(), "no signature was verified") {
+		t.Errorf("executeVerify() with only an offline attestation error = %v, want digest-only failure", err)
+	}
+}
+
+// Test SLSA provenance alone only matches digests: its DSSE signature is never verified
+func TestExecuteVerify_SLSADigestOnly(t *testing.T) {
+	dir := t.TempDir()
+	artifact := filepath.Join(dir, "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(artifact, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("tarball"))
+	statement := fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v1",
+  "subject":[{"name":"tool-1.0.0-linux-amd64.tar.gz","digest":{"sha256":%q}}],
+  "predicateType":"https://slsa.dev/provenance/v1",
+  "predicate":{"buildDefinition":{"buildType":"https://github.com/slsa-framework/slsa-github-generator/generic@v1"},
+    "runDetails":{"builder":{"id":%q}}}}`,
+		hex.EncodeToString(sum[:]), attestation.GitHubGeneratorBuilderPrefix+"generator_generic_slsa3.yml@refs/tags/v2.0.0")
+	slsaPath := filepath.Join(dir, "tool.intoto.jsonl")
+	if err := os.WriteFile(slsaPath, []byte(statement), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := executeVerify(context.Background(), artifact, verifyOptions{SLSAFile: slsaPath})
+	if err == nil || !strings.Contains(err.Error(), "no signature was verified") {
+		t.Errorf("executeVerify() with only SLSA provenance error = %v, want digest-only failure", err)
+	}
+}
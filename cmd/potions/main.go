@@ -1,14 +1,28 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"path"
 	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain-adapters/gateways"
+	"github.com/ochairo/potions/internal/external-adapters/yaml"
 )
 
+// version is the build version, stamped at build time with
+// -ldflags "-X main.version=1.2.3"
+var version = "dev"
+
 func main() {
 	// Check args before setting up context to avoid defer warning
 	if len(os.Args) < 2 {
@@ -37,25 +51,53 @@ func run() int {
 		cancel()
 	}()
 
-	command := os.Args[1]
+	global, args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printUsage()
+		return 1
+	}
+	gateways.SetUserAgent(resolveUserAgent(global.UserAgent, os.Getenv("POTIONS_USER_AGENT"), version))
+	if global.MaxConcurrentDownloads != "" {
+		limit, err := strconv.Atoi(global.MaxConcurrentDownloads)
+		if err != nil || limit < 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid --max-concurrent-downloads %q (want a number, 0 for no limit)\n", global.MaxConcurrentDownloads)
+			return 1
+		}
+		gateways.SetMaxConcurrentDownloads(limit)
+	}
+	if flagDefaults, err = loadFlagDefaults(global.ConfigPath, os.Getenv); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	command := args[0]
 
 	// Dispatch to subcommand
 	// Note: Subcommands call os.Exit() internally on error
 	switch command {
 	case "build":
-		runBuild(ctx, os.Args[2:])
+		runBuild(ctx, args[1:])
 	case "list":
-		runList(ctx, os.Args[2:])
+		runList(ctx, args[1:])
+	case "graph":
+		runGraph(ctx, args[1:])
 	case "scan":
-		runScan(ctx, os.Args[2:])
+		runScan(ctx, args[1:])
 	case "verify":
-		runVerify(ctx, os.Args[2:])
+		runVerify(ctx, args[1:])
+	case "verify-release":
+		runVerifyRelease(ctx, args[1:])
 	case "monitor":
-		runMonitor(ctx, os.Args[2:])
+		runMonitor(ctx, args[1:])
 	case "release":
-		runRelease(ctx, os.Args[2:])
+		runRelease(ctx, args[1:])
 	case "validate-release":
-		runValidateRelease(ctx, os.Args[2:])
+		runValidateRelease(ctx, args[1:])
+	case "validate-recipe":
+		runValidateRecipe(ctx, args[1:])
+	case "version", "--version":
+		fmt.Printf("potions %s\n", version)
 	case "help", "-h", "--help":
 		printUsage()
 	default:
@@ -67,39 +109,317 @@ func run() int {
 	return 0
 }
 
+// globalOptions are the options accepted before the command name
+type globalOptions struct {
+	UserAgent              string
+	ConfigPath             string
+	MaxConcurrentDownloads string
+}
+
+// parseGlobalFlags consumes options given before the command name
+// and returns them plus the remaining arguments
+func parseGlobalFlags(args []string) (globalOptions, []string, error) {
+	var global globalOptions
+	targets := map[string]*string{"user-agent": &global.UserAgent, "config": &global.ConfigPath,
+		"max-concurrent-downloads": &global.MaxConcurrentDownloads}
+	for len(args) > 0 {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[0], "-"), "=")
+		target, ok := targets[name]
+		if !ok || !strings.HasPrefix(args[0], "-") {
+			return global, args, nil
+		}
+		if hasValue {
+			args = args[1:]
+		} else {
+			if len(args) < 2 {
+				return globalOptions{}, nil, fmt.Errorf("%s requires a value", args[0])
+			}
+			value = args[1]
+			args = args[2:]
+		}
+		*target = value
+	}
+	return globalOptions{}, nil, fmt.Errorf("no command given")
+}
+
+// flagDefaults holds the project defaults applied to every command's flags, keyed by config key
+var flagDefaults map[string]string
+
+// configFlagNames lists, per config key, the flag names commands use for that setting
+var configFlagNames = map[string][]string{
+	"recipes-dir": {"recipes-dir", "recipes"},
+	"owner":       {"owner", "repo-owner"},
+	"repo":        {"repo", "repo-name"},
+	"output-dir":  {"output-dir"},
+}
+
+// configEnvVars lists the environment variable overriding each config key
+var configEnvVars = map[string]string{
+	"recipes-dir": "POTIONS_RECIPES_DIR",
+	"owner":       "POTIONS_OWNER",
+	"repo":        "POTIONS_REPO",
+	"output-dir":  "POTIONS_OUTPUT_DIR",
+}
+
+// loadFlagDefaults reads configPath, or .potions.yaml when present and configPath is empty,
+// and overlays POTIONS_* environment variables on it
+func loadFlagDefaults(configPath string, getenv func(string) string) (map[string]string, error) {
+	defaults := make(map[string]string)
+	if configPath == "" {
+		if _, err := os.Stat(yaml.ProjectConfigFile); err == nil {
+			configPath = yaml.ProjectConfigFile
+		}
+	}
+	if configPath != "" {
+		config, err := yaml.LoadProjectConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		defaults = config.Values()
+	}
+	for key, env := range configEnvVars {
+		if value := strings.TrimSpace(getenv(env)); value != "" {
+			defaults[key] = value
+		}
+	}
+	return defaults, nil
+}
+
+// applyFlagDefaults replaces the built-in defaults of fs's flags with project defaults
+// Call it before fs.Parse so flags given on the command line still win
+func applyFlagDefaults(fs *flag.FlagSet, defaults map[string]string) {
+	for key, value := range defaults {
+		for _, name := range configFlagNames[key] {
+			if f := fs.Lookup(name); f != nil {
+				_ = f.Value.Set(value) // Every configurable flag is a string flag
+				f.DefValue = value
+			}
+		}
+	}
+}
+
+// resolveUserAgent picks the User-Agent: --user-agent, then POTIONS_USER_AGENT,
+// then potions/<version>
+func resolveUserAgent(flagValue, envValue, buildVersion string) string {
+	if ua := strings.TrimSpace(flagValue); ua != "" {
+		return ua
+	}
+	if ua := strings.TrimSpace(envValue); ua != "" {
+		return ua
+	}
+	return gateways.DefaultUserAgent(buildVersion)
+}
+
 func printUsage() {
 	fmt.Println(`potions - Automated binary builder and release manager
 
 Usage:
-  potions <command> [options]
+  potions [--user-agent UA] [--config FILE] [--max-concurrent-downloads N] <command> [options]
 
 Commands:
   build             Build binaries for one or more packages
   list              List available package recipes
+  graph             Graph packages, platforms and dependencies (DOT or Mermaid)
   scan              Run security scan on a package/binary
   verify            Verify checksums and signatures
+  verify-release    Download and verify every asset of a published release
   monitor           Check for version updates
   release           Create single or batch GitHub releases
   validate-release  Validate platform coverage for release
+  validate-recipe   Check recipes parse and their download templates are complete
+  version           Print the potions version
+
+Global options:
+  --user-agent UA   User-Agent for outbound HTTP requests
+                    (env: POTIONS_USER_AGENT, default: potions/<version>)
+  --config FILE     Project defaults for --recipes-dir, --owner, --repo and --output-dir
+                    (default: .potions.yaml when present; each is overridden by
+                    POTIONS_RECIPES_DIR, POTIONS_OWNER, POTIONS_REPO, POTIONS_OUTPUT_DIR,
+                    and all by the command's own flags)
+  --max-concurrent-downloads N
+                    Downloads allowed at once across all build workers
+                    (default: 4, 0 for no limit)
 
 Use "potions <command> --help" for more information about a command.`)
 }
 
 func detectPlatform() string {
-	os := runtime.GOOS
-	arch := runtime.GOARCH
+	return fmt.Sprintf("%s-%s", runtime.GOOS, mapGoArch(runtime.GOARCH))
+}
 
-	// Map Go's GOARCH to common platform names
+// mapGoArch maps Go's GOARCH to common platform architecture names
+func mapGoArch(arch string) string {
 	archMap := map[string]string{
-		"amd64": "x86_64",
-		"arm64": "arm64",
-		"386":   "i386",
+		"amd64":   "x86_64",
+		"arm64":   "arm64",
+		"386":     "i386",
+		"arm":     "armv7",
+		"ppc64le": "ppc64le",
+		"s390x":   "s390x",
+		"riscv64": "riscv64",
 	}
 
-	mappedArch := archMap[arch]
-	if mappedArch == "" {
-		mappedArch = arch
+	if mappedArch := archMap[arch]; mappedArch != "" {
+		return mappedArch
 	}
+	return arch
+}
 
-	return fmt.Sprintf("%s-%s", os, mappedArch)
+// resolvePlatform expands "auto" to the target platform, honoring Docker buildx
+// TARGETPLATFORM/TARGETOS/TARGETARCH before falling back to the host platform
+func resolvePlatform(platform string) string {
+	if platform != "auto" {
+		return platform
+	}
+
+	if target := os.Getenv("TARGETPLATFORM"); target != "" {
+		parts := strings.Split(target, "/")
+		if len(parts) >= 2 {
+			variant := ""
+			if len(parts) > 2 {
+				variant = parts[2]
+			}
+			return normalizeDockerPlatform(parts[0], parts[1], variant)
+		}
+	}
+
+	if targetOS, targetArch := os.Getenv("TARGETOS"), os.Getenv("TARGETARCH"); targetOS != "" && targetArch != "" {
+		return normalizeDockerPlatform(targetOS, targetArch, os.Getenv("TARGETVARIANT"))
+	}
+
+	return detectPlatform()
+}
+
+// normalizeDockerPlatform converts Docker's os/arch[/variant] form to potions' os-arch naming
+func normalizeDockerPlatform(goos, arch, variant string) string {
+	switch {
+	case arch == "arm" && variant != "":
+		arch = "arm" + variant
+	case arch == "amd64" && goos == "darwin":
+		arch = "x86_64"
+	case arch == "386":
+		arch = "i386"
+	}
+	return fmt.Sprintf("%s-%s", goos, arch)
+}
+
+// parseNameGlobs splits a comma-separated list of recipe name globs, rejecting malformed patterns
+func parseNameGlobs(value string) ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// newFilteredRecipeRepository parses --recipe-format/--include/--exclude values into a filtered recipe repository
+func newFilteredRecipeRepository(recipesDir, format, include, exclude string) (*yaml.RecipeRepository, error) {
+	recipeFormat, err := yaml.ParseRecipeFormat(format)
+	if err != nil {
+		return nil, fmt.Errorf("--recipe-format: %w", err)
+	}
+	includes, err := parseNameGlobs(include)
+	if err != nil {
+		return nil, fmt.Errorf("--include: %w", err)
+	}
+	excludes, err := parseNameGlobs(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("--exclude: %w", err)
+	}
+	return yaml.NewRecipeRepository(recipesDir).WithFormat(recipeFormat).WithNameFilter(includes, excludes), nil
+}
+
+// stringListFlag collects the values of a repeatable string flag
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// HistoryRecord is one package outcome appended to a --history JSON Lines log
+type HistoryRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Command   string    `json:"command"`
+	Package   string    `json:"package"`
+	Version   string    `json:"version"`
+	Platform  string    `json:"platform,omitempty"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// appendHistory appends records to a JSON Lines file, creating it if needed
+// Unlike the per-run report files, the history is never truncated
+func appendHistory(path string, records []HistoryRecord) error {
+	if path == "" || len(records) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to encode history record: %w", err)
+		}
+	}
+
+	//nolint:gosec // G304: History path is provided by the user via --history
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to append history: %w", err)
+	}
+	return f.Close()
+}
+
+// GitHub App credentials, used instead of GITHUB_TOKEN when POTIONS_GITHUB_APP_ID is set
+const (
+	githubAppIDEnv             = "POTIONS_GITHUB_APP_ID"
+	githubAppInstallationEnv   = "POTIONS_GITHUB_APP_INSTALLATION_ID"
+	githubAppPrivateKeyFileEnv = "POTIONS_GITHUB_APP_PRIVATE_KEY_FILE"
+)
+
+// githubAppConfigured reports whether GitHub App credentials are set in the environment
+func githubAppConfigured() bool {
+	return os.Getenv(githubAppIDEnv) != ""
+}
+
+// newGitHubGateway returns a gateway authenticated as the GitHub App installation configured in
+// the environment, or with token when no App is configured
+func newGitHubGateway(token string) (*gateways.HTTPGitHubGateway, error) {
+	if !githubAppConfigured() {
+		return gateways.NewHTTPGitHubGateway(token), nil
+	}
+	appID, err := strconv.ParseInt(os.Getenv(githubAppIDEnv), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", githubAppIDEnv, err)
+	}
+	installationID, err := strconv.ParseInt(os.Getenv(githubAppInstallationEnv), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s (required with %s): %w", githubAppInstallationEnv, githubAppIDEnv, err)
+	}
+	keyFile := os.Getenv(githubAppPrivateKeyFileEnv)
+	if keyFile == "" {
+		return nil, fmt.Errorf("%s is required with %s", githubAppPrivateKeyFileEnv, githubAppIDEnv)
+	}
+	//nolint:gosec // G304: Key path is provided by the user via POTIONS_GITHUB_APP_PRIVATE_KEY_FILE
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	return gateways.NewHTTPGitHubAppGateway(appID, installationID, key)
 }
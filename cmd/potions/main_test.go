@@ -0,0 +1,197 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMapGoArch(t *testing.T) {
+	tests := []struct {
+		goarch string
+		want   string
+	}{
+		{"amd64", "x86_64"},
+		{"arm64", "arm64"},
+		{"386", "i386"},
+		{"arm", "armv7"},
+		{"ppc64le", "ppc64le"},
+		{"s390x", "s390x"},
+		{"riscv64", "riscv64"},
+		{"mips64", "mips64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goarch, func(t *testing.T) {
+			if got := mapGoArch(tt.goarch); got != tt.want {
+				t.Errorf("mapGoArch(%q) = %q, want %q", tt.goarch, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolvePlatform_Auto tests that --platform auto honors Docker buildx target variables
+func TestResolvePlatform_Auto(t *testing.T) {
+	tests := []struct {
+		name           string
+		targetPlatform string
+		targetOS       string
+		targetArch     string
+		want           string
+	}{
+		{"TARGETPLATFORM arm64", "linux/arm64", "", "", "linux-arm64"},
+		{"TARGETPLATFORM amd64", "linux/amd64", "", "", "linux-amd64"},
+		{"TARGETPLATFORM arm variant", "linux/arm/v7", "", "", "linux-armv7"},
+		{"TARGETOS and TARGETARCH", "", "linux", "arm64", "linux-arm64"},
+		{"fallback to host", "", "", "", detectPlatform()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TARGETPLATFORM", tt.targetPlatform)
+			t.Setenv("TARGETOS", tt.targetOS)
+			t.Setenv("TARGETARCH", tt.targetArch)
+			t.Setenv("TARGETVARIANT", "")
+
+			if got := resolvePlatform("auto"); got != tt.want {
+				t.Errorf("resolvePlatform(auto) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolvePlatform_Explicit tests that explicit platforms pass through unchanged
+func TestResolvePlatform_Explicit(t *testing.T) {
+	t.Setenv("TARGETPLATFORM", "linux/arm64")
+
+	if got := resolvePlatform("darwin-arm64"); got != "darwin-arm64" {
+		t.Errorf("resolvePlatform(darwin-arm64) = %q, want darwin-arm64", got)
+	}
+}
+
+// TestParseNameGlobs tests parsing of --include/--exclude glob lists
+func TestParseNameGlobs(t *testing.T) {
+	got, err := parseNameGlobs(" k8s-*, ,kube?tl ")
+	if err != nil {
+		t.Fatalf("parseNameGlobs() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "k8s-*" || got[1] != "kube?tl" {
+		t.Errorf("parseNameGlobs() = %v, want [k8s-* kube?tl]", got)
+	}
+
+	if _, err := parseNameGlobs("k8s-["); err == nil {
+		t.Error("parseNameGlobs() should reject malformed glob")
+	}
+}
+
+// TestParseGlobalFlags tests that --user-agent and --config are consumed before the command name
+func TestParseGlobalFlags(t *testing.T) {
+	global, args, err := parseGlobalFlags([]string{"--user-agent", "bot/1.0 (ops@example.com)", "--config=ci.yaml", "--max-concurrent-downloads", "2", "build", "--user-agent=x"})
+	if err != nil {
+		t.Fatalf("parseGlobalFlags() error = %v", err)
+	}
+	if global.UserAgent != "bot/1.0 (ops@example.com)" || global.ConfigPath != "ci.yaml" || global.MaxConcurrentDownloads != "2" || len(args) != 2 || args[0] != "build" {
+		t.Errorf("parseGlobalFlags() = %+v, %v; want overrides and [build --user-agent=x]", global, args)
+	}
+
+	if _, _, err := parseGlobalFlags([]string{"--user-agent=bot/1.0"}); err == nil {
+		t.Error("parseGlobalFlags() should fail without a command")
+	}
+	if _, _, err := parseGlobalFlags([]string{"--config"}); err == nil {
+		t.Error("parseGlobalFlags() should fail when --config has no value")
+	}
+}
+
+// TestFlagDefaults tests command flags > env > config file > built-in default precedence
+func TestFlagDefaults(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".potions.yaml")
+	if err := os.WriteFile(configPath, []byte("recipes-dir: ./my-recipes\nowner: acme\nrepo: tools\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"POTIONS_REPO": "env-tools"}
+	defaults, err := loadFlagDefaults(configPath, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatalf("loadFlagDefaults() error = %v", err)
+	}
+
+	parse := func(args ...string) (recipesDir, owner, repo, outputDir string) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		r := fs.String("recipes-dir", "recipes", "")
+		o := fs.String("repo-owner", "ochairo", "")
+		p := fs.String("repo", "potions", "")
+		d := fs.String("output-dir", "dist", "")
+		applyFlagDefaults(fs, defaults)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return *r, *o, *p, *d
+	}
+
+	if r, o, p, d := parse(); r != "./my-recipes" || o != "acme" || p != "env-tools" || d != "dist" {
+		t.Errorf("without flags = %s, %s, %s, %s; want config, config, env and built-in values", r, o, p, d)
+	}
+	if r, o, p, _ := parse("--recipes-dir", "other", "--repo-owner=me", "--repo", "cli"); r != "other" || o != "me" || p != "cli" {
+		t.Errorf("with flags = %s, %s, %s; want the flag values", r, o, p)
+	}
+
+	if err := os.WriteFile(configPath, []byte("recipe-dir: typo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadFlagDefaults(configPath, func(string) string { return "" }); err == nil {
+		t.Error("loadFlagDefaults() should reject unknown config keys")
+	}
+}
+
+// TestResolveUserAgent tests flag > env > stamped version precedence
+func TestResolveUserAgent(t *testing.T) {
+	if got := resolveUserAgent("flag/1", "env/1", "1.0.0"); got != "flag/1" {
+		t.Errorf("resolveUserAgent() = %q, want flag value", got)
+	}
+	if got := resolveUserAgent("", "env/1", "1.0.0"); got != "env/1" {
+		t.Errorf("resolveUserAgent() = %q, want env value", got)
+	}
+	if got := resolveUserAgent("", "", "1.0.0"); got != "potions/1.0.0" {
+		t.Errorf("resolveUserAgent() = %q, want potions/1.0.0", got)
+	}
+}
+
+// Test GitHub App credentials in the environment select App authentication, and incomplete ones fail
+func TestNewGitHubGateway_App(t *testing.T) {
+	t.Setenv(githubAppIDEnv, "")
+	if _, err := newGitHubGateway("token"); err != nil {
+		t.Fatalf("newGitHubGateway() with a token error = %v", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(githubAppIDEnv, "12345")
+	t.Setenv(githubAppInstallationEnv, "")
+	t.Setenv(githubAppPrivateKeyFileEnv, keyFile)
+	if _, err := newGitHubGateway(""); err == nil || !strings.Contains(err.Error(), githubAppInstallationEnv) {
+		t.Errorf("newGitHubGateway() error = %v, want missing installation ID", err)
+	}
+
+	t.Setenv(githubAppInstallationEnv, "678")
+	if _, err := newGitHubGateway(""); err != nil {
+		t.Errorf("newGitHubGateway() with App credentials error = %v", err)
+	}
+
+	t.Setenv(githubAppPrivateKeyFileEnv, "")
+	if _, err := newGitHubGateway(""); err == nil || !strings.Contains(err.Error(), githubAppPrivateKeyFileEnv) {
+		t.Errorf("newGitHubGateway() error = %v, want missing private key file", err)
+	}
+}
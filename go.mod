@@ -3,6 +3,7 @@ module github.com/ochairo/potions
 go 1.25.5
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/ProtonMail/go-crypto v1.3.0
 	gopkg.in/yaml.v3 v3.0.1
 )
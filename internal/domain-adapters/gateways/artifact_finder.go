@@ -5,14 +5,24 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/ochairo/potions/internal/domain/entities"
 )
 
 // ArtifactFinder provides utilities for locating build artifacts
-type ArtifactFinder struct{}
+type ArtifactFinder struct {
+	layout entities.OutputLayout
+}
 
-// NewArtifactFinder creates a new artifact finder
+// NewArtifactFinder creates a new artifact finder for the flat output layout
 func NewArtifactFinder() *ArtifactFinder {
-	return &ArtifactFinder{}
+	return &ArtifactFinder{layout: entities.LayoutFlat}
+}
+
+// WithLayout searches only the package (and version) directory of the given output layout
+func (f *ArtifactFinder) WithLayout(layout entities.OutputLayout) *ArtifactFinder {
+	f.layout = layout
+	return f
 }
 
 // FindRecursive searches recursively for package artifacts
@@ -23,7 +33,13 @@ func (f *ArtifactFinder) FindRecursive(artifactsDir, packageName, version string
 		return nil, fmt.Errorf("artifacts directory does not exist: %s", artifactsDir)
 	}
 
-	versionClean := strings.TrimPrefix(version, "v")
+	// Nested layouts keep each package in its own directory; a missing one means no artifacts
+	artifactsDir = f.layout.Dir(artifactsDir, packageName, version)
+	if _, err := os.Stat(artifactsDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	versionClean := entities.CanonicalVersion(version)
 	var artifacts []string
 
 	err := filepath.Walk(artifactsDir, func(path string, info os.FileInfo, err error) error {
@@ -64,7 +80,7 @@ func (f *ArtifactFinder) FindByGlob(binariesDir, packageName, version string) ([
 	var artifacts []string
 
 	// Remove 'v' prefix from version for file matching
-	versionClean := strings.TrimPrefix(version, "v")
+	versionClean := entities.CanonicalVersion(version)
 
 	// Pattern: packageName-version-platform.tar.gz{,.sha256,.sha512,.sbom.json,.provenance.json}
 	patterns := []string{
@@ -75,6 +91,7 @@ func (f *ArtifactFinder) FindByGlob(binariesDir, packageName, version string) ([
 		fmt.Sprintf("%s-%s-*.tar.gz.provenance.json", packageName, versionClean),
 	}
 
+	binariesDir = f.layout.Dir(binariesDir, packageName, version)
 	for _, pattern := range patterns {
 		fullPattern := filepath.Join(binariesDir, pattern)
 		matches, err := filepath.Glob(fullPattern)
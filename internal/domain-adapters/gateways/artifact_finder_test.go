@@ -0,0 +1,86 @@
+package gateways
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// Test artifacts are discovered in the directory of each output layout
+func TestArtifactFinder_Layouts(t *testing.T) {
+	files := []string{
+		"tool-1.0.0-linux-amd64.tar.gz",
+		"tool-1.0.0-linux-amd64.tar.gz.sha256",
+		"tool-1.0.0-darwin-arm64.tar.gz",
+	}
+
+	for _, layout := range entities.OutputLayouts {
+		t.Run(string(layout), func(t *testing.T) {
+			base := t.TempDir()
+			dir := layout.Dir(base, "tool", "v1.0.0")
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Another package's artifacts must not be picked up
+			other := layout.Dir(base, "other", "1.0.0")
+			if err := os.MkdirAll(other, 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(other, "other-1.0.0-linux-amd64.tar.gz"), []byte("x"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			finder := NewArtifactFinder().WithLayout(layout)
+
+			recursive, err := finder.FindRecursive(base, "tool", "v1.0.0")
+			if err != nil {
+				t.Fatalf("FindRecursive() error = %v", err)
+			}
+			globbed, err := finder.FindByGlob(base, "tool", "v1.0.0")
+			if err != nil {
+				t.Fatalf("FindByGlob() error = %v", err)
+			}
+
+			for name, got := range map[string][]string{"FindRecursive": recursive, "FindByGlob": globbed} {
+				var names []string
+				for _, path := range got {
+					if filepath.Dir(path) != dir {
+						t.Errorf("%s() returned %s outside %s", name, path, dir)
+					}
+					names = append(names, filepath.Base(path))
+				}
+				sort.Strings(names)
+				want := append([]string(nil), files...)
+				sort.Strings(want)
+				if len(names) != len(want) {
+					t.Fatalf("%s() = %v, want %v", name, names, want)
+				}
+				for i := range want {
+					if names[i] != want[i] {
+						t.Errorf("%s() = %v, want %v", name, names, want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+// Test a nested layout without the package's directory finds nothing instead of failing
+func TestArtifactFinder_MissingLayoutDir(t *testing.T) {
+	artifacts, err := NewArtifactFinder().WithLayout(entities.LayoutByPackageVersion).FindRecursive(t.TempDir(), "tool", "1.0.0")
+	if err != nil {
+		t.Fatalf("FindRecursive() error = %v", err)
+	}
+	if len(artifacts) != 0 {
+		t.Errorf("FindRecursive() = %v, want none", artifacts)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"debug/elf"
 	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
 	"fmt"
 	"strings"
 	"time"
@@ -13,7 +15,7 @@ import (
 )
 
 // binaryAnalyzerGateway implements binary security analysis using pure Go
-// Uses debug/elf and debug/macho packages - no external tools required
+// Uses debug/elf, debug/macho and debug/pe packages - no external tools required
 type binaryAnalyzerGateway struct{}
 
 // NewBinaryAnalyzerGateway creates a new binary analyzer gateway
@@ -30,6 +32,8 @@ func (g *binaryAnalyzerGateway) AnalyzeBinaryHardening(_ context.Context, binary
 		return g.analyzeDarwinBinary(binaryPath)
 	case strings.HasPrefix(platform, "linux"):
 		return g.analyzeLinuxBinary(binaryPath)
+	case strings.HasPrefix(platform, "windows"):
+		return g.analyzeWindowsBinary(binaryPath)
 	default:
 		return nil, fmt.Errorf("unsupported platform: %s", platform)
 	}
@@ -158,9 +162,107 @@ func (g *binaryAnalyzerGateway) analyzeDarwinBinary(binaryPath string) (*entitie
 	}, nil
 }
 
+// analyzeWindowsBinary analyzes a Windows PE binary using debug/pe
+func (g *binaryAnalyzerGateway) analyzeWindowsBinary(binaryPath string) (*entities.BinaryAnalysis, error) {
+	f, err := pe.Open(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PE file: %w", err)
+	}
+	//nolint:errcheck // Defer close on read-only file
+	defer f.Close()
+
+	var dllCharacteristics uint16
+	var loadConfig pe.DataDirectory
+	is64 := false
+	switch header := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dllCharacteristics = header.DllCharacteristics
+		if header.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_LOAD_CONFIG {
+			loadConfig = header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_LOAD_CONFIG]
+		}
+	case *pe.OptionalHeader64:
+		dllCharacteristics = header.DllCharacteristics
+		if header.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_LOAD_CONFIG {
+			loadConfig = header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_LOAD_CONFIG]
+		}
+		is64 = true
+	default:
+		return nil, fmt.Errorf("PE file has no optional header")
+	}
+
+	features := entities.HardeningFeatures{}
+
+	// ASLR and DEP are opt-in DLL characteristics flags
+	features.PIEEnabled = dllCharacteristics&pe.IMAGE_DLLCHARACTERISTICS_DYNAMIC_BASE != 0
+	features.NXBit = dllCharacteristics&pe.IMAGE_DLLCHARACTERISTICS_NX_COMPAT != 0
+	features.CFG = dllCharacteristics&pe.IMAGE_DLLCHARACTERISTICS_GUARD_CF != 0
+
+	// /GS stack cookies and the SafeSEH handler table are recorded in the load config directory
+	securityCookie, seHandlerCount := peLoadConfig(f, loadConfig, is64)
+	features.StackCanaries = securityCookie != 0
+
+	// 64-bit exception handling is table based and NO_SEH images have no handlers to hijack,
+	// so only 32-bit images with handlers need a SafeSEH table
+	features.SafeSEH = is64 || dllCharacteristics&pe.IMAGE_DLLCHARACTERISTICS_NO_SEH != 0 || seHandlerCount > 0
+
+	score := scoreHardeningChecks([]bool{
+		features.PIEEnabled,
+		features.NXBit,
+		features.CFG,
+		features.SafeSEH,
+		features.StackCanaries,
+	})
+
+	return &entities.BinaryAnalysis{
+		Platform:          "windows",
+		HardeningFeatures: features,
+		SecurityScore:     score,
+		Timestamp:         time.Now(),
+	}, nil
+}
+
+// peLoadConfig returns the security cookie address and SafeSEH handler count from a PE's
+// load config directory, or zeros when the image has none
+func peLoadConfig(f *pe.File, dir pe.DataDirectory, is64 bool) (securityCookie uint64, seHandlerCount uint64) {
+	if dir.VirtualAddress == 0 || dir.Size == 0 {
+		return 0, 0
+	}
+	var data []byte
+	for _, section := range f.Sections {
+		if dir.VirtualAddress < section.VirtualAddress || dir.VirtualAddress >= section.VirtualAddress+max(section.VirtualSize, section.Size) {
+			continue
+		}
+		sectionData, err := section.Data()
+		if err != nil {
+			return 0, 0
+		}
+		offset := dir.VirtualAddress - section.VirtualAddress
+		if offset >= uint32(len(sectionData)) {
+			return 0, 0
+		}
+		data = sectionData[offset:min(uint32(len(sectionData)), offset+dir.Size)]
+		break
+	}
+
+	// Field offsets in IMAGE_LOAD_CONFIG_DIRECTORY32/64; older images stop before the later fields
+	if is64 {
+		if len(data) >= 96 {
+			securityCookie = binary.LittleEndian.Uint64(data[88:96])
+		}
+		return securityCookie, 0
+	}
+	if len(data) >= 64 {
+		securityCookie = uint64(binary.LittleEndian.Uint32(data[60:64]))
+	}
+	if len(data) >= 72 {
+		seHandlerCount = uint64(binary.LittleEndian.Uint32(data[68:72]))
+	}
+	return securityCookie, seHandlerCount
+}
+
 // calculateHardeningScore calculates a security score based on hardening features
 func (g *binaryAnalyzerGateway) calculateHardeningScore(features entities.HardeningFeatures) entities.SecurityScore {
-	checks := []bool{
+	return scoreHardeningChecks([]bool{
 		features.PIEEnabled,
 		features.StackCanaries,
 		features.NXBit,
@@ -168,8 +270,11 @@ func (g *binaryAnalyzerGateway) calculateHardeningScore(features entities.Harden
 		features.FortifySource,
 		features.CodeSigned,
 		features.HardenedRuntime,
-	}
+	})
+}
 
+// scoreHardeningChecks scores the share of passed checks on a 0-10 scale
+func scoreHardeningChecks(checks []bool) entities.SecurityScore {
 	passed := 0
 	total := 0
 
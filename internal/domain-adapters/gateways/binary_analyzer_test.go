@@ -1,7 +1,10 @@
 package gateways
 
 import (
+	"bytes"
 	"context"
+	"debug/pe"
+	"encoding/binary"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,7 +16,7 @@ import (
 func TestBinaryAnalyzer_UnsupportedPlatform(t *testing.T) {
 	analyzer := NewBinaryAnalyzerGateway()
 
-	_, err := analyzer.AnalyzeBinaryHardening(context.Background(), "/tmp/test", "windows-amd64")
+	_, err := analyzer.AnalyzeBinaryHardening(context.Background(), "/tmp/test", "freebsd-amd64")
 
 	if err == nil {
 		t.Fatal("Expected error for unsupported platform, got nil")
@@ -184,7 +187,7 @@ func TestBinaryAnalyzer_PlatformDetection(t *testing.T) {
 		{"linux-arm64", true, "failed to open ELF file"},
 		{"darwin-amd64", true, "failed to open Mach-O file"},
 		{"darwin-arm64", true, "failed to open Mach-O file"},
-		{"windows-amd64", true, "unsupported platform"},
+		{"windows-amd64", true, "failed to open PE file"},
 		{"freebsd-amd64", true, "unsupported platform"},
 	}
 
@@ -205,6 +208,120 @@ func TestBinaryAnalyzer_PlatformDetection(t *testing.T) {
 	}
 }
 
+// writePEFixture writes a minimal PE image with the given DLL characteristics whose only
+// section holds loadConfig as the load config directory
+func writePEFixture(t *testing.T, is64 bool, dllCharacteristics uint16, loadConfig []byte) string {
+	t.Helper()
+	const sectionRVA = 0x1000
+	loadConfigDir := pe.DataDirectory{VirtualAddress: sectionRVA, Size: uint32(len(loadConfig))}
+
+	var optionalHeader any
+	fileHeader := pe.FileHeader{NumberOfSections: 1, Characteristics: 0x0102}
+	if is64 {
+		header := &pe.OptionalHeader64{Magic: 0x20b, NumberOfRvaAndSizes: 16, DllCharacteristics: dllCharacteristics}
+		header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_LOAD_CONFIG] = loadConfigDir
+		optionalHeader = header
+		fileHeader.Machine = pe.IMAGE_FILE_MACHINE_AMD64
+	} else {
+		header := &pe.OptionalHeader32{Magic: 0x10b, NumberOfRvaAndSizes: 16, DllCharacteristics: dllCharacteristics}
+		header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_LOAD_CONFIG] = loadConfigDir
+		optionalHeader = header
+		fileHeader.Machine = pe.IMAGE_FILE_MACHINE_I386
+	}
+	fileHeader.SizeOfOptionalHeader = uint16(binary.Size(optionalHeader))
+
+	const peOffset = 0x40
+	dataOffset := peOffset + 4 + binary.Size(fileHeader) + int(fileHeader.SizeOfOptionalHeader) + binary.Size(pe.SectionHeader32{})
+	section := pe.SectionHeader32{
+		VirtualSize:      uint32(len(loadConfig)),
+		VirtualAddress:   sectionRVA,
+		SizeOfRawData:    uint32(len(loadConfig)),
+		PointerToRawData: uint32(dataOffset),
+	}
+	copy(section.Name[:], ".rdata")
+
+	var image bytes.Buffer
+	dosHeader := make([]byte, peOffset)
+	copy(dosHeader, "MZ")
+	binary.LittleEndian.PutUint32(dosHeader[0x3c:], peOffset)
+	image.Write(dosHeader)
+	image.WriteString("PE\x00\x00")
+	for _, part := range []any{fileHeader, optionalHeader, section} {
+		if err := binary.Write(&image, binary.LittleEndian, part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	image.Write(loadConfig)
+
+	path := filepath.Join(t.TempDir(), "tool.exe")
+	if err := os.WriteFile(path, image.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Test PE hardening is read from DLL characteristics and the load config directory
+func TestBinaryAnalyzer_AnalyzeWindowsBinary(t *testing.T) {
+	loadConfig64 := make([]byte, 112)
+	binary.LittleEndian.PutUint64(loadConfig64[88:], 0x140003000) // SecurityCookie
+	loadConfig32 := make([]byte, 72)
+	binary.LittleEndian.PutUint32(loadConfig32[60:], 0x403000) // SecurityCookie
+	loadConfig32SEH := make([]byte, 72)
+	binary.LittleEndian.PutUint32(loadConfig32SEH[68:], 3) // SEHandlerCount
+
+	tests := []struct {
+		name               string
+		is64               bool
+		dllCharacteristics uint16
+		loadConfig         []byte
+		want               entities.HardeningFeatures
+		wantPassed         int
+	}{
+		{
+			name:               "hardened 64-bit",
+			is64:               true,
+			dllCharacteristics: pe.IMAGE_DLLCHARACTERISTICS_DYNAMIC_BASE | pe.IMAGE_DLLCHARACTERISTICS_NX_COMPAT | pe.IMAGE_DLLCHARACTERISTICS_GUARD_CF,
+			loadConfig:         loadConfig64,
+			want:               entities.HardeningFeatures{PIEEnabled: true, NXBit: true, CFG: true, SafeSEH: true, StackCanaries: true},
+			wantPassed:         5,
+		},
+		{
+			name:               "32-bit without SafeSEH table",
+			dllCharacteristics: pe.IMAGE_DLLCHARACTERISTICS_DYNAMIC_BASE | pe.IMAGE_DLLCHARACTERISTICS_NX_COMPAT,
+			loadConfig:         loadConfig32,
+			want:               entities.HardeningFeatures{PIEEnabled: true, NXBit: true, StackCanaries: true},
+			wantPassed:         3,
+		},
+		{
+			name:       "32-bit with SafeSEH table only",
+			loadConfig: loadConfig32SEH,
+			want:       entities.HardeningFeatures{SafeSEH: true},
+			wantPassed: 1,
+		},
+	}
+
+	analyzer := NewBinaryAnalyzerGateway()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writePEFixture(t, tt.is64, tt.dllCharacteristics, tt.loadConfig)
+			analysis, err := analyzer.AnalyzeBinaryHardening(context.Background(), path, "windows-x86_64")
+			if err != nil {
+				t.Fatalf("AnalyzeBinaryHardening() error = %v", err)
+			}
+			if analysis.Platform != "windows" {
+				t.Errorf("Platform = %q, want windows", analysis.Platform)
+			}
+			if analysis.HardeningFeatures != tt.want {
+				t.Errorf("HardeningFeatures = %+v, want %+v", analysis.HardeningFeatures, tt.want)
+			}
+			score := analysis.SecurityScore
+			if score.Total != 5 || score.Passed != tt.wantPassed || score.Score != float64(tt.wantPassed)*2 {
+				t.Errorf("SecurityScore = %+v, want %d/5 passed", score, tt.wantPassed)
+			}
+		})
+	}
+}
+
 // Helper function
 func stringContainsSubstr(s, substr string) bool {
 	return len(s) >= len(substr) && stringIndexOf(s, substr) >= 0
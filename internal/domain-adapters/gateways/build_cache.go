@@ -0,0 +1,96 @@
+package gateways
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileBuildCache stores finished tarballs on disk in a content-addressable layout
+// Layout: <dir>/<key>/<tarball name>
+type FileBuildCache struct {
+	dir string
+}
+
+// NewFileBuildCache creates a new file-based build cache rooted at dir
+func NewFileBuildCache(dir string) *FileBuildCache {
+	return &FileBuildCache{dir: dir}
+}
+
+// Restore copies the cached tarball for key into outputDir
+func (c *FileBuildCache) Restore(key, outputDir string) (string, bool, error) {
+	entryDir := filepath.Join(c.dir, key)
+	entries, err := os.ReadDir(entryDir)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	for _, entry := range entries {
+		// Skip partially written files from concurrent stores
+		if entry.IsDir() || filepath.Ext(entry.Name()) == ".tmp" {
+			continue
+		}
+
+		if err := os.MkdirAll(outputDir, 0750); err != nil {
+			return "", false, fmt.Errorf("failed to create output directory: %w", err)
+		}
+		destPath := filepath.Join(outputDir, entry.Name())
+		if err := copyFileAtomic(filepath.Join(entryDir, entry.Name()), destPath); err != nil {
+			return "", false, fmt.Errorf("failed to restore cached build: %w", err)
+		}
+		return destPath, true, nil
+	}
+
+	return "", false, nil
+}
+
+// Store saves the tarball at path under key
+// Writes go through a temp file and rename so concurrent builds never observe partial entries
+func (c *FileBuildCache) Store(key, path string) error {
+	entryDir := filepath.Join(c.dir, key)
+	if err := os.MkdirAll(entryDir, 0750); err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+
+	if err := copyFileAtomic(path, filepath.Join(entryDir, filepath.Base(path))); err != nil {
+		return fmt.Errorf("failed to store build in cache: %w", err)
+	}
+	return nil
+}
+
+// copyFileAtomic copies src to dst via a temp file in dst's directory
+func copyFileAtomic(src, dst string) error {
+	//nolint:gosec // G304: src is a build artifact or cache entry path
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // Defer close on read-only file
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, dst); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
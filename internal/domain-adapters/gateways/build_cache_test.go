@@ -0,0 +1,46 @@
+package gateways
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test storing and restoring a tarball through the file build cache
+func TestFileBuildCache_StoreRestore(t *testing.T) {
+	cache := NewFileBuildCache(t.TempDir())
+
+	srcDir := t.TempDir()
+	tarball := filepath.Join(srcDir, "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(tarball, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "dist")
+	if _, found, err := cache.Restore("key", outputDir); err != nil || found {
+		t.Fatalf("Restore() on empty cache = found %v, err %v", found, err)
+	}
+
+	if err := cache.Store("key", tarball); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	restored, found, err := cache.Restore("key", outputDir)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !found {
+		t.Fatal("Restore() should find stored entry")
+	}
+	if restored != filepath.Join(outputDir, "tool-1.0.0-linux-amd64.tar.gz") {
+		t.Errorf("Restore() path = %s", restored)
+	}
+
+	data, err := os.ReadFile(restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "tarball" {
+		t.Errorf("Restored content = %q, want tarball", data)
+	}
+}
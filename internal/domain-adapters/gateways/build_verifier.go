@@ -0,0 +1,136 @@
+package gateways
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// verifyArgPattern restricts verify command arguments to a safe character set
+var verifyArgPattern = regexp.MustCompile(`^[A-Za-z0-9._/:=@+,%-]+$`)
+
+// commandRunner runs a command in dir and returns its combined output
+type commandRunner func(ctx context.Context, dir, name string, args ...string) ([]byte, error)
+
+// BuildVerifier runs a recipe's verify_command against the packaged binary
+type BuildVerifier struct {
+	timeout time.Duration
+	run     commandRunner
+}
+
+// NewBuildVerifier creates a new build verifier
+func NewBuildVerifier() *BuildVerifier {
+	return &BuildVerifier{
+		timeout: 1 * time.Minute,
+		run:     runCommand,
+	}
+}
+
+// runCommand executes name directly (no shell) in dir
+func runCommand(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	//nolint:gosec // G204: Binary resolved inside the package and arguments validated by parseVerifyCommand
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// VerifyBuild extracts the packaged tarball and runs verify_command against it,
+// failing if the command errors or its output does not match verify_expect
+func (v *BuildVerifier) VerifyBuild(ctx context.Context, def *entities.Recipe, artifact *entities.Artifact) error {
+	if def.Build.VerifyCommand == "" {
+		return nil
+	}
+
+	args, err := parseVerifyCommand(def.Build.VerifyCommand, artifact.Version, artifact.Platform)
+	if err != nil {
+		return err
+	}
+
+	var expect *regexp.Regexp
+	if def.Build.VerifyExpect != "" {
+		pattern := strings.ReplaceAll(def.Build.VerifyExpect, "{version}",
+			regexp.QuoteMeta(entities.CanonicalVersion(artifact.Version)))
+		expect, err = regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid verify_expect regex: %w", err)
+		}
+	}
+
+	workDir, err := os.MkdirTemp("", "potions-verify-*")
+	if err != nil {
+		return fmt.Errorf("failed to create verify directory: %w", err)
+	}
+	//nolint:errcheck // Best effort cleanup of temp directory
+	defer os.RemoveAll(workDir)
+
+	if err := NewDownloader().extractTarGz(artifact.Path, workDir, 0); err != nil {
+		return fmt.Errorf("failed to extract package for verification: %w", err)
+	}
+
+	binPath, err := resolveVerifyBinary(workDir, args[0])
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	output, err := v.run(runCtx, workDir, binPath, args[1:]...)
+	if err != nil {
+		return fmt.Errorf("verify command %q failed: %w\nOutput: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+
+	if expect != nil && !expect.Match(output) {
+		return fmt.Errorf("verify command output did not match %q\nOutput: %s", expect.String(), strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// parseVerifyCommand substitutes placeholders and splits the command into validated arguments
+func parseVerifyCommand(command, version, platform string) ([]string, error) {
+	command = strings.ReplaceAll(command, "{version}", entities.CanonicalVersion(version))
+	command = strings.ReplaceAll(command, "{platform}", platform)
+
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("verify_command is empty")
+	}
+
+	for _, arg := range args {
+		if !verifyArgPattern.MatchString(arg) {
+			return nil, fmt.Errorf("security: verify_command argument %q contains disallowed characters", arg)
+		}
+	}
+
+	return args, nil
+}
+
+// resolveVerifyBinary locates the command's binary inside the extracted package,
+// so only packaged binaries (never arbitrary system commands) are executed
+func resolveVerifyBinary(baseDir, name string) (string, error) {
+	var candidates []string
+	if strings.Contains(name, "/") {
+		candidates = []string{filepath.Join(baseDir, name)}
+	} else {
+		candidates = []string{filepath.Join(baseDir, name), filepath.Join(baseDir, "bin", name)}
+	}
+
+	for _, candidate := range candidates {
+		if err := validatePathWithinBase(candidate, baseDir); err != nil {
+			return "", fmt.Errorf("security: %w", err)
+		}
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("verify_command binary %q not found in package", name)
+}
@@ -0,0 +1,102 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// writeVerifyTarball packages a fake bin/tool binary and returns the tarball path
+func writeVerifyTarball(t *testing.T) string {
+	t.Helper()
+	srcDir := filepath.Join(t.TempDir(), "src")
+	if err := os.MkdirAll(filepath.Join(srcDir, "bin"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	//nolint:gosec // G306: test binary must be executable
+	if err := os.WriteFile(filepath.Join(srcDir, "bin", "tool"), []byte("binary"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	tarball := filepath.Join(t.TempDir(), "tool-1.2.3-linux-amd64.tar.gz")
+	if err := NewPackager().createTarball(srcDir, tarball); err != nil {
+		t.Fatal(err)
+	}
+	return tarball
+}
+
+func TestBuildVerifier_VerifyBuild(t *testing.T) {
+	tarball := writeVerifyTarball(t)
+	artifact := &entities.Artifact{Name: "tool", Version: "v1.2.3", Platform: "linux-amd64", Path: tarball}
+
+	tests := []struct {
+		name    string
+		command string
+		expect  string
+		output  string
+		runErr  error
+		wantErr string
+	}{
+		{"passing", "tool --version", `^tool {version}\b`, "tool 1.2.3 (linux-amd64)\n", nil, ""},
+		{"no expectation", "bin/tool version", "", "anything", nil, ""},
+		{"command fails", "tool --version", "", "boom", errors.New("exit status 1"), "verify command"},
+		{"output mismatch", "tool --version", `^tool {version}\b`, "tool 1.2.2\n", nil, "did not match"},
+		{"binary not packaged", "curl --version", "", "", nil, "not found in package"},
+		{"injection rejected", "tool --version;rm", "", "", nil, "disallowed characters"},
+		{"path escape rejected", "../../bin/sh -c", "", "", nil, "security"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranBinary string
+			var ranArgs []string
+			v := NewBuildVerifier()
+			v.run = func(_ context.Context, _, name string, args ...string) ([]byte, error) {
+				ranBinary = name
+				ranArgs = args
+				return []byte(tt.output), tt.runErr
+			}
+
+			def := &entities.Recipe{Name: "tool", Build: entities.RecipeBuildStep{
+				VerifyCommand: tt.command,
+				VerifyExpect:  tt.expect,
+			}}
+			err := v.VerifyBuild(context.Background(), def, artifact)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("VerifyBuild() error = %v", err)
+				}
+				if filepath.Base(ranBinary) != "tool" || filepath.Base(filepath.Dir(ranBinary)) != "bin" {
+					t.Errorf("ran %s, want packaged bin/tool", ranBinary)
+				}
+				if len(ranArgs) != 1 {
+					t.Errorf("args = %v, want one argument", ranArgs)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyBuild() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseVerifyCommand_Placeholders(t *testing.T) {
+	args, err := parseVerifyCommand("tool --expect={version} --target {platform}", "v2.0.0", "darwin-arm64")
+	if err != nil {
+		t.Fatalf("parseVerifyCommand() error = %v", err)
+	}
+	if got := strings.Join(args, " "); got != "tool --expect=2.0.0 --target darwin-arm64" {
+		t.Errorf("parseVerifyCommand() = %s", got)
+	}
+
+	if _, err := parseVerifyCommand("tool {version}", "1.0.0$(id)", "linux-amd64"); err == nil {
+		t.Error("parseVerifyCommand() should reject unsafe substituted version")
+	}
+}
@@ -2,11 +2,26 @@ package gateways
 
 import (
 	"context"
+	//nolint:gosec // G501: MD5 only verifies sums some upstreams still publish, when a recipe opts in
+	"crypto/md5"
+	//nolint:gosec // G505: SHA1 only verifies sums some upstreams still publish
+	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os"
+	"strings"
+)
+
+// Supported checksum algorithms
+const (
+	ChecksumMD5    = "md5"
+	ChecksumSHA1   = "sha1"
+	ChecksumSHA256 = "sha256"
+	ChecksumSHA512 = "sha512"
 )
 
 // checksumVerifier implements checksum verification using pure Go
@@ -21,7 +36,28 @@ func NewChecksumVerifier() *checksumVerifier {
 
 // VerifyChecksum verifies a file's SHA256 checksum
 // Pure Go implementation - no external sha256sum binary needed
-func (v *checksumVerifier) VerifyChecksum(_ context.Context, filePath, expectedSum string) error {
+func (v *checksumVerifier) VerifyChecksum(ctx context.Context, filePath, expectedSum string) error {
+	return v.VerifyChecksumWithAlgorithm(ctx, filePath, expectedSum, ChecksumSHA256)
+}
+
+// VerifyChecksumWithAlgorithm verifies a file's checksum using the given algorithm (md5, sha1, sha256 or sha512)
+func (v *checksumVerifier) VerifyChecksumWithAlgorithm(_ context.Context, filePath, expectedSum, algorithm string) error {
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case ChecksumMD5:
+		//nolint:gosec // G401: MD5 only verifies sums some upstreams still publish, when a recipe opts in
+		h = md5.New()
+	case ChecksumSHA1:
+		//nolint:gosec // G401: SHA1 only verifies sums some upstreams still publish
+		h = sha1.New()
+	case ChecksumSHA256:
+		h = sha256.New()
+	case ChecksumSHA512:
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+
 	//nolint:gosec // G304: File path is user-provided for checksum verification
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -30,7 +66,6 @@ func (v *checksumVerifier) VerifyChecksum(_ context.Context, filePath, expectedS
 	//nolint:errcheck // Defer close on read-only file
 	defer f.Close()
 
-	h := sha256.New()
 	if _, err := io.Copy(h, f); err != nil {
 		return fmt.Errorf("failed to hash file: %w", err)
 	}
@@ -38,7 +73,7 @@ func (v *checksumVerifier) VerifyChecksum(_ context.Context, filePath, expectedS
 	actualSum := hex.EncodeToString(h.Sum(nil))
 
 	if actualSum != expectedSum {
-		return fmt.Errorf("checksum mismatch: expected %s, got %s", expectedSum, actualSum)
+		return fmt.Errorf("%s checksum mismatch: expected %s, got %s", algorithm, expectedSum, actualSum)
 	}
 
 	return nil
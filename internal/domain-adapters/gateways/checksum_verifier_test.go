@@ -165,3 +165,27 @@ func TestLargeFileChecksum(t *testing.T) {
 		t.Errorf("VerifyChecksum() for large file error = %v", err)
 	}
 }
+
+// TestVerifyChecksumWithAlgorithm tests SHA512 verification and unsupported algorithms
+func TestVerifyChecksumWithAlgorithm(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("hello"), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	verifier := NewChecksumVerifier()
+	sha512Sum := "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"
+
+	if err := verifier.VerifyChecksumWithAlgorithm(context.Background(), testFile, sha512Sum, ChecksumSHA512); err != nil {
+		t.Errorf("VerifyChecksumWithAlgorithm(sha512) error = %v", err)
+	}
+
+	if err := verifier.VerifyChecksumWithAlgorithm(context.Background(), testFile, sha512Sum, ChecksumSHA256); err == nil {
+		t.Error("Expected mismatch when verifying SHA512 digest as SHA256")
+	}
+
+	if err := verifier.VerifyChecksumWithAlgorithm(context.Background(), testFile, sha512Sum, "md5"); err == nil {
+		t.Error("Expected error for unsupported algorithm")
+	}
+}
@@ -71,9 +71,14 @@ func (c *compositeSecurityGateway) VerifyGPGSignature(ctx context.Context, fileP
 	return c.gpgVerifier.VerifyGPGSignature(ctx, filePath, sigURL)
 }
 
+// VerifyGPGSignedChecksums verifies a signed checksums manifest and the file's entry in it
+func (c *compositeSecurityGateway) VerifyGPGSignedChecksums(ctx context.Context, filePath, checksumsURL, sigURL string) error {
+	return c.gpgVerifier.VerifyGPGSignedChecksums(ctx, filePath, checksumsURL, sigURL)
+}
+
 // ImportGPGKeys imports GPG keys from keyservers
-func (c *compositeSecurityGateway) ImportGPGKeys(ctx context.Context, keyIDs []string) error {
-	return c.gpgVerifier.ImportGPGKeys(ctx, keyIDs)
+func (c *compositeSecurityGateway) ImportGPGKeys(ctx context.Context, keyIDs []string, lookup entities.GPGKeyLookup) error {
+	return c.gpgVerifier.ImportGPGKeys(ctx, keyIDs, lookup)
 }
 
 func (c *compositeSecurityGateway) ImportGPGKeysFromURL(ctx context.Context, keysURL string) error {
@@ -132,7 +132,7 @@ func TestCompositeGateway_ImportGPGKeys(t *testing.T) {
 	gateway := NewCompositeSecurityGateway()
 
 	// This will fail with network error or key not found
-	err := gateway.ImportGPGKeys(context.Background(), []string{"TESTKEY123"})
+	err := gateway.ImportGPGKeys(context.Background(), []string{"TESTKEY123"}, entities.GPGKeyLookup{})
 
 	if err == nil {
 		t.Fatal("Expected error for invalid key, got nil")
@@ -143,7 +143,7 @@ func TestCompositeGateway_ImportGPGKeys(t *testing.T) {
 func TestCompositeGateway_ImportGPGKeys_EmptyKeys(t *testing.T) {
 	gateway := NewCompositeSecurityGateway()
 
-	err := gateway.ImportGPGKeys(context.Background(), []string{})
+	err := gateway.ImportGPGKeys(context.Background(), []string{}, entities.GPGKeyLookup{})
 
 	if err == nil {
 		t.Fatal("Expected error for empty key list, got nil")
@@ -200,7 +200,7 @@ func TestCompositeGateway_IntegrationFlow(t *testing.T) {
 
 	// Test GPG operations
 	t.Run("GPG Operations", func(t *testing.T) {
-		err := gateway.ImportGPGKeys(context.Background(), []string{})
+		err := gateway.ImportGPGKeys(context.Background(), []string{}, entities.GPGKeyLookup{})
 		if err == nil {
 			t.Error("Should fail with empty key list")
 		}
@@ -0,0 +1,173 @@
+package gateways
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// maxChecksumFileSize bounds checksum files, which hold at most a few hundred lines
+const maxChecksumFileSize = 1 << 20
+
+// checksumHexPattern matches hex digests of any supported algorithm
+var checksumHexPattern = regexp.MustCompile(`^[0-9a-fA-F]{32,128}$`)
+
+// checksumDigestLengths maps each supported algorithm to its hex digest length
+var checksumDigestLengths = map[string]int{
+	ChecksumMD5:    32,
+	ChecksumSHA1:   40,
+	ChecksumSHA256: 64,
+	ChecksumSHA512: 128,
+}
+
+// weakChecksumAlgorithms are only accepted when a recipe sets allow_weak_checksum
+var weakChecksumAlgorithms = map[string]bool{ChecksumMD5: true, ChecksumSHA1: true}
+
+// verifyDownloadChecksum fetches checksumURL and verifies the downloaded file at filePath against
+// the entry for filename (or the file's only hash); algorithm is the recipe's checksum_algorithm,
+// with SHA-256 and SHA-512 detected by digest length when it is empty
+func (d *Downloader) verifyDownloadChecksum(ctx context.Context, checksumURL, filePath, filename, algorithm string, allowWeak bool, headers http.Header) error {
+	algorithm, err := checkChecksumAlgorithm(algorithm, allowWeak)
+	if err != nil {
+		return err
+	}
+
+	checksumPath := filePath + ".checksum"
+	if err := d.downloadFile(ctx, checksumURL, checksumPath, headers); err != nil {
+		return fmt.Errorf("failed to fetch checksum file: %w", err)
+	}
+	//nolint:errcheck // Best effort cleanup of the fetched checksum file
+	defer os.Remove(checksumPath)
+
+	info, err := os.Stat(checksumPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat checksum file: %w", err)
+	}
+	if info.Size() > maxChecksumFileSize {
+		return fmt.Errorf("checksum file %s is too large (%d bytes)", checksumURL, info.Size())
+	}
+
+	//nolint:gosec // G304: checksumPath is the file just downloaded next to the artifact
+	content, err := os.ReadFile(checksumPath)
+	if err != nil {
+		return fmt.Errorf("failed to read checksum file: %w", err)
+	}
+
+	expected, algorithm, err := findChecksum(string(content), filename, algorithm)
+	if err != nil {
+		return fmt.Errorf("%s: %w", checksumURL, err)
+	}
+	if err := NewChecksumVerifier().VerifyChecksumWithAlgorithm(ctx, filePath, expected, algorithm); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Verified %s checksum of %s\n", algorithm, filename)
+	return nil
+}
+
+// checkChecksumAlgorithm normalizes a recipe's checksum_algorithm, rejecting unknown ones and
+// weak ones unless allowWeak is set
+func checkChecksumAlgorithm(algorithm string, allowWeak bool) (string, error) {
+	algorithm = strings.ToLower(algorithm)
+	if _, ok := checksumDigestLengths[algorithm]; algorithm != "" && !ok {
+		return "", fmt.Errorf("unsupported checksum_algorithm %q (valid: sha256, sha512, sha1, md5)", algorithm)
+	}
+	if weakChecksumAlgorithms[algorithm] && !allowWeak {
+		return "", fmt.Errorf("%s checksums are weak; set download.allow_weak_checksum to accept them", algorithm)
+	}
+	return algorithm, nil
+}
+
+// verifyReleaseBodyChecksum verifies the downloaded file against the "<hash>  <filename>" line for
+// filename in the description of the github-release source's release for version
+func (d *Downloader) verifyReleaseBodyChecksum(ctx context.Context, source, version, filePath, filename, algorithm string, allowWeak bool) error {
+	algorithm, err := checkChecksumAlgorithm(algorithm, allowWeak)
+	if err != nil {
+		return err
+	}
+	repo, ok := strings.CutPrefix(source, "github-release:")
+	if !ok || repo == "" {
+		return fmt.Errorf("checksum_from_release_body requires a github-release version source, got %q", source)
+	}
+
+	release, err := d.fetchReleaseByVersion(ctx, repo, version)
+	if err != nil {
+		return err
+	}
+
+	// Only named lines count: a lone hash in the prose cannot be tied to this file
+	var lines []string
+	for _, line := range strings.Split(release.Body, "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "`")
+		if len(strings.Fields(line)) >= 2 {
+			lines = append(lines, line)
+		}
+	}
+	expected, algorithm, err := findChecksum(strings.Join(lines, "\n"), filename, algorithm)
+	if err != nil {
+		return fmt.Errorf("release %s %s body: %w", repo, release.TagName, err)
+	}
+	if err := NewChecksumVerifier().VerifyChecksumWithAlgorithm(ctx, filePath, expected, algorithm); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Verified %s checksum of %s from the release description\n", algorithm, filename)
+	return nil
+}
+
+// findChecksum returns the lowercase digest for filename from a checksum file and its algorithm,
+// accepting "<hash>  <name>" / "<hash> *<name>" lines or a file holding a single bare hash; only
+// digests of algorithm are considered, or SHA-256 and SHA-512 ones when algorithm is empty
+func findChecksum(content, filename, algorithm string) (string, string, error) {
+	var bare []string
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !checksumHexPattern.MatchString(fields[0]) {
+			continue
+		}
+		if !matchesChecksumAlgorithm(fields[0], algorithm) {
+			continue
+		}
+		sum := strings.ToLower(fields[0])
+		if len(fields) == 1 {
+			bare = append(bare, sum)
+			continue
+		}
+		name := strings.TrimPrefix(fields[len(fields)-1], "*")
+		if name == filename || path.Base(name) == filename {
+			return sum, digestAlgorithm(sum, algorithm), nil
+		}
+	}
+
+	if len(bare) == 1 {
+		return bare[0], digestAlgorithm(bare[0], algorithm), nil
+	}
+	if algorithm != "" {
+		return "", "", fmt.Errorf("no %s checksum found for %s", algorithm, filename)
+	}
+	return "", "", fmt.Errorf("no checksum found for %s", filename)
+}
+
+// matchesChecksumAlgorithm reports whether digest has the length of algorithm's digests,
+// or of a SHA-256 or SHA-512 digest when algorithm is empty
+func matchesChecksumAlgorithm(digest, algorithm string) bool {
+	if algorithm == "" {
+		return len(digest) == checksumDigestLengths[ChecksumSHA256] || len(digest) == checksumDigestLengths[ChecksumSHA512]
+	}
+	return len(digest) == checksumDigestLengths[algorithm]
+}
+
+// digestAlgorithm returns algorithm, or the SHA-2 variant implied by digest's length when it is empty
+func digestAlgorithm(digest, algorithm string) string {
+	if algorithm != "" {
+		return algorithm
+	}
+	if len(digest) == checksumDigestLengths[ChecksumSHA512] {
+		return ChecksumSHA512
+	}
+	return ChecksumSHA256
+}
@@ -0,0 +1,160 @@
+package gateways
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// preRequestPlaceholder is replaced in download header values by the value the pre_request extracted
+const preRequestPlaceholder = "{pre_request}"
+
+// maxPreRequestBodySize bounds the page a pre_request reads, which only has to hold a token
+const maxPreRequestBodySize = 1 << 20
+
+// validateDownloadHeaders checks that a recipe's download headers and pre_request fit together:
+// a pre_request needs a URL, exactly one extraction and a header using its value
+func validateDownloadHeaders(download entities.RecipeDownload) error {
+	if err := validateHeaders(download.Headers); err != nil {
+		return err
+	}
+	referenced := false
+	for _, value := range download.Headers {
+		referenced = referenced || strings.Contains(value, preRequestPlaceholder)
+	}
+
+	pre := download.PreRequest
+	if pre == nil {
+		if referenced {
+			return fmt.Errorf("headers reference %s but no pre_request is configured", preRequestPlaceholder)
+		}
+		return nil
+	}
+	if pre.URL == "" {
+		return errors.New("pre_request.url is required")
+	}
+	if err := validateHeaders(pre.Headers); err != nil {
+		return fmt.Errorf("pre_request: %w", err)
+	}
+	if (pre.ExtractRegex == "") == (pre.ExtractJSON == "") {
+		return errors.New("pre_request needs exactly one of extract_regex or extract_json")
+	}
+	if pre.ExtractRegex != "" {
+		if _, err := regexp.Compile(pre.ExtractRegex); err != nil {
+			return fmt.Errorf("invalid pre_request.extract_regex: %w", err)
+		}
+	}
+	if !referenced {
+		return fmt.Errorf("pre_request value is never sent: reference %s in a download header", preRequestPlaceholder)
+	}
+	return nil
+}
+
+// validateHeaders checks recipe header names and rejects values that would split the request
+func validateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if name == "" || strings.ContainsAny(name, " :\t\r\n") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("header %s: value must not contain line breaks", name)
+		}
+	}
+	return nil
+}
+
+// downloadHeaders builds the request headers for a recipe's download: authentication, the recipe's
+// headers, and the value its pre_request extracts substituted into them
+func (d *Downloader) downloadHeaders(ctx context.Context, download entities.RecipeDownload, version string, platformConfig *entities.PlatformConfig) (http.Header, error) {
+	headers := downloadAuthHeaders(download)
+	if len(download.Headers) == 0 {
+		return headers, nil
+	}
+
+	value := ""
+	if pre := download.PreRequest; pre != nil {
+		// The pre_request carries only its own headers, plus the token when it stays on the download's host
+		preURL := d.BuildDownloadURL(pre.URL, version, platformConfig)
+		downloadURL := d.BuildDownloadURL(download.DownloadURL, version, platformConfig)
+		preHeaders := headersForHost(preURL, downloadURL, headers).Clone()
+		for name, header := range pre.Headers {
+			preHeaders.Set(name, header)
+		}
+
+		var err error
+		if value, err = d.runPreRequest(ctx, pre, preURL, preHeaders); err != nil {
+			return nil, fmt.Errorf("pre_request failed: %w", err)
+		}
+	}
+	for name, template := range download.Headers {
+		headers.Set(name, strings.ReplaceAll(template, preRequestPlaceholder, value))
+	}
+	return headers, nil
+}
+
+// runPreRequest fetches the pre_request page at url and extracts its value; the extracted value is never logged
+func (d *Downloader) runPreRequest(ctx context.Context, pre *entities.DownloadPreRequest, url string, headers http.Header) (string, error) {
+	body, err := d.fetchPreRequestPage(ctx, url, headers)
+	if err != nil {
+		return "", err
+	}
+
+	if pre.ExtractJSON != "" {
+		decoder := json.NewDecoder(strings.NewReader(body))
+		decoder.UseNumber()
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			return "", fmt.Errorf("failed to parse JSON response from %s: %w", url, err)
+		}
+		return selectJSONPath(doc, pre.ExtractJSON)
+	}
+
+	match := regexp.MustCompile(pre.ExtractRegex).FindStringSubmatch(body)
+	if match == nil {
+		return "", fmt.Errorf("extract_regex %q did not match the response from %s", pre.ExtractRegex, url)
+	}
+	if len(match) > 1 {
+		return match[1], nil
+	}
+	return match[0], nil
+}
+
+// fetchPreRequestPage GETs url and returns its body
+func (d *Downloader) fetchPreRequestPage(ctx context.Context, url string, headers http.Header) (string, error) {
+	ctx, cancel := requestContext(ctx, d.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent())
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	//nolint:errcheck // Defer close on HTTP response body
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d: %s (URL: %s)", resp.StatusCode, resp.Status, url)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPreRequestBodySize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,199 @@
+package gateways
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// Test a download gated on a token from a first endpoint succeeds with the extracted token
+func TestDownloader_DownloadArtifact_PreRequest(t *testing.T) {
+	recordRetryWaits(t)
+
+	const token = "tok-1.0.0-abc123"
+	var tokenRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token/1.0.0":
+			tokenRequests++
+			_, _ = w.Write([]byte(`{"data": {"token": "` + token + `", "expires": 60}}`))
+		case r.URL.Path == "/page":
+			_, _ = w.Write([]byte(`<a href="/dl?key=` + token + `">download</a>`))
+		case strings.HasPrefix(r.URL.Path, "/tool-"):
+			if r.Header.Get("X-Download-Token") != token || r.Header.Get("Cookie") != "session="+token {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte("gated binary"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	platforms := map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}}
+	headers := map[string]string{"X-Download-Token": "{pre_request}", "Cookie": "session={pre_request}"}
+	tests := []struct {
+		name       string
+		preRequest *entities.DownloadPreRequest
+	}{
+		{"json", &entities.DownloadPreRequest{URL: server.URL + "/token/{version}", ExtractJSON: "data.token"}},
+		{"regex", &entities.DownloadPreRequest{URL: server.URL + "/page", ExtractRegex: `key=([a-z0-9.-]+)`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := &entities.Recipe{
+				Name: "tool",
+				Download: entities.RecipeDownload{
+					DownloadURL: server.URL + "/tool-{version}-{os}-{arch}",
+					Headers:     headers,
+					PreRequest:  tt.preRequest,
+					Platforms:   platforms,
+				},
+			}
+
+			artifact, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir())
+			if err != nil {
+				t.Fatalf("DownloadArtifact() error = %v", err)
+			}
+			//nolint:gosec // G304: artifact.Path is the test download
+			content, err := os.ReadFile(artifact.Path)
+			if err != nil || string(content) != "gated binary" {
+				t.Errorf("downloaded %q, %v; want the gated binary", content, err)
+			}
+		})
+	}
+	if tokenRequests != 1 {
+		t.Errorf("token endpoint requests = %d, want 1", tokenRequests)
+	}
+
+	// Without the token step the download is refused
+	def := &entities.Recipe{Name: "tool", Download: entities.RecipeDownload{
+		DownloadURL: server.URL + "/tool-{version}-{os}-{arch}",
+		Platforms:   platforms,
+	}}
+	if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir()); err == nil {
+		t.Error("DownloadArtifact() should fail without the pre_request token")
+	}
+
+	// An extraction that finds nothing fails before the download
+	def.Download.Headers = headers
+	def.Download.PreRequest = &entities.DownloadPreRequest{URL: server.URL + "/page", ExtractRegex: `token=(\w+)`}
+	if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir()); err == nil ||
+		!strings.Contains(err.Error(), "did not match") {
+		t.Errorf("DownloadArtifact() error = %v, want extract_regex mismatch", err)
+	}
+}
+
+// Test a pre_request on another host gets only its own headers, never the download token or headers
+func TestDownloader_DownloadArtifact_PreRequestOtherHost(t *testing.T) {
+	const downloadToken, value = "s3cr3t-token", "tok-abc"
+	var received http.Header
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"token": "` + value + `"}`))
+	}))
+	defer tokenServer.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+downloadToken || r.Header.Get("X-Download-Token") != value {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("gated binary"))
+	}))
+	defer server.Close()
+
+	t.Setenv("POTIONS_TEST_DOWNLOAD_TOKEN", downloadToken)
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL:  server.URL + "/tool-{version}",
+			AuthTokenEnv: "POTIONS_TEST_DOWNLOAD_TOKEN",
+			Headers:      map[string]string{"X-Download-Token": "{pre_request}", "X-Api-Key": "download-only"},
+			PreRequest: &entities.DownloadPreRequest{
+				URL:         tokenServer.URL + "/token",
+				ExtractJSON: "token",
+				Headers:     map[string]string{"X-Client": "potions"},
+			},
+			Platforms: map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}},
+		},
+	}
+
+	if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir()); err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+	if received.Get("Authorization") != "" || received.Get("X-Api-Key") != "" {
+		t.Errorf("pre_request on another host received Authorization %q and X-Api-Key %q, want neither",
+			received.Get("Authorization"), received.Get("X-Api-Key"))
+	}
+	if received.Get("X-Client") != "potions" {
+		t.Errorf("pre_request X-Client = %q, want its own header", received.Get("X-Client"))
+	}
+}
+
+func TestValidateDownloadHeaders(t *testing.T) {
+	pre := &entities.DownloadPreRequest{URL: "https://example.com/token", ExtractJSON: "token"}
+	tests := []struct {
+		name     string
+		download entities.RecipeDownload
+		wantErr  string
+	}{
+		{name: "no headers", download: entities.RecipeDownload{}},
+		{name: "static headers", download: entities.RecipeDownload{Headers: map[string]string{"Accept": "application/octet-stream"}}},
+		{name: "pre_request used", download: entities.RecipeDownload{Headers: map[string]string{"X-Token": "{pre_request}"}, PreRequest: pre}},
+		{
+			name:     "placeholder without pre_request",
+			download: entities.RecipeDownload{Headers: map[string]string{"X-Token": "{pre_request}"}},
+			wantErr:  "no pre_request is configured",
+		},
+		{
+			name:     "pre_request unused",
+			download: entities.RecipeDownload{Headers: map[string]string{"Accept": "*/*"}, PreRequest: pre},
+			wantErr:  "never sent",
+		},
+		{
+			name: "both extractions",
+			download: entities.RecipeDownload{Headers: map[string]string{"X-Token": "{pre_request}"},
+				PreRequest: &entities.DownloadPreRequest{URL: pre.URL, ExtractJSON: "token", ExtractRegex: "(.*)"}},
+			wantErr: "exactly one of extract_regex or extract_json",
+		},
+		{
+			name: "invalid regex",
+			download: entities.RecipeDownload{Headers: map[string]string{"X-Token": "{pre_request}"},
+				PreRequest: &entities.DownloadPreRequest{URL: pre.URL, ExtractRegex: "("}},
+			wantErr: "invalid pre_request.extract_regex",
+		},
+		{
+			name: "invalid pre_request header",
+			download: entities.RecipeDownload{Headers: map[string]string{"X-Token": "{pre_request}"},
+				PreRequest: &entities.DownloadPreRequest{URL: pre.URL, ExtractJSON: "token", Headers: map[string]string{"X-A": "a\r\nb"}}},
+			wantErr: "pre_request: header X-A",
+		},
+		{
+			name:     "invalid header name",
+			download: entities.RecipeDownload{Headers: map[string]string{"X Token": "value"}},
+			wantErr:  "invalid header name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDownloadHeaders(tt.download)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateDownloadHeaders() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateDownloadHeaders() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package gateways
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxConcurrentDownloads bounds simultaneous artifact downloads across the process
+const DefaultMaxConcurrentDownloads = 4
+
+// downloadSlots is shared by every Downloader so parallel build workers cannot exceed the limit
+var downloadSlots = newDownloadLimiter(DefaultMaxConcurrentDownloads)
+
+// downloadLimiter is a counting semaphore backed by a buffered channel, so waiting respects ctx
+type downloadLimiter struct {
+	mu    sync.Mutex
+	slots chan struct{} // nil disables the limit
+}
+
+func newDownloadLimiter(limit int) *downloadLimiter {
+	l := &downloadLimiter{}
+	l.setLimit(limit)
+	return l
+}
+
+// acquire blocks until a download slot is free and returns the function releasing it,
+// or fails once ctx is done
+func (l *downloadLimiter) acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	slots := l.slots
+	l.mu.Unlock()
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a download slot: %w", ctx.Err())
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-slots })
+	}, nil
+}
+
+// setLimit replaces the semaphore; slots already held are released to the one they came from,
+// so the limit should be set before downloads start
+func (l *downloadLimiter) setLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit <= 0 {
+		l.slots = nil
+		return
+	}
+	l.slots = make(chan struct{}, limit)
+}
+
+// SetMaxConcurrentDownloads sets how many downloads may run at once across the process (zero disables the limit)
+func SetMaxConcurrentDownloads(limit int) {
+	if limit >= 0 {
+		downloadSlots.setLimit(limit)
+	}
+}
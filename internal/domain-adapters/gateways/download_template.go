@@ -0,0 +1,85 @@
+package gateways
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// urlPlaceholderPattern matches a {name} placeholder in a download template
+var urlPlaceholderPattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_-]*)\}`)
+
+// builtinURLPlaceholders are substituted by BuildDownloadURL on every platform
+var builtinURLPlaceholders = []string{"version", "os", "arch", "suffix"}
+
+// ValidateDownloadTemplates checks that every placeholder in the recipe's download templates
+// is substituted on each of its platforms
+func ValidateDownloadTemplates(def *entities.Recipe) error {
+	platforms := make([]string, 0, len(def.Download.Platforms))
+	for platform := range def.Download.Platforms {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	for _, platform := range platforms {
+		platformConfig := withPlatformDefaults(platform, def.Download.Platforms[platform])
+		if err := validatePlatformTemplates(def, platform, &platformConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePlatformTemplates checks the templates BuildDownloadURL expands for one platform
+func validatePlatformTemplates(def *entities.Recipe, platform string, platformConfig *entities.PlatformConfig) error {
+	templates := []struct{ field, template string }{
+		{"download_url", def.Download.DownloadURL},
+		{"mirror", def.Download.Mirror},
+		{"checksum_url", def.Download.ChecksumURL},
+		{"asset_pattern", platformConfig.AssetPattern},
+	}
+	for _, t := range templates {
+		if err := ValidateURLTemplate(t.template, platformConfig); err != nil {
+			return fmt.Errorf("%s for %s: %w", t.field, platform, err)
+		}
+	}
+
+	// The suffix is substituted into the URL after the other placeholders, so only {version} is expanded in it
+	if unknown := unsubstitutedPlaceholders(platformConfig.Suffix, []string{"version"}); len(unknown) > 0 {
+		return fmt.Errorf("suffix for %s: unsubstituted placeholder %s (only {version} is expanded in a suffix)",
+			platform, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// ValidateURLTemplate returns an error naming each placeholder in template that BuildDownloadURL
+// would leave in the URL: known placeholders are version, os, arch, suffix and the platform's custom keys
+func ValidateURLTemplate(template string, platformConfig *entities.PlatformConfig) error {
+	known := append([]string(nil), builtinURLPlaceholders...)
+	if platformConfig != nil {
+		for key := range platformConfig.Custom {
+			known = append(known, key)
+		}
+	}
+	sort.Strings(known)
+
+	if unknown := unsubstitutedPlaceholders(template, known); len(unknown) > 0 {
+		return fmt.Errorf("unsubstituted placeholder %s in %q (known: %s)", strings.Join(unknown, ", "), template, strings.Join(known, ", "))
+	}
+	return nil
+}
+
+// unsubstitutedPlaceholders lists the {placeholders} of template that are not in known, in order of appearance
+func unsubstitutedPlaceholders(template string, known []string) []string {
+	var unknown []string
+	for _, match := range urlPlaceholderPattern.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(known, match[1]) && !slices.Contains(unknown, match[0]) {
+			unknown = append(unknown, match[0])
+		}
+	}
+	return unknown
+}
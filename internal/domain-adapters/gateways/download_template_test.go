@@ -0,0 +1,80 @@
+package gateways
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+func TestValidateURLTemplate(t *testing.T) {
+	platform := &entities.PlatformConfig{OS: "linux", Arch: "x86_64", Custom: map[string]string{"triple": "x86_64-unknown-linux-musl"}}
+
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{name: "builtin placeholders", template: "https://example.com/v{version}/tool-{os}-{arch}{suffix}.tar.gz"},
+		{name: "custom placeholder", template: "https://example.com/v{version}/tool-{triple}.tar.gz"},
+		{name: "glob braces are not placeholders", template: "tool-*-{os}.tar.gz"},
+		{
+			name:     "missing custom key",
+			template: "https://example.com/v{version}/tool-{target}.tar.gz",
+			wantErr:  "unsubstituted placeholder {target}",
+		},
+		{
+			name:     "each missing key named once",
+			template: "https://example.com/{target}/{libc}/{target}.tar.gz",
+			wantErr:  "unsubstituted placeholder {target}, {libc}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateURLTemplate(tt.template, platform)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateURLTemplate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateURLTemplate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDownloadTemplates(t *testing.T) {
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL: "https://example.com/v{version}/tool-{target}.tar.gz",
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-x86_64": {Custom: map[string]string{"target": "x86_64-unknown-linux-gnu"}},
+				"darwin-arm64": {},
+			},
+		},
+	}
+
+	err := ValidateDownloadTemplates(def)
+	if err == nil || !strings.Contains(err.Error(), "download_url for darwin-arm64: unsubstituted placeholder {target}") {
+		t.Fatalf("ValidateDownloadTemplates() error = %v, want darwin-arm64 missing {target}", err)
+	}
+
+	// Build time fails before any request is made
+	if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "darwin-arm64", t.TempDir()); err == nil ||
+		!strings.Contains(err.Error(), "unsubstituted placeholder {target}") {
+		t.Errorf("DownloadArtifact() error = %v, want unsubstituted placeholder", err)
+	}
+
+	def.Download.Platforms["darwin-arm64"] = entities.PlatformConfig{
+		Suffix: "-{target}",
+		Custom: map[string]string{"target": "aarch64-apple-darwin"},
+	}
+	if err := ValidateDownloadTemplates(def); err == nil || !strings.Contains(err.Error(), "suffix for darwin-arm64") {
+		t.Errorf("ValidateDownloadTemplates() error = %v, want suffix placeholder error", err)
+	}
+}
@@ -2,9 +2,14 @@ package gateways
 
 import (
 	"archive/tar"
+	"archive/zip"
+	"bytes"
 	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
@@ -15,6 +20,19 @@ import (
 	"time"
 
 	"github.com/ochairo/potions/internal/domain/entities"
+	"github.com/ochairo/potions/internal/domain/interfaces"
+)
+
+// gitCloneMaxAttempts bounds git clone attempts on transient failures
+const gitCloneMaxAttempts = 3
+
+// DefaultDownloadTimeout is the per-request deadline for artifact downloads
+const DefaultDownloadTimeout = 5 * time.Minute
+
+// Extraction limits to guard against decompression bombs
+const (
+	maxExtractFileSize  = 1 << 30 // 1GB per file
+	maxExtractTotalSize = 4 << 30 // 4GB per archive
 )
 
 // Security validation functions
@@ -90,24 +108,54 @@ func validateGitTag(tag string) error {
 // Downloader handles downloading artifacts from URLs
 type Downloader struct {
 	httpClient *http.Client
+	timeout    time.Duration // Per-request deadline; zero disables it
+	logger     interfaces.Logger
+	runGit     gitRunner
+	sleep      func(time.Duration)
+	apiBaseURL string // GitHub API used to resolve asset_pattern downloads
 }
 
+// gitRunner runs git with args until ctx is done and returns its captured stderr
+type gitRunner func(ctx context.Context, args ...string) (string, error)
+
 // NewDownloader creates a new downloader
 func NewDownloader() *Downloader {
 	return &Downloader{
-		httpClient: &http.Client{
-			Timeout: 5 * time.Minute, // Long timeout for large downloads
-		},
+		httpClient: NewHTTPClient(0),
+		timeout:    DefaultDownloadTimeout,
+		logger:     &interfaces.NoOpLogger{},
+		runGit:     runGitCommand,
+		sleep:      time.Sleep,
+		apiBaseURL: defaultGitHubAPIURL,
 	}
 }
 
-// DownloadArtifact downloads an artifact based on recipe and platform
-func (d *Downloader) DownloadArtifact(def *entities.Recipe, version, platform, outputDir string) (*entities.Artifact, error) {
+// WithTimeout sets the per-request download deadline (zero disables it)
+func (d *Downloader) WithTimeout(timeout time.Duration) *Downloader {
+	d.timeout = timeout
+	return d
+}
+
+// WithLogger sets the logger used for retry diagnostics
+func (d *Downloader) WithLogger(logger interfaces.Logger) *Downloader {
+	d.logger = logger
+	return d
+}
+
+// DownloadFile downloads url to dest as-is, without verification or extraction, until ctx is done
+func (d *Downloader) DownloadFile(ctx context.Context, url, dest string) error {
+	return d.downloadFile(ctx, url, dest, nil)
+}
+
+// DownloadArtifact downloads an artifact based on recipe and platform; cancelling ctx aborts
+// any request or git clone in flight
+func (d *Downloader) DownloadArtifact(ctx context.Context, def *entities.Recipe, version, platform, outputDir string) (*entities.Artifact, error) {
 	// Get platform config
-	platformConfig, exists := def.Download.Platforms[platform]
+	platformConfig, exists := def.Download.Platform(platform)
 	if !exists {
 		return nil, fmt.Errorf("platform %s not supported", platform)
 	}
+	platformConfig = withPlatformDefaults(def.Download.PlatformKey(platform), platformConfig)
 
 	// Create output directory
 	if err := os.MkdirAll(outputDir, 0750); err != nil {
@@ -116,6 +164,7 @@ func (d *Downloader) DownloadArtifact(def *entities.Recipe, version, platform, o
 
 	var finalPath string
 	var downloadedFilePath string
+	var sourceURL string
 
 	// Check if this is a git-based download
 	if def.Download.Method == "git" && def.Download.GitURL != "" {
@@ -129,16 +178,46 @@ func (d *Downloader) DownloadArtifact(def *entities.Recipe, version, platform, o
 			return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
 		}
 
-		if err := d.cloneGitRepo(def.Download.GitURL, gitTag, absCloneDir); err != nil {
+		releaseSlot, err := downloadSlots.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = d.cloneGitRepo(ctx, def.Download.GitURL, gitTag, absCloneDir)
+		releaseSlot()
+		if err != nil {
 			return nil, fmt.Errorf("git clone failed: %w", err)
 		}
 		finalPath = absCloneDir
+		sourceURL = gitSourceURL(def.Download.GitURL, gitTag)
 		// For git downloads, there's no separate download file
 		downloadedFilePath = ""
 	} else {
-		// HTTP download (existing behavior)
+		// A placeholder left in the URL would only surface as a 404
+		if err := validatePlatformTemplates(def, platform, &platformConfig); err != nil {
+			return nil, fmt.Errorf("invalid download template: %w", err)
+		}
+		if err := validateDownloadHeaders(def.Download); err != nil {
+			return nil, fmt.Errorf("invalid download headers: %w", err)
+		}
+
+		// HTTP download (existing behavior); the slot is held until the network work is done
+		releaseSlot, err := downloadSlots.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer releaseSlot()
 		url := d.BuildDownloadURL(def.Download.DownloadURL, version, &platformConfig)
 
+		// Pick the asset from the release itself rather than templating its URL
+		if platformConfig.AssetPattern != "" {
+			pattern := d.BuildDownloadURL(platformConfig.AssetPattern, version, &platformConfig)
+			assetURL, err := d.findReleaseAsset(ctx, def.Version.Source, version, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("release asset lookup failed: %w", err)
+			}
+			url = assetURL
+		}
+
 		// Build mirror URL if available
 		mirrorURL := ""
 		if def.Download.Mirror != "" {
@@ -150,35 +229,68 @@ func (d *Downloader) DownloadArtifact(def *entities.Recipe, version, platform, o
 		outputPath := filepath.Join(outputDir, filename)
 
 		// Download file with mirror fallback
-		if err := d.downloadFileWithFallback(url, mirrorURL, outputPath); err != nil {
+		headers, err := d.downloadHeaders(ctx, def.Download, version, &platformConfig)
+		if err != nil {
+			return nil, err
+		}
+		usedURL, err := d.downloadFileWithFallback(ctx, url, mirrorURL, outputPath, headers)
+		if err != nil {
 			return nil, fmt.Errorf("download failed: %w", err)
 		}
+		sourceURL = usedURL
 
 		// Keep track of the original downloaded file path
 		downloadedFilePath = outputPath
 
-		// Extract if tarball
-		if strings.HasSuffix(filename, ".tar.gz") || strings.HasSuffix(filename, ".tgz") {
+		// Verify the archive itself before anything is extracted from it
+		if def.Download.ChecksumURL != "" {
+			checksumURL := d.BuildDownloadURL(def.Download.ChecksumURL, version, &platformConfig)
+			if err := d.verifyDownloadChecksum(ctx, checksumURL, outputPath, filename,
+				def.Download.ChecksumAlgorithm, def.Download.AllowWeakChecksum, headersForHost(checksumURL, url, headers)); err != nil {
+				return nil, fmt.Errorf("checksum verification failed: %w", err)
+			}
+		} else if def.Download.ChecksumFromReleaseBody {
+			if err := d.verifyReleaseBodyChecksum(ctx, def.Version.Source, version, outputPath, filename,
+				def.Download.ChecksumAlgorithm, def.Download.AllowWeakChecksum); err != nil {
+				return nil, fmt.Errorf("checksum verification failed: %w", err)
+			}
+		}
+		releaseSlot()
+
+		// Extract if archive
+		switch {
+		case strings.HasSuffix(filename, ".tar.gz") || strings.HasSuffix(filename, ".tgz"):
 			// Create unique extraction directory using filename without extension
 			baseName := strings.TrimSuffix(strings.TrimSuffix(filename, ".tar.gz"), ".tgz")
 			extractDir := filepath.Join(outputDir, baseName+"-extracted")
-			if err := d.extractTarGz(outputPath, extractDir); err != nil {
+			if err := d.extractTarGz(outputPath, extractDir, def.Download.ExtractStripComponents); err != nil {
 				return nil, fmt.Errorf("extraction failed: %w", err)
 			}
 
-			// Find the actual extracted directory
-			entries, err := os.ReadDir(extractDir)
+			root, err := d.extractedContents(extractDir, def.Download.InnerArchive)
 			if err != nil {
-				return nil, fmt.Errorf("failed to read extracted directory: %w", err)
+				return nil, err
+			}
+			finalPath = root
+		case strings.HasSuffix(filename, ".zip"):
+			extractDir := filepath.Join(outputDir, strings.TrimSuffix(filename, ".zip")+"-extracted")
+			if err := d.extractZip(outputPath, extractDir); err != nil {
+				return nil, fmt.Errorf("extraction failed: %w", err)
 			}
 
-			// If there's exactly one directory, use it as the working directory
-			if len(entries) == 1 && entries[0].IsDir() {
-				finalPath = filepath.Join(extractDir, entries[0].Name())
-			} else {
-				finalPath = extractDir
+			root, err := d.extractedContents(extractDir, def.Download.InnerArchive)
+			if err != nil {
+				return nil, err
+			}
+			finalPath = root
+		case strings.HasSuffix(filename, ".gz"):
+			// Gzipped single file (not a tarball): decompress to the underlying raw binary
+			binaryPath := strings.TrimSuffix(outputPath, ".gz")
+			if err := d.decompressGzip(outputPath, binaryPath); err != nil {
+				return nil, fmt.Errorf("decompression failed: %w", err)
 			}
-		} else {
+			finalPath = binaryPath
+		default:
 			finalPath = outputPath
 		}
 	}
@@ -190,6 +302,7 @@ func (d *Downloader) DownloadArtifact(def *entities.Recipe, version, platform, o
 		Platform:     platform,
 		Path:         finalPath,
 		DownloadPath: downloadedFilePath,
+		SourceURL:    sourceURL,
 		Type:         "binary",
 	}
 
@@ -233,44 +346,125 @@ func (d *Downloader) BuildDownloadURL(template, version string, platformConfig *
 	return url
 }
 
+// withPlatformDefaults fills empty OS/Arch values from an "<os>-<arch>" platform key
+// (e.g. linux-armv7, linux-ppc64le) so recipes only need overrides for upstream naming
+func withPlatformDefaults(platform string, cfg entities.PlatformConfig) entities.PlatformConfig {
+	osName, arch, found := strings.Cut(platform, "-")
+	if !found {
+		return cfg
+	}
+	if cfg.OS == "" {
+		cfg.OS = osName
+	}
+	if cfg.Arch == "" {
+		cfg.Arch = arch
+	}
+	return cfg
+}
+
+// downloadAuthHeaders builds request headers for authenticated downloads
+// The token is read from the environment variable named by the recipe and never logged
+func downloadAuthHeaders(download entities.RecipeDownload) http.Header {
+	headers := make(http.Header)
+	if download.AuthTokenEnv == "" {
+		return headers
+	}
+
+	token := os.Getenv(download.AuthTokenEnv)
+	if token == "" {
+		fmt.Fprintf(os.Stderr, "⚠️  %s is not set, downloading without authentication\n", download.AuthTokenEnv)
+		return headers
+	}
+
+	scheme := download.AuthScheme
+	if scheme == "" {
+		scheme = "Bearer"
+	}
+	headers.Set("Authorization", scheme+" "+token)
+	return headers
+}
+
 // downloadFileWithFallback downloads a file from URL with automatic fallback to mirror on failure
-func (d *Downloader) downloadFileWithFallback(primaryURL, mirrorURL, dest string) error {
+// and returns the URL the file was actually fetched from
+// Authorization and recipe headers are only sent to the mirror when it shares the primary URL's host
+func (d *Downloader) downloadFileWithFallback(ctx context.Context, primaryURL, mirrorURL, dest string, headers http.Header) (string, error) {
 	// Try primary URL first
-	err := d.downloadFile(primaryURL, dest)
+	err := d.downloadFile(ctx, primaryURL, dest, headers)
 	if err == nil {
-		return nil
+		return primaryURL, nil
 	}
 
 	// If primary fails and mirror is available, try mirror
 	if mirrorURL != "" && mirrorURL != primaryURL {
 		fmt.Fprintf(os.Stderr, "Primary URL failed (%v), attempting mirror...\n", err)
-		mirrorErr := d.downloadFile(mirrorURL, dest)
+		mirrorErr := d.downloadFile(ctx, mirrorURL, dest, headersForHost(mirrorURL, primaryURL, headers))
 		if mirrorErr == nil {
 			fmt.Fprintf(os.Stderr, "Successfully downloaded from mirror\n")
-			return nil
+			return mirrorURL, nil
 		}
 		// Return original error (primary) but mention both failed
-		return fmt.Errorf("primary failed: %w (mirror also failed: %w)", err, mirrorErr)
+		return "", fmt.Errorf("primary failed: %w (mirror also failed: %w)", err, mirrorErr)
 	}
 
 	// No mirror or mirror is same as primary
-	return err
+	return "", err
+}
+
+// gitSourceURL formats a git repository and ref as a pip/SPDX-style source URL
+func gitSourceURL(gitURL, ref string) string {
+	return fmt.Sprintf("git+%s@%s", gitURL, ref)
+}
+
+// requestContext derives a context with the given deadline, or a plain cancellable one when timeout is zero
+func requestContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// sameHost reports whether two URLs point at the same host
+func sameHost(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	return errA == nil && errB == nil && ua.Host == ub.Host
+}
+
+// headersForHost returns headers for a request to target, or none when target is on a different
+// host than the download they were built for, so a token never leaves the download's host
+func headersForHost(target, downloadURL string, headers http.Header) http.Header {
+	if !sameHost(target, downloadURL) {
+		return make(http.Header)
+	}
+	return headers
 }
 
 // downloadFile downloads a file from URL to destination
-func (d *Downloader) downloadFile(url, dest string) error {
+func (d *Downloader) downloadFile(ctx context.Context, url, dest string, headers http.Header) error {
+	// Bound the whole request, including the body copy, by ctx and the per-request deadline
+	ctx, cancel := requestContext(ctx, d.timeout)
+	defer cancel()
+
 	// Create request
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set user agent
-	req.Header.Set("User-Agent", "potions/1.0")
+	req.Header.Set("User-Agent", UserAgent())
+	for key, values := range headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
 
 	// Execute request
 	resp, err := d.httpClient.Do(req)
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("download timed out after %s: %w", d.timeout, err)
+		}
 		return fmt.Errorf("HTTP request failed: %w", err)
 	}
 	//nolint:errcheck // Defer close on HTTP response body
@@ -292,6 +486,9 @@ func (d *Downloader) downloadFile(url, dest string) error {
 	// Copy with progress tracking
 	written, err := io.Copy(out, resp.Body)
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("download timed out after %s (%d bytes received): %w", d.timeout, written, err)
+		}
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -301,8 +498,23 @@ func (d *Downloader) downloadFile(url, dest string) error {
 	return nil
 }
 
-// extractTarGz extracts a .tar.gz file to destination directory
-func (d *Downloader) extractTarGz(tarPath, destDir string) error {
+// extractTarGz extracts a .tar.gz file to destination directory, removing a newly
+// created destination on failure so a truncated archive never leaves a partial tree
+// stripComponents drops that many leading path components from each entry, like tar --strip-components
+func (d *Downloader) extractTarGz(tarPath, destDir string, stripComponents int) error {
+	_, statErr := os.Stat(destDir)
+	if err := d.extractTarGzEntries(tarPath, destDir, stripComponents); err != nil {
+		if os.IsNotExist(statErr) {
+			//nolint:errcheck // Best effort cleanup of the partial extraction
+			os.RemoveAll(destDir)
+		}
+		return err
+	}
+	return nil
+}
+
+// extractTarGzEntries extracts every entry and checks the archive was consumed completely
+func (d *Downloader) extractTarGzEntries(tarPath, destDir string, stripComponents int) error {
 	// Open tar.gz file
 	//nolint:gosec // G304: File path tarPath is function parameter for extraction
 	file, err := os.Open(tarPath)
@@ -334,6 +546,8 @@ func (d *Downloader) extractTarGz(tarPath, destDir string) error {
 		linkname string
 	}
 	var symlinks []symlinkInfo
+	var fileCount int
+	var totalBytes int64
 
 	// Extract all files (first pass: files and directories)
 	for {
@@ -345,19 +559,25 @@ func (d *Downloader) extractTarGz(tarPath, destDir string) error {
 			return fmt.Errorf("tar read error: %w", err)
 		}
 
-		// Build target path
-		//nolint:gosec // G305: Path traversal validated by checks below
-		target := filepath.Join(destDir, header.Name)
-
 		// SECURITY: Prevent Zip Slip vulnerability
 		// 1. Check for absolute paths in tar entries
 		if filepath.IsAbs(header.Name) {
 			return fmt.Errorf("security: tar entry contains absolute path: %s", header.Name)
 		}
 
+		// Drop the leading components; entries no deeper than the stripped prefix are skipped
+		name, ok := stripPathComponents(header.Name, stripComponents)
+		if !ok {
+			continue
+		}
+
+		// Build target path
+		//nolint:gosec // G305: Path traversal validated by checks below
+		target := filepath.Join(destDir, name)
+
 		// 2. Check for path traversal attempts (../ as path component, not substring in filename)
-		// Split the path into components and check each one
-		pathComponents := strings.Split(filepath.ToSlash(header.Name), "/")
+		// Split the path into components and check each one, after stripping
+		pathComponents := strings.Split(filepath.ToSlash(name), "/")
 		for _, component := range pathComponents {
 			if component == ".." {
 				return fmt.Errorf("security: tar entry contains path traversal: %s", header.Name)
@@ -402,13 +622,22 @@ func (d *Downloader) extractTarGz(tarPath, destDir string) error {
 			if err != nil {
 				return fmt.Errorf("failed to create file: %w", err)
 			} // Copy file contents with size limit (1GB max to prevent decompression bombs)
-			if _, err := io.Copy(outFile, io.LimitReader(tr, 1<<30)); err != nil {
+			written, err := io.Copy(outFile, io.LimitReader(tr, maxExtractFileSize+1))
+			if err != nil {
 				_ = outFile.Close()
-				return fmt.Errorf("failed to write file: %w", err)
+				return fmt.Errorf("failed to write %s (archive truncated or corrupt?): %w", header.Name, err)
 			}
 			if err := outFile.Close(); err != nil {
 				return fmt.Errorf("failed to close file: %w", err)
 			}
+			if written > maxExtractFileSize {
+				return fmt.Errorf("security: tar entry %s exceeds size limit", header.Name)
+			}
+			if written != header.Size {
+				return fmt.Errorf("incomplete tar entry %s: wrote %d of %d bytes", header.Name, written, header.Size)
+			}
+			fileCount++
+			totalBytes += written
 
 		case tar.TypeSymlink:
 			// Defer symlink creation to second pass
@@ -422,6 +651,15 @@ func (d *Downloader) extractTarGz(tarPath, destDir string) error {
 		}
 	}
 
+	// Read through the end of the gzip stream so its length and CRC trailer are verified;
+	// a truncated download fails here even when the tar end marker was intact
+	if _, err := io.Copy(io.Discard, io.LimitReader(gzr, maxExtractFileSize)); err != nil {
+		return fmt.Errorf("archive is truncated or corrupt: %w", err)
+	}
+	if fileCount == 0 && len(symlinks) == 0 {
+		return fmt.Errorf("archive %s contains no files", filepath.Base(tarPath))
+	}
+
 	// Second pass: create symlinks after all files exist
 	for _, link := range symlinks {
 		// Create parent directory for symlink with writable permissions
@@ -435,10 +673,231 @@ func (d *Downloader) extractTarGz(tarPath, destDir string) error {
 		}
 	}
 
+	fmt.Fprintf(os.Stderr, "Extracted %d files (%d bytes) to %s\n", fileCount, totalBytes, destDir)
+	return nil
+}
+
+// stripPathComponents removes the first n components of an archive entry name (a leading
+// "./" is not counted), reporting false when nothing of the entry remains
+func stripPathComponents(name string, n int) (string, bool) {
+	if n <= 0 {
+		return name, true
+	}
+	var components []string
+	for _, component := range strings.Split(filepath.ToSlash(name), "/") {
+		if component != "" && component != "." {
+			components = append(components, component)
+		}
+	}
+	if len(components) <= n {
+		return "", false
+	}
+	return strings.Join(components[n:], "/"), true
+}
+
+// decompressGzip decompresses a gzipped single file to destPath as an executable
+func (d *Downloader) decompressGzip(gzPath, destPath string) error {
+	//nolint:gosec // G304: File path gzPath is function parameter for decompression
+	file, err := os.Open(gzPath)
+	if err != nil {
+		return fmt.Errorf("failed to open gzip file: %w", err)
+	}
+	//nolint:errcheck // Defer close on read-only file
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	//nolint:errcheck // Defer close on gzip reader
+	defer gzr.Close()
+
+	//nolint:gosec // G302,G304: destPath is derived from the download path; raw binaries must be executable
+	outFile, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0750)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	written, err := io.Copy(outFile, io.LimitReader(gzr, maxExtractFileSize+1))
+	if err != nil {
+		_ = outFile.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := outFile.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if written > maxExtractFileSize {
+		return fmt.Errorf("security: %s exceeds decompression size limit", filepath.Base(gzPath))
+	}
+
+	return nil
+}
+
+// extractZip extracts a .zip file to destination directory
+func (d *Downloader) extractZip(zipPath, destDir string) error {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip: %w", err)
+	}
+	//nolint:errcheck // Defer close on read-only archive
+	defer zr.Close()
+
+	if err := os.MkdirAll(destDir, 0750); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	var totalWritten int64
+	for _, f := range zr.File {
+		// Build target path
+		//nolint:gosec // G305: Path traversal validated by checks below
+		target := filepath.Join(destDir, f.Name)
+
+		// SECURITY: Prevent Zip Slip vulnerability (same checks as tar extraction)
+		if filepath.IsAbs(f.Name) || strings.HasPrefix(filepath.ToSlash(f.Name), "/") {
+			return fmt.Errorf("security: zip entry contains absolute path: %s", f.Name)
+		}
+		for _, component := range strings.Split(filepath.ToSlash(f.Name), "/") {
+			if component == ".." {
+				return fmt.Errorf("security: zip entry contains path traversal: %s", f.Name)
+			}
+		}
+		if err := validatePathWithinBase(target, destDir); err != nil {
+			return fmt.Errorf("security: path traversal attempt: %w", err)
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			continue
+		}
+
+		if f.Mode()&os.ModeSymlink != 0 {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring symlink in zip: %s\n", f.Name)
+			continue
+		}
+
+		// SECURITY: Enforce per-file and aggregate size limits (declared sizes can lie,
+		// so the copy below is limited as well)
+		if f.UncompressedSize64 > maxExtractFileSize {
+			return fmt.Errorf("security: zip entry %s exceeds size limit (%d bytes)", f.Name, f.UncompressedSize64)
+		}
+
+		written, err := extractZipFile(f, target, maxExtractTotalSize-totalWritten)
+		if err != nil {
+			return err
+		}
+		totalWritten += written
+	}
+
 	fmt.Fprintf(os.Stderr, "Extracted to %s\n", destDir)
 	return nil
 }
 
+// extractZipFile writes a single zip entry to target, enforcing size limits
+func extractZipFile(f *zip.File, target string, remaining int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return 0, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	// SECURITY: Set explicit file permissions, preserving the executable bit when recorded
+	mode := os.FileMode(0640)
+	if f.Mode()&0111 != 0 {
+		mode = 0750
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open zip entry %s: %w", f.Name, err)
+	}
+	//nolint:errcheck // Defer close on read-only zip entry
+	defer rc.Close()
+
+	//nolint:gosec // G304: target path validated by validatePathWithinBase
+	outFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	limit := min(int64(maxExtractFileSize), remaining)
+	written, err := io.Copy(outFile, io.LimitReader(rc, limit+1))
+	if err != nil {
+		_ = outFile.Close()
+		return written, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := outFile.Close(); err != nil {
+		return written, fmt.Errorf("failed to close file: %w", err)
+	}
+	if written > limit {
+		return written, fmt.Errorf("security: zip entry %s exceeds extraction size limit", f.Name)
+	}
+
+	return written, nil
+}
+
+// extractedContents returns the working directory for an extracted download, first extracting
+// the archive matching innerArchive when the recipe declares one (a single level only)
+func (d *Downloader) extractedContents(extractDir, innerArchive string) (string, error) {
+	root, err := extractedRoot(extractDir)
+	if err != nil || innerArchive == "" {
+		return root, err
+	}
+
+	if _, err := filepath.Match(innerArchive, ""); err != nil {
+		return "", fmt.Errorf("invalid inner_archive pattern %q: %w", innerArchive, err)
+	}
+	var matches []string
+	err = filepath.WalkDir(extractDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if matched, _ := filepath.Match(innerArchive, entry.Name()); matched && entry.Type().IsRegular() {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search for inner archive: %w", err)
+	}
+	if len(matches) != 1 {
+		return "", fmt.Errorf("inner_archive %q matched %d files in the download, want exactly one", innerArchive, len(matches))
+	}
+
+	// The inner archive goes through the same traversal and size protections as the outer one
+	inner := matches[0]
+	name := filepath.Base(inner)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"):
+		innerDir := filepath.Join(filepath.Dir(inner), strings.TrimSuffix(strings.TrimSuffix(name, ".tar.gz"), ".tgz")+"-extracted")
+		if err := d.extractTarGz(inner, innerDir, 0); err != nil {
+			return "", fmt.Errorf("inner archive extraction failed: %w", err)
+		}
+		return extractedRoot(innerDir)
+	case strings.HasSuffix(name, ".zip"):
+		innerDir := filepath.Join(filepath.Dir(inner), strings.TrimSuffix(name, ".zip")+"-extracted")
+		if err := d.extractZip(inner, innerDir); err != nil {
+			return "", fmt.Errorf("inner archive extraction failed: %w", err)
+		}
+		return extractedRoot(innerDir)
+	default:
+		return "", fmt.Errorf("inner archive %s is not a .tar.gz, .tgz or .zip file", name)
+	}
+}
+
+// extractedRoot returns the working directory for an extracted archive:
+// the single top-level directory if there is exactly one, otherwise extractDir itself
+func extractedRoot(extractDir string) (string, error) {
+	entries, err := os.ReadDir(extractDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read extracted directory: %w", err)
+	}
+
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(extractDir, entries[0].Name()), nil
+	}
+	return extractDir, nil
+}
+
 // sanitizeFilename removes invalid characters and query parameters from a filename
 func sanitizeFilename(rawURL string) string {
 	// Parse URL to remove query parameters and fragments
@@ -465,7 +924,7 @@ func sanitizeFilename(rawURL string) string {
 }
 
 // cloneGitRepo clones a git repository to the destination directory
-func (d *Downloader) cloneGitRepo(gitURL, tag, destDir string) error {
+func (d *Downloader) cloneGitRepo(ctx context.Context, gitURL, tag, destDir string) error {
 	// Security: Validate destDir is an absolute path and is clean
 	if !filepath.IsAbs(destDir) {
 		return fmt.Errorf("destination directory must be absolute path")
@@ -483,15 +942,107 @@ func (d *Downloader) cloneGitRepo(gitURL, tag, destDir string) error {
 		return err
 	}
 
-	//nolint:gosec // G204: gitURL and tag validated by validateGitURL and validateGitTag
-	cmd := exec.Command("git", "clone", "--depth=1", "--branch="+tag, gitURL, destDir)
-	cmd.Stdout = os.Stderr
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
+	cloneArgs := []string{"clone", "--depth=1", "--branch=" + tag, gitURL, destDir}
+	if err := d.runGitWithRetry(ctx, destDir, cloneArgs...); err != nil {
 		return fmt.Errorf("git clone failed: %w", err)
 	}
 
 	fmt.Fprintf(os.Stderr, "Cloned %s (tag: %s) to %s\n", gitURL, tag, destDir)
 	return nil
 }
+
+// runGitWithRetry runs a git command, retrying with backoff when stderr indicates a
+// transient network failure; permanent failures (missing repo or tag) fail immediately
+// cleanupDir is removed between attempts so a partial clone doesn't block the retry
+func (d *Downloader) runGitWithRetry(ctx context.Context, cleanupDir string, args ...string) error {
+	var lastErr error
+	for attempt := 1; attempt <= gitCloneMaxAttempts; attempt++ {
+		d.logger.Info("Running git", interfaces.F("command", args[0]), interfaces.F("attempt", attempt), interfaces.F("max_attempts", gitCloneMaxAttempts))
+
+		stderr, err := d.runGit(ctx, args...)
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("git %s interrupted: %w", args[0], ctxErr)
+		}
+
+		if !isRetryableGitError(stderr) {
+			d.logger.Error("git failed with permanent error", interfaces.F("command", args[0]), interfaces.F("attempt", attempt), interfaces.F("error", lastErr))
+			return lastErr
+		}
+		if attempt == gitCloneMaxAttempts {
+			break
+		}
+
+		backoff := calculateBackoff(attempt - 1)
+		d.logger.Warn("git failed with transient error, retrying", interfaces.F("command", args[0]), interfaces.F("attempt", attempt), interfaces.F("backoff", backoff.String()), interfaces.F("error", lastErr))
+		if cleanupDir != "" {
+			if rmErr := os.RemoveAll(cleanupDir); rmErr != nil {
+				return fmt.Errorf("failed to clean up partial clone: %w", rmErr)
+			}
+		}
+		d.sleep(backoff)
+	}
+
+	return fmt.Errorf("after %d attempts: %w", gitCloneMaxAttempts, lastErr)
+}
+
+// runGitCommand executes git, streaming output to stderr while capturing stderr for classification
+func runGitCommand(ctx context.Context, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	//nolint:gosec // G204: Arguments validated by validateGitURL and validateGitTag
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	err := cmd.Run()
+	return stderr.String(), err
+}
+
+// Git stderr fragments that identify permanent and transient failures
+var (
+	permanentGitErrors = []string{
+		"not found",
+		"does not exist",
+		"authentication failed",
+		"could not read username",
+		"permission denied",
+	}
+	retryableGitErrors = []string{
+		"could not resolve host",
+		"connection timed out",
+		"operation timed out",
+		"connection reset",
+		"connection refused",
+		"failed to connect",
+		"early eof",
+		"rpc failed",
+		"remote end hung up unexpectedly",
+		"tls connection",
+		"gnutls",
+		"http 502",
+		"http 503",
+		"http 504",
+		"returned error: 502",
+		"returned error: 503",
+		"returned error: 504",
+	}
+)
+
+// isRetryableGitError reports whether git stderr describes a transient failure
+// Unrecognized failures are treated as permanent so real errors surface quickly
+func isRetryableGitError(stderr string) bool {
+	msg := strings.ToLower(stderr)
+	for _, fragment := range permanentGitErrors {
+		if strings.Contains(msg, fragment) {
+			return false
+		}
+	}
+	for _, fragment := range retryableGitErrors {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
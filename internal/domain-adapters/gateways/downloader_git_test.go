@@ -1,6 +1,7 @@
 package gateways
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -31,7 +32,7 @@ func TestDownloader_GitClone(t *testing.T) {
 	platform := "linux-amd64"
 
 	// Call DownloadArtifact which should use git clone
-	artifact, err := downloader.DownloadArtifact(recipe, version, platform, tmpDir)
+	artifact, err := downloader.DownloadArtifact(context.Background(), recipe, version, platform, tmpDir)
 	if err != nil {
 		t.Fatalf("DownloadArtifact with git method failed: %v", err)
 	}
@@ -70,7 +71,7 @@ func TestDownloader_GitClone_InvalidTag(t *testing.T) {
 	platform := "linux-amd64"
 
 	// Should fail with invalid tag
-	_, err := downloader.DownloadArtifact(recipe, version, platform, tmpDir)
+	_, err := downloader.DownloadArtifact(context.Background(), recipe, version, platform, tmpDir)
 	if err == nil {
 		t.Fatal("Expected error for invalid git tag, got nil")
 	}
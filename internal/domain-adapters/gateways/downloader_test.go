@@ -1,11 +1,32 @@
 package gateways
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5" //nolint:gosec // G501: builds an MD5 checksum file to test its rejection
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain/entities"
+	"github.com/ochairo/potions/internal/domain/interfaces"
 )
 
 func TestDownloader_BuildDownloadURL(t *testing.T) {
@@ -39,6 +60,20 @@ func TestDownloader_BuildDownloadURL(t *testing.T) {
 			platform: entities.PlatformConfig{OS: "darwin", Arch: "amd64"},
 			want:     "https://github.com/FiloSottile/age/releases/download/v1.1.1/age-v1.1.1-darwin-amd64.tar.gz",
 		},
+		{
+			name:     "ppc64le from platform key",
+			template: "https://example.com/tool-{version}-{os}-{arch}.tar.gz",
+			version:  "2.0.0",
+			platform: withPlatformDefaults("linux-ppc64le", entities.PlatformConfig{}),
+			want:     "https://example.com/tool-2.0.0-linux-ppc64le.tar.gz",
+		},
+		{
+			name:     "armv7 with upstream arch override",
+			template: "https://example.com/tool-{version}-{os}-{arch}.tar.gz",
+			version:  "2.0.0",
+			platform: withPlatformDefaults("linux-armv7", entities.PlatformConfig{Arch: "armhf"}),
+			want:     "https://example.com/tool-2.0.0-linux-armhf.tar.gz",
+		},
 	}
 
 	for _, tt := range tests {
@@ -63,7 +98,7 @@ func TestDownloader_DownloadArtifact_UnsupportedPlatform(t *testing.T) {
 		},
 	}
 
-	_, err := d.DownloadArtifact(def, "1.0.0", "unsupported-platform", "/tmp/test")
+	_, err := d.DownloadArtifact(context.Background(), def, "1.0.0", "unsupported-platform", "/tmp/test")
 	if err == nil {
 		t.Error("DownloadArtifact() should fail for unsupported platform")
 	}
@@ -78,7 +113,7 @@ func TestDownloader_ExtractTarGz_PathTraversal(t *testing.T) {
 
 	// For now, just verify the function signature exists
 	tempDir := t.TempDir()
-	err := d.extractTarGz("/nonexistent.tar.gz", tempDir)
+	err := d.extractTarGz("/nonexistent.tar.gz", tempDir, 0)
 
 	// Should fail because file doesn't exist, not because of security check
 	if err == nil {
@@ -106,7 +141,7 @@ func TestDownloader_DownloadArtifact_Integration(t *testing.T) {
 
 	outputDir := t.TempDir()
 
-	artifact, err := d.DownloadArtifact(def, "1.1.1", "linux-amd64", outputDir)
+	artifact, err := d.DownloadArtifact(context.Background(), def, "1.1.1", "linux-amd64", outputDir)
 	if err != nil {
 		t.Fatalf("DownloadArtifact() error = %v", err)
 	}
@@ -207,7 +242,7 @@ func TestDownloader_DownloadFileWithFallback_MirrorUsed(t *testing.T) {
 	mirrorURL := "http://invalid-mirror-url-12345.example.local/file.txt"
 
 	// This should fail since both URLs are invalid, but it demonstrates the fallback logic
-	err := d.downloadFileWithFallback(primaryURL, mirrorURL, destFile)
+	_, err := d.downloadFileWithFallback(context.Background(), primaryURL, mirrorURL, destFile, nil)
 	if err == nil {
 		t.Error("downloadFileWithFallback() should fail with invalid URLs")
 	}
@@ -229,8 +264,1041 @@ func TestDownloader_DownloadFileWithFallback_NoMirror(t *testing.T) {
 	// Test without mirror - just primary URL
 	primaryURL := "http://invalid-url.example.local/file.txt"
 
-	err := d.downloadFileWithFallback(primaryURL, "", destFile)
+	_, err := d.downloadFileWithFallback(context.Background(), primaryURL, "", destFile, nil)
 	if err == nil {
 		t.Error("downloadFileWithFallback() should fail with invalid URL and no mirror")
 	}
 }
+
+func TestDownloader_DownloadArtifact_AuthTokenEnv(t *testing.T) {
+	const token = "s3cr3t-token"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("private binary"))
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "private-tool",
+		Download: entities.RecipeDownload{
+			DownloadURL:  server.URL + "/private-tool-{version}-{os}-{arch}",
+			AuthTokenEnv: "POTIONS_TEST_DOWNLOAD_TOKEN",
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+	}
+
+	t.Run("without token", func(t *testing.T) {
+		t.Setenv("POTIONS_TEST_DOWNLOAD_TOKEN", "")
+		d := NewDownloader()
+		_, err := d.DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir())
+		if err == nil {
+			t.Fatal("DownloadArtifact() should fail without token")
+		}
+		if !strings.Contains(err.Error(), "401") {
+			t.Errorf("DownloadArtifact() error = %v, want HTTP 401", err)
+		}
+		if strings.Contains(err.Error(), token) {
+			t.Error("error message must not contain the token")
+		}
+	})
+
+	t.Run("with token", func(t *testing.T) {
+		t.Setenv("POTIONS_TEST_DOWNLOAD_TOKEN", token)
+		d := NewDownloader()
+		artifact, err := d.DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir())
+		if err != nil {
+			t.Fatalf("DownloadArtifact() error = %v", err)
+		}
+		//nolint:gosec // G304: test reads downloaded file
+		data, err := os.ReadFile(artifact.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "private binary" {
+			t.Errorf("downloaded content = %q, want %q", data, "private binary")
+		}
+	})
+}
+
+func TestDownloadAuthHeaders(t *testing.T) {
+	t.Setenv("POTIONS_TEST_DOWNLOAD_TOKEN", "abc")
+
+	tests := []struct {
+		name     string
+		download entities.RecipeDownload
+		want     string
+	}{
+		{"no auth configured", entities.RecipeDownload{}, ""},
+		{"default bearer scheme", entities.RecipeDownload{AuthTokenEnv: "POTIONS_TEST_DOWNLOAD_TOKEN"}, "Bearer abc"},
+		{"custom scheme", entities.RecipeDownload{AuthTokenEnv: "POTIONS_TEST_DOWNLOAD_TOKEN", AuthScheme: "token"}, "token abc"},
+		{"unset variable", entities.RecipeDownload{AuthTokenEnv: "POTIONS_TEST_UNSET_TOKEN"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := downloadAuthHeaders(tt.download).Get("Authorization")
+			if got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// zipEntry describes a file to place in a test zip archive
+type zipEntry struct {
+	name    string
+	content string
+	mode    os.FileMode
+}
+
+// buildTestZip returns a zip archive containing the given entries
+func buildTestZip(t *testing.T, entries []zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		header.SetMode(e.mode)
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownloader_DownloadArtifact_Zip(t *testing.T) {
+	archive := buildTestZip(t, []zipEntry{
+		{name: "tool-1.0.0/bin/tool", content: "#!/bin/sh\necho tool\n", mode: 0755},
+		{name: "tool-1.0.0/README.md", content: "readme", mode: 0644},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL: server.URL + "/tool-{version}-{os}-{arch}.zip",
+			Platforms: map[string]entities.PlatformConfig{
+				"windows-amd64": {OS: "windows", Arch: "amd64"},
+			},
+		},
+	}
+
+	outputDir := t.TempDir()
+	artifact, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "windows-amd64", outputDir)
+	if err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+
+	wantRoot := filepath.Join(outputDir, "tool-1.0.0-windows-amd64-extracted", "tool-1.0.0")
+	if artifact.Path != wantRoot {
+		t.Errorf("artifact.Path = %s, want %s", artifact.Path, wantRoot)
+	}
+	if artifact.DownloadPath != filepath.Join(outputDir, "tool-1.0.0-windows-amd64.zip") {
+		t.Errorf("artifact.DownloadPath = %s", artifact.DownloadPath)
+	}
+
+	info, err := os.Stat(filepath.Join(wantRoot, "bin", "tool"))
+	if err != nil {
+		t.Fatalf("nested binary not extracted: %v", err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("binary mode = %v, want executable bit preserved", info.Mode().Perm())
+	}
+
+	readme, err := os.Stat(filepath.Join(wantRoot, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readme.Mode().Perm()&0111 != 0 {
+		t.Errorf("README mode = %v, want non-executable", readme.Mode().Perm())
+	}
+}
+
+func TestDownloader_ExtractZip_PathTraversal(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{"parent directory", "../evil"},
+		{"nested parent directory", "tool/../../evil"},
+		{"absolute path", "/tmp/evil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			zipPath := filepath.Join(dir, "malicious.zip")
+			archive := buildTestZip(t, []zipEntry{{name: tt.entry, content: "pwned", mode: 0644}})
+			if err := os.WriteFile(zipPath, archive, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			destDir := filepath.Join(dir, "extracted")
+			err := NewDownloader().extractZip(zipPath, destDir)
+			if err == nil {
+				t.Fatal("extractZip() should reject path traversal entry")
+			}
+			if !strings.Contains(err.Error(), "security") {
+				t.Errorf("extractZip() error = %v, want security error", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "evil")); err == nil {
+				t.Error("malicious entry was written outside destination")
+			}
+		})
+	}
+}
+
+func TestExtractedRoot(t *testing.T) {
+	single := t.TempDir()
+	if err := os.Mkdir(filepath.Join(single, "only"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if root, err := extractedRoot(single); err != nil || root != filepath.Join(single, "only") {
+		t.Errorf("extractedRoot() = %s, %v, want single top-level dir", root, err)
+	}
+
+	flat := t.TempDir()
+	if err := os.WriteFile(filepath.Join(flat, "tool.exe"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if root, err := extractedRoot(flat); err != nil || root != flat {
+		t.Errorf("extractedRoot() = %s, %v, want extract dir", root, err)
+	}
+}
+
+// recordingLogger captures log messages for assertions
+type recordingLogger struct {
+	interfaces.NoOpLogger
+	messages []string
+}
+
+func (r *recordingLogger) Info(msg string, _ ...interfaces.Field) {
+	r.messages = append(r.messages, "INFO "+msg)
+}
+
+func (r *recordingLogger) Warn(msg string, _ ...interfaces.Field) {
+	r.messages = append(r.messages, "WARN "+msg)
+}
+
+func (r *recordingLogger) Error(msg string, _ ...interfaces.Field) {
+	r.messages = append(r.messages, "ERROR "+msg)
+}
+
+// newStubbedGitDownloader returns a downloader whose git runner replays stderrs in order
+// (an empty entry means success) and whose sleeps are recorded instead of performed
+func newStubbedGitDownloader(stderrs []string) (*Downloader, *recordingLogger, *int, *[]time.Duration) {
+	logger := &recordingLogger{}
+	calls := 0
+	var sleeps []time.Duration
+
+	d := NewDownloader().WithLogger(logger)
+	d.runGit = func(_ context.Context, _ ...string) (string, error) {
+		stderr := stderrs[calls]
+		calls++
+		if stderr == "" {
+			return "", nil
+		}
+		return stderr, errors.New("exit status 128")
+	}
+	d.sleep = func(backoff time.Duration) { sleeps = append(sleeps, backoff) }
+	return d, logger, &calls, &sleeps
+}
+
+// Test a transient clone failure is retried and then succeeds
+func TestDownloader_CloneGitRepo_RetriesTransientFailure(t *testing.T) {
+	d, logger, calls, sleeps := newStubbedGitDownloader([]string{
+		"fatal: unable to access 'https://github.com/org/repo.git/': Could not resolve host: github.com",
+		"",
+	})
+
+	destDir := filepath.Join(t.TempDir(), "repo")
+	if err := d.cloneGitRepo(context.Background(), "https://github.com/org/repo.git", "v1.0.0", destDir); err != nil {
+		t.Fatalf("cloneGitRepo() error = %v", err)
+	}
+
+	if *calls != 2 {
+		t.Errorf("git calls = %d, want 2", *calls)
+	}
+	if len(*sleeps) != 1 {
+		t.Errorf("backoff sleeps = %v, want 1", *sleeps)
+	}
+
+	want := []string{"INFO Running git", "WARN git failed with transient error, retrying", "INFO Running git"}
+	if strings.Join(logger.messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("log messages = %v, want %v", logger.messages, want)
+	}
+}
+
+// Test a missing tag or repository fails without retrying
+func TestDownloader_CloneGitRepo_PermanentFailureNoRetry(t *testing.T) {
+	d, _, calls, sleeps := newStubbedGitDownloader([]string{
+		"fatal: Remote branch v9.9.9 not found in upstream origin",
+		"",
+	})
+
+	destDir := filepath.Join(t.TempDir(), "repo")
+	err := d.cloneGitRepo(context.Background(), "https://github.com/org/repo.git", "v9.9.9", destDir)
+	if err == nil {
+		t.Fatal("cloneGitRepo() should fail for a missing tag")
+	}
+	if !strings.Contains(err.Error(), "not found in upstream origin") {
+		t.Errorf("error = %v, want git stderr included", err)
+	}
+
+	if *calls != 1 {
+		t.Errorf("git calls = %d, want 1", *calls)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("backoff sleeps = %v, want none", *sleeps)
+	}
+}
+
+// Test retries are bounded for persistent transient failures
+func TestDownloader_CloneGitRepo_RetriesExhausted(t *testing.T) {
+	transient := "error: RPC failed; curl 56 Recv failure: Connection reset by peer"
+	d, _, calls, _ := newStubbedGitDownloader([]string{transient, transient, transient, ""})
+
+	err := d.cloneGitRepo(context.Background(), "https://github.com/org/repo.git", "v1.0.0", filepath.Join(t.TempDir(), "repo"))
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("cloneGitRepo() error = %v, want exhausted retries", err)
+	}
+	if *calls != gitCloneMaxAttempts {
+		t.Errorf("git calls = %d, want %d", *calls, gitCloneMaxAttempts)
+	}
+}
+
+// Test classification of git stderr output
+func TestIsRetryableGitError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"fatal: unable to access 'https://x/': Could not resolve host: x", true},
+		{"fatal: the remote end hung up unexpectedly\nfatal: early EOF", true},
+		{"fatal: unable to access 'https://x/': The requested URL returned error: 503", true},
+		{"remote: Repository not found.\nfatal: repository 'https://x/' not found", false},
+		{"fatal: Remote branch v1 not found in upstream origin", false},
+		{"fatal: could not read Username for 'https://github.com'", false},
+		{"fatal: something unexpected", false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableGitError(tt.stderr); got != tt.want {
+			t.Errorf("isRetryableGitError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+// Test a gzipped single-file download is decompressed to a raw binary and packaged
+func TestDownloader_DownloadArtifact_GzipSingleFile(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho tool\n")
+	var compressed bytes.Buffer
+	gzw := gzip.NewWriter(&compressed)
+	if _, err := gzw.Write(binary); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL: server.URL + "/tool-{version}-{os}-{arch}.gz",
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+	}
+
+	outputDir := t.TempDir()
+	artifact, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", outputDir)
+	if err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+
+	wantPath := filepath.Join(outputDir, "tool-1.0.0-linux-amd64")
+	if artifact.Path != wantPath {
+		t.Errorf("artifact.Path = %s, want %s", artifact.Path, wantPath)
+	}
+	if artifact.DownloadPath != wantPath+".gz" {
+		t.Errorf("artifact.DownloadPath = %s, want %s.gz", artifact.DownloadPath, wantPath)
+	}
+	data, err := os.ReadFile(wantPath)
+	if err != nil {
+		t.Fatalf("decompressed binary not written: %v", err)
+	}
+	if !bytes.Equal(data, binary) {
+		t.Errorf("decompressed content = %q, want %q", data, binary)
+	}
+
+	packaged, err := NewPackager().PackageArtifact(context.Background(), def, artifact, "1.0.0", "linux-amd64", outputDir)
+	if err != nil {
+		t.Fatalf("PackageArtifact() error = %v", err)
+	}
+	f, err := os.Open(packaged.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gzr)
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatalf("packaged tarball is empty: %v", err)
+	}
+	content, err := io.ReadAll(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Name != "tool" || !bytes.Equal(content, binary) {
+		t.Errorf("packaged entry %s = %q, want tool with the decompressed binary", header.Name, content)
+	}
+}
+
+// Test the artifact records the resolved download URL, or the mirror when the primary fails
+func TestDownloader_DownloadArtifact_SourceURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/broken/") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("binary"))
+	}))
+	defer server.Close()
+
+	platforms := map[string]entities.PlatformConfig{
+		"linux-amd64": {OS: "linux", Arch: "amd64"},
+	}
+	tests := []struct {
+		name     string
+		download entities.RecipeDownload
+		wantTmpl string
+	}{
+		{"primary", entities.RecipeDownload{DownloadURL: server.URL + "/tool-{version}-{os}-{arch}", Platforms: platforms}, "/tool-{version}-{os}-{arch}"},
+		{"mirror", entities.RecipeDownload{DownloadURL: server.URL + "/broken/tool-{version}", Mirror: server.URL + "/mirror/tool-{version}-{arch}", Platforms: platforms}, "/mirror/tool-{version}-{arch}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDownloader()
+			def := &entities.Recipe{Name: "tool", Download: tt.download}
+			platformConfig := platforms["linux-amd64"]
+
+			artifact, err := d.DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir())
+			if err != nil {
+				t.Fatalf("DownloadArtifact() error = %v", err)
+			}
+
+			want := d.BuildDownloadURL(server.URL+tt.wantTmpl, "1.0.0", &platformConfig)
+			if artifact.SourceURL != want {
+				t.Errorf("artifact.SourceURL = %s, want %s", artifact.SourceURL, want)
+			}
+		})
+	}
+}
+
+// Test a configured short deadline aborts a slow download with a deadline error
+func TestDownloader_DownloadFile_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	d := NewDownloader().WithTimeout(100 * time.Millisecond)
+	start := time.Now()
+	err := d.downloadFile(context.Background(), server.URL+"/tool.tar.gz", filepath.Join(t.TempDir(), "tool.tar.gz"), nil)
+	if err == nil {
+		t.Fatal("downloadFile() should fail when the deadline passes")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("downloadFile() error = %v, want deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("download took %v, want abort near the deadline", elapsed)
+	}
+}
+
+// Test cancelling the caller's context aborts a download that has no deadline of its own
+func TestDownloader_DownloadFile_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := NewDownloader().WithTimeout(0).DownloadFile(ctx, server.URL+"/tool.tar.gz", filepath.Join(t.TempDir(), "tool.tar.gz"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DownloadFile() error = %v, want the caller's deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("download took %v, want abort when the context is done", elapsed)
+	}
+}
+
+// buildTestTarGz returns a gzipped tarball holding files (name -> content)
+func buildTestTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content := files[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Test a truncated tar.gz fails extraction and leaves no partial tree behind
+func TestDownloader_ExtractTarGz_Truncated(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{
+		"tool-1.0.0/bin/tool":  strings.Repeat("binary-", 4096),
+		"tool-1.0.0/README.md": strings.Repeat("readme-", 4096),
+	})
+
+	for _, cut := range []int{len(archive) / 2, len(archive) - 4} {
+		tarPath := filepath.Join(t.TempDir(), "tool.tar.gz")
+		if err := os.WriteFile(tarPath, archive[:cut], 0600); err != nil {
+			t.Fatal(err)
+		}
+		destDir := filepath.Join(t.TempDir(), "extracted")
+
+		err := NewDownloader().extractTarGz(tarPath, destDir, 0)
+		if err == nil {
+			t.Fatalf("extractTarGz() with archive cut at %d/%d bytes should fail", cut, len(archive))
+		}
+		if _, statErr := os.Stat(destDir); !os.IsNotExist(statErr) {
+			t.Errorf("extractTarGz() left a partial tree at %s after error %v", destDir, err)
+		}
+	}
+}
+
+// Test extract_strip_components drops a two-level prefix so files land at the extraction root
+func TestDownloader_DownloadArtifact_StripComponents(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{
+		"tool-1.0.0/dist/bin/tool":   "#!/bin/sh\n",
+		"tool-1.0.0/dist/README.md":  "# tool\n",
+		"./tool-1.0.0/dist/LICENSE":  "MIT\n",
+		"tool-1.0.0/SHALLOW":         "dropped\n",
+		"tool-1.0.0/dist/share/a.md": "doc\n",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL:            server.URL + "/tool-{version}-{os}-{arch}.tar.gz",
+			ExtractStripComponents: 2,
+			Platforms:              map[string]entities.PlatformConfig{"linux-amd64": {}},
+		},
+	}
+
+	artifact, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+	for _, name := range []string{"bin/tool", "README.md", "LICENSE", "share/a.md"} {
+		if _, err := os.Stat(filepath.Join(artifact.Path, name)); err != nil {
+			t.Errorf("%s should be at the root of %s: %v", name, artifact.Path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(artifact.Path, "SHALLOW")); !os.IsNotExist(err) {
+		t.Error("entries no deeper than the stripped prefix should be skipped")
+	}
+
+	// Traversal checks apply to the name left after stripping
+	escaping := filepath.Join(t.TempDir(), "escape.tar.gz")
+	if err := os.WriteFile(escaping, buildTestTarGz(t, map[string]string{"a/b/../../x": "x"}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = NewDownloader().extractTarGz(escaping, filepath.Join(t.TempDir(), "out"), 2)
+	if err == nil || !strings.Contains(err.Error(), "path traversal") {
+		t.Errorf("extractTarGz() error = %v, want path traversal rejected after stripping", err)
+	}
+}
+
+// Test inner_archive extracts the zip shipped inside a tarball and points the artifact at its contents
+func TestDownloader_DownloadArtifact_InnerArchive(t *testing.T) {
+	inner := buildTestZip(t, []zipEntry{
+		{name: "tool/bin/tool", content: "#!/bin/sh\necho tool\n", mode: 0755},
+	})
+	archive := buildTestTarGz(t, map[string]string{
+		"release/tool-1.0.0-linux-amd64.zip": string(inner),
+		"release/NOTICE":                     "notice\n",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL:  server.URL + "/tool-{version}-{os}-{arch}.tar.gz",
+			InnerArchive: "tool-*.zip",
+			Platforms:    map[string]entities.PlatformConfig{"linux-amd64": {}},
+		},
+	}
+
+	artifact, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Join(artifact.Path, "bin", "tool"))
+	if err != nil {
+		t.Fatalf("innermost binary should be under %s: %v", artifact.Path, err)
+	}
+	if info.Mode()&0111 == 0 {
+		t.Errorf("innermost binary mode = %v, want executable", info.Mode())
+	}
+
+	// A pattern that matches nothing is an error rather than a silent fallback to the outer tree
+	def.Download.InnerArchive = "*.tar.gz"
+	if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir()); err == nil ||
+		!strings.Contains(err.Error(), "matched 0 files") {
+		t.Errorf("DownloadArtifact() error = %v, want no inner archive match", err)
+	}
+}
+
+// Test checksum_url is verified against the archive before it is extracted
+func TestDownloader_DownloadArtifact_ChecksumURL(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{"tool-1.0.0/bin/tool": "#!/bin/sh\n"})
+	sum := sha256.Sum256(archive)
+	checksums := fmt.Sprintf("%s  tool-1.0.0-linux-amd64.tar.gz\n%s  tool-1.0.0-darwin-arm64.tar.gz\n",
+		hex.EncodeToString(sum[:]), strings.Repeat("0", 64))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "SHA256SUMS") {
+			_, _ = w.Write([]byte(checksums))
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL: server.URL + "/tool-{version}-{os}-{arch}.tar.gz",
+			ChecksumURL: server.URL + "/tool-{version}-SHA256SUMS",
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":  {OS: "linux", Arch: "amd64"},
+				"darwin-arm64": {OS: "darwin", Arch: "arm64"},
+			},
+		},
+	}
+
+	outputDir := t.TempDir()
+	if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", outputDir); err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+
+	_, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "darwin-arm64", outputDir)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("DownloadArtifact() error = %v, want checksum mismatch", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(outputDir, "tool-1.0.0-darwin-arm64-extracted")); !os.IsNotExist(statErr) {
+		t.Error("archive with a bad checksum should not be extracted")
+	}
+}
+
+// Test the download token and recipe headers are not sent to a checksum_url on another host
+func TestDownloader_DownloadArtifact_ChecksumURLOtherHost(t *testing.T) {
+	const token = "s3cr3t-token"
+	archive := []byte("private binary")
+	sum := sha256.Sum256(archive)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+	var leaked []string
+	checksumServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range []string{"Authorization", "X-Api-Key"} {
+			if r.Header.Get(name) != "" {
+				leaked = append(leaked, name)
+			}
+		}
+		_, _ = w.Write([]byte(hex.EncodeToString(sum[:]) + "  tool-1.0.0-linux-amd64\n"))
+	}))
+	defer checksumServer.Close()
+
+	t.Setenv("POTIONS_TEST_DOWNLOAD_TOKEN", token)
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL:  server.URL + "/tool-{version}-{os}-{arch}",
+			ChecksumURL:  checksumServer.URL + "/SHA256SUMS",
+			AuthTokenEnv: "POTIONS_TEST_DOWNLOAD_TOKEN",
+			Headers:      map[string]string{"X-Api-Key": "recipe-key"},
+			Platforms:    map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}},
+		},
+	}
+
+	if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir()); err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+	if len(leaked) > 0 {
+		t.Errorf("checksum host received %v, want no download headers", leaked)
+	}
+}
+
+// Test checksum_algorithm selects the digest and weak algorithms need allow_weak_checksum
+func TestDownloader_DownloadArtifact_ChecksumAlgorithm(t *testing.T) {
+	archive := buildTestTarGz(t, map[string]string{"tool-1.0.0/bin/tool": "#!/bin/sh\n"})
+	sha512Sum := sha512.Sum512(archive)
+	md5Sum := md5.Sum(archive) //nolint:gosec // G401: MD5 checksum file under test
+	sums := map[string]string{
+		"SHA512SUMS": hex.EncodeToString(sha512Sum[:]) + "  tool-1.0.0-linux-amd64.tar.gz\n",
+		"MD5SUMS":    hex.EncodeToString(md5Sum[:]) + "  tool-1.0.0-linux-amd64.tar.gz\n",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if content, ok := sums[path.Base(r.URL.Path)]; ok {
+			_, _ = w.Write([]byte(content))
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	recipe := func(sumsFile, algorithm string, allowWeak bool) *entities.Recipe {
+		return &entities.Recipe{
+			Name: "tool",
+			Download: entities.RecipeDownload{
+				DownloadURL:       server.URL + "/tool-{version}-{os}-{arch}.tar.gz",
+				ChecksumURL:       server.URL + "/" + sumsFile,
+				ChecksumAlgorithm: algorithm,
+				AllowWeakChecksum: allowWeak,
+				Platforms:         map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		def     *entities.Recipe
+		wantErr string
+	}{
+		{"sha512 declared", recipe("SHA512SUMS", "sha512", false), ""},
+		{"sha512 detected", recipe("SHA512SUMS", "", false), ""},
+		{"declared algorithm missing from file", recipe("SHA512SUMS", "sha256", false), "no sha256 checksum found"},
+		{"md5 rejected by default", recipe("MD5SUMS", "md5", false), "allow_weak_checksum"},
+		{"md5 allowed", recipe("MD5SUMS", "md5", true), ""},
+		{"unknown algorithm", recipe("MD5SUMS", "crc32", true), "unsupported checksum_algorithm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDownloader().DownloadArtifact(context.Background(), tt.def, "1.0.0", "linux-amd64", t.TempDir())
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("DownloadArtifact() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("DownloadArtifact() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Test checksum files with named entries and single bare hashes
+func TestFindChecksum(t *testing.T) {
+	sha256Sum := strings.Repeat("a", 64)
+	sha512Sum := strings.Repeat("B", 128)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"sha256sum format", sha256Sum + "  other.tar.gz\n" + strings.Repeat("c", 64) + "  tool.tar.gz\n", strings.Repeat("c", 64), false},
+		{"binary marker and path", sha256Sum + " *dist/tool.tar.gz\n", sha256Sum, false},
+		{"bare hash", sha512Sum + "\n", strings.ToLower(sha512Sum), false},
+		{"missing entry", sha256Sum + "  other.tar.gz\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := findChecksum(tt.content, "tool.tar.gz", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("findChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test asset_pattern selects the matching asset from a github-release source's release
+func TestDownloader_DownloadArtifact_AssetPattern(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	archive := buildTestTarGz(t, map[string]string{"tool/bin/tool": "#!/bin/sh\n"})
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/tool/releases/tags/v1.0.0":
+			assets := []string{
+				"tool-1.0.0-darwin-arm64.tar.gz",
+				"tool-1.0.0-linux-amd64.tar.gz",
+				"tool-1.0.0-linux-amd64.tar.gz.sha256",
+				"tool-1.0.0-linux-arm64.tar.gz",
+				"tool-1.0.0-linux-arm64-musl.tar.gz",
+			}
+			var entries []string
+			for _, name := range assets {
+				entries = append(entries, fmt.Sprintf(`{"name":%q,"browser_download_url":%q}`, name, server.URL+"/download/"+name))
+			}
+			_, _ = fmt.Fprintf(w, `{"tag_name":"v1.0.0","assets":[%s]}`, strings.Join(entries, ","))
+		case "/download/tool-1.0.0-linux-amd64.tar.gz":
+			_, _ = w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name:    "tool",
+		Version: entities.VersionConfig{Source: "github-release:acme/tool"},
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {AssetPattern: "tool-*-{os}-{arch}.tar.gz"},
+				"linux-arm64": {AssetPattern: "tool-*-linux-arm64*.tar.gz"},
+			},
+		},
+	}
+
+	downloader := NewDownloader()
+	downloader.apiBaseURL = server.URL
+	artifact, err := downloader.DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+	if want := server.URL + "/download/tool-1.0.0-linux-amd64.tar.gz"; artifact.SourceURL != want {
+		t.Errorf("SourceURL = %s, want %s", artifact.SourceURL, want)
+	}
+
+	_, err = downloader.DownloadArtifact(context.Background(), def, "1.0.0", "linux-arm64", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "matches 2 assets") {
+		t.Errorf("DownloadArtifact() error = %v, want ambiguous pattern error", err)
+	}
+}
+
+// Test concurrent DownloadArtifact calls never have more downloads in flight than the process-wide limit
+func TestDownloader_DownloadArtifact_MaxConcurrentDownloads(t *testing.T) {
+	SetMaxConcurrentDownloads(2)
+	t.Cleanup(func() { SetMaxConcurrentDownloads(DefaultMaxConcurrentDownloads) })
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("binary"))
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL: server.URL + "/tool-{version}-{os}-{arch}",
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 6)
+	for range 6 {
+		outputDir := t.TempDir()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate downloaders share the same process-wide limit
+			if _, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", outputDir); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("DownloadArtifact() error = %v", err)
+	}
+
+	if maxInFlight != 2 {
+		t.Errorf("max in-flight downloads = %d, want the limit of 2", maxInFlight)
+	}
+}
+
+// Test a download waiting for a slot gives up once its context is done
+func TestDownloadLimiter_AcquireCancelled(t *testing.T) {
+	limiter := newDownloadLimiter(1)
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() error = %v, want the context's deadline while the slot is held", err)
+	}
+
+	release()
+	release()
+	if release, err := limiter.acquire(context.Background()); err != nil {
+		t.Errorf("acquire() error = %v after the slot was released", err)
+	} else {
+		release()
+	}
+}
+
+// Test checksum_from_release_body verifies the download against its line in the release description
+func TestDownloader_DownloadArtifact_ChecksumFromReleaseBody(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	binary := []byte("tool binary")
+	sum := sha256.Sum256(binary)
+
+	body := "## Checksums\n\n```\n" + strings.Repeat("0", 64) + "  tool-1.0.0-darwin-arm64\n" +
+		hex.EncodeToString(sum[:]) + "  tool-1.0.0-linux-amd64\n```\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/tool/releases/tags/v1.0.0":
+			release, _ := json.Marshal(map[string]string{"tag_name": "v1.0.0", "body": body})
+			_, _ = w.Write(release)
+		case "/download/tool-1.0.0-linux-amd64", "/download/tool-1.0.0-darwin-arm64":
+			_, _ = w.Write(binary)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name:    "tool",
+		Version: entities.VersionConfig{Source: "github-release:acme/tool"},
+		Download: entities.RecipeDownload{
+			DownloadURL:             server.URL + "/download/tool-{version}-{os}-{arch}",
+			ChecksumFromReleaseBody: true,
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":  {OS: "linux", Arch: "amd64"},
+				"darwin-arm64": {OS: "darwin", Arch: "arm64"},
+			},
+		},
+	}
+
+	downloader := NewDownloader()
+	downloader.apiBaseURL = server.URL
+	if _, err := downloader.DownloadArtifact(context.Background(), def, "1.0.0", "linux-amd64", t.TempDir()); err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+
+	_, err := downloader.DownloadArtifact(context.Background(), def, "1.0.0", "darwin-arm64", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "checksum verification failed") {
+		t.Errorf("DownloadArtifact() error = %v, want a mismatch against the release body", err)
+	}
+}
+
+// Test a platform_map entry downloads the recipe's upstream-named platform for a potions platform
+func TestDownloader_DownloadArtifact_PlatformMap(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		_, _ = w.Write([]byte("binary"))
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL: server.URL + "/tool-{version}-{os}-{arch}",
+			Platforms:   map[string]entities.PlatformConfig{"macos-aarch64": {}},
+			PlatformMap: map[string]string{"darwin-arm64": "macos-aarch64"},
+		},
+	}
+
+	artifact, err := NewDownloader().DownloadArtifact(context.Background(), def, "1.0.0", "darwin-arm64", t.TempDir())
+	if err != nil {
+		t.Fatalf("DownloadArtifact() error = %v", err)
+	}
+	if requested != "/tool-1.0.0-macos-aarch64" {
+		t.Errorf("requested %s, want the upstream macos-aarch64 name", requested)
+	}
+	if artifact.Platform != "darwin-arm64" {
+		t.Errorf("artifact.Platform = %q, want darwin-arm64", artifact.Platform)
+	}
+}
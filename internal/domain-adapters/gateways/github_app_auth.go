@@ -0,0 +1,149 @@
+package gateways
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// appTokenRefreshMargin renews installation tokens this long before they expire
+const appTokenRefreshMargin = time.Minute
+
+// appInstallationAuth mints GitHub App installation tokens and caches each until shortly before it expires
+type appInstallationAuth struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	now            func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewHTTPGitHubAppGateway creates a GitHub gateway authenticated as a GitHub App installation
+// privateKeyPEM is the App's RSA private key (PKCS#1 or PKCS#8); installation tokens are minted
+// on first use and refreshed transparently when they expire
+func NewHTTPGitHubAppGateway(appID, installationID int64, privateKeyPEM []byte) (*HTTPGitHubGateway, error) {
+	key, err := parseAppPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	g := NewHTTPGitHubGateway("")
+	g.app = &appInstallationAuth{
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		now:            time.Now,
+	}
+	return g, nil
+}
+
+// parseAppPrivateKey decodes a PEM-encoded RSA private key
+func parseAppPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid GitHub App private key: %T is not an RSA key", parsed)
+	}
+	return key, nil
+}
+
+// installationToken returns a cached installation token, minting a new one when none is valid
+func (a *appInstallationAuth) installationToken(ctx context.Context, client *http.Client, baseURL, userAgent string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && a.now().Add(appTokenRefreshMargin).Before(a.expiresAt) {
+		return a.token, nil
+	}
+
+	jwt, err := a.signJWT()
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", baseURL, a.installationID)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to mint installation token: %w", err)
+	}
+	//nolint:errcheck // Defer close on HTTP response body
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("failed to mint installation token: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var minted struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&minted); err != nil {
+		return "", fmt.Errorf("failed to decode installation token: %w", err)
+	}
+	if minted.Token == "" {
+		return "", fmt.Errorf("failed to mint installation token: response has no token")
+	}
+
+	a.token = minted.Token
+	a.expiresAt = minted.ExpiresAt
+	return a.token, nil
+}
+
+// signJWT creates the short-lived RS256 JWT that authenticates the App itself
+// iat is backdated a minute to tolerate clock drift; GitHub caps exp at ten minutes
+func (a *appInstallationAuth) signJWT() (string, error) {
+	now := a.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
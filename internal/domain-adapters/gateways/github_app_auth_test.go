@@ -0,0 +1,106 @@
+package gateways
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test App gateways mint an installation token, reuse it, and refresh it once expired
+func TestGitHubAppGateway_InstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var minted int
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/installations/42/access_tokens" {
+			if r.Method != http.MethodPost {
+				t.Errorf("mint method = %s, want POST", r.Method)
+			}
+			claims := verifyTestJWT(t, &key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			if claims["iss"] != "7" {
+				t.Errorf("JWT iss = %v, want the App ID 7", claims["iss"])
+			}
+			minted++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"token": "installation-%d", "expires_at": %q}`,
+				minted, now.Add(time.Hour).Format(time.RFC3339))
+			return
+		}
+		used = append(used, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id": 1, "tag_name": "tool-1.0.0"}`))
+	}))
+	defer server.Close()
+
+	gateway, err := NewHTTPGitHubAppGateway(7, 42, keyPEM)
+	if err != nil {
+		t.Fatalf("NewHTTPGitHubAppGateway() error = %v", err)
+	}
+	gateway.baseURL = server.URL
+	gateway.app.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := gateway.GetRelease(context.Background(), "test", "repo", "tool-1.0.0"); err != nil {
+			t.Fatalf("GetRelease() error = %v", err)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := gateway.GetRelease(context.Background(), "test", "repo", "tool-1.0.0"); err != nil {
+		t.Fatalf("GetRelease() after expiry error = %v", err)
+	}
+
+	want := "token installation-1,token installation-1,token installation-2"
+	if minted != 2 || strings.Join(used, ",") != want {
+		t.Errorf("minted %d tokens, requests used %v, want 2 mints and %s", minted, used, want)
+	}
+}
+
+// Test a malformed App private key is rejected up front
+func TestNewHTTPGitHubAppGateway_InvalidKey(t *testing.T) {
+	if _, err := NewHTTPGitHubAppGateway(7, 42, []byte("not a key")); err == nil {
+		t.Error("NewHTTPGitHubAppGateway() should reject a key without a PEM block")
+	}
+}
+
+// verifyTestJWT checks an RS256 JWT signature and returns its claims
+func verifyTestJWT(t *testing.T, pub *rsa.PublicKey, jwt string) map[string]any {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT %q does not have three parts", jwt)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("JWT signature invalid: %v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
@@ -6,149 +6,52 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"net/http"
 	"net/url"
-	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ochairo/potions/internal/domain/interfaces/gateways"
 )
 
-const (
-	// Max retries for transient errors
-	maxRetries = 3
-	// Initial backoff duration
-	initialBackoff = 1 * time.Second
-	// Max backoff duration
-	maxBackoff = 32 * time.Second
-)
+// Default GitHub REST API base URL
+const defaultGitHubAPIURL = "https://api.github.com"
 
 // HTTPGitHubGateway implements GitHubGateway using standard HTTP client
 type HTTPGitHubGateway struct {
 	client    *http.Client
 	token     string
 	userAgent string
+	baseURL   string
+	app       *appInstallationAuth // Set when authenticating as a GitHub App installation
 }
 
 // NewHTTPGitHubGateway creates a new GitHub gateway with HTTP client
 func NewHTTPGitHubGateway(token string) *HTTPGitHubGateway {
 	return &HTTPGitHubGateway{
-		client: &http.Client{
-			Timeout: 5 * time.Minute, // Increased for large artifact uploads
-		},
+		client:    NewHTTPClient(5 * time.Minute), // Long enough for large artifact uploads
 		token:     token,
-		userAgent: "potions/1.0",
+		userAgent: UserAgent(),
+		baseURL:   defaultGitHubAPIURL,
 	}
 }
 
-// checkRateLimit checks GitHub API rate limit headers and returns error if exhausted
-func checkRateLimit(resp *http.Response) error {
-	remaining := resp.Header.Get("X-RateLimit-Remaining")
-	if remaining == "" {
-		return nil // No rate limit header, continue
-	}
-
-	remainingInt, err := strconv.Atoi(remaining)
-	if err != nil {
-		return nil // Invalid header, ignore
-	}
-
-	// If exhausted, return error immediately (don't wait in tests/CI)
-	if remainingInt == 0 {
-		resetTime := resp.Header.Get("X-RateLimit-Reset")
-		if resetTime != "" {
-			if resetUnix, err := strconv.ParseInt(resetTime, 10, 64); err == nil {
-				resetAt := time.Unix(resetUnix, 0)
-				return fmt.Errorf("GitHub API rate limit exceeded (0 remaining), resets at %s", resetAt.Format(time.RFC3339))
-			}
+// authorize sets the Authorization header, minting a GitHub App installation token when needed
+func (g *HTTPGitHubGateway) authorize(req *http.Request) error {
+	token := g.token
+	if g.app != nil {
+		var err error
+		if token, err = g.app.installationToken(req.Context(), g.client, g.baseURL, g.userAgent); err != nil {
+			return err
 		}
-		return fmt.Errorf("GitHub API rate limit exceeded (0 remaining)")
 	}
-
-	// Warn if getting low
-	if remainingInt <= 10 {
-		// Note: This is adapter layer, direct logging is acceptable here
-		// In production, consider injecting logger interface
-		fmt.Fprintf(os.Stderr, "⚠️  GitHub API rate limit low: %d remaining\n", remainingInt)
+	// Public releases are readable anonymously
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
 	}
-
 	return nil
 }
 
-// isRetryableError checks if an HTTP status code is retryable
-func isRetryableError(statusCode int) bool {
-	switch statusCode {
-	case http.StatusForbidden, // 403 - rate limit
-		http.StatusTooManyRequests,     // 429
-		http.StatusInternalServerError, // 500
-		http.StatusBadGateway,          // 502
-		http.StatusServiceUnavailable,  // 503
-		http.StatusGatewayTimeout:      // 504
-		return true
-	default:
-		return false
-	}
-}
-
-// calculateBackoff returns the backoff duration for a retry attempt
-func calculateBackoff(attempt int) time.Duration {
-	backoff := float64(initialBackoff) * math.Pow(2, float64(attempt))
-	if backoff > float64(maxBackoff) {
-		backoff = float64(maxBackoff)
-	}
-	return time.Duration(backoff)
-}
-
-// doWithRetry executes an HTTP request with exponential backoff retry
-func (g *HTTPGitHubGateway) doWithRetry(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			backoff := calculateBackoff(attempt - 1)
-			time.Sleep(backoff)
-		}
-
-		resp, err = g.client.Do(req)
-		if err != nil {
-			// Network errors are retryable
-			if attempt < maxRetries {
-				continue
-			}
-			return nil, err
-		}
-
-		// Check rate limit
-		if rateLimitErr := checkRateLimit(resp); rateLimitErr != nil {
-			//nolint:errcheck,gosec // G104: Best effort close on rate limit error
-			resp.Body.Close()
-			return nil, rateLimitErr
-		}
-
-		// Success or non-retryable error
-		if !isRetryableError(resp.StatusCode) {
-			return resp, nil
-		}
-
-		// Retryable error - close body and retry
-		//nolint:errcheck,gosec // G104: Best effort close before retry
-		resp.Body.Close()
-
-		if attempt < maxRetries {
-			continue
-		}
-
-		// Max retries reached
-		return resp, nil
-	}
-
-	return resp, err
-}
-
 // githubRelease represents the GitHub API release format
 type githubRelease struct {
 	ID          int64  `json:"id,omitempty"`
@@ -174,9 +77,25 @@ type githubAsset struct {
 	BrowserDownloadURL string `json:"browser_download_url"`
 }
 
+// toDomainRelease converts an API release into the domain representation
+func toDomainRelease(r githubRelease) *gateways.GitHubRelease {
+	return &gateways.GitHubRelease{
+		ID:          r.ID,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		CreatedAt:   r.CreatedAt,
+		PublishedAt: r.PublishedAt,
+		HTMLURL:     r.HTMLURL,
+		UploadURL:   r.UploadURL,
+	}
+}
+
 // CreateRelease creates a new GitHub release
 func (g *HTTPGitHubGateway) CreateRelease(ctx context.Context, owner, repo string, release *gateways.GitHubRelease) (*gateways.GitHubRelease, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", owner, repo)
+	url := fmt.Sprintf("%s/repos/%s/%s/releases", g.baseURL, owner, repo)
 
 	apiRelease := githubRelease{
 		TagName:    release.TagName,
@@ -196,12 +115,14 @@ func (g *HTTPGitHubGateway) CreateRelease(ctx context.Context, owner, repo strin
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "token "+g.token)
+	if err := g.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", g.userAgent)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := g.doWithRetry(req)
+	resp, err := g.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create release: %w", err)
 	}
@@ -221,34 +142,77 @@ func (g *HTTPGitHubGateway) CreateRelease(ctx context.Context, owner, repo strin
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &gateways.GitHubRelease{
-		ID:          result.ID,
-		TagName:     result.TagName,
-		Name:        result.Name,
-		Body:        result.Body,
-		Draft:       result.Draft,
-		Prerelease:  result.Prerelease,
-		CreatedAt:   result.CreatedAt,
-		PublishedAt: result.PublishedAt,
-		HTMLURL:     result.HTMLURL,
-		UploadURL:   result.UploadURL,
-	}, nil
+	return toDomainRelease(result), nil
+}
+
+// UpdateRelease updates an existing release (e.g., to publish a draft)
+func (g *HTTPGitHubGateway) UpdateRelease(ctx context.Context, owner, repo string, releaseID int64, release *gateways.GitHubRelease) (*gateways.GitHubRelease, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d", g.baseURL, owner, repo, releaseID)
+
+	apiRelease := githubRelease{
+		TagName:    release.TagName,
+		Name:       release.Name,
+		Body:       release.Body,
+		Draft:      release.Draft,
+		Prerelease: release.Prerelease,
+	}
+
+	body, err := json.Marshal(apiRelease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal release: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := g.authorize(req); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update release: %w", err)
+	}
+	//nolint:errcheck // Defer close on HTTP response body
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update release: status %d (failed to read response)", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to update release: status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return toDomainRelease(result), nil
 }
 
 // GetRelease retrieves a release by tag name
 func (g *HTTPGitHubGateway) GetRelease(ctx context.Context, owner, repo, tag string) (*gateways.GitHubRelease, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/tags/%s", owner, repo, tag)
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", g.baseURL, owner, repo, tag)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "token "+g.token)
+	if err := g.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", g.userAgent)
 
-	resp, err := g.doWithRetry(req)
+	resp, err := g.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get release: %w", err)
 	}
@@ -272,18 +236,7 @@ func (g *HTTPGitHubGateway) GetRelease(ctx context.Context, owner, repo, tag str
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &gateways.GitHubRelease{
-		ID:          result.ID,
-		TagName:     result.TagName,
-		Name:        result.Name,
-		Body:        result.Body,
-		Draft:       result.Draft,
-		Prerelease:  result.Prerelease,
-		CreatedAt:   result.CreatedAt,
-		PublishedAt: result.PublishedAt,
-		HTMLURL:     result.HTMLURL,
-		UploadURL:   result.UploadURL,
-	}, nil
+	return toDomainRelease(result), nil
 }
 
 // UploadAsset uploads a file to a release
@@ -318,13 +271,15 @@ func (g *HTTPGitHubGateway) UploadAsset(ctx context.Context, uploadURL, filename
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "token "+g.token)
+	if err := g.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", g.userAgent)
 	req.Header.Set("Content-Type", "application/octet-stream")
 	req.ContentLength = int64(buf.Len())
 
-	resp, err := g.doWithRetry(req)
+	resp, err := g.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload asset: %w", err)
 	}
@@ -355,22 +310,50 @@ func (g *HTTPGitHubGateway) UploadAsset(ctx context.Context, uploadURL, filename
 	}, nil
 }
 
-// ListReleaseAssets lists all assets for a release
+// ListReleaseAssets lists all assets for a release, following pagination
 func (g *HTTPGitHubGateway) ListReleaseAssets(ctx context.Context, owner, repo string, releaseID int64) ([]*gateways.GitHubAsset, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/%d/assets", owner, repo, releaseID)
+	pageURL := fmt.Sprintf("%s/repos/%s/%s/releases/%d/assets?per_page=100", g.baseURL, owner, repo, releaseID)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	var assets []*gateways.GitHubAsset
+	for pageURL != "" {
+		results, next, err := g.listReleaseAssetsPage(ctx, pageURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range results {
+			assets = append(assets, &gateways.GitHubAsset{
+				ID:                 a.ID,
+				Name:               a.Name,
+				Label:              a.Label,
+				State:              a.State,
+				Size:               a.Size,
+				DownloadCount:      a.DownloadCount,
+				BrowserDownloadURL: a.BrowserDownloadURL,
+			})
+		}
+		pageURL = next
+	}
+
+	return assets, nil
+}
+
+// listReleaseAssetsPage fetches a single page of assets and returns the next page URL, if any
+func (g *HTTPGitHubGateway) listReleaseAssetsPage(ctx context.Context, pageURL string) ([]githubAsset, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "token "+g.token)
+	if err := g.authorize(req); err != nil {
+		return nil, "", err
+	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", g.userAgent)
 
-	resp, err := g.doWithRetry(req)
+	resp, err := g.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list assets: %w", err)
+		return nil, "", fmt.Errorf("failed to list assets: %w", err)
 	}
 	//nolint:errcheck // Defer close on HTTP response body
 	defer resp.Body.Close()
@@ -378,77 +361,121 @@ func (g *HTTPGitHubGateway) ListReleaseAssets(ctx context.Context, owner, repo s
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list assets: status %d (failed to read response)", resp.StatusCode)
+			return nil, "", fmt.Errorf("failed to list assets: status %d (failed to read response)", resp.StatusCode)
 		}
-		return nil, fmt.Errorf("failed to list assets: status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, "", fmt.Errorf("failed to list assets: status %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	var results []githubAsset
 	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	assets := make([]*gateways.GitHubAsset, len(results))
-	for i, a := range results {
-		assets[i] = &gateways.GitHubAsset{
-			ID:                 a.ID,
-			Name:               a.Name,
-			Label:              a.Label,
-			State:              a.State,
-			Size:               a.Size,
-			DownloadCount:      a.DownloadCount,
-			BrowserDownloadURL: a.BrowserDownloadURL,
+	return results, nextPageURL(resp.Header.Get("Link")), nil
+}
+
+// nextPageURL extracts the rel="next" URL from a GitHub Link header
+// Format: <https://api.github.com/...?page=2>; rel="next", <...>; rel="last"
+func nextPageURL(linkHeader string) string {
+	for _, link := range strings.Split(linkHeader, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
 		}
-	}
 
-	return assets, nil
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(target, "<>")
+			}
+		}
+	}
+	return ""
 }
 
-// ListReleases lists all releases in a repository
+// ListReleases lists all releases in a repository, following pagination
 func (g *HTTPGitHubGateway) ListReleases(ctx context.Context, owner, repo string) ([]*gateways.GitHubRelease, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=100", owner, repo)
+	pageURL := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100", g.baseURL, owner, repo)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	var releases []*gateways.GitHubRelease
+	for pageURL != "" {
+		results, next, err := g.listReleasesPage(ctx, pageURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range results {
+			releases = append(releases, toDomainRelease(r))
+		}
+		pageURL = next
+	}
+
+	return releases, nil
+}
+
+// listReleasesPage fetches a single page of releases and returns the next page URL, if any
+func (g *HTTPGitHubGateway) listReleasesPage(ctx context.Context, pageURL string) ([]githubRelease, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "token "+g.token)
+	if err := g.authorize(req); err != nil {
+		return nil, "", err
+	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", g.userAgent)
 
-	resp, err := g.doWithRetry(req)
+	resp, err := g.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list releases: %w", err)
+		return nil, "", fmt.Errorf("failed to list releases: %w", err)
 	}
 	//nolint:errcheck // Defer close on HTTP response body
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to list releases: status %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	var apiReleases []githubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&apiReleases); err != nil {
-		return nil, fmt.Errorf("failed to decode releases: %w", err)
-	}
-
-	releases := make([]*gateways.GitHubRelease, len(apiReleases))
-	for i, r := range apiReleases {
-		releases[i] = &gateways.GitHubRelease{
-			ID:          r.ID,
-			TagName:     r.TagName,
-			Name:        r.Name,
-			Body:        r.Body,
-			Draft:       r.Draft,
-			Prerelease:  r.Prerelease,
-			CreatedAt:   r.CreatedAt,
-			PublishedAt: r.PublishedAt,
-			HTMLURL:     r.HTMLURL,
-			UploadURL:   r.UploadURL,
-		}
+		return nil, "", fmt.Errorf("failed to list releases: status %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
-	return releases, nil
+	var results []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, "", fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	return results, nextPageURL(resp.Header.Get("Link")), nil
+}
+
+// DeleteRelease deletes a release and its assets; the release's git tag is left in place
+func (g *HTTPGitHubGateway) DeleteRelease(ctx context.Context, owner, repo string, releaseID int64) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d", g.baseURL, owner, repo, releaseID)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := g.authorize(req); err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete release: %w", err)
+	}
+	//nolint:errcheck // Defer close on HTTP response body
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete release: status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
 }
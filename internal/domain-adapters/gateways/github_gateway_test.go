@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain/interfaces/gateways"
 )
@@ -204,3 +205,196 @@ func TestGitHubGateway_UploadAsset_EmptyContent(t *testing.T) {
 		t.Errorf("Asset name = %s, want empty.tar.gz", result.Name)
 	}
 }
+
+// Test update release sends a PATCH to the release endpoint
+func TestGitHubGateway_UpdateRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Method = %s, want PATCH", r.Method)
+		}
+		if r.URL.Path != "/repos/test/repo/releases/42" {
+			t.Errorf("Path = %s, want /repos/test/repo/releases/42", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if draft, ok := body["draft"].(bool); !ok || draft {
+			t.Errorf("draft = %v, want false", body["draft"])
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": 42, "tag_name": "v1.0.0", "draft": false}`))
+	}))
+	defer server.Close()
+
+	gateway := NewHTTPGitHubGateway("test-token")
+	gateway.baseURL = server.URL
+
+	updated, err := gateway.UpdateRelease(context.Background(), "test", "repo", 42, &gateways.GitHubRelease{
+		TagName: "v1.0.0",
+		Draft:   false,
+	})
+	if err != nil {
+		t.Fatalf("UpdateRelease() error = %v", err)
+	}
+	if updated.Draft {
+		t.Error("Expected release to be published")
+	}
+}
+
+// Test list release assets follows Link header pagination
+func TestGitHubGateway_ListReleaseAssets_Pagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test/repo/releases/7/assets" {
+			t.Errorf("Path = %s, want /repos/test/repo/releases/7/assets", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<`+server.URL+`/repos/test/repo/releases/7/assets?per_page=100&page=2>; rel="next", <`+
+				server.URL+`/repos/test/repo/releases/7/assets?per_page=100&page=2>; rel="last"`)
+			_, _ = w.Write([]byte(`[{"id": 1, "name": "a.tar.gz"}, {"id": 2, "name": "a.tar.gz.sha256"}]`))
+		case "2":
+			w.Header().Set("Link", `<`+server.URL+`/repos/test/repo/releases/7/assets?per_page=100&page=1>; rel="prev"`)
+			_, _ = w.Write([]byte(`[{"id": 3, "name": "b.tar.gz"}]`))
+		default:
+			t.Errorf("Unexpected page %s", r.URL.Query().Get("page"))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	gateway := NewHTTPGitHubGateway("test-token")
+	gateway.baseURL = server.URL
+
+	assets, err := gateway.ListReleaseAssets(context.Background(), "test", "repo", 7)
+	if err != nil {
+		t.Fatalf("ListReleaseAssets() error = %v", err)
+	}
+
+	var names []string
+	for _, a := range assets {
+		names = append(names, a.Name)
+	}
+	if got := strings.Join(names, ","); got != "a.tar.gz,a.tar.gz.sha256,b.tar.gz" {
+		t.Errorf("Assets = %s, want all assets from both pages", got)
+	}
+}
+
+// Test list releases follows Link header pagination
+func TestGitHubGateway_ListReleases_Pagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/test/repo/releases" {
+			t.Errorf("Path = %s, want /repos/test/repo/releases", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<`+server.URL+`/repos/test/repo/releases?per_page=100&page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id": 1, "tag_name": "tool-v2.0.0"}]`))
+		case "2":
+			_, _ = w.Write([]byte(`[{"id": 2, "tag_name": "tool-v1.0.0"}]`))
+		default:
+			t.Errorf("Unexpected page %s", r.URL.Query().Get("page"))
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	gateway := NewHTTPGitHubGateway("test-token")
+	gateway.baseURL = server.URL
+
+	releases, err := gateway.ListReleases(context.Background(), "test", "repo")
+	if err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+
+	var tags []string
+	for _, r := range releases {
+		tags = append(tags, r.TagName)
+	}
+	if got := strings.Join(tags, ","); got != "tool-v2.0.0,tool-v1.0.0" {
+		t.Errorf("Releases = %s, want releases from both pages", got)
+	}
+}
+
+// Test parsing the next page URL from a Link header
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"empty", "", ""},
+		{"next and last", `<https://api.github.com/x?page=2>; rel="next", <https://api.github.com/x?page=5>; rel="last"`, "https://api.github.com/x?page=2"},
+		{"last page", `<https://api.github.com/x?page=1>; rel="first", <https://api.github.com/x?page=4>; rel="prev"`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.header); got != tt.want {
+				t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// setBackoffJitter toggles backoff jitter for the duration of a test
+func setBackoffJitter(t *testing.T, enabled bool) {
+	t.Helper()
+	previous := backoffJitter
+	backoffJitter = enabled
+	t.Cleanup(func() { backoffJitter = previous })
+}
+
+// Test backoff without jitter follows initial*2^attempt capped at max
+func TestCalculateBackoff_NoJitter(t *testing.T) {
+	setBackoffJitter(t, false)
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 1 * time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{5, 32 * time.Second},
+		{10, maxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := calculateBackoff(tt.attempt); got != tt.want {
+			t.Errorf("calculateBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+// Test backoff with jitter stays within [0, capped backoff] and varies
+func TestCalculateBackoff_Jitter(t *testing.T) {
+	setBackoffJitter(t, true)
+
+	for attempt := 0; attempt <= 8; attempt++ {
+		capped := initialBackoff << attempt
+		if capped > maxBackoff {
+			capped = maxBackoff
+		}
+
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 50; i++ {
+			got := calculateBackoff(attempt)
+			if got < 0 || got > capped {
+				t.Fatalf("calculateBackoff(%d) = %v, want within [0, %v]", attempt, got, capped)
+			}
+			seen[got] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("calculateBackoff(%d) returned the same value 50 times, want jitter", attempt)
+		}
+	}
+}
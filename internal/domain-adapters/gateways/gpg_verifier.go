@@ -3,7 +3,12 @@ package gateways
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
 
+	"github.com/ochairo/potions/internal/domain/entities"
 	"github.com/ochairo/potions/internal/external-adapters/gpg"
 )
 
@@ -14,7 +19,8 @@ import (
 // - Age encryption: github.com/FiloSottile/age
 // - Direct GPG binary execution via exec.Command (zero Go dependencies)
 type gpgVerifier struct {
-	verifier *gpg.Verifier
+	verifier   *gpg.Verifier
+	httpClient *http.Client
 }
 
 // NewGPGVerifier creates a new GPG verifier gateway
@@ -22,13 +28,14 @@ type gpgVerifier struct {
 //nolint:revive // unexported-return: Intentionally returns concrete type for testability
 func NewGPGVerifier() *gpgVerifier {
 	return &gpgVerifier{
-		verifier: gpg.NewVerifier(),
+		verifier:   gpg.NewVerifier().WithHTTPClient(NewHTTPClient(defaultRequestTimeout)),
+		httpClient: NewHTTPClient(defaultRequestTimeout),
 	}
 }
 
-// ImportGPGKeys imports GPG keys from keyservers
-func (g *gpgVerifier) ImportGPGKeys(ctx context.Context, keyIDs []string) error {
-	if err := g.verifier.ImportKeys(ctx, keyIDs); err != nil {
+// ImportGPGKeys imports GPG keys from the lookup's WKD and Keybase sources or the keyservers
+func (g *gpgVerifier) ImportGPGKeys(ctx context.Context, keyIDs []string, lookup entities.GPGKeyLookup) error {
+	if err := g.verifier.ImportKeysVia(ctx, keyIDs, gpg.KeyLookup(lookup)); err != nil {
 		return fmt.Errorf("failed to import GPG keys: %w", err)
 	}
 	return nil
@@ -51,29 +58,6 @@ func (g *gpgVerifier) ImportGPGKeyFromFile(keyPath string) error {
 }
 
 // VerifyGPGSignature verifies a detached GPG signature downloaded from a URL
-//
-// TODO: Implement HashiCorp SHA256SUMS verification pattern
-// HashiCorp products (terraform, vault, consul, nomad, packer, boundary) use a different signing scheme:
-//  1. They provide SHA256SUMS file containing checksums for all platforms
-//  2. They provide SHA256SUMS.sig which signs the checksums file (not individual binaries)
-//  3. Verification requires:
-//     a. Download SHA256SUMS file from: https://releases.hashicorp.com/{product}/{version}/{product}_{version}_SHA256SUMS
-//     b. Download SHA256SUMS.sig from: https://releases.hashicorp.com/{product}/{version}/{product}_{version}_SHA256SUMS.sig
-//     c. Verify GPG signature: gpg --verify SHA256SUMS.sig SHA256SUMS
-//     d. Parse SHA256SUMS file (format: "<hash>  <filename>" per line)
-//     e. Calculate SHA256 of downloaded binary
-//     f. Find matching line in SHA256SUMS for the specific platform binary
-//     g. Compare calculated checksum with expected checksum
-//
-// Implementation approach:
-//   - Detect pattern: if sigURL contains "SHA256SUMS.sig"
-//   - Download SHA256SUMS file (derive URL from sigURL by removing .sig)
-//   - Verify GPG signature of SHA256SUMS file using existing VerifySignature logic
-//   - Add new function: VerifyChecksumFromSHA256SUMS(filePath, sha256sumsContent string) error
-//   - Parse SHA256SUMS, extract checksum for basename(filePath)
-//   - Calculate SHA256 of filePath and compare
-//
-// This would enable signature verification for all HashiCorp products (currently disabled)
 func (g *gpgVerifier) VerifyGPGSignature(ctx context.Context, filePath, sigURL string) error {
 	if err := g.verifier.VerifySignature(ctx, filePath, sigURL); err != nil {
 		return fmt.Errorf("GPG signature verification failed: %w", err)
@@ -81,6 +65,76 @@ func (g *gpgVerifier) VerifyGPGSignature(ctx context.Context, filePath, sigURL s
 	return nil
 }
 
+// VerifyGPGSignedChecksums verifies the GPG signature over a checksums manifest (e.g. SHA256SUMS)
+// and then the file against the manifest entry for its basename
+// Used for upstreams that sign only their checksums rather than each artifact
+func (g *gpgVerifier) VerifyGPGSignedChecksums(ctx context.Context, filePath, checksumsURL, sigURL string) error {
+	manifestPath, err := g.fetchChecksumManifest(ctx, checksumsURL)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // Best effort cleanup of the fetched manifest
+	defer os.Remove(manifestPath)
+
+	if err := g.verifier.VerifySignature(ctx, manifestPath, sigURL); err != nil {
+		return fmt.Errorf("GPG signature verification of %s failed: %w", checksumsURL, err)
+	}
+
+	//nolint:gosec // G304: manifestPath is the temporary file just written
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read checksums manifest: %w", err)
+	}
+
+	filename := filepath.Base(filePath)
+	expected, algorithm, err := findChecksum(string(content), filename, "")
+	if err != nil {
+		return fmt.Errorf("%s: %w", checksumsURL, err)
+	}
+	if err := NewChecksumVerifier().VerifyChecksumWithAlgorithm(ctx, filePath, expected, algorithm); err != nil {
+		return fmt.Errorf("%s does not match signed checksums: %w", filename, err)
+	}
+	return nil
+}
+
+// fetchChecksumManifest downloads a checksums manifest to a temporary file
+func (g *gpgVerifier) fetchChecksumManifest(ctx context.Context, checksumsURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumsURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create checksums request: %w", err)
+	}
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download checksums: %w", err)
+	}
+	//nolint:errcheck // Defer close
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("checksums download failed with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxChecksumFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read checksums: %w", err)
+	}
+	if len(data) > maxChecksumFileSize {
+		return "", fmt.Errorf("checksums file %s is too large", checksumsURL)
+	}
+
+	manifest, err := os.CreateTemp("", "potions-checksums-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create checksums file: %w", err)
+	}
+	//nolint:errcheck // Close error surfaces through the write below
+	defer manifest.Close()
+	if _, err := manifest.Write(data); err != nil {
+		_ = os.Remove(manifest.Name())
+		return "", fmt.Errorf("failed to write checksums file: %w", err)
+	}
+	return manifest.Name(), nil
+}
+
 // VerifyGPGSignatureFromFile verifies a detached GPG signature from a local file
 func (g *gpgVerifier) VerifyGPGSignatureFromFile(filePath, sigPath string) error {
 	if err := g.verifier.VerifySignatureFromFile(filePath, sigPath); err != nil {
@@ -0,0 +1,84 @@
+package gateways
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// Test a file is trusted through a GPG-signed SHA256SUMS and rejected when its hash differs
+func TestGPGVerifier_VerifyGPGSignedChecksums(t *testing.T) {
+	dir := t.TempDir()
+	entity, err := openpgp.NewEntity("Upstream Release", "", "release@example.com",
+		&packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pub bytes.Buffer
+	w, err := armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "release-key.asc")
+	if err := os.WriteFile(keyPath, pub.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte("release tarball"))
+	sums := hex.EncodeToString(sum[:]) + "  tool_1.0.0_linux_amd64.tar.gz\n"
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, entity, strings.NewReader(sums), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/SHA256SUMS":
+			_, _ = w.Write([]byte(sums))
+		case "/SHA256SUMS.asc":
+			_, _ = w.Write(sig.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGPGVerifier()
+	if err := g.ImportGPGKeyFromFile(keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	filePath := filepath.Join(dir, "tool_1.0.0_linux_amd64.tar.gz")
+	if err := os.WriteFile(filePath, []byte("release tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.VerifyGPGSignedChecksums(context.Background(), filePath, server.URL+"/SHA256SUMS", server.URL+"/SHA256SUMS.asc"); err != nil {
+		t.Fatalf("VerifyGPGSignedChecksums() error = %v", err)
+	}
+
+	if err := os.WriteFile(filePath, []byte("tampered tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = g.VerifyGPGSignedChecksums(context.Background(), filePath, server.URL+"/SHA256SUMS", server.URL+"/SHA256SUMS.asc")
+	if err == nil || !strings.Contains(err.Error(), "does not match signed checksums") {
+		t.Errorf("VerifyGPGSignedChecksums() error = %v, want checksum mismatch", err)
+	}
+}
@@ -0,0 +1,215 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// Max retries for transient errors
+	maxRetries = 3
+	// Initial backoff duration
+	initialBackoff = 1 * time.Second
+	// Max backoff duration
+	maxBackoff = 32 * time.Second
+	// Overall deadline for API, keyserver and probe requests
+	defaultRequestTimeout = 30 * time.Second
+)
+
+// sharedTransport pools connections across every client returned by NewHTTPClient
+var sharedTransport http.RoundTripper = newPooledTransport()
+
+// newPooledTransport returns the default transport with a larger idle connection pool
+func newPooledTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = 10
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
+
+// NewHTTPClient returns a client on the shared pooled transport that retries transient failures
+// timeout bounds a whole request including its retries; zero disables it
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{base: sharedTransport, maxRetries: maxRetries},
+		Timeout:   timeout,
+	}
+}
+
+// retryTransport retries network errors and retryable statuses with jittered exponential backoff,
+// honoring Retry-After, and fails fast once a GitHub rate limit is exhausted
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+}
+
+// waitForRetry pauses between attempts until d elapses or ctx is done
+// Tests replace it to avoid real sleeps
+var waitForRetry = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RoundTrip sends req, retrying transient failures while the request context allows
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A body that cannot be replayed only gets one attempt
+	retries := t.maxRetries
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		retries = 0
+	}
+
+	var wait time.Duration
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			if err := waitForRetry(req.Context(), wait); err != nil {
+				return nil, err
+			}
+			var err error
+			if attemptReq, err = rewindRequest(req); err != nil {
+				return nil, err
+			}
+		}
+		wait = calculateBackoff(attempt)
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			// Network errors are retryable unless the caller gave up or the host does not exist
+			var dnsErr *net.DNSError
+			if attempt >= retries || req.Context().Err() != nil || (errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+				return nil, err
+			}
+			continue
+		}
+
+		if rateLimitErr := checkRateLimit(resp); rateLimitErr != nil {
+			//nolint:errcheck,gosec // G104: Best effort close on rate limit error
+			resp.Body.Close()
+			return nil, rateLimitErr
+		}
+
+		// Success, a non-retryable error, or out of retries
+		if !isRetryableError(resp.StatusCode) || attempt >= retries {
+			return resp, nil
+		}
+
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			wait = retryAfter
+		}
+		//nolint:errcheck,gosec // G104: Best effort close before retry
+		resp.Body.Close()
+	}
+}
+
+// rewindRequest clones req with a fresh copy of its body for another attempt
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body for retry: %w", err)
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds, capped at the max backoff
+func parseRetryAfter(value string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return min(time.Duration(seconds)*time.Second, maxBackoff), true
+}
+
+// RateLimitError reports an exhausted GitHub API rate limit
+type RateLimitError struct {
+	ResetAt time.Time // When the limit resets; zero if the response did not say
+}
+
+func (e *RateLimitError) Error() string {
+	if e.ResetAt.IsZero() {
+		return "GitHub API rate limit exceeded (0 remaining)"
+	}
+	return fmt.Sprintf("GitHub API rate limit exceeded (0 remaining), resets at %s", e.ResetAt.Format(time.RFC3339))
+}
+
+// checkRateLimit checks GitHub API rate limit headers and returns a *RateLimitError if exhausted
+func checkRateLimit(resp *http.Response) error {
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	if remaining == "" {
+		return nil // No rate limit header, continue
+	}
+
+	remainingInt, err := strconv.Atoi(remaining)
+	if err != nil {
+		return nil // Invalid header, ignore
+	}
+
+	// If exhausted, return error immediately (don't wait in tests/CI)
+	if remainingInt == 0 {
+		rateLimitErr := &RateLimitError{}
+		if resetUnix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			rateLimitErr.ResetAt = time.Unix(resetUnix, 0)
+		}
+		return rateLimitErr
+	}
+
+	// Warn if getting low
+	if remainingInt <= 10 {
+		// Note: This is adapter layer, direct logging is acceptable here
+		// In production, consider injecting logger interface
+		fmt.Fprintf(os.Stderr, "⚠️  GitHub API rate limit low: %d remaining\n", remainingInt)
+	}
+
+	return nil
+}
+
+// isRetryableError checks if an HTTP status code is retryable
+func isRetryableError(statusCode int) bool {
+	switch statusCode {
+	case http.StatusForbidden, // 403 - rate limit
+		http.StatusTooManyRequests,     // 429
+		http.StatusInternalServerError, // 500
+		http.StatusBadGateway,          // 502
+		http.StatusServiceUnavailable,  // 503
+		http.StatusGatewayTimeout:      // 504
+		return true
+	default:
+		return false
+	}
+}
+
+// backoffJitter enables full jitter on retry backoffs so concurrent retries don't synchronize
+// Tests disable it to get deterministic durations
+var backoffJitter = true
+
+// calculateBackoff returns the backoff duration for a retry attempt
+// With jitter enabled the duration is uniformly random in [0, initial*2^attempt capped at max]
+func calculateBackoff(attempt int) time.Duration {
+	backoff := float64(initialBackoff) * math.Pow(2, float64(attempt))
+	if backoff > float64(maxBackoff) {
+		backoff = float64(maxBackoff)
+	}
+	if backoffJitter {
+		//nolint:gosec // G404: Jitter timing does not need a cryptographic random source
+		return time.Duration(rand.Int64N(int64(backoff) + 1))
+	}
+	return time.Duration(backoff)
+}
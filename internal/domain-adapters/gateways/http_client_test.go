@@ -0,0 +1,211 @@
+package gateways
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// recordRetryWaits replaces retry sleeps with a recorder for the duration of a test
+func recordRetryWaits(t *testing.T) *[]time.Duration {
+	t.Helper()
+	setBackoffJitter(t, false)
+	var waits []time.Duration
+	previous := waitForRetry
+	waitForRetry = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { waitForRetry = previous })
+	return &waits
+}
+
+// Test transient statuses are retried with backoff, honoring Retry-After and replaying the body
+func TestRetryTransport_RetriesTransientStatus(t *testing.T) {
+	waits := recordRetryWaits(t)
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch len(bodies) {
+		case 1:
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader([]byte("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewHTTPClient(0).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after retries", resp.StatusCode)
+	}
+	if strings.Join(bodies, ",") != "payload,payload,payload" {
+		t.Errorf("request bodies = %q, want the payload replayed on every attempt", bodies)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 5*time.Second || (*waits)[1] != 2*time.Second {
+		t.Errorf("retry waits = %v, want [5s (Retry-After) 2s (backoff)]", *waits)
+	}
+}
+
+// Test retries stop after the limit and return the last response
+func TestRetryTransport_GivesUp(t *testing.T) {
+	waits := recordRetryWaits(t)
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPClient(0).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable || attempts.Load() != maxRetries+1 {
+		t.Errorf("status %d after %d attempts, want 503 after %d", resp.StatusCode, attempts.Load(), maxRetries+1)
+	}
+	if len(*waits) != maxRetries {
+		t.Errorf("retry waits = %v, want %d", *waits, maxRetries)
+	}
+}
+
+// Test an exhausted GitHub rate limit fails immediately instead of retrying
+func TestRetryTransport_RateLimitExhausted(t *testing.T) {
+	recordRetryWaits(t)
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1767225600")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPClient(0).Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Fatalf("Get() error = %v, want rate limit error", err)
+	}
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || !rateLimitErr.ResetAt.Equal(time.Unix(1767225600, 0)) {
+		t.Errorf("Get() error = %#v, want *RateLimitError with the reset time", err)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("attempts = %d, want 1", attempts.Load())
+	}
+}
+
+// Test a canceled context interrupts the backoff wait
+func TestRetryTransport_ContextCanceled(t *testing.T) {
+	setBackoffJitter(t, false)
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := NewHTTPClient(0).Do(req); err == nil {
+		t.Fatal("Do() should fail once the context is done")
+	}
+	if elapsed := time.Since(start); elapsed > initialBackoff/2 {
+		t.Errorf("Do() took %v, want it to stop waiting when the context expires", elapsed)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("attempts = %d, want 1", attempts.Load())
+	}
+}
+
+// Test every HTTP gateway is built on the shared retrying transport
+func TestGateways_UseSharedTransport(t *testing.T) {
+	clients := map[string]*http.Client{
+		"Downloader":        NewDownloader().httpClient,
+		"VersionFetcher":    NewVersionFetcher().httpClient,
+		"HTTPGitHubGateway": NewHTTPGitHubGateway("token").client,
+		"URLProber":         NewURLProber().httpClient,
+		"OSVGateway":        NewOSVGateway().httpClient,
+		"GPGVerifier":       NewGPGVerifier().httpClient,
+	}
+	for name, client := range clients {
+		transport, ok := client.Transport.(*retryTransport)
+		if !ok || transport.base != sharedTransport {
+			t.Errorf("%s transport = %T, want the shared retry transport", name, client.Transport)
+		}
+	}
+}
+
+// Test the GPG adapter's key imports retry through the shared transport
+func TestGPGVerifier_ImportKeysFromURL_Retries(t *testing.T) {
+	recordRetryWaits(t)
+
+	entity, err := openpgp.NewEntity("Upstream Release", "", "release@example.com",
+		&packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys bytes.Buffer
+	w, err := armor.Encode(&keys, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(keys.Bytes())
+	}))
+	defer server.Close()
+
+	if err := NewGPGVerifier().ImportGPGKeysFromURL(context.Background(), server.URL+"/KEYS"); err != nil {
+		t.Fatalf("ImportGPGKeysFromURL() error = %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("attempts = %d, want a retry after the 503", attempts.Load())
+	}
+}
@@ -23,10 +23,8 @@ type osvGateway struct {
 //nolint:revive // unexported-return: Intentionally returns concrete type for testability
 func NewOSVGateway() *osvGateway {
 	return &osvGateway{
-		apiURL: "https://api.osv.dev/v1/query",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiURL:     "https://api.osv.dev/v1/query",
+		httpClient: NewHTTPClient(defaultRequestTimeout),
 	}
 }
 
@@ -54,6 +52,7 @@ func (g *osvGateway) ScanWithOSV(ctx context.Context, artifact *entities.Artifac
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", UserAgent())
 
 	resp, err := g.httpClient.Do(req)
 	if err != nil {
@@ -102,6 +101,10 @@ func (g *osvGateway) ScanWithOSV(ctx context.Context, artifact *entities.Artifac
 
 // detectEcosystem tries to detect the package ecosystem
 func (g *osvGateway) detectEcosystem(artifact *entities.Artifact) string {
+	if artifact.Ecosystem != "" {
+		return artifact.Ecosystem
+	}
+
 	// Simple heuristics - could be improved
 	name := artifact.Name
 
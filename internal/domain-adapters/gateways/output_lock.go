@@ -0,0 +1,144 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// OutputLockFile is created in an output directory while a build writes to it
+const OutputLockFile = ".potions.lock"
+
+// ErrOutputDirLocked is returned when another live potions process holds the output directory
+var ErrOutputDirLocked = errors.New("output dir in use")
+
+// outputLockPollInterval is how often a waiting acquisition retries
+var outputLockPollInterval = 500 * time.Millisecond
+
+// OutputLock is an advisory lock on an output directory, held by the PID written into its lock file
+type OutputLock struct {
+	path string
+}
+
+// AcquireOutputLock locks dir for this process; a lock held by a live process fails with
+// ErrOutputDirLocked, or is waited for when wait is set; locks of dead processes are taken over
+func AcquireOutputLock(ctx context.Context, dir string, wait bool) (*OutputLock, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	path := filepath.Join(dir, OutputLockFile)
+
+	// The PID is written to a private file first and hard-linked into place, so the lock
+	// never exists without its holder and a half-written lock is never mistaken for stale
+	pending := fmt.Sprintf("%s.%d", path, os.Getpid())
+	if err := os.WriteFile(pending, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write output lock: %w", err)
+	}
+	//nolint:errcheck // Best effort cleanup of the pending lock file
+	defer os.Remove(pending)
+
+	announced := false
+	for {
+		err := os.Link(pending, path)
+		if err == nil {
+			return &OutputLock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create output lock: %w", err)
+		}
+
+		pid, alive := lockHolder(path)
+		if !alive {
+			// Stale lock from a crashed or killed build
+			if err := takeOverStaleLock(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !wait {
+			return nil, fmt.Errorf("%w: %s is locked by potions process %d (remove %s if that process is not a build)",
+				ErrOutputDirLocked, dir, pid, path)
+		}
+		if !announced {
+			fmt.Fprintf(os.Stderr, "Waiting for potions process %d to release %s\n", pid, dir)
+			announced = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for output lock: %w", ctx.Err())
+		case <-time.After(outputLockPollInterval):
+		}
+	}
+}
+
+// takeOverStaleLock moves a lock found stale aside under a name private to this process, so of
+// several processes finding the same stale lock only one removes it. A live process may have
+// replaced the lock after it was found stale; its lock is then what was moved, and it is put back
+func takeOverStaleLock(path string) error {
+	claimed := fmt.Sprintf("%s.stale.%d", path, os.Getpid())
+	if err := os.Rename(path, claimed); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Another process took it over first
+			return nil
+		}
+		return fmt.Errorf("failed to remove stale output lock: %w", err)
+	}
+	//nolint:errcheck // Best effort cleanup of the claimed lock file
+	defer os.Remove(claimed)
+
+	if _, alive := lockHolder(claimed); alive {
+		if err := os.Link(claimed, path); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to restore output lock: %w", err)
+		}
+	}
+	return nil
+}
+
+// Release removes the lock file
+func (l *OutputLock) Release() error {
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release output lock: %w", err)
+	}
+	return nil
+}
+
+// lockHolder returns the PID recorded in a lock file and whether that process is still running;
+// an unreadable or malformed lock counts as stale, and so does one recording this process, which
+// cannot hold a lock it is acquiring: a killed build in a container leaves its PID to the next one
+func lockHolder(path string) (int, bool) {
+	//nolint:gosec // G304: path is the lock file inside the user's output directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// Released between our create attempt and this read: retry as if stale
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	if pid == os.Getpid() {
+		return pid, false
+	}
+	return pid, processAlive(pid)
+}
+
+// processAlive reports whether a process with pid exists
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess only succeeds for running processes on Windows, where signal 0 is unsupported
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,120 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Test a lock held by another live process makes acquisition fail, or wait until it is released
+func TestAcquireOutputLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, OutputLockFile)
+	// The test binary's parent (go test) stands in for another running build
+	held := []byte(strconv.Itoa(os.Getppid()) + "\n")
+	if err := os.WriteFile(path, held, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AcquireOutputLock(context.Background(), dir, false); !errors.Is(err, ErrOutputDirLocked) {
+		t.Fatalf("AcquireOutputLock() error = %v, want ErrOutputDirLocked", err)
+	}
+
+	outputLockPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { outputLockPollInterval = 500 * time.Millisecond })
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = os.Remove(path)
+		close(released)
+	}()
+	waited, err := AcquireOutputLock(context.Background(), dir, true)
+	if err != nil {
+		t.Fatalf("waiting AcquireOutputLock() error = %v", err)
+	}
+	select {
+	case <-released:
+	default:
+		t.Error("waiting AcquireOutputLock() returned before the lock was released")
+	}
+
+	if err := waited.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file still present after Release(): %v", err)
+	}
+
+	if err := os.WriteFile(path, held, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := AcquireOutputLock(ctx, dir, true); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting AcquireOutputLock() error = %v, want the context deadline", err)
+	}
+}
+
+// Test a lock recording this process's PID is stale, as left by a killed build whose PID was reused
+func TestAcquireOutputLock_OwnPID(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, OutputLockFile), []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := AcquireOutputLock(context.Background(), dir, false)
+	if err != nil {
+		t.Fatalf("AcquireOutputLock() over a lock with our own PID error = %v", err)
+	}
+	defer func() { _ = lock.Release() }()
+}
+
+// Test a lock left by a process that is no longer running is taken over
+func TestAcquireOutputLock_Stale(t *testing.T) {
+	dir := t.TempDir()
+	// Above any Linux pid_max, so no such process exists
+	if err := os.WriteFile(filepath.Join(dir, OutputLockFile), []byte("99999999\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := AcquireOutputLock(context.Background(), dir, false)
+	if err != nil {
+		t.Fatalf("AcquireOutputLock() over a stale lock error = %v", err)
+	}
+	defer func() { _ = lock.Release() }()
+}
+
+// Test a stale-lock takeover that finds a live lock in its place puts that lock back
+func TestTakeOverStaleLock_Replaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), OutputLockFile)
+	live := []byte(strconv.Itoa(os.Getppid()) + "\n")
+	if err := os.WriteFile(path, live, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := takeOverStaleLock(path); err != nil {
+		t.Fatalf("takeOverStaleLock() error = %v", err)
+	}
+	//nolint:gosec // G304: path is the lock file in the test's temp directory
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != string(live) {
+		t.Errorf("lock after takeover = %q, %v; want the live holder's lock restored", data, err)
+	}
+
+	if err := os.WriteFile(path, []byte("99999999\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := takeOverStaleLock(path); err != nil {
+		t.Fatalf("takeOverStaleLock() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stale lock still present after takeover: %v", err)
+	}
+	if matches, _ := filepath.Glob(path + ".stale.*"); len(matches) != 0 {
+		t.Errorf("claimed lock files left behind: %v", matches)
+	}
+}
@@ -69,10 +69,8 @@ func (p *Packager) PackageArtifact(
 		// If no bin directory, package the entire extracted directory
 	}
 
-	// Create output filename: packagename-version-platform.tar.gz
-	// Remove 'v' prefix from version if present
-	cleanVersion := strings.TrimPrefix(version, "v")
-	tarballName := fmt.Sprintf("%s-%s-%s.tar.gz", def.Name, cleanVersion, platform)
+	// Create output filename: packagename-version-platform.tar.gz (without a 'v' prefix)
+	tarballName := entities.ArtifactFileName(def.Name, version, platform)
 
 	// Output to the configured output directory
 	if outputDir == "" {
@@ -93,11 +91,12 @@ func (p *Packager) PackageArtifact(
 
 	// Create new artifact pointing to the tarball
 	packagedArtifact := &entities.Artifact{
-		Name:     def.Name,
-		Version:  version,
-		Platform: platform,
-		Path:     tarballPath,
-		Type:     "archive",
+		Name:      def.Name,
+		Version:   version,
+		Platform:  platform,
+		Path:      tarballPath,
+		SourceURL: artifact.SourceURL,
+		Type:      "archive",
 	}
 
 	return packagedArtifact, nil
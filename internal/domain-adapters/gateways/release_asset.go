@@ -0,0 +1,122 @@
+package gateways
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// releaseAssets is the subset of a GitHub release used to select a download
+type releaseAssets struct {
+	TagName string `json:"tag_name"`
+	Body    string `json:"body"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// findReleaseAsset returns the browser_download_url of the single asset of a github-release
+// source's release for version whose name matches pattern
+func (d *Downloader) findReleaseAsset(ctx context.Context, source, version, pattern string) (string, error) {
+	repo, ok := strings.CutPrefix(source, "github-release:")
+	if !ok || repo == "" {
+		return "", fmt.Errorf("asset_pattern requires a github-release version source, got %q", source)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid asset_pattern %q: %w", pattern, err)
+	}
+
+	release, err := d.fetchReleaseByVersion(ctx, repo, version)
+	if err != nil {
+		return "", err
+	}
+
+	var matches, names []string
+	var assetURL string
+	for _, asset := range release.Assets {
+		names = append(names, asset.Name)
+		if matched, _ := path.Match(pattern, asset.Name); matched {
+			matches = append(matches, asset.Name)
+			assetURL = asset.BrowserDownloadURL
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no asset of %s %s matches %q (assets: %s)", repo, release.TagName, pattern, strings.Join(names, ", "))
+	case 1:
+		fmt.Fprintf(os.Stderr, "Selected release asset %s\n", matches[0])
+		return assetURL, nil
+	default:
+		return "", fmt.Errorf("asset_pattern %q matches %d assets of %s %s: %s", pattern, len(matches), repo, release.TagName, strings.Join(matches, ", "))
+	}
+}
+
+// fetchReleaseByVersion looks up the release tagged version, trying the tag with and without a "v" prefix
+func (d *Downloader) fetchReleaseByVersion(ctx context.Context, repo, version string) (*releaseAssets, error) {
+	tags := []string{version, "v" + version}
+	if trimmed, ok := strings.CutPrefix(version, "v"); ok {
+		tags[1] = trimmed
+	}
+
+	for _, tag := range tags {
+		release, err := d.fetchReleaseByTag(ctx, repo, tag)
+		if err != nil {
+			return nil, err
+		}
+		if release != nil {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("no GitHub release of %s tagged %s", repo, strings.Join(tags, " or "))
+}
+
+// fetchReleaseByTag fetches a release by tag, returning nil when the tag has no release
+func (d *Downloader) fetchReleaseByTag(ctx context.Context, repo, tag string) (*releaseAssets, error) {
+	ctx, cancel := requestContext(ctx, d.timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/repos/%s/releases/tags/%s", d.apiBaseURL, repo, tag)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", UserAgent())
+
+	// Add GitHub token if available (required for higher rate limits)
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub API request failed: %w", err)
+	}
+	//nolint:errcheck // Defer close on HTTP response body
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var release releaseAssets
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub release: %w", err)
+	}
+	return &release, nil
+}
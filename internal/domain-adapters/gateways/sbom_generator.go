@@ -19,7 +19,9 @@ import (
 
 // sbomGenerator implements SBOM generation using pure Go
 // Uses debug/elf and debug/macho packages - no syft binary required
-type sbomGenerator struct{}
+type sbomGenerator struct {
+	strict bool
+}
 
 // NewSBOMGenerator creates a new SBOM generator gateway
 //
@@ -28,6 +30,12 @@ func NewSBOMGenerator() *sbomGenerator {
 	return &sbomGenerator{}
 }
 
+// WithStrict fails SBOM generation for binaries whose dependencies cannot be resolved
+func (g *sbomGenerator) WithStrict(strict bool) *sbomGenerator {
+	g.strict = strict
+	return g
+}
+
 // GenerateSBOM generates a Software Bill of Materials for an artifact
 func (g *sbomGenerator) GenerateSBOM(_ context.Context, artifact *entities.Artifact) (*entities.SBOM, error) {
 	if artifact == nil {
@@ -55,6 +63,7 @@ func (g *sbomGenerator) GenerateSBOM(_ context.Context, artifact *entities.Artif
 			Type:    "application",
 			Name:    artifact.Name,
 			Version: artifact.Version,
+			PURL:    entities.GenericPURL(artifact.Name, artifact.Version),
 			Hashes: []entities.Hash{
 				{
 					Algorithm: "SHA-256",
@@ -74,6 +83,14 @@ func (g *sbomGenerator) GenerateSBOM(_ context.Context, artifact *entities.Artif
 		} else {
 			components = append(components, deps...)
 		}
+
+		// Strict mode: a detected binary with no resolved dependencies needs investigation
+		if g.strict && len(deps) == 0 && g.isBinary(artifact.Path) {
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", entities.ErrIncompleteSBOM, filepath.Base(artifact.Path), err)
+			}
+			return nil, fmt.Errorf("%w: %s", entities.ErrIncompleteSBOM, filepath.Base(artifact.Path))
+		}
 	}
 
 	return &entities.SBOM{
@@ -93,6 +110,16 @@ func (g *sbomGenerator) GenerateSBOM(_ context.Context, artifact *entities.Artif
 	}, nil
 }
 
+// IsBinary reports whether the file at path starts with an ELF or Mach-O magic number
+func IsBinary(path string) bool {
+	return (&sbomGenerator{}).isBinary(path)
+}
+
+// DetectBinaryPlatform returns "linux" for ELF and "darwin" for Mach-O binaries, or "unknown"
+func DetectBinaryPlatform(path string) string {
+	return (&sbomGenerator{}).detectPlatform(path)
+}
+
 // isBinary attempts to determine if a file is a binary
 func (g *sbomGenerator) isBinary(path string) bool {
 	//nolint:gosec // G304: path is from filepath.Walk for SBOM generation
@@ -155,12 +182,14 @@ func (g *sbomGenerator) extractBinaryDependencies(binaryPath, platform string) (
 // detectPlatform attempts to detect the platform from the binary
 func (g *sbomGenerator) detectPlatform(binaryPath string) string {
 	// Try ELF first
-	if _, err := elf.Open(binaryPath); err == nil {
+	if f, err := elf.Open(binaryPath); err == nil {
+		_ = f.Close()
 		return "linux"
 	}
 
 	// Try Mach-O
-	if _, err := macho.Open(binaryPath); err == nil {
+	if f, err := macho.Open(binaryPath); err == nil {
+		_ = f.Close()
 		return "darwin"
 	}
 
@@ -199,6 +228,7 @@ func (g *sbomGenerator) extractELFDependencies(binaryPath string) ([]entities.Co
 			Name:    name,
 			Version: version,
 			Hashes:  []entities.Hash{}, // Could resolve library path and hash it
+			PURL:    entities.GenericPURL(name, version),
 		})
 	}
 
@@ -245,6 +275,7 @@ func (g *sbomGenerator) extractMachODependencies(binaryPath string) ([]entities.
 			Name:    name,
 			Version: version,
 			Hashes:  []entities.Hash{},
+			PURL:    entities.GenericPURL(name, version),
 		})
 	}
 
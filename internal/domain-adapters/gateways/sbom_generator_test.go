@@ -2,8 +2,11 @@ package gateways
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/ochairo/potions/internal/domain/entities"
@@ -67,6 +70,17 @@ func TestGenerateSBOM(t *testing.T) {
 		t.Errorf("Main component version = %v, want 1.0.0", mainComponent.Version)
 	}
 
+	// Verify package URLs
+	purlPattern := regexp.MustCompile(`^pkg:generic/[A-Za-z0-9.\-_~%]+(@[A-Za-z0-9.\-_~%]+)?$`)
+	for _, component := range sbom.Components {
+		if !purlPattern.MatchString(component.PURL) {
+			t.Errorf("component %s purl = %q, want a well-formed pkg:generic purl", component.Name, component.PURL)
+		}
+	}
+	if mainComponent.PURL != "pkg:generic/test-package@1.0.0" {
+		t.Errorf("Main component purl = %v, want pkg:generic/test-package@1.0.0", mainComponent.PURL)
+	}
+
 	// Verify hash
 	if len(mainComponent.Hashes) == 0 {
 		t.Error("Main component hashes is empty, expected SHA256 hash")
@@ -257,3 +271,50 @@ func TestIsNumeric(t *testing.T) {
 		})
 	}
 }
+
+// writeStaticELF writes a minimal ELF64 executable header with no dynamic section
+func writeStaticELF(t *testing.T, path string) {
+	t.Helper()
+	header := make([]byte, 64)
+	copy(header, []byte{0x7F, 'E', 'L', 'F', 2, 1, 1})
+	binary.LittleEndian.PutUint16(header[16:], 2)  // ET_EXEC
+	binary.LittleEndian.PutUint16(header[18:], 62) // EM_X86_64
+	binary.LittleEndian.PutUint32(header[20:], 1)  // EV_CURRENT
+	binary.LittleEndian.PutUint16(header[52:], 64) // e_ehsize
+	binary.LittleEndian.PutUint16(header[54:], 56) // e_phentsize
+	binary.LittleEndian.PutUint16(header[58:], 64) // e_shentsize
+	if err := os.WriteFile(path, header, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test strict mode fails binaries without resolved dependencies while non-binaries are exempt
+func TestGenerateSBOM_Strict(t *testing.T) {
+	tmpDir := t.TempDir()
+	binaryPath := filepath.Join(tmpDir, "tool")
+	writeStaticELF(t, binaryPath)
+	scriptPath := filepath.Join(tmpDir, "tool.sh")
+	if err := os.WriteFile(scriptPath, []byte("#!/bin/sh\necho hi\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	binaryArtifact := &entities.Artifact{Name: "tool", Version: "1.0.0", Platform: "linux-amd64", Path: binaryPath, Type: "binary"}
+
+	sbom, err := NewSBOMGenerator().GenerateSBOM(context.Background(), binaryArtifact)
+	if err != nil {
+		t.Fatalf("GenerateSBOM() error = %v, want best-effort success", err)
+	}
+	if len(sbom.Components) != 1 {
+		t.Errorf("Components = %d, want only the top component", len(sbom.Components))
+	}
+
+	strict := NewSBOMGenerator().WithStrict(true)
+	if _, err := strict.GenerateSBOM(context.Background(), binaryArtifact); !errors.Is(err, entities.ErrIncompleteSBOM) {
+		t.Errorf("strict GenerateSBOM() error = %v, want ErrIncompleteSBOM", err)
+	}
+
+	scriptArtifact := &entities.Artifact{Name: "tool", Version: "1.0.0", Platform: "linux-amd64", Path: scriptPath, Type: "binary"}
+	if _, err := strict.GenerateSBOM(context.Background(), scriptArtifact); err != nil {
+		t.Errorf("strict GenerateSBOM() on a non-binary error = %v, want success", err)
+	}
+}
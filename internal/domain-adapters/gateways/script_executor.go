@@ -17,13 +17,16 @@ import (
 // ScriptExecutor handles execution of build scripts
 type ScriptExecutor struct {
 	defaultTimeout time.Duration
+	execute        func(ctx context.Context, config ExecuteScriptConfig) *ExecuteResult
 }
 
 // NewScriptExecutor creates a new script executor
 func NewScriptExecutor() *ScriptExecutor {
-	return &ScriptExecutor{
+	se := &ScriptExecutor{
 		defaultTimeout: 30 * time.Minute,
 	}
+	se.execute = se.ExecuteScript
+	return se
 }
 
 // ExecuteScriptConfig contains configuration for executing a shell script.
@@ -158,10 +161,14 @@ func (se *ScriptExecutor) ExecuteBuildScripts(
 		timeout = time.Duration(def.Build.TimeoutMinutes) * time.Minute
 	}
 
+	// Select per-platform script overrides, falling back to the default scripts
+	configure := scriptsForPlatform(def.Configure, artifact.Platform)
+	build := scriptsForPlatform(def.Build, artifact.Platform)
+
 	// Execute configure script if present
-	if def.Configure.Script != "" {
-		result := se.ExecuteScript(ctx, ExecuteScriptConfig{
-			Script:      def.Configure.Script,
+	if configure.Script != "" {
+		result := se.execute(ctx, ExecuteScriptConfig{
+			Script:      configure.Script,
 			WorkingDir:  workingDir,
 			Env:         env,
 			Timeout:     timeout,
@@ -179,9 +186,9 @@ func (se *ScriptExecutor) ExecuteBuildScripts(
 	}
 
 	// Execute custom_build script if present
-	if def.Build.CustomBuild != "" {
-		result := se.ExecuteScript(ctx, ExecuteScriptConfig{
-			Script:      def.Build.CustomBuild,
+	if build.CustomBuild != "" {
+		result := se.execute(ctx, ExecuteScriptConfig{
+			Script:      build.CustomBuild,
 			WorkingDir:  workingDir,
 			Env:         env,
 			Timeout:     timeout,
@@ -199,9 +206,9 @@ func (se *ScriptExecutor) ExecuteBuildScripts(
 	}
 
 	// Execute custom_install script (build step)
-	if def.Build.CustomInstall != "" {
-		result := se.ExecuteScript(ctx, ExecuteScriptConfig{
-			Script:      def.Build.CustomInstall,
+	if build.CustomInstall != "" {
+		result := se.execute(ctx, ExecuteScriptConfig{
+			Script:      build.CustomInstall,
 			WorkingDir:  workingDir,
 			Env:         env,
 			Timeout:     timeout,
@@ -223,6 +230,29 @@ func (se *ScriptExecutor) ExecuteBuildScripts(
 	return nil
 }
 
+// scriptsForPlatform applies the step's override for platform (e.g. "darwin-arm64"),
+// or else for its OS (e.g. "darwin"); unset override fields keep the default scripts
+func scriptsForPlatform(step entities.RecipeBuildStep, platform string) entities.RecipeBuildStep {
+	override, ok := step.Platforms[platform]
+	if !ok {
+		osName, _, _ := strings.Cut(platform, "-")
+		if override, ok = step.Platforms[osName]; !ok {
+			return step
+		}
+	}
+
+	if override.Script != "" {
+		step.Script = override.Script
+	}
+	if override.CustomBuild != "" {
+		step.CustomBuild = override.CustomBuild
+	}
+	if override.CustomInstall != "" {
+		step.CustomInstall = override.CustomInstall
+	}
+	return step
+}
+
 // isDirectory checks if a path is a directory
 func isDirectory(path string) bool {
 	info, err := os.Stat(path)
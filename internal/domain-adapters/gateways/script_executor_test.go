@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -159,6 +160,54 @@ func TestScriptExecutor_ExecuteBuildScripts(t *testing.T) {
 	}
 }
 
+// Test per-platform overrides: darwin targets run the darwin scripts, linux keeps the defaults
+func TestScriptExecutor_ExecuteBuildScripts_PlatformOverrides(t *testing.T) {
+	def := &entities.Recipe{
+		Name: "tool",
+		Configure: entities.RecipeBuildStep{
+			Script: "./configure --enable-static",
+			Platforms: map[string]entities.ScriptOverride{
+				"darwin": {Script: "./configure --with-darwin-ssl"},
+			},
+		},
+		Build: entities.RecipeBuildStep{
+			CustomBuild:   "make",
+			CustomInstall: "make install",
+			Platforms: map[string]entities.ScriptOverride{
+				"darwin":       {CustomBuild: "gmake"},
+				"darwin-arm64": {CustomBuild: "gmake ARCH=arm64"},
+			},
+		},
+	}
+
+	tests := []struct {
+		platform string
+		want     []string
+	}{
+		{"linux-x86_64", []string{"./configure --enable-static", "make", "make install"}},
+		{"darwin-x86_64", []string{"./configure --with-darwin-ssl", "gmake", "make install"}},
+		{"darwin-arm64", []string{"./configure --with-darwin-ssl", "gmake ARCH=arm64", "make install"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			var scripts []string
+			se := NewScriptExecutor()
+			se.execute = func(_ context.Context, config ExecuteScriptConfig) *ExecuteResult {
+				scripts = append(scripts, config.Script)
+				return &ExecuteResult{Success: true}
+			}
+
+			artifact := &entities.Artifact{Version: "1.0.0", Platform: tt.platform, Path: t.TempDir(), Type: "source"}
+			if err := se.ExecuteBuildScripts(context.Background(), def, artifact, t.TempDir()); err != nil {
+				t.Fatalf("ExecuteBuildScripts() error = %v", err)
+			}
+			if strings.Join(scripts, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("scripts run = %q, want %q", scripts, tt.want)
+			}
+		})
+	}
+}
+
 func TestScriptExecutor_ValidateScript(t *testing.T) {
 	se := NewScriptExecutor()
 
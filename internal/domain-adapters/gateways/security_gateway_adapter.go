@@ -28,7 +28,7 @@ func NewSecurityGatewayAdapter(logger interfaces.Logger) *SecurityGatewayAdapter
 
 	return &SecurityGatewayAdapter{
 		logger:              logger,
-		gpgVerifier:         gpg.NewVerifier(),
+		gpgVerifier:         gpg.NewVerifier().WithHTTPClient(NewHTTPClient(defaultRequestTimeout)),
 		cosignVerifier:      cosign.NewVerifier(),
 		attestationVerifier: attestation.NewVerifier(),
 	}
@@ -57,12 +57,12 @@ func (s *SecurityGatewayAdapter) VerifyGPGSignature(ctx context.Context, filePat
 }
 
 // ImportGPGKeys imports GPG keys from keyservers
-func (s *SecurityGatewayAdapter) ImportGPGKeys(ctx context.Context, keyIDs []string) error {
+func (s *SecurityGatewayAdapter) ImportGPGKeys(ctx context.Context, keyIDs []string, lookup entities.GPGKeyLookup) error {
 	s.logger.Info("importing GPG keys",
 		interfaces.F("key_count", len(keyIDs)),
 	)
 
-	return s.gpgVerifier.ImportKeys(ctx, keyIDs)
+	return s.gpgVerifier.ImportKeysVia(ctx, keyIDs, gpg.KeyLookup(lookup))
 }
 
 // ImportGPGKeysFromURL imports GPG keys from a URL
@@ -0,0 +1,95 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+var (
+	// toolNamePattern restricts required tool names to plain executable names
+	toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+	// toolVersionPattern extracts the first dotted version from a tool's version output
+	toolVersionPattern = regexp.MustCompile(`[0-9]+\.[0-9]+(\.[0-9]+)?`)
+	// minVersionPattern validates build.requires min_version values
+	minVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+)
+
+// toolVersionArgs lists tools whose version is printed by a subcommand rather than --version
+var toolVersionArgs = map[string][]string{
+	"go": {"version"},
+}
+
+// ToolchainChecker verifies a recipe's build.requires against tools installed on the host
+type ToolchainChecker struct {
+	timeout time.Duration
+	run     commandRunner
+}
+
+// NewToolchainChecker creates a new toolchain checker
+func NewToolchainChecker() *ToolchainChecker {
+	return &ToolchainChecker{
+		timeout: 30 * time.Second,
+		run:     runToolVersion,
+	}
+}
+
+// runToolVersion executes a tool from PATH to print its version
+func runToolVersion(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	//nolint:gosec // G204: Tool name validated by toolNamePattern and arguments are fixed
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// CheckRequirements runs each required tool's version command and fails on the first unmet requirement
+func (c *ToolchainChecker) CheckRequirements(ctx context.Context, reqs []entities.ToolRequirement) error {
+	for _, req := range reqs {
+		if err := c.checkRequirement(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ToolchainChecker) checkRequirement(ctx context.Context, req entities.ToolRequirement) error {
+	if !toolNamePattern.MatchString(req.Tool) {
+		return fmt.Errorf("security: invalid tool name %q in build.requires", req.Tool)
+	}
+	minVersion := strings.TrimPrefix(req.MinVersion, "v")
+	if !minVersionPattern.MatchString(minVersion) {
+		return fmt.Errorf("invalid min_version %q for %s", req.MinVersion, req.Tool)
+	}
+
+	args, ok := toolVersionArgs[req.Tool]
+	if !ok {
+		args = []string{"--version"}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	output, err := c.run(runCtx, "", req.Tool, args...)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%s %s or newer is required but %s was not found in PATH", req.Tool, req.MinVersion, req.Tool)
+		}
+		return fmt.Errorf("failed to run %s %s: %w\nOutput: %s", req.Tool, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+
+	installed := toolVersionPattern.FindString(string(output))
+	if installed == "" {
+		return fmt.Errorf("could not determine %s version from output: %s", req.Tool, strings.TrimSpace(string(output)))
+	}
+
+	if entities.CompareVersions(installed, minVersion) < 0 {
+		return fmt.Errorf("%s %s or newer is required, found %s", req.Tool, req.MinVersion, installed)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// Test build.requires is checked against the version reported by the installed tool
+func TestToolchainChecker_CheckRequirements(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      entities.ToolRequirement
+		output   string
+		runErr   error
+		wantArgs string
+		wantErr  string
+	}{
+		{"go below minimum", entities.ToolRequirement{Tool: "go", MinVersion: "1.22"}, "go version go1.21.5 linux/amd64\n", nil, "version", "go 1.22 or newer is required, found 1.21.5"},
+		{"go satisfies minimum", entities.ToolRequirement{Tool: "go", MinVersion: "1.22"}, "go version go1.22.0 linux/amd64\n", nil, "version", ""},
+		{"minor compared numerically", entities.ToolRequirement{Tool: "cargo", MinVersion: "1.9"}, "cargo 1.75.0 (1d8b05cdd 2023-11-20)\n", nil, "--version", ""},
+		{"tool missing", entities.ToolRequirement{Tool: "zig", MinVersion: "0.11"}, "", fmt.Errorf("exec: %w", exec.ErrNotFound), "--version", "not found in PATH"},
+		{"command fails", entities.ToolRequirement{Tool: "node", MinVersion: "20"}, "boom", errors.New("exit status 1"), "--version", "failed to run node"},
+		{"invalid minimum", entities.ToolRequirement{Tool: "node", MinVersion: ">=20"}, "", nil, "", "invalid min_version"},
+		{"unparseable output", entities.ToolRequirement{Tool: "node", MinVersion: "20.0"}, "unknown", nil, "--version", "could not determine"},
+		{"injection rejected", entities.ToolRequirement{Tool: "go;rm", MinVersion: "1.22"}, "", nil, "", "invalid tool name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranArgs []string
+			c := NewToolchainChecker()
+			c.run = func(_ context.Context, _, _ string, args ...string) ([]byte, error) {
+				ranArgs = args
+				return []byte(tt.output), tt.runErr
+			}
+
+			err := c.CheckRequirements(context.Background(), []entities.ToolRequirement{tt.req})
+
+			if got := strings.Join(ranArgs, " "); got != tt.wantArgs {
+				t.Errorf("ran with args %q, want %q", got, tt.wantArgs)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckRequirements() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckRequirements() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
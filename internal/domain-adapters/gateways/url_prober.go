@@ -0,0 +1,53 @@
+package gateways
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// URLProber checks whether remote URLs exist without downloading them
+type URLProber struct {
+	httpClient *http.Client
+}
+
+// NewURLProber creates a new URL prober
+func NewURLProber() *URLProber {
+	return &URLProber{
+		httpClient: NewHTTPClient(defaultRequestTimeout),
+	}
+}
+
+// URLExists reports whether url answers 200, using HEAD and falling back to GET
+// for servers that reject HEAD requests
+func (p *URLProber) URLExists(ctx context.Context, url string) (bool, error) {
+	status, err := p.probe(ctx, http.MethodHead, url)
+	if err != nil {
+		return false, err
+	}
+	if status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented {
+		status, err = p.probe(ctx, http.MethodGet, url)
+		if err != nil {
+			return false, err
+		}
+	}
+	return status == http.StatusOK, nil
+}
+
+// probe sends a single request and returns the response status code
+func (p *URLProber) probe(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe %s: %w", url, err)
+	}
+	//nolint:errcheck // Defer close on probe response
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,47 @@
+package gateways
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test URLExists reports 200 responses and falls back to GET when HEAD is rejected
+func TestURLProber_URLExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tool.tar.gz.asc":
+			w.WriteHeader(http.StatusOK)
+		case "/head-rejected.asc":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	prober := NewURLProber()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/tool.tar.gz.asc", true},
+		{"/tool.tar.gz.sig", false},
+		{"/head-rejected.asc", true},
+	}
+
+	for _, tt := range tests {
+		got, err := prober.URLExists(context.Background(), server.URL+tt.path)
+		if err != nil {
+			t.Fatalf("URLExists(%s) error = %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("URLExists(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package gateways
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// urlCacheEntry is the last response of a url: version source and the validators to revalidate it
+type urlCacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         string `json:"body"`
+}
+
+// cacheable reports whether the response carried a validator a later request can send back
+func (e *urlCacheEntry) cacheable() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// urlCachePath returns the file caching url under dir
+func urlCachePath(dir, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// loadURLCacheEntry returns the cached response for url, or nil when there is no usable entry
+func loadURLCacheEntry(dir, url string) *urlCacheEntry {
+	//nolint:gosec // G304: path is derived from the URL's digest inside the cache directory
+	data, err := os.ReadFile(urlCachePath(dir, url))
+	if err != nil {
+		return nil
+	}
+	var entry urlCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url || !entry.cacheable() {
+		return nil
+	}
+	return &entry
+}
+
+// storeURLCacheEntry writes entry through a temp file and rename so concurrent runs never read a partial entry
+func storeURLCacheEntry(dir string, entry *urlCacheEntry) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create version cache: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode version cache entry: %w", err)
+	}
+
+	path := urlCachePath(dir, entry.URL)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write version cache entry: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write version cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write version cache entry: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write version cache entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package gateways
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// userAgentProduct is the product token used in the default User-Agent
+const userAgentProduct = "potions"
+
+// userAgent holds the User-Agent sent on every outbound HTTP request
+var userAgent atomic.Value
+
+func init() {
+	userAgent.Store(DefaultUserAgent(""))
+}
+
+// DefaultUserAgent returns the User-Agent for a build version ("dev" when unstamped)
+func DefaultUserAgent(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		version = "dev"
+	}
+	return userAgentProduct + "/" + version
+}
+
+// SetUserAgent overrides the User-Agent for subsequent requests; empty values are ignored
+func SetUserAgent(ua string) {
+	if ua = strings.TrimSpace(ua); ua != "" {
+		userAgent.Store(ua)
+	}
+}
+
+// UserAgent returns the User-Agent sent on outbound HTTP requests
+func UserAgent() string {
+	return userAgent.Load().(string)
+}
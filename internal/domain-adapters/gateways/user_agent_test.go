@@ -0,0 +1,63 @@
+package gateways
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// Test the configured User-Agent is sent by the downloader, version fetcher and GitHub gateway
+func TestUserAgent_SentOnRequests(t *testing.T) {
+	previous := UserAgent()
+	t.Cleanup(func() { SetUserAgent(previous) })
+	SetUserAgent("potions-test/2.0 (+mailto:ops@example.com)")
+
+	var mu sync.Mutex
+	seen := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = r.Header.Get("User-Agent")
+		mu.Unlock()
+		if r.URL.Path == "/repos/owner/repo/releases" {
+			_, _ = w.Write([]byte("[]"))
+			return
+		}
+		_, _ = w.Write([]byte("1.2.3"))
+	}))
+	defer server.Close()
+
+	if err := NewDownloader().downloadFile(context.Background(), server.URL+"/download", filepath.Join(t.TempDir(), "file"), nil); err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+	if _, err := NewVersionFetcher().fetchFromURL(server.URL + "/version"); err != nil {
+		t.Fatalf("fetchFromURL() error = %v", err)
+	}
+	gateway := NewHTTPGitHubGateway("")
+	gateway.baseURL = server.URL
+	if _, err := gateway.ListReleases(context.Background(), "owner", "repo"); err != nil {
+		t.Fatalf("ListReleases() error = %v", err)
+	}
+
+	for _, path := range []string{"/download", "/version", "/repos/owner/repo/releases"} {
+		if got := seen[path]; got != "potions-test/2.0 (+mailto:ops@example.com)" {
+			t.Errorf("User-Agent for %s = %q, want configured value", path, got)
+		}
+	}
+}
+
+// Test the default User-Agent is stamped with the build version
+func TestDefaultUserAgent(t *testing.T) {
+	tests := map[string]string{
+		"":       "potions/dev",
+		"1.4.0":  "potions/1.4.0",
+		"v1.4.0": "potions/1.4.0",
+	}
+	for version, want := range tests {
+		if got := DefaultUserAgent(version); got != want {
+			t.Errorf("DefaultUserAgent(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package gateways
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,20 +16,39 @@ import (
 	"github.com/ochairo/potions/internal/domain/entities"
 )
 
+// DefaultVersionTimeout is the per-request deadline for version lookups
+const DefaultVersionTimeout = 30 * time.Second
+
 // VersionFetcher handles fetching latest versions from various sources
 type VersionFetcher struct {
 	httpClient *http.Client
+	timeout    time.Duration // Per-request deadline; zero disables it
+	apiBaseURL string
+	urlCache   string // Directory caching url: source responses for conditional requests; empty disables it
 }
 
 // NewVersionFetcher creates a new version fetcher
 func NewVersionFetcher() *VersionFetcher {
 	return &VersionFetcher{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second, // Increased timeout for slow/flaky URLs
-		},
+		httpClient: NewHTTPClient(0),
+		timeout:    DefaultVersionTimeout,
+		apiBaseURL: defaultGitHubAPIURL,
 	}
 }
 
+// WithTimeout sets the per-request version lookup deadline (zero disables it)
+func (vf *VersionFetcher) WithTimeout(timeout time.Duration) *VersionFetcher {
+	vf.timeout = timeout
+	return vf
+}
+
+// WithURLCache caches url: source responses in dir and revalidates them with
+// If-None-Match/If-Modified-Since, reusing the cached body on 304 Not Modified
+func (vf *VersionFetcher) WithURLCache(dir string) *VersionFetcher {
+	vf.urlCache = dir
+	return vf
+}
+
 // FetchLatestVersion fetches the latest version based on the version.source field
 func (vf *VersionFetcher) FetchLatestVersion(def *entities.Recipe) (string, error) {
 	source := def.Version.Source
@@ -43,7 +64,7 @@ func (vf *VersionFetcher) FetchLatestVersion(def *entities.Recipe) (string, erro
 	//nolint:gocritic // ifElseChain: checking string prefixes with different logic, not suitable for switch
 	if strings.HasPrefix(source, "url:") {
 		url := strings.TrimPrefix(source, "url:")
-		rawVersion, err = vf.fetchFromURL(url)
+		rawVersion, err = vf.fetchURLSource(url)
 		if err == nil && def.Version.ExtractPattern != "" {
 			// For URL sources, extract and filter all matches to find latest valid version
 			rawVersion, err = vf.extractAndFilterVersion(rawVersion, def.Version.ExtractPattern, def.Version.ExcludePatterns)
@@ -52,12 +73,19 @@ func (vf *VersionFetcher) FetchLatestVersion(def *entities.Recipe) (string, erro
 			}
 			isGitHubTag = true // Mark that filtering was done during extraction
 		}
+	} else if strings.HasPrefix(source, "json:") {
+		rawVersion, err = vf.fetchFromJSON(strings.TrimPrefix(source, "json:"))
 	} else if strings.HasPrefix(source, "github-release:") {
 		repo := strings.TrimPrefix(source, "github-release:")
-		rawVersion, err = vf.fetchGitHubRelease(repo)
+		if def.Version.PreferStable {
+			rawVersion, err = vf.fetchGitHubStableRelease(repo)
+		} else {
+			rawVersion, err = vf.fetchGitHubRelease(repo)
+		}
 	} else if strings.HasPrefix(source, "github-tag:") {
-		repo := strings.TrimPrefix(source, "github-tag:")
-		rawVersion, err = vf.fetchGitHubTag(repo, def.Version.ExcludePatterns)
+		// An optional "#<prefix>" pins the lookup to one release line (e.g., "#1.20")
+		repo, constraint, _ := strings.Cut(strings.TrimPrefix(source, "github-tag:"), "#")
+		rawVersion, err = vf.fetchGitHubTag(repo, def.Version.ExcludePatterns, constraint)
 		isGitHubTag = true // Mark that filtering was already done
 	} else if strings.HasPrefix(source, "static:") {
 		// Static version - just return the value after the colon (e.g., "latest", "6.0")
@@ -97,81 +125,207 @@ func (vf *VersionFetcher) FetchLatestVersion(def *entities.Recipe) (string, erro
 	return strings.TrimSpace(rawVersion), nil
 }
 
-// doWithRetry executes an HTTP request with exponential backoff retry
-func (vf *VersionFetcher) doWithRetry(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			backoff := calculateBackoff(attempt - 1)
-			time.Sleep(backoff)
-		}
-
-		resp, err = vf.httpClient.Do(req)
-		if err != nil {
-			// Network errors are retryable
-			if attempt < maxRetries {
-				continue
-			}
-			return nil, err
+// PlannedRequests returns the URLs FetchLatestVersion would query for def without sending them
+// Static sources need no request and yield an empty list
+func (vf *VersionFetcher) PlannedRequests(def *entities.Recipe) ([]string, error) {
+	source := def.Version.Source
+	switch {
+	case source == "":
+		return nil, fmt.Errorf("version.source not specified")
+	case strings.HasPrefix(source, "url:"):
+		return []string{strings.TrimPrefix(source, "url:")}, nil
+	case strings.HasPrefix(source, "json:"):
+		spec := strings.TrimPrefix(source, "json:")
+		idx := strings.LastIndex(spec, "#")
+		if idx <= 0 || idx == len(spec)-1 {
+			return nil, fmt.Errorf("invalid json source %q: expected URL#path.to.field", spec)
 		}
-
-		// Check rate limit before processing response
-		if rateLimitErr := checkRateLimit(resp); rateLimitErr != nil {
-			//nolint:errcheck,gosec // G104: Best effort close on rate limit error
-			resp.Body.Close()
-			// If we waited for reset, retry immediately
-			if attempt < maxRetries {
-				continue
-			}
-			return nil, rateLimitErr
+		return []string{spec[:idx]}, nil
+	case strings.HasPrefix(source, "github-release:"):
+		repo := strings.TrimPrefix(source, "github-release:")
+		if def.Version.PreferStable {
+			return []string{fmt.Sprintf("%s/repos/%s/releases?per_page=100", vf.apiBaseURL, repo)}, nil
 		}
+		return []string{fmt.Sprintf("%s/repos/%s/releases/latest", vf.apiBaseURL, repo)}, nil
+	case strings.HasPrefix(source, "github-tag:"):
+		repo, _, _ := strings.Cut(strings.TrimPrefix(source, "github-tag:"), "#")
+		return []string{fmt.Sprintf("%s/repos/%s/tags", vf.apiBaseURL, repo)}, nil
+	case strings.HasPrefix(source, "static:"):
+		return []string{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported version.source format: %s", source)
+	}
+}
 
-		// Success or non-retryable error
-		if !isRetryableError(resp.StatusCode) {
-			return resp, nil
-		}
+// do sends req under the per-request deadline, which covers the client's retries and is
+// released when the body is closed
+func (vf *VersionFetcher) do(req *http.Request) (*http.Response, error) {
+	ctx, cancel := requestContext(req.Context(), vf.timeout)
+	req.Header.Set("User-Agent", UserAgent())
+	resp, err := vf.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
 
-		// Retryable error - close body and retry
-		//nolint:errcheck,gosec // G104: Best effort close before retry
-		resp.Body.Close()
+// cancelOnClose releases a request context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
 
-		if attempt < maxRetries {
-			continue
-		}
+// Close closes the body and cancels its request context
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
 
-		// Max retries reached
-		return resp, nil
+// fetchURLSource fetches a url: version source, revalidating the cached response when a URL cache is set
+// A cache that cannot be written only costs a full fetch on the next run, so store errors are ignored
+func (vf *VersionFetcher) fetchURLSource(url string) (string, error) {
+	if vf.urlCache == "" {
+		return vf.fetchFromURL(url)
 	}
 
-	return resp, err
+	cached := loadURLCacheEntry(vf.urlCache, url)
+	entry, err := vf.fetchURL(url, cached)
+	if err != nil {
+		return "", err
+	}
+	if entry != cached && entry.cacheable() {
+		_ = storeURLCacheEntry(vf.urlCache, entry)
+	}
+	return entry.Body, nil
 }
 
 // fetchFromURL fetches version from a plain URL
 func (vf *VersionFetcher) fetchFromURL(url string) (string, error) {
+	entry, err := vf.fetchURL(url, nil)
+	if err != nil {
+		return "", err
+	}
+	return entry.Body, nil
+}
+
+// fetchURL GETs url, sending cached's validators; a 304 Not Modified returns cached itself
+// Setting Accept-Encoding disables the transport's transparent gunzip, so gzip bodies are decoded here
+func (vf *VersionFetcher) fetchURL(url string, cached *urlCacheEntry) (*urlCacheEntry, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
 	}
 
-	resp, err := vf.doWithRetry(req)
+	resp, err := vf.do(req)
 	if err != nil {
-		return "", fmt.Errorf("HTTP request failed: %w", err)
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	//nolint:errcheck // Defer close
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	reader := io.Reader(resp.Body)
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip response: %w", err)
+		}
+		//nolint:errcheck // Defer close on gzip reader
+		defer gz.Close()
+		reader = gz
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(reader)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return string(body), nil
+	return &urlCacheEntry{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Body:         string(body),
+	}, nil
+}
+
+// fetchFromJSON fetches a JSON document and selects the version by a dotted path
+// Format: URL#path.to.field (array indices as "items.0" or "items[0]")
+func (vf *VersionFetcher) fetchFromJSON(spec string) (string, error) {
+	idx := strings.LastIndex(spec, "#")
+	if idx <= 0 || idx == len(spec)-1 {
+		return "", fmt.Errorf("invalid json source %q: expected URL#path.to.field", spec)
+	}
+	url, path := spec[:idx], spec[idx+1:]
+
+	body, err := vf.fetchFromURL(url)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return selectJSONPath(doc, path)
+}
+
+// selectJSONPath walks a decoded JSON document along a dotted path and returns the scalar at its end
+func selectJSONPath(doc interface{}, path string) (string, error) {
+	path = strings.ReplaceAll(strings.ReplaceAll(path, "[", "."), "]", "")
+
+	current := doc
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			continue
+		}
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return "", fmt.Errorf("JSON path %q: field %q not found", path, segment)
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", fmt.Errorf("JSON path %q: invalid array index %q (length %d)", path, segment, len(node))
+			}
+			current = node[index]
+		default:
+			return "", fmt.Errorf("JSON path %q: cannot descend into %q of a scalar value", path, segment)
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	default:
+		return "", fmt.Errorf("JSON path %q does not select a string or number", path)
+	}
 }
 
 // GitHubRelease represents a GitHub release
@@ -184,26 +338,12 @@ type GitHubRelease struct {
 
 // fetchGitHubRelease fetches the latest release from GitHub
 func (vf *VersionFetcher) fetchGitHubRelease(repo string) (string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo)
-
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := vf.newGitHubRequest(fmt.Sprintf("%s/repos/%s/releases/latest", vf.apiBaseURL, repo))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add Accept header for GitHub API
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	// Add GitHub token if available (required for higher rate limits)
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		token = os.Getenv("GH_TOKEN")
-	}
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+		return "", err
 	}
 
-	resp, err := vf.doWithRetry(req)
+	resp, err := vf.do(req)
 	if err != nil {
 		return "", fmt.Errorf("GitHub API request failed: %w", err)
 	}
@@ -230,19 +370,59 @@ func (vf *VersionFetcher) fetchGitHubRelease(repo string) (string, error) {
 	return release.TagName, nil
 }
 
-// GitHubTag represents a GitHub tag
-type GitHubTag struct {
-	Name string `json:"name"`
-	Ref  string `json:"ref"`
-}
+// stableVersionPattern matches plain release versions (no pre-release or build suffix)
+var stableVersionPattern = regexp.MustCompile(`^v?[0-9]+\.[0-9]+(\.[0-9]+)?$`)
 
-// fetchGitHubTag fetches the latest tag from GitHub, optionally filtering unwanted tags
-func (vf *VersionFetcher) fetchGitHubTag(repo string, filterRegex string) (string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/tags", repo)
+// fetchGitHubStableRelease picks the highest stable semver release from the releases list
+// Avoids trusting /releases/latest, which some projects point at rolling tags like "nightly"
+func (vf *VersionFetcher) fetchGitHubStableRelease(repo string) (string, error) {
+	req, err := vf.newGitHubRequest(fmt.Sprintf("%s/repos/%s/releases?per_page=100", vf.apiBaseURL, repo))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := vf.do(req)
+	if err != nil {
+		return "", fmt.Errorf("GitHub API request failed: %w", err)
+	}
+	//nolint:errcheck // Defer close
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("GitHub API error %d (failed to read response)", resp.StatusCode)
+		}
+		return "", fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var releases []GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return "", fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+
+	best := ""
+	for _, release := range releases {
+		if release.Draft || release.Prerelease || !stableVersionPattern.MatchString(release.TagName) {
+			continue
+		}
+		if best == "" || entities.CompareVersions(release.TagName, best) > 0 {
+			best = release.TagName
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("no stable semver release found for %s", repo)
+	}
+
+	return best, nil
+}
 
+// newGitHubRequest creates a GitHub API GET request with token auth when available
+func (vf *VersionFetcher) newGitHubRequest(url string) (*http.Request, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/vnd.github+json")
@@ -256,7 +436,24 @@ func (vf *VersionFetcher) fetchGitHubTag(repo string, filterRegex string) (strin
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	resp, err := vf.doWithRetry(req)
+	return req, nil
+}
+
+// GitHubTag represents a GitHub tag
+type GitHubTag struct {
+	Name string `json:"name"`
+	Ref  string `json:"ref"`
+}
+
+// fetchGitHubTag fetches the latest tag from GitHub, optionally filtering unwanted tags
+// and keeping only tags within the constraint's version line
+func (vf *VersionFetcher) fetchGitHubTag(repo, filterRegex, constraint string) (string, error) {
+	req, err := vf.newGitHubRequest(fmt.Sprintf("%s/repos/%s/tags", vf.apiBaseURL, repo))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := vf.do(req)
 	if err != nil {
 		return "", fmt.Errorf("GitHub API request failed: %w", err)
 	}
@@ -280,6 +477,19 @@ func (vf *VersionFetcher) fetchGitHubTag(repo string, filterRegex string) (strin
 		return "", fmt.Errorf("no tags found")
 	}
 
+	if constraint != "" {
+		matching := tags[:0]
+		for _, tag := range tags {
+			if matchesVersionLine(tag.Name, constraint) {
+				matching = append(matching, tag)
+			}
+		}
+		if len(matching) == 0 {
+			return "", fmt.Errorf("no tags match version constraint %s", constraint)
+		}
+		tags = matching
+	}
+
 	// If filter regex is provided, find first tag that doesn't match filter
 	if filterRegex != "" {
 		for _, tag := range tags {
@@ -294,6 +504,14 @@ func (vf *VersionFetcher) fetchGitHubTag(repo string, filterRegex string) (strin
 	return tags[0].Name, nil
 }
 
+// matchesVersionLine reports whether tag is the constraint version or a release within it
+// (e.g., "v1.20.3" matches "1.20" but "1.200.0" does not)
+func matchesVersionLine(tag, constraint string) bool {
+	version := strings.TrimPrefix(tag, "v")
+	constraint = strings.TrimPrefix(constraint, "v")
+	return version == constraint || strings.HasPrefix(version, constraint+".")
+}
+
 // extractAndFilterVersion extracts ALL version matches and returns the latest valid one
 func (vf *VersionFetcher) extractAndFilterVersion(input, pattern, excludePatterns string) (string, error) {
 	re, err := regexp.Compile(pattern)
@@ -338,7 +556,7 @@ func (vf *VersionFetcher) extractAndFilterVersion(input, pattern, excludePattern
 	// Find the highest version using semantic version comparison
 	latestVersion := validVersions[0].version
 	for i := 1; i < len(validVersions); i++ {
-		if vf.compareVersions(validVersions[i].version, latestVersion) > 0 {
+		if entities.CompareVersions(validVersions[i].version, latestVersion) > 0 {
 			latestVersion = validVersions[i].version
 		}
 	}
@@ -346,65 +564,6 @@ func (vf *VersionFetcher) extractAndFilterVersion(input, pattern, excludePattern
 	return latestVersion, nil
 }
 
-// compareVersions compares two version strings semantically
-// Returns: 1 if v1 > v2, -1 if v1 < v2, 0 if equal
-func (vf *VersionFetcher) compareVersions(v1, v2 string) int {
-	// Split versions by dots
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-
-	// Compare each part numerically
-	maxLen := len(parts1)
-	if len(parts2) > maxLen {
-		maxLen = len(parts2)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		var num1, num2 int
-
-		if i < len(parts1) {
-			// Extract numeric part (handle cases like "1rc1" -> 1)
-			numStr := ""
-			for _, ch := range parts1[i] {
-				if ch >= '0' && ch <= '9' {
-					numStr += string(ch)
-				} else {
-					break
-				}
-			}
-			if numStr != "" {
-				if n, err := strconv.Atoi(numStr); err == nil {
-					num1 = n
-				}
-			}
-		}
-
-		if i < len(parts2) {
-			numStr := ""
-			for _, ch := range parts2[i] {
-				if ch >= '0' && ch <= '9' {
-					numStr += string(ch)
-				} else {
-					break
-				}
-			}
-			if numStr != "" {
-				if n, err := strconv.Atoi(numStr); err == nil {
-					num2 = n
-				}
-			}
-		}
-
-		if num1 > num2 {
-			return 1
-		} else if num1 < num2 {
-			return -1
-		}
-	}
-
-	return 0
-}
-
 // extractVersion extracts version using regex
 func (vf *VersionFetcher) extractVersion(input, pattern string) (string, error) {
 	re, err := regexp.Compile(pattern)
@@ -1,6 +1,9 @@
 package gateways
 
 import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -319,3 +322,231 @@ func TestVersionFetcher_FetchLatestVersion_FilterPrerelease(t *testing.T) {
 		})
 	}
 }
+
+// TestVersionFetcher_PreferStable tests that prefer_stable skips a rolling /latest release
+func TestVersionFetcher_PreferStable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/tool/releases/latest":
+			_, _ = w.Write([]byte(`{"tag_name":"nightly","draft":false,"prerelease":false}`))
+		case "/repos/owner/tool/releases":
+			_, _ = w.Write([]byte(`[
+				{"tag_name":"nightly","draft":false,"prerelease":false},
+				{"tag_name":"v2.0.0-rc1","draft":false,"prerelease":true},
+				{"tag_name":"v1.10.0","draft":false,"prerelease":false},
+				{"tag_name":"v3.0.0","draft":true,"prerelease":false},
+				{"tag_name":"v1.9.2","draft":false,"prerelease":false},
+				{"tag_name":"canary","draft":false,"prerelease":false}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	vf := NewVersionFetcher()
+	vf.apiBaseURL = server.URL
+
+	def := &entities.Recipe{
+		Name:    "tool",
+		Version: entities.VersionConfig{Source: "github-release:owner/tool"},
+	}
+
+	latest, err := vf.FetchLatestVersion(def)
+	if err != nil {
+		t.Fatalf("FetchLatestVersion() error = %v", err)
+	}
+	if latest != "nightly" {
+		t.Errorf("FetchLatestVersion() without prefer_stable = %q, want nightly", latest)
+	}
+
+	def.Version.PreferStable = true
+	stable, err := vf.FetchLatestVersion(def)
+	if err != nil {
+		t.Fatalf("FetchLatestVersion() error = %v", err)
+	}
+	if stable != "v1.10.0" {
+		t.Errorf("FetchLatestVersion() with prefer_stable = %q, want v1.10.0", stable)
+	}
+}
+
+// TestVersionFetcher_GitHubTagConstraint tests that a "#<prefix>" constraint keeps one release line
+func TestVersionFetcher_GitHubTagConstraint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"name":"v22.3.0"},{"name":"v200.0.0"},{"name":"v20.15.0"},{"name":"v20.14.0"}]`))
+	}))
+	defer server.Close()
+
+	vf := NewVersionFetcher()
+	vf.apiBaseURL = server.URL
+
+	def := &entities.Recipe{Version: entities.VersionConfig{Source: "github-tag:nodejs/node#20"}}
+	version, err := vf.FetchLatestVersion(def)
+	if err != nil {
+		t.Fatalf("FetchLatestVersion() error = %v", err)
+	}
+	if version != "v20.15.0" {
+		t.Errorf("FetchLatestVersion() = %q, want v20.15.0", version)
+	}
+
+	def.Version.Source = "github-tag:nodejs/node#18"
+	if _, err := vf.FetchLatestVersion(def); err == nil {
+		t.Error("FetchLatestVersion() should fail when no tag matches the constraint")
+	}
+}
+
+// TestVersionFetcher_PreferStable_NoStable tests the error when no stable release exists
+func TestVersionFetcher_PreferStable_NoStable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"tag_name":"nightly","draft":false,"prerelease":false}]`))
+	}))
+	defer server.Close()
+
+	vf := NewVersionFetcher()
+	vf.apiBaseURL = server.URL
+
+	def := &entities.Recipe{
+		Name:    "tool",
+		Version: entities.VersionConfig{Source: "github-release:owner/tool", PreferStable: true},
+	}
+
+	if _, err := vf.FetchLatestVersion(def); err == nil || !strings.Contains(err.Error(), "no stable semver release") {
+		t.Errorf("FetchLatestVersion() error = %v, want no stable semver release error", err)
+	}
+}
+
+// TestVersionFetcher_JSONSource tests selecting a nested version from a JSON manifest
+func TestVersionFetcher_JSONSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"channels": {
+				"stable": {"version": "v1.2.3"},
+				"beta": {"version": "v1.3.0-beta.1"}
+			},
+			"releases": [{"version": "1.2.3", "build": 42}, {"version": "1.2.2", "build": 41}]
+		}`))
+	}))
+	defer server.Close()
+
+	vf := NewVersionFetcher()
+
+	tests := []struct {
+		name    string
+		path    string
+		cleanup string
+		want    string
+	}{
+		{"nested object", "channels.stable.version", "", "v1.2.3"},
+		{"nested object with cleanup", "channels.stable.version", "s/^v//", "1.2.3"},
+		{"array index dotted", "releases.1.version", "", "1.2.2"},
+		{"array index brackets", "releases[0].build", "", "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := &entities.Recipe{
+				Name: "tool",
+				Version: entities.VersionConfig{
+					Source:  "json:" + server.URL + "/manifest.json#" + tt.path,
+					Cleanup: tt.cleanup,
+				},
+			}
+
+			got, err := vf.FetchLatestVersion(def)
+			if err != nil {
+				t.Fatalf("FetchLatestVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FetchLatestVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSelectJSONPath_Errors tests invalid JSON path selections
+func TestSelectJSONPath_Errors(t *testing.T) {
+	doc := map[string]interface{}{
+		"channels": map[string]interface{}{"stable": map[string]interface{}{"version": "1.0"}},
+		"list":     []interface{}{"a"},
+	}
+
+	for _, path := range []string{"channels.missing", "list.5", "list.x", "channels.stable.version.deeper", "channels"} {
+		if _, err := selectJSONPath(doc, path); err == nil {
+			t.Errorf("selectJSONPath(%q) should fail", path)
+		}
+	}
+}
+
+// Test gzip-encoded URL responses are decompressed before version extraction
+func TestVersionFetcher_URLSource_Gzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte("<a href=\"tool-2.4.1.tar.gz\">tool-2.4.1.tar.gz</a>\n<a href=\"tool-2.3.9.tar.gz\">tool-2.3.9.tar.gz</a>\n"))
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name: "tool",
+		Version: entities.VersionConfig{
+			Source:         "url:" + server.URL + "/downloads/",
+			ExtractPattern: `tool-([0-9.]+)\.tar\.gz`,
+		},
+	}
+
+	got, err := NewVersionFetcher().FetchLatestVersion(def)
+	if err != nil {
+		t.Fatalf("FetchLatestVersion() error = %v", err)
+	}
+	if got != "2.4.1" {
+		t.Errorf("FetchLatestVersion() = %q, want 2.4.1", got)
+	}
+}
+
+// Test a url: source revalidates its cached response and reuses the cached version on 304
+func TestVersionFetcher_URLSource_ConditionalCache(t *testing.T) {
+	const lastModified = "Wed, 01 Oct 2025 12:00:00 GMT"
+	var fullResponses, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write([]byte("v1.31.2\n"))
+	}))
+	defer server.Close()
+
+	def := &entities.Recipe{
+		Name:    "kubectl",
+		Version: entities.VersionConfig{Source: "url:" + server.URL + "/stable.txt", Cleanup: "s/^v//"},
+	}
+	vf := NewVersionFetcher().WithURLCache(t.TempDir())
+
+	for i := 0; i < 2; i++ {
+		got, err := vf.FetchLatestVersion(def)
+		if err != nil {
+			t.Fatalf("FetchLatestVersion() call %d error = %v", i+1, err)
+		}
+		if got != "1.31.2" {
+			t.Errorf("FetchLatestVersion() call %d = %q, want 1.31.2", i+1, got)
+		}
+	}
+	if fullResponses != 1 || notModified != 1 {
+		t.Errorf("server sent %d full and %d 304 responses, want 1 and 1", fullResponses, notModified)
+	}
+
+	// json: sources are always fetched in full, even for a URL the cache holds
+	if _, err := vf.fetchFromJSON(server.URL + "/stable.txt#version"); err == nil {
+		t.Error("fetchFromJSON() should fail on a plain-text body")
+	}
+	if fullResponses != 2 {
+		t.Errorf("full responses = %d after the json: lookup, want 2", fullResponses)
+	}
+}
@@ -3,7 +3,15 @@ package orchestrators
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,9 +25,48 @@ type VersionFetcher interface {
 	FetchLatestVersion(def *entities.Recipe) (string, error)
 }
 
+// IsVersionLookup reports whether a requested version must be resolved upstream:
+// empty, "latest", or a named "@channel"
+func IsVersionLookup(version string) bool {
+	return version == "" || version == "latest" || strings.HasPrefix(version, "@")
+}
+
+// ResolveLatestVersion fetches the newest version for a lookup request, using the
+// recipe's version.channels source for "@channel" and the primary source otherwise
+func ResolveLatestVersion(fetcher VersionFetcher, def *entities.Recipe, request string) (string, error) {
+	channel := strings.TrimPrefix(request, "@")
+	if request == channel || channel == "latest" {
+		if source, ok := def.Version.Channels["latest"]; ok {
+			return fetcher.FetchLatestVersion(recipeWithVersionSource(def, source))
+		}
+		return fetcher.FetchLatestVersion(def)
+	}
+
+	source, ok := def.Version.Channels[channel]
+	if !ok {
+		names := make([]string, 0, len(def.Version.Channels))
+		for name := range def.Version.Channels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "", fmt.Errorf("unknown version channel %q: %s defines no version.channels", channel, def.Name)
+		}
+		return "", fmt.Errorf("unknown version channel %q (available: %s)", channel, strings.Join(names, ", "))
+	}
+	return fetcher.FetchLatestVersion(recipeWithVersionSource(def, source))
+}
+
+// recipeWithVersionSource returns a shallow copy of def whose version.source is source
+func recipeWithVersionSource(def *entities.Recipe, source string) *entities.Recipe {
+	channelDef := *def
+	channelDef.Version.Source = source
+	return &channelDef
+}
+
 // Downloader interface for downloading artifacts
 type Downloader interface {
-	DownloadArtifact(def *entities.Recipe, version, platform, outputDir string) (*entities.Artifact, error)
+	DownloadArtifact(ctx context.Context, def *entities.Recipe, version, platform, outputDir string) (*entities.Artifact, error)
 }
 
 // ScriptExecutor interface for executing build scripts
@@ -35,10 +82,49 @@ type Packager interface {
 // SecurityGateway interface for security operations
 type SecurityGateway interface {
 	VerifyGPGSignature(ctx context.Context, filePath, sigURL string) error
-	ImportGPGKeys(ctx context.Context, keyIDs []string) error
+	VerifyGPGSignedChecksums(ctx context.Context, filePath, checksumsURL, sigURL string) error
+	ImportGPGKeys(ctx context.Context, keyIDs []string, lookup entities.GPGKeyLookup) error
 	ImportGPGKeysFromURL(ctx context.Context, keysURL string) error
 }
 
+// BuildCache stores finished package tarballs keyed by build inputs
+type BuildCache interface {
+	// Restore copies the cached tarball for key into outputDir, reporting whether it was found
+	Restore(key, outputDir string) (string, bool, error)
+	// Store saves the tarball at path under key
+	Store(key, path string) error
+}
+
+// BuildVerifier runs post-build sanity checks against a packaged artifact
+type BuildVerifier interface {
+	VerifyBuild(ctx context.Context, def *entities.Recipe, artifact *entities.Artifact) error
+}
+
+// URLProber checks whether a remote URL exists
+type URLProber interface {
+	URLExists(ctx context.Context, url string) (bool, error)
+}
+
+// Build stages reported to a BuildProgress as they complete
+const (
+	StageDownload = "download"
+	StageScan     = "scan"
+	StageBuild    = "build"
+)
+
+// BuildProgress is notified as each stage of a package build completes
+type BuildProgress interface {
+	StageDone(packageName, version, platform, stage string)
+}
+
+// ToolchainChecker verifies that required build tools are installed at sufficient versions
+type ToolchainChecker interface {
+	CheckRequirements(ctx context.Context, reqs []entities.ToolRequirement) error
+}
+
+// defaultSignatureExtensions are tried in order when a recipe sets neither signature_url nor signature_extensions
+var defaultSignatureExtensions = []string{".asc", ".sig"}
+
 // BuildOrchestrator coordinates the complete package build workflow
 type BuildOrchestrator struct {
 	defRepo        repositories.RecipeRepository
@@ -48,8 +134,17 @@ type BuildOrchestrator struct {
 	downloader     Downloader
 	scriptExecutor ScriptExecutor
 	packager       Packager
+	buildCache     BuildCache
+	buildVerifier  BuildVerifier
+	urlProber      URLProber
+	toolchain      ToolchainChecker
+	progress       BuildProgress
+	hostPlatform   string
 	enableSecurity bool
 	outputDir      string
+	ifExists       entities.IfExistsPolicy
+	layout         entities.OutputLayout
+	phaseTimeouts  PhaseTimeouts
 	logger         interfaces.Logger
 }
 
@@ -87,12 +182,75 @@ func NewBuildOrchestrator(
 		downloader:     downloader,
 		scriptExecutor: scriptExecutor,
 		packager:       packager,
+		hostPlatform:   runtime.GOOS + "-" + runtime.GOARCH,
 		enableSecurity: config.EnableSecurityScan,
 		outputDir:      outputDir,
 		logger:         logger,
 	}
 }
 
+// WithHostPlatform sets the host platform checked against recipe build_host constraints
+func (o *BuildOrchestrator) WithHostPlatform(platform string) *BuildOrchestrator {
+	o.hostPlatform = platform
+	return o
+}
+
+// WithProgress reports each completed build stage to progress
+func (o *BuildOrchestrator) WithProgress(progress BuildProgress) *BuildOrchestrator {
+	o.progress = progress
+	return o
+}
+
+// WithBuildCache enables serving repeated builds of identical inputs from cache
+func (o *BuildOrchestrator) WithBuildCache(cache BuildCache) *BuildOrchestrator {
+	o.buildCache = cache
+	return o
+}
+
+// WithBuildVerifier enables running recipe verify commands after packaging
+func (o *BuildOrchestrator) WithBuildVerifier(verifier BuildVerifier) *BuildOrchestrator {
+	o.buildVerifier = verifier
+	return o
+}
+
+// WithURLProber enables probing candidate signature URLs before GPG verification
+func (o *BuildOrchestrator) WithURLProber(prober URLProber) *BuildOrchestrator {
+	o.urlProber = prober
+	return o
+}
+
+// WithIfExists sets what happens when the tarball's final path under layout already exists
+func (o *BuildOrchestrator) WithIfExists(policy entities.IfExistsPolicy, layout entities.OutputLayout) *BuildOrchestrator {
+	o.ifExists = policy
+	o.layout = layout
+	return o
+}
+
+// WithPhaseTimeouts bounds the download, scan and build phases individually within the caller's deadline
+func (o *BuildOrchestrator) WithPhaseTimeouts(timeouts PhaseTimeouts) *BuildOrchestrator {
+	o.phaseTimeouts = timeouts
+	return o
+}
+
+// WithToolchainChecker enables build.requires checks before build scripts run
+func (o *BuildOrchestrator) WithToolchainChecker(checker ToolchainChecker) *BuildOrchestrator {
+	o.toolchain = checker
+	return o
+}
+
+// BuildCacheKey derives a content-addressable key from the recipe, resolved version and platform
+func BuildCacheKey(def *entities.Recipe, version, platform string) (string, error) {
+	recipeJSON, err := json.Marshal(def)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash recipe: %w", err)
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00", def.Name, version, platform)
+	_, _ = h.Write(recipeJSON)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // BuildResult contains the result of a build operation
 type BuildResult struct {
 	Recipe           *entities.Recipe
@@ -101,10 +259,24 @@ type BuildResult struct {
 	DownloadDuration time.Duration
 	BuildDuration    time.Duration
 	TotalDuration    time.Duration
+	SourcePath       string // Extracted source directory (empty on cache hits)
+	DownloadPath     string // Downloaded archive (empty for git clones and cache hits)
+	CacheHit         bool
+	KeptExisting     bool   // The final tarball already existed and --if-exists skip kept it
+	Skipped          bool   // Platform requires a different build host; not a failure
+	SkipReason       string // Why the platform was skipped (e.g., "requires darwin host")
 	Success          bool
 	Error            error
+	TimedOutPhase    string // Stage whose own deadline expired (StageDownload, StageScan or StageBuild)
+	Verification     string // verify_command outcome: VerificationPassed, VerificationSkippedCrossPlatform or empty when not run
 }
 
+// verify_command outcomes reported in BuildResult.Verification
+const (
+	VerificationPassed               = "passed"
+	VerificationSkippedCrossPlatform = "skipped (cross-platform)"
+)
+
 // BuildPackage executes the complete build workflow for a package
 // If version is empty, it will fetch the latest version automatically
 func (o *BuildOrchestrator) BuildPackage(ctx context.Context, packageName, version, platform string) (*BuildResult, error) {
@@ -119,9 +291,9 @@ func (o *BuildOrchestrator) BuildPackage(ctx context.Context, packageName, versi
 	}
 	result.Recipe = def
 
-	// Step 2: Fetch version if not provided or if "latest" is specified
-	if version == "" || version == "latest" {
-		fetchedVersion, err := o.versionFetcher.FetchLatestVersion(def)
+	// Step 2: Fetch version if not provided, "latest" or an "@channel" is specified
+	if IsVersionLookup(version) {
+		fetchedVersion, err := ResolveLatestVersion(o.versionFetcher, def, version)
 		if err != nil {
 			result.Error = fmt.Errorf("failed to fetch latest version: %w", err)
 			return result, result.Error
@@ -130,21 +302,82 @@ func (o *BuildOrchestrator) BuildPackage(ctx context.Context, packageName, versi
 	}
 
 	// Step 3: Validate platform support
-	_, hasPlatform := def.Download.Platforms[platform]
+	platformConfig, hasPlatform := def.Download.Platform(platform)
 	if !hasPlatform {
 		result.Error = fmt.Errorf("package %s does not support platform %s", packageName, platform)
 		return result, result.Error
 	}
 
+	// Step 3.1: Skip platforms that cannot be built on this host
+	if buildHost := platformConfig.BuildHost; buildHost != "" && !hostSatisfies(buildHost, o.hostPlatform) {
+		result.Skipped = true
+		result.SkipReason = fmt.Sprintf("requires %s host", buildHost)
+		result.TotalDuration = time.Since(startTime)
+		return result, nil
+	}
+
+	// Step 3.2: Apply the if-exists policy before anything can overwrite a prior tarball
+	if o.ifExists == entities.IfExistsSkip || o.ifExists == entities.IfExistsError {
+		target := o.layout.ArtifactPath(o.outputDir, def.Name, version, platform)
+		if _, err := os.Stat(target); err == nil {
+			if o.ifExists == entities.IfExistsError {
+				result.Error = fmt.Errorf("artifact %s already exists (--if-exists error)", target)
+				return result, result.Error
+			}
+			result.Artifact = &entities.Artifact{
+				Name:     def.Name,
+				Version:  version,
+				Platform: platform,
+				Path:     target,
+				Type:     "archive",
+			}
+			result.KeptExisting = true
+			result.Success = true
+			result.TotalDuration = time.Since(startTime)
+			return result, nil
+		}
+	}
+
+	// Step 3.5: Serve from build cache if these exact inputs were built before; the cache skips only
+	// the download and build, so the vulnerability scan still runs against today's advisories and policy
+	var cacheKey string
+	if o.buildCache != nil {
+		cacheKey = o.lookupBuildCache(def, version, platform, result)
+		if result.CacheHit {
+			if err := o.scanArtifact(ctx, def, result.Artifact, packageName, version, platform, result); err != nil {
+				//nolint:errcheck // Best effort removal of the restored tarball of a blocked build
+				os.Remove(result.Artifact.Path)
+				return result, err
+			}
+			result.Success = true
+			result.TotalDuration = time.Since(startTime)
+			return result, nil
+		}
+	}
+
+	// Step 3.6: Fail fast if the recipe's required toolchain is missing or too old
+	if o.toolchain != nil && len(def.Build.Requires) > 0 {
+		if err := o.toolchain.CheckRequirements(ctx, def.Build.Requires); err != nil {
+			result.Error = fmt.Errorf("toolchain requirement not met: %w", err)
+			return result, result.Error
+		}
+	}
+
 	// Step 4: Download artifact
 	downloadStart := time.Now()
-	artifact, err := o.downloader.DownloadArtifact(def, version, platform, o.outputDir)
+	artifact, err := runPhase(ctx, StageDownload, o.phaseTimeouts.Download, func(phaseCtx context.Context) (*entities.Artifact, error) {
+		return o.downloader.DownloadArtifact(phaseCtx, def, version, platform, o.outputDir)
+	})
 	if err != nil {
+		result.TimedOutPhase = timedOutPhase(err)
 		result.Error = fmt.Errorf("failed to download artifact: %w", err)
 		return result, result.Error
 	}
 	result.Artifact = artifact
+	result.SourcePath = artifact.Path
+	result.DownloadPath = artifact.DownloadPath
 	result.DownloadDuration = time.Since(downloadStart)
+	o.stageDone(packageName, version, platform, StageDownload)
 
 	// Step 4.5: Verify GPG signature if required (only for HTTP downloads)
 	hasGPGKeys := len(def.Security.GPGKeyIDs) > 0 || def.Security.GPGKeysURL != ""
@@ -159,25 +392,18 @@ func (o *BuildOrchestrator) BuildPackage(ctx context.Context, packageName, versi
 		}
 	}
 
-	// Step 5: Security workflow (if enabled and requested)
-	if o.enableSecurity && def.Security.ScanVulnerabilities {
-		secResult, err := o.securityOrch.PerformSecurityWorkflow(ctx, artifact)
-		if err != nil {
-			result.Error = fmt.Errorf("security workflow failed: %w", err)
-			return result, result.Error
-		}
-		result.SecurityResult = secResult
-
-		// Check if build should be blocked
-		if secResult.Blocked {
-			result.Error = fmt.Errorf("build blocked due to security issues: %s", secResult.BlockReason)
-			return result, result.Error
-		}
+	// Step 5: Security workflow (if enabled and requested for this platform)
+	if err := o.scanArtifact(ctx, def, artifact, packageName, version, platform, result); err != nil {
+		return result, err
 	}
 
 	// Step 6: Build/Install using script executor
 	buildStart := time.Now()
-	if err := o.scriptExecutor.ExecuteBuildScripts(ctx, def, artifact, o.outputDir); err != nil {
+	_, err = runPhase(ctx, StageBuild, o.phaseTimeouts.Build, func(buildCtx context.Context) (struct{}, error) {
+		return struct{}{}, o.scriptExecutor.ExecuteBuildScripts(buildCtx, def, artifact, o.outputDir)
+	})
+	if err != nil {
+		result.TimedOutPhase = timedOutPhase(err)
 		result.Error = fmt.Errorf("build/install failed: %w", err)
 		return result, result.Error
 	}
@@ -190,19 +416,226 @@ func (o *BuildOrchestrator) BuildPackage(ctx context.Context, packageName, versi
 		return result, result.Error
 	}
 	// Update artifact to point to the packaged tar.gz instead of extracted directory
+	if packagedArtifact != nil && packagedArtifact.SourceURL == "" {
+		packagedArtifact.SourceURL = artifact.SourceURL
+	}
 	result.Artifact = packagedArtifact
+	o.stageDone(packageName, version, platform, StageBuild)
+
+	// A packaged size outside the recipe's bounds points at a broken build script
+	if err := checkArtifactSize(def.Build, packagedArtifact); err != nil {
+		result.Error = err
+		return result, result.Error
+	}
+
+	// Step 8: Verify the packaged binary runs (if the recipe defines a verify command)
+	// A binary for another platform cannot run here, or would run through an emulator, so it is not executed
+	if def.Build.VerifyCommand != "" {
+		switch {
+		case o.buildVerifier == nil:
+			o.logger.Warn("recipe defines verify_command but no build verifier is configured")
+		case !hostSatisfies(platform, o.hostPlatform):
+			result.Verification = VerificationSkippedCrossPlatform
+			o.logger.Info("verify_command skipped (cross-platform)",
+				interfaces.F("platform", platform), interfaces.F("host", o.hostPlatform))
+		default:
+			if err := o.buildVerifier.VerifyBuild(ctx, def, packagedArtifact); err != nil {
+				result.Error = fmt.Errorf("build verification failed: %w", err)
+				return result, result.Error
+			}
+			result.Verification = VerificationPassed
+		}
+	}
+
+	if cacheKey != "" {
+		if err := o.buildCache.Store(cacheKey, packagedArtifact.Path); err != nil {
+			o.logger.Warn("failed to store build in cache", interfaces.F("error", err))
+		}
+	}
 
 	result.Success = true
 	result.TotalDuration = time.Since(startTime)
 	return result, nil
 }
 
+// PhaseTimeouts holds per-phase build deadlines; zero leaves a phase bounded only by the caller's context
+type PhaseTimeouts struct {
+	Download time.Duration
+	Scan     time.Duration
+	Build    time.Duration
+}
+
+// PhaseTimeoutError reports that one build phase exceeded its own deadline
+type PhaseTimeoutError struct {
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("%s phase exceeded %s timeout", e.Phase, e.Timeout)
+}
+
+// Unwrap lets errors.Is match the error against context.DeadlineExceeded
+func (e *PhaseTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// runPhase runs fn under a deadline of timeout within ctx, returning a *PhaseTimeoutError when the
+// phase deadline (rather than ctx's) expires. fn is waited for even after the deadline, so nothing
+// a phase started can outlive it; phases must return promptly once their context is done
+func runPhase[T any](ctx context.Context, phase string, timeout time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	value, err := fn(phaseCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		var zero T
+		return zero, &PhaseTimeoutError{Phase: phase, Timeout: timeout}
+	}
+	return value, err
+}
+
+// timedOutPhase returns the phase named by a *PhaseTimeoutError in err's chain, or ""
+func timedOutPhase(err error) string {
+	var phaseErr *PhaseTimeoutError
+	if errors.As(err, &phaseErr) {
+		return phaseErr.Phase
+	}
+	return ""
+}
+
+// stageDone notifies the progress listener, if any, that a build stage finished
+func (o *BuildOrchestrator) stageDone(packageName, version, platform, stage string) {
+	if o.progress != nil {
+		o.progress.StageDone(packageName, version, platform, stage)
+	}
+}
+
+// checkArtifactSize fails a packaged artifact outside the recipe's build.min_size/max_size bounds
+func checkArtifactSize(step entities.RecipeBuildStep, artifact *entities.Artifact) error {
+	if (step.MinSize <= 0 && step.MaxSize <= 0) || artifact == nil || artifact.Path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(artifact.Path)
+	if err != nil {
+		return fmt.Errorf("failed to check packaged artifact size: %w", err)
+	}
+
+	size := info.Size()
+	name := filepath.Base(artifact.Path)
+	if step.MinSize > 0 && size < step.MinSize {
+		return fmt.Errorf("packaged artifact %s is %d bytes, below build.min_size of %d bytes", name, size, step.MinSize)
+	}
+	if step.MaxSize > 0 && size > step.MaxSize {
+		return fmt.Errorf("packaged artifact %s is %d bytes, above build.max_size of %d bytes", name, size, step.MaxSize)
+	}
+	return nil
+}
+
+// scanArtifact runs the security workflow when enabled and requested for the platform,
+// recording the outcome in result and failing when the policy blocks the build
+func (o *BuildOrchestrator) scanArtifact(ctx context.Context, def *entities.Recipe, artifact *entities.Artifact, packageName, version, platform string, result *BuildResult) error {
+	policy := def.Security.PolicyFor(platform)
+	if o.enableSecurity && def.Security.ScanVulnerabilities && !policy.ScanVulnerabilities {
+		o.logger.Info("vulnerability scanning disabled by recipe override", interfaces.F("platform", platform))
+	}
+	if !o.enableSecurity || !policy.ScanVulnerabilities {
+		return nil
+	}
+
+	artifact.OSVHints = def.Security.OSV
+	secResult, err := runPhase(ctx, StageScan, o.phaseTimeouts.Scan, func(scanCtx context.Context) (*SecurityWorkflowResult, error) {
+		return o.securityOrch.PerformSecurityWorkflow(scanCtx, artifact, policy)
+	})
+	if err != nil {
+		result.TimedOutPhase = timedOutPhase(err)
+		result.Error = fmt.Errorf("security workflow failed: %w", err)
+		return result.Error
+	}
+	result.SecurityResult = secResult
+
+	// Check if build should be blocked
+	if secResult.Blocked {
+		result.Error = fmt.Errorf("build blocked due to security issues: %s", secResult.BlockReason)
+		return result.Error
+	}
+	o.stageDone(packageName, version, platform, StageScan)
+	return nil
+}
+
+// lookupBuildCache restores a cached tarball into the output directory on a hit
+// and returns the cache key to store under after a fresh build
+func (o *BuildOrchestrator) lookupBuildCache(def *entities.Recipe, version, platform string, result *BuildResult) string {
+	key, err := BuildCacheKey(def, version, platform)
+	if err != nil {
+		o.logger.Warn("build cache disabled for this build", interfaces.F("error", err))
+		return ""
+	}
+
+	cachedPath, found, err := o.buildCache.Restore(key, o.outputDir)
+	if err != nil {
+		o.logger.Warn("failed to restore build from cache", interfaces.F("error", err))
+		return key
+	}
+	if !found {
+		return key
+	}
+
+	o.logger.Info("using cached build", interfaces.F("package", def.Name), interfaces.F("version", version),
+		interfaces.F("platform", platform))
+	result.Artifact = &entities.Artifact{
+		Name:     def.Name,
+		Version:  version,
+		Platform: platform,
+		Path:     cachedPath,
+		Type:     "archive",
+	}
+	result.CacheHit = true
+	return key
+}
+
+// hostSatisfies reports whether the host platform meets a build_host constraint naming an
+// OS ("darwin") or a full platform ("darwin-arm64"); amd64 and x86_64 are treated alike
+func hostSatisfies(buildHost, hostPlatform string) bool {
+	normalize := func(platform string) string {
+		return strings.ReplaceAll(strings.ToLower(platform), "x86_64", "amd64")
+	}
+	buildHost, hostPlatform = normalize(buildHost), normalize(hostPlatform)
+	if strings.Contains(buildHost, "-") {
+		return buildHost == hostPlatform
+	}
+	hostOS, _, _ := strings.Cut(hostPlatform, "-")
+	return buildHost == hostOS
+}
+
 // GetBuildSummary returns a human-readable summary of the build
 func (r *BuildResult) GetBuildSummary() string {
+	if r.Skipped {
+		return fmt.Sprintf("Build skipped: %s", r.SkipReason)
+	}
 	if !r.Success {
 		return fmt.Sprintf("Build failed: %v", r.Error)
 	}
 
+	if r.KeptExisting {
+		return fmt.Sprintf("Build skipped: kept existing %s", r.Artifact.Path)
+	}
+
+	if r.CacheHit {
+		return fmt.Sprintf(`Build served from cache
+Package: %s
+Platform: %s
+Total: %v`,
+			r.Recipe.Name,
+			r.Artifact.Platform,
+			r.TotalDuration,
+		)
+	}
+
 	summary := fmt.Sprintf(`Build successful!
 Package: %s
 Platform: %s
@@ -216,6 +649,10 @@ Total: %v`,
 		r.TotalDuration,
 	)
 
+	if r.Artifact.SourceURL != "" {
+		summary += fmt.Sprintf("\nSource: %s", r.Artifact.SourceURL)
+	}
+
 	if r.SecurityResult != nil {
 		// Note: GetSecuritySummary is a method on SecurityOrchestrator, not SecurityWorkflowResult
 		// For now, include basic security info
@@ -229,6 +666,40 @@ Total: %v`,
 	return summary
 }
 
+// findSignatureURL returns downloadURL plus the first signature extension that exists upstream
+// Without a URL prober the first configured extension is used unprobed
+func (o *BuildOrchestrator) findSignatureURL(ctx context.Context, def *entities.Recipe, downloadURL string) (string, error) {
+	extensions := def.Security.SignatureExtensions
+	if len(extensions) == 0 {
+		extensions = defaultSignatureExtensions
+	}
+
+	candidates := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		candidates = append(candidates, downloadURL+ext)
+	}
+
+	if o.urlProber == nil {
+		return candidates[0], nil
+	}
+
+	for _, candidate := range candidates {
+		exists, err := o.urlProber.URLExists(ctx, candidate)
+		if err != nil {
+			o.logger.Warn("failed to probe signature URL", interfaces.F("url", candidate), interfaces.F("error", err))
+			continue
+		}
+		if exists {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no signature found (tried: %s)", strings.Join(candidates, ", "))
+}
+
 // verifyGPGSignature verifies the GPG signature of a downloaded artifact
 func (o *BuildOrchestrator) verifyGPGSignature(ctx context.Context, def *entities.Recipe, artifact *entities.Artifact) error {
 	// Import GPG keys from KEYS URL if provided (auto-fetch)
@@ -241,13 +712,18 @@ func (o *BuildOrchestrator) verifyGPGSignature(ctx context.Context, def *entitie
 	case len(def.Security.GPGKeyIDs) > 0:
 		// Fallback to manual key IDs
 		o.logger.Info("importing GPG keys", interfaces.F("keys", def.Security.GPGKeyIDs))
-		if err := o.securityGW.ImportGPGKeys(ctx, def.Security.GPGKeyIDs); err != nil {
+		if err := o.securityGW.ImportGPGKeys(ctx, def.Security.GPGKeyIDs, def.Security.GPGKeyLookup); err != nil {
 			return fmt.Errorf("failed to import GPG keys: %w", err)
 		}
 	default:
 		return fmt.Errorf("no GPG keys configured (need gpg_keys_url or gpg_key_ids)")
 	}
 
+	// Upstreams that sign only their checksums manifest are verified through it
+	if def.Security.SignedChecksumsURL != "" {
+		return o.verifySignedChecksums(ctx, def, artifact)
+	}
+
 	// Determine signature URL
 	var sigURL string
 	switch {
@@ -255,8 +731,12 @@ func (o *BuildOrchestrator) verifyGPGSignature(ctx context.Context, def *entitie
 		// Use recipe-defined signature URL with template substitution
 		sigURL = strings.ReplaceAll(def.Security.SignatureURL, "{version}", artifact.Version)
 	case def.Download.DownloadURL != "":
-		// Fallback: try common extensions
-		sigURL = strings.ReplaceAll(def.Download.DownloadURL, "{version}", artifact.Version) + ".sig"
+		// Fallback: try signature extensions against the download URL
+		var err error
+		sigURL, err = o.findSignatureURL(ctx, def, strings.ReplaceAll(def.Download.DownloadURL, "{version}", artifact.Version))
+		if err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("no signature URL configured and no download URL to construct from")
 	}
@@ -277,3 +757,31 @@ func (o *BuildOrchestrator) verifyGPGSignature(ctx context.Context, def *entitie
 	o.logger.Info("GPG signature verified successfully")
 	return nil
 }
+
+// verifySignedChecksums verifies the GPG signature over the recipe's checksums manifest, then the
+// downloaded file against its manifest entry
+func (o *BuildOrchestrator) verifySignedChecksums(ctx context.Context, def *entities.Recipe, artifact *entities.Artifact) error {
+	checksumsURL := strings.ReplaceAll(def.Security.SignedChecksumsURL, "{version}", artifact.Version)
+
+	sigURL := strings.ReplaceAll(def.Security.SignatureURL, "{version}", artifact.Version)
+	if sigURL == "" {
+		var err error
+		sigURL, err = o.findSignatureURL(ctx, def, checksumsURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	verifyPath := artifact.DownloadPath
+	if verifyPath == "" {
+		verifyPath = artifact.Path
+	}
+
+	o.logger.Info("verifying GPG-signed checksums", interfaces.F("checksums", checksumsURL), interfaces.F("signature", sigURL))
+	if err := o.securityGW.VerifyGPGSignedChecksums(ctx, verifyPath, checksumsURL, sigURL); err != nil {
+		return fmt.Errorf("signed checksums verification failed: %w", err)
+	}
+
+	o.logger.Info("GPG-signed checksums verified successfully")
+	return nil
+}
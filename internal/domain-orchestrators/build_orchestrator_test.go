@@ -3,10 +3,16 @@ package orchestrators
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain/entities"
+	"github.com/ochairo/potions/internal/domain/interfaces"
 )
 
 // Mock implementations for testing
@@ -45,9 +51,11 @@ func (m *mockVersionFetcher) FetchLatestVersion(_ *entities.Recipe) (string, err
 type mockDownloader struct {
 	artifact *entities.Artifact
 	err      error
+	platform string
 }
 
-func (m *mockDownloader) DownloadArtifact(_ *entities.Recipe, _, _, _ string) (*entities.Artifact, error) {
+func (m *mockDownloader) DownloadArtifact(_ context.Context, _ *entities.Recipe, _, platform, _ string) (*entities.Artifact, error) {
+	m.platform = platform
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -55,10 +63,12 @@ func (m *mockDownloader) DownloadArtifact(_ *entities.Recipe, _, _, _ string) (*
 }
 
 type mockScriptExecutor struct {
-	err error
+	err   error
+	calls int
 }
 
 func (m *mockScriptExecutor) ExecuteBuildScripts(_ context.Context, _ *entities.Recipe, _ *entities.Artifact, _ string) error {
+	m.calls++
 	return m.err
 }
 
@@ -74,13 +84,57 @@ func (m *mockPackager) PackageArtifact(_ context.Context, _ *entities.Recipe, _
 	return m.artifact, nil
 }
 
-type mockSecurityGateway struct{}
+type mockBuildCache struct {
+	entries map[string]string
+}
+
+func (m *mockBuildCache) Restore(key, _ string) (string, bool, error) {
+	path, ok := m.entries[key]
+	return path, ok, nil
+}
+
+func (m *mockBuildCache) Store(key, path string) error {
+	m.entries[key] = path
+	return nil
+}
+
+type mockBuildVerifier struct {
+	err   error
+	calls int
+}
+
+func (m *mockBuildVerifier) VerifyBuild(_ context.Context, _ *entities.Recipe, _ *entities.Artifact) error {
+	m.calls++
+	return m.err
+}
+
+type mockSecurityGateway struct {
+	sigURL       string
+	checksumsURL string
+}
+
+func (m *mockSecurityGateway) VerifyGPGSignature(_ context.Context, _, sigURL string) error {
+	m.sigURL = sigURL
+	return nil
+}
 
-func (m *mockSecurityGateway) VerifyGPGSignature(_ context.Context, _, _ string) error {
+func (m *mockSecurityGateway) VerifyGPGSignedChecksums(_ context.Context, _, checksumsURL, sigURL string) error {
+	m.checksumsURL = checksumsURL
+	m.sigURL = sigURL
 	return nil
 }
 
-func (m *mockSecurityGateway) ImportGPGKeys(_ context.Context, _ []string) error {
+type mockURLProber struct {
+	existing map[string]bool
+	probed   []string
+}
+
+func (m *mockURLProber) URLExists(_ context.Context, url string) (bool, error) {
+	m.probed = append(m.probed, url)
+	return m.existing[url], nil
+}
+
+func (m *mockSecurityGateway) ImportGPGKeys(_ context.Context, _ []string, _ entities.GPGKeyLookup) error {
 	return nil
 }
 
@@ -310,6 +364,73 @@ func TestBuildOrchestrator_FetchLatestVersion(t *testing.T) {
 	}
 }
 
+// sourceVersionFetcher returns a version per version.source and records the sources used
+type sourceVersionFetcher struct {
+	versions map[string]string
+	sources  []string
+}
+
+func (m *sourceVersionFetcher) FetchLatestVersion(def *entities.Recipe) (string, error) {
+	m.sources = append(m.sources, def.Version.Source)
+	version, ok := m.versions[def.Version.Source]
+	if !ok {
+		return "", fmt.Errorf("unexpected source %s", def.Version.Source)
+	}
+	return version, nil
+}
+
+// Test "@lts" resolves via the lts channel source while the default uses the primary source
+func TestBuildOrchestrator_VersionChannels(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "node",
+		Version: entities.VersionConfig{
+			Source: "github-release:nodejs/node",
+			Channels: map[string]string{
+				"lts": "github-tag:nodejs/node#20",
+			},
+		},
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+	}
+	fetcher := &sourceVersionFetcher{versions: map[string]string{
+		"github-release:nodejs/node": "22.3.0",
+		"github-tag:nodejs/node#20":  "20.15.0",
+	}}
+
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		nil,
+		&mockSecurityGateway{},
+		fetcher,
+		&mockDownloader{artifact: &entities.Artifact{Path: "node.tar.gz"}},
+		&mockScriptExecutor{},
+		&mockPackager{artifact: &entities.Artifact{Path: "node.tar.gz"}},
+		BuildOrchestratorConfig{},
+		nil,
+	)
+
+	for _, request := range []string{"@lts", "", "latest"} {
+		if _, err := orch.BuildPackage(context.Background(), "node", request, "linux-amd64"); err != nil {
+			t.Fatalf("BuildPackage(%q) error = %v", request, err)
+		}
+	}
+	want := []string{"github-tag:nodejs/node#20", "github-release:nodejs/node", "github-release:nodejs/node"}
+	if strings.Join(fetcher.sources, ",") != strings.Join(want, ",") {
+		t.Errorf("version sources = %v, want %v", fetcher.sources, want)
+	}
+	if recipe.Version.Source != "github-release:nodejs/node" {
+		t.Errorf("recipe source mutated to %q", recipe.Version.Source)
+	}
+
+	_, err := orch.BuildPackage(context.Background(), "node", "@nightly", "linux-amd64")
+	if err == nil || !strings.Contains(err.Error(), `unknown version channel "nightly" (available: lts)`) {
+		t.Errorf("BuildPackage(@nightly) error = %v, want unknown channel", err)
+	}
+}
+
 // Test version fetch failure
 func TestBuildOrchestrator_VersionFetchFailure(t *testing.T) {
 	recipe := &entities.Recipe{
@@ -365,6 +486,22 @@ func TestBuildResult_GetBuildSummary_Success(t *testing.T) {
 	}
 }
 
+// Test the build summary names where the source was downloaded from
+func TestBuildResult_GetBuildSummary_SourceURL(t *testing.T) {
+	result := &BuildResult{
+		Recipe: &entities.Recipe{Name: "kubectl"},
+		Artifact: &entities.Artifact{
+			Platform:  "linux-amd64",
+			SourceURL: "https://dl.k8s.io/release/v1.28.0/bin/linux/amd64/kubectl",
+		},
+		Success: true,
+	}
+
+	if summary := result.GetBuildSummary(); !contains(summary, "Source: https://dl.k8s.io/release/v1.28.0/bin/linux/amd64/kubectl") {
+		t.Errorf("Summary should contain the source URL, got: %s", summary)
+	}
+}
+
 // Test build result summary for failure
 func TestBuildResult_GetBuildSummary_Failure(t *testing.T) {
 	result := &BuildResult{
@@ -383,6 +520,275 @@ func TestBuildResult_GetBuildSummary_Failure(t *testing.T) {
 	}
 }
 
+// Test a second build of identical inputs is served from the build cache
+func TestBuildOrchestrator_BuildCache(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "kubectl",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+	}
+
+	executor := &mockScriptExecutor{}
+	cache := &mockBuildCache{entries: make(map[string]string)}
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		nil,
+		&mockSecurityGateway{},
+		&mockVersionFetcher{},
+		&mockDownloader{artifact: &entities.Artifact{Path: "kubectl"}},
+		executor,
+		&mockPackager{artifact: &entities.Artifact{Path: "dist/kubectl-1.28.0-linux-amd64.tar.gz"}},
+		BuildOrchestratorConfig{},
+		nil,
+	).WithBuildCache(cache)
+
+	first, err := orch.BuildPackage(context.Background(), "kubectl", "1.28.0", "linux-amd64")
+	if err != nil {
+		t.Fatalf("First build failed: %v", err)
+	}
+	if first.CacheHit {
+		t.Error("First build should not be a cache hit")
+	}
+
+	second, err := orch.BuildPackage(context.Background(), "kubectl", "1.28.0", "linux-amd64")
+	if err != nil {
+		t.Fatalf("Second build failed: %v", err)
+	}
+	if !second.CacheHit {
+		t.Error("Second build should be served from cache")
+	}
+	if second.Artifact.Path != "dist/kubectl-1.28.0-linux-amd64.tar.gz" {
+		t.Errorf("Cached artifact path = %s", second.Artifact.Path)
+	}
+	if executor.calls != 1 {
+		t.Errorf("Build scripts executed %d times, want 1", executor.calls)
+	}
+
+	// A different version must not hit the cache
+	if _, err := orch.BuildPackage(context.Background(), "kubectl", "1.29.0", "linux-amd64"); err != nil {
+		t.Fatalf("Third build failed: %v", err)
+	}
+	if executor.calls != 2 {
+		t.Errorf("Build scripts executed %d times, want 2", executor.calls)
+	}
+}
+
+// Test a cache hit skips the download and build but is scanned again, so a new advisory blocks it
+func TestBuildOrchestrator_BuildCacheRescans(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "kubectl",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{"linux-amd64": {OS: "linux", Arch: "amd64"}},
+		},
+		Security: entities.RecipeSecurity{ScanVulnerabilities: true},
+	}
+	cachedPath := filepath.Join(t.TempDir(), "kubectl-1.28.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(cachedPath, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	executor := &mockScriptExecutor{}
+	svc := &mockSecurityService{report: &entities.SecurityReport{Score: 10}}
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		NewSecurityOrchestrator(svc),
+		nil,
+		&mockVersionFetcher{},
+		&mockDownloader{artifact: &entities.Artifact{Path: "kubectl"}},
+		executor,
+		&mockPackager{artifact: &entities.Artifact{Path: cachedPath}},
+		BuildOrchestratorConfig{EnableSecurityScan: true},
+		&interfaces.NoOpLogger{},
+	).WithBuildCache(&mockBuildCache{entries: make(map[string]string)})
+
+	if _, err := orch.BuildPackage(context.Background(), "kubectl", "1.28.0", "linux-amd64"); err != nil {
+		t.Fatalf("First build failed: %v", err)
+	}
+
+	svc.block = true
+	result, err := orch.BuildPackage(context.Background(), "kubectl", "1.28.0", "linux-amd64")
+	if err == nil || !strings.Contains(err.Error(), "build blocked") {
+		t.Fatalf("cached build error = %v, want blocked by the new scan", err)
+	}
+	if !result.CacheHit || executor.calls != 1 {
+		t.Errorf("CacheHit = %v, build scripts ran %d times; want a cache hit without a rebuild", result.CacheHit, executor.calls)
+	}
+	if len(svc.policies) != 2 {
+		t.Errorf("scanned %d times, want 2", len(svc.policies))
+	}
+	if _, err := os.Stat(cachedPath); !os.IsNotExist(err) {
+		t.Errorf("restored tarball of a blocked build should be removed, stat error = %v", err)
+	}
+}
+
+// Test cache keys change with the recipe content
+func TestBuildCacheKey(t *testing.T) {
+	recipe := &entities.Recipe{Name: "kubectl"}
+	key1, err := BuildCacheKey(recipe, "1.28.0", "linux-amd64")
+	if err != nil {
+		t.Fatalf("BuildCacheKey() error = %v", err)
+	}
+
+	key2, _ := BuildCacheKey(recipe, "1.28.0", "linux-amd64")
+	if key1 != key2 {
+		t.Error("BuildCacheKey() should be deterministic")
+	}
+
+	changed := &entities.Recipe{Name: "kubectl", Description: "changed"}
+	key3, _ := BuildCacheKey(changed, "1.28.0", "linux-amd64")
+	if key1 == key3 {
+		t.Error("BuildCacheKey() should change when the recipe changes")
+	}
+}
+
+// Test verify_command outcome decides the build result
+func TestBuildOrchestrator_VerifyCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		verifyErr   error
+		wantSuccess bool
+	}{
+		{"passing verify command", nil, true},
+		{"failing verify command", errors.New("exit status 1"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := &entities.Recipe{
+				Name: "kubectl",
+				Download: entities.RecipeDownload{
+					Platforms: map[string]entities.PlatformConfig{
+						"linux-amd64": {OS: "linux", Arch: "amd64"},
+					},
+				},
+				Build: entities.RecipeBuildStep{VerifyCommand: "kubectl version --client"},
+			}
+
+			verifier := &mockBuildVerifier{err: tt.verifyErr}
+			orch := NewBuildOrchestrator(
+				&mockRecipeRepository{recipe: recipe},
+				nil,
+				&mockSecurityGateway{},
+				&mockVersionFetcher{},
+				&mockDownloader{artifact: &entities.Artifact{Path: "kubectl"}},
+				&mockScriptExecutor{},
+				&mockPackager{artifact: &entities.Artifact{Path: "dist/kubectl-1.28.0-linux-amd64.tar.gz"}},
+				BuildOrchestratorConfig{},
+				nil,
+			).WithBuildVerifier(verifier).WithHostPlatform("linux-x86_64")
+
+			result, err := orch.BuildPackage(context.Background(), "kubectl", "1.28.0", "linux-amd64")
+
+			if verifier.calls != 1 {
+				t.Errorf("VerifyBuild called %d times, want 1", verifier.calls)
+			}
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (err: %v)", result.Success, tt.wantSuccess, err)
+			}
+			if !tt.wantSuccess && (err == nil || !strings.Contains(err.Error(), "build verification failed")) {
+				t.Errorf("Expected verification error, got %v", err)
+			}
+		})
+	}
+}
+
+// Test verify_command is not run for an artifact built for another platform
+func TestBuildOrchestrator_VerifyCommandCrossPlatform(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "kubectl",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"darwin-arm64": {OS: "darwin", Arch: "arm64"},
+			},
+		},
+		Build: entities.RecipeBuildStep{VerifyCommand: "kubectl version --client"},
+	}
+
+	verifier := &mockBuildVerifier{err: errors.New("exec format error")}
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		nil,
+		&mockSecurityGateway{},
+		&mockVersionFetcher{},
+		&mockDownloader{artifact: &entities.Artifact{Path: "kubectl"}},
+		&mockScriptExecutor{},
+		&mockPackager{artifact: &entities.Artifact{Path: "dist/kubectl-1.28.0-darwin-arm64.tar.gz"}},
+		BuildOrchestratorConfig{},
+		nil,
+	).WithBuildVerifier(verifier).WithHostPlatform("linux-amd64")
+
+	result, err := orch.BuildPackage(context.Background(), "kubectl", "1.28.0", "darwin-arm64")
+	if err != nil {
+		t.Fatalf("BuildPackage() error = %v", err)
+	}
+	if verifier.calls != 0 {
+		t.Errorf("VerifyBuild called %d times, want 0 for a darwin-arm64 artifact on linux-amd64", verifier.calls)
+	}
+	if result.Verification != VerificationSkippedCrossPlatform {
+		t.Errorf("Verification = %q, want %q", result.Verification, VerificationSkippedCrossPlatform)
+	}
+}
+
+// Test build.min_size/max_size reject packaged artifacts outside the declared bounds
+func TestBuildOrchestrator_ArtifactSizeBounds(t *testing.T) {
+	tarball := filepath.Join(t.TempDir(), "kubectl-1.28.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(tarball, make([]byte, 100), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		minSize int64
+		maxSize int64
+		wantErr string
+	}{
+		{"no bounds", 0, 0, ""},
+		{"within bounds", 50, 200, ""},
+		{"below min_size", 1000, 0, "below build.min_size of 1000 bytes"},
+		{"above max_size", 0, 10, "above build.max_size of 10 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := &entities.Recipe{
+				Name: "kubectl",
+				Download: entities.RecipeDownload{
+					Platforms: map[string]entities.PlatformConfig{
+						"linux-amd64": {OS: "linux", Arch: "amd64"},
+					},
+				},
+				Build: entities.RecipeBuildStep{MinSize: tt.minSize, MaxSize: tt.maxSize},
+			}
+
+			orch := NewBuildOrchestrator(
+				&mockRecipeRepository{recipe: recipe},
+				nil,
+				&mockSecurityGateway{},
+				&mockVersionFetcher{},
+				&mockDownloader{artifact: &entities.Artifact{Path: "kubectl"}},
+				&mockScriptExecutor{},
+				&mockPackager{artifact: &entities.Artifact{Path: tarball}},
+				BuildOrchestratorConfig{},
+				nil,
+			)
+
+			result, err := orch.BuildPackage(context.Background(), "kubectl", "1.28.0", "linux-amd64")
+			if tt.wantErr == "" {
+				if err != nil || !result.Success {
+					t.Errorf("BuildPackage() error = %v, want success", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || result.Success {
+				t.Errorf("BuildPackage() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // Helper function to check if string contains substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsAt(s, substr))
@@ -396,3 +802,416 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+// Test the signature fallback probes extensions in order and uses the first that exists
+func TestBuildOrchestrator_SignatureExtensions(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions []string
+		existing   []string
+		wantURL    string
+		wantErr    bool
+	}{
+		{"sig missing, asc exists", []string{".sig", ".asc"}, []string{"https://example.com/tool-1.0.0.tar.gz.asc"}, "https://example.com/tool-1.0.0.tar.gz.asc", false},
+		{"default prefers asc", nil, []string{"https://example.com/tool-1.0.0.tar.gz.asc", "https://example.com/tool-1.0.0.tar.gz.sig"}, "https://example.com/tool-1.0.0.tar.gz.asc", false},
+		{"extension without dot", []string{"sign"}, []string{"https://example.com/tool-1.0.0.tar.gz.sign"}, "https://example.com/tool-1.0.0.tar.gz.sign", false},
+		{"none exist", []string{".sig", ".asc"}, nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := &entities.Recipe{
+				Name: "tool",
+				Download: entities.RecipeDownload{
+					DownloadURL: "https://example.com/tool-{version}.tar.gz",
+					Platforms: map[string]entities.PlatformConfig{
+						"linux-amd64": {OS: "linux", Arch: "amd64"},
+					},
+				},
+				Security: entities.RecipeSecurity{
+					VerifySignature:     true,
+					GPGKeyIDs:           []string{"ABCDEF"},
+					SignatureExtensions: tt.extensions,
+				},
+			}
+
+			existing := make(map[string]bool)
+			for _, url := range tt.existing {
+				existing[url] = true
+			}
+			prober := &mockURLProber{existing: existing}
+			securityGW := &mockSecurityGateway{}
+
+			orch := NewBuildOrchestrator(
+				&mockRecipeRepository{recipe: recipe},
+				nil,
+				securityGW,
+				&mockVersionFetcher{version: "1.0.0"},
+				&mockDownloader{artifact: &entities.Artifact{Path: "tool", Version: "1.0.0"}},
+				&mockScriptExecutor{},
+				&mockPackager{},
+				BuildOrchestratorConfig{},
+				&interfaces.NoOpLogger{},
+			).WithURLProber(prober)
+
+			_, err := orch.BuildPackage(context.Background(), "tool", "1.0.0", "linux-amd64")
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "no signature found") {
+					t.Fatalf("BuildPackage() error = %v, want no signature found", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildPackage() error = %v", err)
+			}
+			if securityGW.sigURL != tt.wantURL {
+				t.Errorf("verified signature URL = %q, want %q (probed %v)", securityGW.sigURL, tt.wantURL, prober.probed)
+			}
+		})
+	}
+}
+
+// Test recipes with signed_checksums_url verify the manifest signature rather than the artifact's
+func TestBuildOrchestrator_SignedChecksums(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			DownloadURL: "https://example.com/{version}/tool_{version}_{os}_{arch}.zip",
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+		Security: entities.RecipeSecurity{
+			VerifySignature:    true,
+			GPGKeyIDs:          []string{"ABCDEF"},
+			SignedChecksumsURL: "https://example.com/{version}/tool_{version}_SHA256SUMS",
+		},
+	}
+	securityGW := &mockSecurityGateway{}
+
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		nil,
+		securityGW,
+		&mockVersionFetcher{version: "1.0.0"},
+		&mockDownloader{artifact: &entities.Artifact{Path: "tool", Version: "1.0.0"}},
+		&mockScriptExecutor{},
+		&mockPackager{},
+		BuildOrchestratorConfig{},
+		&interfaces.NoOpLogger{},
+	)
+
+	if _, err := orch.BuildPackage(context.Background(), "tool", "1.0.0", "linux-amd64"); err != nil {
+		t.Fatalf("BuildPackage() error = %v", err)
+	}
+	if securityGW.checksumsURL != "https://example.com/1.0.0/tool_1.0.0_SHA256SUMS" {
+		t.Errorf("checksums URL = %q", securityGW.checksumsURL)
+	}
+	if securityGW.sigURL != "https://example.com/1.0.0/tool_1.0.0_SHA256SUMS.asc" {
+		t.Errorf("signature URL = %q, want manifest URL plus .asc", securityGW.sigURL)
+	}
+}
+
+type mockToolchainChecker struct {
+	err  error
+	reqs []entities.ToolRequirement
+}
+
+func (m *mockToolchainChecker) CheckRequirements(_ context.Context, reqs []entities.ToolRequirement) error {
+	m.reqs = reqs
+	return m.err
+}
+
+// Test an unmet build.requires fails the build before any build script runs
+func TestBuildOrchestrator_ToolchainRequirementUnmet(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+		Build: entities.RecipeBuildStep{
+			Script:   "go build ./...",
+			Requires: []entities.ToolRequirement{{Tool: "go", MinVersion: "1.22"}},
+		},
+	}
+	checker := &mockToolchainChecker{err: errors.New("go 1.22 or newer is required, found 1.21.5")}
+	executor := &mockScriptExecutor{}
+
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		nil,
+		nil,
+		&mockVersionFetcher{version: "1.0.0"},
+		&mockDownloader{artifact: &entities.Artifact{Path: "tool", Version: "1.0.0"}},
+		executor,
+		&mockPackager{},
+		BuildOrchestratorConfig{},
+		&interfaces.NoOpLogger{},
+	).WithToolchainChecker(checker)
+
+	result, err := orch.BuildPackage(context.Background(), "tool", "1.0.0", "linux-amd64")
+	if err == nil || !strings.Contains(err.Error(), "toolchain requirement not met") {
+		t.Fatalf("BuildPackage() error = %v, want toolchain requirement failure", err)
+	}
+	if result.Success || result.Artifact != nil {
+		t.Errorf("BuildPackage() result = %+v, want pre-build failure", result)
+	}
+	if executor.calls != 0 {
+		t.Errorf("build scripts ran %d times, want 0", executor.calls)
+	}
+	if len(checker.reqs) != 1 || checker.reqs[0].Tool != "go" {
+		t.Errorf("checked requirements = %+v, want go requirement", checker.reqs)
+	}
+}
+
+// Test a darwin-host-only platform is skipped, not failed, on a linux host
+func TestBuildOrchestrator_BuildHostSkipped(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":  {OS: "linux", Arch: "amd64"},
+				"darwin-arm64": {OS: "darwin", Arch: "arm64", BuildHost: "darwin"},
+			},
+		},
+		Build: entities.RecipeBuildStep{Script: "make"},
+	}
+	executor := &mockScriptExecutor{}
+
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		nil,
+		nil,
+		&mockVersionFetcher{version: "1.0.0"},
+		&mockDownloader{err: errors.New("download should not run")},
+		executor,
+		&mockPackager{},
+		BuildOrchestratorConfig{},
+		&interfaces.NoOpLogger{},
+	).WithHostPlatform("linux-x86_64")
+
+	result, err := orch.BuildPackage(context.Background(), "tool", "1.0.0", "darwin-arm64")
+	if err != nil {
+		t.Fatalf("BuildPackage() error = %v, want skip", err)
+	}
+	if !result.Skipped || result.SkipReason != "requires darwin host" {
+		t.Errorf("BuildPackage() result = %+v, want skipped requiring darwin host", result)
+	}
+	if executor.calls != 0 {
+		t.Errorf("build scripts ran %d times, want 0", executor.calls)
+	}
+	if got := result.GetBuildSummary(); got != "Build skipped: requires darwin host" {
+		t.Errorf("GetBuildSummary() = %q", got)
+	}
+}
+
+// Test a recipe keyed by upstream platform names builds when requested by the mapped potions platform
+func TestBuildOrchestrator_PlatformMap(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"macos-aarch64": {},
+				"linux-x86_64":  {},
+			},
+			PlatformMap: map[string]string{"darwin-arm64": "macos-aarch64"},
+		},
+	}
+	downloader := &mockDownloader{artifact: &entities.Artifact{Path: "tool-1.0.0-darwin-arm64.tar.gz"}}
+
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		nil,
+		nil,
+		&mockVersionFetcher{version: "1.0.0"},
+		downloader,
+		&mockScriptExecutor{},
+		&mockPackager{},
+		BuildOrchestratorConfig{},
+		&interfaces.NoOpLogger{},
+	)
+
+	if _, err := orch.BuildPackage(context.Background(), "tool", "1.0.0", "darwin-arm64"); err != nil {
+		t.Fatalf("BuildPackage() error = %v", err)
+	}
+	if downloader.platform != "darwin-arm64" {
+		t.Errorf("downloaded platform = %q, want the canonical darwin-arm64", downloader.platform)
+	}
+	if _, err := orch.BuildPackage(context.Background(), "tool", "1.0.0", "darwin-amd64"); err == nil {
+		t.Error("BuildPackage() should fail for a platform neither declared nor mapped")
+	}
+}
+
+// Test per-platform security overrides skip scanning on one platform and tighten thresholds on another
+func TestBuildOrchestrator_PlatformSecurityOverrides(t *testing.T) {
+	disabled := false
+	minScore := 8.0
+	recipe := &entities.Recipe{
+		Name: "tool",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":  {OS: "linux", Arch: "amd64"},
+				"linux-arm64":  {OS: "linux", Arch: "arm64"},
+				"darwin-arm64": {OS: "darwin", Arch: "arm64"},
+			},
+		},
+		Security: entities.RecipeSecurity{
+			ScanVulnerabilities: true,
+			Platforms: map[string]entities.SecurityOverride{
+				"linux":       {BlockSeverity: "HIGH"},
+				"linux-arm64": {MinScore: &minScore},
+				"darwin":      {ScanVulnerabilities: &disabled},
+			},
+		},
+	}
+	svc := &mockSecurityService{report: &entities.SecurityReport{Score: 10}}
+
+	orch := NewBuildOrchestrator(
+		&mockRecipeRepository{recipe: recipe},
+		NewSecurityOrchestrator(svc),
+		nil,
+		&mockVersionFetcher{version: "1.0.0"},
+		&mockDownloader{artifact: &entities.Artifact{Path: "tool"}},
+		&mockScriptExecutor{},
+		&mockPackager{},
+		BuildOrchestratorConfig{EnableSecurityScan: true},
+		&interfaces.NoOpLogger{},
+	)
+
+	for _, platform := range []string{"darwin-arm64", "linux-amd64", "linux-arm64"} {
+		result, err := orch.BuildPackage(context.Background(), "tool", "1.0.0", platform)
+		if err != nil {
+			t.Fatalf("BuildPackage(%s) error = %v", platform, err)
+		}
+		if scanned := result.SecurityResult != nil; scanned != (platform != "darwin-arm64") {
+			t.Errorf("BuildPackage(%s) scanned = %v", platform, scanned)
+		}
+	}
+
+	want := []entities.SecurityPolicy{
+		{ScanVulnerabilities: true, MinScore: entities.DefaultMinSecurityScore, BlockSeverity: "HIGH"},
+		{ScanVulnerabilities: true, MinScore: 8.0, BlockSeverity: "HIGH"},
+	}
+	if len(svc.policies) != len(want) {
+		t.Fatalf("scanned %d platforms, want %d (darwin disabled)", len(svc.policies), len(want))
+	}
+	for i := range want {
+		if svc.policies[i] != want[i] {
+			t.Errorf("policy %d = %+v, want %+v", i, svc.policies[i], want[i])
+		}
+	}
+}
+
+// Test build_host matches an OS or a full platform, treating amd64 and x86_64 alike
+func TestHostSatisfies(t *testing.T) {
+	tests := []struct {
+		buildHost, host string
+		want            bool
+	}{
+		{"darwin", "darwin-arm64", true},
+		{"darwin", "linux-x86_64", false},
+		{"darwin-arm64", "darwin-arm64", true},
+		{"darwin-arm64", "darwin-x86_64", false},
+		{"linux-amd64", "linux-x86_64", true},
+	}
+	for _, tt := range tests {
+		if got := hostSatisfies(tt.buildHost, tt.host); got != tt.want {
+			t.Errorf("hostSatisfies(%q, %q) = %v, want %v", tt.buildHost, tt.host, got, tt.want)
+		}
+	}
+}
+
+type slowDownloader struct {
+	delay    time.Duration
+	artifact *entities.Artifact
+}
+
+func (m *slowDownloader) DownloadArtifact(ctx context.Context, _ *entities.Recipe, _, _, _ string) (*entities.Artifact, error) {
+	select {
+	case <-time.After(m.delay):
+		return m.artifact, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type blockingScriptExecutor struct{}
+
+func (blockingScriptExecutor) ExecuteBuildScripts(ctx context.Context, _ *entities.Recipe, _ *entities.Artifact, _ string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Test runPhase returns only after the phase function has stopped, even past the deadline
+func TestRunPhase_WaitsForPhase(t *testing.T) {
+	var stopped atomic.Bool
+	_, err := runPhase(context.Background(), StageDownload, 10*time.Millisecond, func(ctx context.Context) (struct{}, error) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond) // cleanup after cancellation
+		stopped.Store(true)
+		return struct{}{}, ctx.Err()
+	})
+	if timedOutPhase(err) != StageDownload {
+		t.Errorf("runPhase() error = %v, want a download phase timeout", err)
+	}
+	if !stopped.Load() {
+		t.Error("runPhase() returned while the phase was still running")
+	}
+}
+
+// Test each phase deadline is enforced separately and reported by phase
+func TestBuildOrchestrator_PhaseTimeouts(t *testing.T) {
+	recipe := &entities.Recipe{
+		Name: "kubectl",
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64": {OS: "linux", Arch: "amd64"},
+			},
+		},
+	}
+	artifact := &entities.Artifact{Path: "kubectl"}
+
+	tests := []struct {
+		name       string
+		downloader Downloader
+		executor   ScriptExecutor
+		wantPhase  string
+	}{
+		{"slow download", &slowDownloader{delay: time.Second, artifact: artifact}, &mockScriptExecutor{}, StageDownload},
+		{"slow build", &mockDownloader{artifact: artifact}, blockingScriptExecutor{}, StageBuild},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := NewBuildOrchestrator(
+				&mockRecipeRepository{recipe: recipe},
+				nil,
+				&mockSecurityGateway{},
+				&mockVersionFetcher{version: "1.0.0"},
+				tt.downloader,
+				tt.executor,
+				&mockPackager{artifact: artifact},
+				BuildOrchestratorConfig{},
+				nil,
+			).WithPhaseTimeouts(PhaseTimeouts{Download: 20 * time.Millisecond, Build: 20 * time.Millisecond})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			result, err := orch.BuildPackage(ctx, "kubectl", "1.0.0", "linux-amd64")
+			var phaseErr *PhaseTimeoutError
+			if !errors.As(err, &phaseErr) {
+				t.Fatalf("BuildPackage() error = %v, want *PhaseTimeoutError", err)
+			}
+			if phaseErr.Phase != tt.wantPhase || result.TimedOutPhase != tt.wantPhase {
+				t.Errorf("timed out phase = %q (result %q), want %q", phaseErr.Phase, result.TimedOutPhase, tt.wantPhase)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("errors.Is(%v, context.DeadlineExceeded) = false", err)
+			}
+			if ctx.Err() != nil {
+				t.Errorf("overall context expired; phase deadline should have fired first")
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package orchestrators
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ochairo/potions/internal/domain/entities"
@@ -34,50 +36,83 @@ type SecurityWorkflowResult struct {
 	BlockReason      string
 }
 
-// PerformSecurityWorkflow executes the complete security workflow for an artifact
+// PerformSecurityWorkflow executes the complete security workflow for an artifact, blocking
+// it according to the policy resolved for the artifact's platform
 // This is the main use case that coordinates all security operations
-func (o *SecurityOrchestrator) PerformSecurityWorkflow(ctx context.Context, artifact *entities.Artifact) (*SecurityWorkflowResult, error) {
+func (o *SecurityOrchestrator) PerformSecurityWorkflow(ctx context.Context, artifact *entities.Artifact, policy entities.SecurityPolicy) (*SecurityWorkflowResult, error) {
 	startTime := time.Now()
 
 	result := &SecurityWorkflowResult{
 		Artifact: artifact,
 	}
 
+	// Steps 1-3 are independent: the network-bound vulnerability scan runs alongside the
+	// file-bound binary analysis and SBOM generation. A scan failure cancels the others.
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg             sync.WaitGroup
+		securityReport *entities.SecurityReport
+		scanErr        error
+		binaryAnalysis *entities.BinaryAnalysis
+		sbom           *entities.SBOM
+		sbomErr        error
+	)
+
 	// Step 1: Vulnerability scanning
-	securityReport, err := o.securityService.PerformSecurityScan(ctx, artifact)
-	if err != nil {
-		return nil, fmt.Errorf("vulnerability scan failed: %w", err)
-	}
-	result.SecurityReport = securityReport
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		securityReport, scanErr = o.securityService.PerformSecurityScan(workCtx, artifact)
+		if scanErr != nil {
+			cancel()
+		}
+	}()
 
-	// Step 2: Check if build should be blocked
-	if o.securityService.ShouldBlockBuild(securityReport) {
-		result.Blocked = true
-		result.BlockReason = o.determineBlockReason(securityReport)
-		result.WorkflowDuration = time.Since(startTime)
-		return result, nil
+	// Step 2: Binary analysis (if artifact is a binary); best-effort, errors are ignored
+	if artifact.Type == "binary" && artifact.Path != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if analysis, err := o.securityService.AnalyzeBinary(workCtx, artifact.Path, artifact.Platform); err == nil {
+				binaryAnalysis = analysis
+			}
+		}()
 	}
 
-	// Step 3: Binary analysis (if artifact is a binary)
-	if artifact.Type == "binary" && artifact.Path != "" {
-		binaryAnalysis, err := o.securityService.AnalyzeBinary(ctx, artifact.Path, artifact.Platform)
-		if err != nil {
-			// Log warning but don't fail - binary analysis is best-effort
-			// In production, use proper logger
-			_ = err
-		} else {
-			result.BinaryAnalysis = binaryAnalysis
+	// Step 3: Generate SBOM; nice-to-have, errors are ignored unless strict generation
+	// reports a binary without resolved dependencies
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		generated, err := o.securityService.GenerateSBOM(workCtx, artifact)
+		if err == nil {
+			sbom = generated
+		} else if errors.Is(err, entities.ErrIncompleteSBOM) {
+			sbomErr = err
 		}
+	}()
+
+	wg.Wait()
+
+	if scanErr != nil {
+		return nil, fmt.Errorf("vulnerability scan failed: %w", scanErr)
+	}
+	if sbomErr != nil {
+		return nil, sbomErr
 	}
+	result.SecurityReport = securityReport
 
-	// Step 4: Generate SBOM
-	sbom, err := o.securityService.GenerateSBOM(ctx, artifact)
-	if err != nil {
-		// Log warning but don't fail - SBOM is nice-to-have
-		_ = err
-	} else {
-		result.SBOM = sbom
+	// Step 4: Check if build should be blocked (decided by the vulnerability scan alone)
+	if o.securityService.ShouldBlockBuild(securityReport, policy) {
+		result.Blocked = true
+		result.BlockReason = o.determineBlockReason(securityReport, policy)
+		result.WorkflowDuration = time.Since(startTime)
+		return result, nil
 	}
+	result.BinaryAnalysis = binaryAnalysis
+	result.SBOM = sbom
 
 	// Step 5: Generate security attestation
 	attestation, err := o.securityService.GenerateAttestation(ctx, artifact, result.BinaryAnalysis)
@@ -93,22 +128,18 @@ func (o *SecurityOrchestrator) PerformSecurityWorkflow(ctx context.Context, arti
 }
 
 // determineBlockReason analyzes the security report to determine why the build was blocked
-func (o *SecurityOrchestrator) determineBlockReason(report *entities.SecurityReport) string {
-	// Check for critical vulnerabilities
-	criticalCount := 0
-	for _, vuln := range report.Vulnerabilities {
-		if vuln.Severity == "CRITICAL" {
-			criticalCount++
+func (o *SecurityOrchestrator) determineBlockReason(report *entities.SecurityReport, policy entities.SecurityPolicy) string {
+	// Check for vulnerabilities at or above the blocking severity
+	if blocking := o.securityService.FilterVulnerabilities(report.Vulnerabilities, policy.BlockSeverity); len(blocking) > 0 {
+		if policy.BlockSeverity == entities.DefaultBlockSeverity {
+			return fmt.Sprintf("Build blocked: %d %s vulnerabilities found", len(blocking), policy.BlockSeverity)
 		}
-	}
-
-	if criticalCount > 0 {
-		return fmt.Sprintf("Build blocked: %d CRITICAL vulnerabilities found", criticalCount)
+		return fmt.Sprintf("Build blocked: %d vulnerabilities of %s or higher severity found", len(blocking), policy.BlockSeverity)
 	}
 
 	// Check for low security score
-	if report.Score < 5.0 {
-		return fmt.Sprintf("Build blocked: Security score %.1f/10.0 below threshold (5.0)", report.Score)
+	if report.Score < policy.MinScore {
+		return fmt.Sprintf("Build blocked: Security score %.1f/10.0 below threshold (%.1f)", report.Score, policy.MinScore)
 	}
 
 	return "Build blocked: Security requirements not met"
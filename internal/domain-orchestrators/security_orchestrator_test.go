@@ -0,0 +1,122 @@
+package orchestrators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// mockSecurityService is a configurable services.SecurityService for workflow tests
+type mockSecurityService struct {
+	report      *entities.SecurityReport
+	scanErr     error
+	block       bool
+	sbomErr     error
+	sbomStarted chan struct{}             // closed when GenerateSBOM is called, if set
+	policies    []entities.SecurityPolicy // policy of each ShouldBlockBuild call
+}
+
+func (m *mockSecurityService) PerformSecurityScan(ctx context.Context, _ *entities.Artifact) (*entities.SecurityReport, error) {
+	if m.sbomStarted != nil {
+		select {
+		case <-m.sbomStarted:
+		case <-time.After(2 * time.Second):
+			return nil, errors.New("SBOM generation did not start while the scan was running")
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return m.report, m.scanErr
+}
+
+func (m *mockSecurityService) GenerateSBOM(_ context.Context, _ *entities.Artifact) (*entities.SBOM, error) {
+	if m.sbomStarted != nil {
+		close(m.sbomStarted)
+	}
+	if m.sbomErr != nil {
+		return nil, m.sbomErr
+	}
+	return &entities.SBOM{Components: []entities.Component{{Name: "zlib"}}}, nil
+}
+
+func (m *mockSecurityService) AnalyzeBinary(_ context.Context, _, _ string) (*entities.BinaryAnalysis, error) {
+	return &entities.BinaryAnalysis{}, nil
+}
+
+func (m *mockSecurityService) GenerateAttestation(_ context.Context, _ *entities.Artifact, _ *entities.BinaryAnalysis) (*entities.SecurityAttestation, error) {
+	return &entities.SecurityAttestation{}, nil
+}
+
+func (m *mockSecurityService) CalculateSecurityScore(_ *entities.SecurityReport) float64 { return 10 }
+
+func (m *mockSecurityService) FilterVulnerabilities(v []entities.Vulnerability, _ string) []entities.Vulnerability {
+	return v
+}
+
+func (m *mockSecurityService) ShouldBlockBuild(_ *entities.SecurityReport, policy entities.SecurityPolicy) bool {
+	m.policies = append(m.policies, policy)
+	return m.block
+}
+
+// Test scan, binary analysis and SBOM run concurrently and all results are populated
+func TestSecurityOrchestrator_PerformSecurityWorkflow_Concurrent(t *testing.T) {
+	svc := &mockSecurityService{
+		report:      &entities.SecurityReport{Score: 9.5},
+		sbomStarted: make(chan struct{}),
+	}
+	artifact := &entities.Artifact{Type: "binary", Path: "/tmp/tool", Platform: "linux-x86_64"}
+
+	result, err := NewSecurityOrchestrator(svc).PerformSecurityWorkflow(context.Background(), artifact, entities.DefaultSecurityPolicy())
+	if err != nil {
+		t.Fatalf("PerformSecurityWorkflow() error = %v", err)
+	}
+	if result.SecurityReport == nil || result.BinaryAnalysis == nil || result.SBOM == nil || result.Attestation == nil {
+		t.Errorf("result = %+v, want scan report, binary analysis, SBOM and attestation", result)
+	}
+	if result.Blocked {
+		t.Error("result should not be blocked")
+	}
+}
+
+// Test a scan error surfaces and a blocking scan still blocks the build
+func TestSecurityOrchestrator_PerformSecurityWorkflow_ScanErrorAndBlock(t *testing.T) {
+	artifact := &entities.Artifact{Type: "binary", Path: "/tmp/tool"}
+
+	scanErr := errors.New("OSV unavailable")
+	_, err := NewSecurityOrchestrator(&mockSecurityService{scanErr: scanErr}).PerformSecurityWorkflow(context.Background(), artifact, entities.DefaultSecurityPolicy())
+	if !errors.Is(err, scanErr) {
+		t.Errorf("PerformSecurityWorkflow() error = %v, want scan error", err)
+	}
+
+	report := &entities.SecurityReport{Vulnerabilities: []entities.Vulnerability{{ID: "CVE-1", Severity: "CRITICAL"}}}
+	result, err := NewSecurityOrchestrator(&mockSecurityService{report: report, block: true}).PerformSecurityWorkflow(context.Background(), artifact, entities.DefaultSecurityPolicy())
+	if err != nil {
+		t.Fatalf("PerformSecurityWorkflow() error = %v", err)
+	}
+	if !result.Blocked || result.BlockReason != "Build blocked: 1 CRITICAL vulnerabilities found" {
+		t.Errorf("result = %+v, want blocked by the CRITICAL vulnerability", result)
+	}
+	if result.Attestation != nil {
+		t.Error("blocked builds should not be attested")
+	}
+}
+
+// Test only strict-mode incomplete SBOM errors fail the workflow
+func TestSecurityOrchestrator_PerformSecurityWorkflow_IncompleteSBOM(t *testing.T) {
+	artifact := &entities.Artifact{Type: "binary", Path: "/tmp/tool"}
+
+	svc := &mockSecurityService{report: &entities.SecurityReport{Score: 9.5}, sbomErr: errors.New("artifact path does not exist")}
+	result, err := NewSecurityOrchestrator(svc).PerformSecurityWorkflow(context.Background(), artifact, entities.DefaultSecurityPolicy())
+	if err != nil || result.SBOM != nil {
+		t.Fatalf("PerformSecurityWorkflow() = %+v, %v; want best-effort SBOM failure ignored", result, err)
+	}
+
+	svc.sbomErr = fmt.Errorf("SBOM generation failed: %w", entities.ErrIncompleteSBOM)
+	if _, err := NewSecurityOrchestrator(svc).PerformSecurityWorkflow(context.Background(), artifact, entities.DefaultSecurityPolicy()); !errors.Is(err, entities.ErrIncompleteSBOM) {
+		t.Errorf("PerformSecurityWorkflow() error = %v, want ErrIncompleteSBOM", err)
+	}
+}
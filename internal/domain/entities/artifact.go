@@ -8,5 +8,8 @@ type Artifact struct {
 	Platform     string
 	Path         string // Working directory path (extracted or downloaded file)
 	DownloadPath string // Original downloaded file path (for GPG verification)
+	SourceURL    string // Resolved download URL, or git+<repo>@<ref> for git clones
 	Type         string // "binary", "source", "archive", etc.
+	Ecosystem    string // OSV ecosystem; guessed from the name when empty
+	OSVHints     []OSVHint
 }
@@ -19,6 +19,8 @@ type HardeningFeatures struct {
 	CodeSigned      bool   // Code signing (macOS)
 	HardenedRuntime bool   // Hardened runtime (macOS)
 	FortifySource   bool   // FORTIFY_SOURCE (Linux)
+	CFG             bool   // Control Flow Guard (Windows)
+	SafeSEH         bool   // Safe structured exception handlers (Windows)
 }
 
 // SecurityScore represents a calculated security score for a binary
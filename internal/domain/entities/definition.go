@@ -1,5 +1,12 @@
 package entities
 
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
 // Recipe represents a software package recipe from YAML
 type Recipe struct {
 	Name         string
@@ -11,14 +18,50 @@ type Recipe struct {
 	Configure    RecipeBuildStep
 	Build        RecipeBuildStep
 	Dependencies []string
+	// Deprecated marks a recipe whose upstream is no longer maintained
+	Deprecated bool
+	// EOLDate is the upstream end-of-life date; zero when none is declared
+	EOLDate time.Time
+	// LatestAlias mirrors each release's tarballs into a <package>-latest release under version-less names
+	LatestAlias bool
+}
+
+// Retired reports whether the recipe is deprecated or has reached its end-of-life date at now
+func (r *Recipe) Retired(now time.Time) bool {
+	return r.Deprecated || (!r.EOLDate.IsZero() && !now.Before(r.EOLDate))
+}
+
+// LifecycleNote describes the recipe's deprecation and end-of-life status, or "" when neither applies
+func (r *Recipe) LifecycleNote(now time.Time) string {
+	var notes []string
+	if r.Deprecated {
+		notes = append(notes, "deprecated")
+	}
+	if !r.EOLDate.IsZero() {
+		if now.Before(r.EOLDate) {
+			notes = append(notes, "end of life on "+r.EOLDate.Format(time.DateOnly))
+		} else {
+			notes = append(notes, "end of life since "+r.EOLDate.Format(time.DateOnly))
+		}
+	}
+	switch len(notes) {
+	case 0:
+		return ""
+	case 1:
+		return notes[0]
+	default:
+		return notes[0] + ", " + notes[1]
+	}
 }
 
 // VersionConfig represents version fetching and processing configuration
 type VersionConfig struct {
-	Source          string // e.g., "github-release:owner/repo", "url:https://...", "static:latest"
-	ExcludePatterns string // Regex patterns to exclude (alpha, beta, rc, etc.)
-	ExtractPattern  string // Regex to extract version from tag/response
-	Cleanup         string // Sed-like pattern or simple find:replace to clean up version
+	Source          string            // e.g., "github-release:owner/repo", "url:https://...", "json:https://...#path.to.field", "static:latest"
+	ExcludePatterns string            // Regex patterns to exclude (alpha, beta, rc, etc.)
+	ExtractPattern  string            // Regex to extract version from tag/response
+	Cleanup         string            // Sed-like pattern or simple find:replace to clean up version
+	PreferStable    bool              // github-release: pick the highest stable semver release instead of /releases/latest
+	Channels        map[string]string // Named sources selected with "@name" (e.g., "lts": "github-tag:owner/repo#1.20")
 }
 
 // RecipeDownload represents download configuration
@@ -26,18 +69,85 @@ type RecipeDownload struct {
 	OfficialBinary bool
 	DownloadURL    string
 	Mirror         string // Fallback mirror URL (supports {version} placeholder)
+	ChecksumURL    string // Checksum file verified before extraction (supports download_url placeholders)
 	Method         string // "http" (default) or "git"
 	GitURL         string // Git repository URL (when method=git)
 	GitTagPrefix   string // Prefix for git tags (e.g., "v", "llvmorg-")
+	AuthTokenEnv   string // Environment variable holding a token for authenticated downloads
+	AuthScheme     string // Authorization scheme for AuthTokenEnv (default "Bearer")
 	Platforms      map[string]PlatformConfig
+	// ExtractStripComponents drops this many leading path components from each entry of a
+	// tar.gz download, like tar --strip-components
+	ExtractStripComponents int
+	// InnerArchive is a glob naming the one archive (.tar.gz, .tgz or .zip) inside the extracted
+	// download that holds the real contents; it is extracted as well (e.g. "*.zip")
+	InnerArchive string
+	// ChecksumAlgorithm is the digest used by ChecksumURL (sha256, sha512, sha1 or md5); empty
+	// detects SHA-256 or SHA-512 by digest length
+	ChecksumAlgorithm string
+	// AllowWeakChecksum accepts an md5 or sha1 ChecksumAlgorithm
+	AllowWeakChecksum bool
+	// ChecksumFromReleaseBody verifies the download against a "<hash>  <filename>" line in the
+	// github-release description, for projects that publish no checksum file
+	ChecksumFromReleaseBody bool
+	// Headers are sent with the download and its checksum file; values may reference the
+	// value extracted by PreRequest as {pre_request}
+	Headers map[string]string
+	// PreRequest fetches a page before the download to extract a token for Headers; nil disables it
+	PreRequest *DownloadPreRequest
+	// PlatformMap maps a canonical potions platform ("darwin-arm64") to the Platforms key the
+	// recipe uses for it ("macos-aarch64") when the two differ
+	PlatformMap map[string]string
+}
+
+// PlatformKey returns the Platforms key for a canonical potions platform, following PlatformMap
+func (d RecipeDownload) PlatformKey(platform string) string {
+	if key, ok := d.PlatformMap[platform]; ok {
+		return key
+	}
+	return platform
+}
+
+// Platform returns the platform config for a canonical potions platform
+func (d RecipeDownload) Platform(platform string) (PlatformConfig, bool) {
+	cfg, ok := d.Platforms[d.PlatformKey(platform)]
+	return cfg, ok
+}
+
+// CanonicalPlatform returns the potions platform a Platforms key stands for, reversing PlatformMap
+// Platforms are checked in sorted order so a key mapped twice always resolves the same way
+func (d RecipeDownload) CanonicalPlatform(key string) string {
+	for _, platform := range slices.Sorted(maps.Keys(d.PlatformMap)) {
+		if d.PlatformMap[platform] == key {
+			return platform
+		}
+	}
+	return key
+}
+
+// DownloadPreRequest is a preliminary GET whose response yields a value for the download headers
+type DownloadPreRequest struct {
+	URL          string // Page to fetch (supports download_url placeholders)
+	ExtractRegex string // Regex applied to the body; its first capture group (or whole match) is the value
+	ExtractJSON  string // Dotted path to the value in a JSON body, instead of ExtractRegex
+	// Headers are sent with the pre_request only; the download's Authorization is added when URL
+	// is on the download's host, and the download's own Headers are never sent
+	Headers map[string]string
 }
 
 // PlatformConfig represents platform-specific configuration
 type PlatformConfig struct {
-	OS     string
-	Arch   string
-	Suffix string            // Platform-specific suffix for download URLs
-	Custom map[string]string // Custom platform-specific fields for URL templates (e.g., "target": "x86_64-apple-darwin")
+	OS       string
+	Arch     string
+	Suffix   string            // Platform-specific suffix for download URLs
+	Custom   map[string]string // Custom platform-specific fields for URL templates (e.g., "target": "x86_64-apple-darwin")
+	Optional bool              // Best-effort platform: a missing build does not block a release
+	// AssetPattern selects the download from a github-release source's assets by glob
+	// (supports download_url placeholders) instead of templating download_url
+	AssetPattern string
+	// BuildHost restricts building to hosts of an OS ("darwin") or platform ("darwin-arm64");
+	// other hosts skip the platform instead of failing
+	BuildHost string
 }
 
 // RecipeSecurity represents security configuration
@@ -45,8 +155,82 @@ type RecipeSecurity struct {
 	VerifySignature     bool
 	ScanVulnerabilities bool
 	GPGKeyIDs           []string
-	GPGKeysURL          string // URL to project's KEYS file for auto-importing (e.g., Apache KEYS)
-	SignatureURL        string // Custom signature URL (supports {version} placeholder)
+	GPGKeyLookup        GPGKeyLookup // WKD and Keybase sources tried for GPGKeyIDs before the keyservers
+	GPGKeysURL          string       // URL to project's KEYS file for auto-importing (e.g., Apache KEYS)
+	SignatureURL        string       // Custom signature URL (supports {version} placeholder)
+	SignatureExtensions []string     // Extensions tried in order against the download URL when SignatureURL is unset
+	// SignedChecksumsURL names a checksums manifest (e.g. SHA256SUMS) whose GPG signature is verified
+	// instead of the artifact's; the artifact is then checked against its manifest entry ({version} placeholder)
+	SignedChecksumsURL string
+	// OSV lists the packages vulnerability scans query instead of guessing from the artifact name
+	OSV []OSVHint
+	// Platforms overrides scanning and blocking thresholds, keyed by platform ("darwin-arm64") or OS ("darwin")
+	Platforms map[string]SecurityOverride
+}
+
+// Default thresholds at which a vulnerability scan blocks a build
+const (
+	DefaultBlockSeverity    = "CRITICAL"
+	DefaultMinSecurityScore = 5.0
+)
+
+// SecurityOverride changes a recipe's scanning settings for matching target platforms
+// Unset fields fall back to the recipe-wide settings
+type SecurityOverride struct {
+	ScanVulnerabilities *bool
+	MinScore            *float64 // Security score below which the build is blocked
+	BlockSeverity       string   // Lowest vulnerability severity that blocks the build
+}
+
+// SecurityPolicy is the scanning configuration resolved for one target platform
+type SecurityPolicy struct {
+	ScanVulnerabilities bool
+	MinScore            float64
+	BlockSeverity       string
+}
+
+// DefaultSecurityPolicy scans and blocks on CRITICAL vulnerabilities or a score below 5.0
+func DefaultSecurityPolicy() SecurityPolicy {
+	return SecurityPolicy{ScanVulnerabilities: true, MinScore: DefaultMinSecurityScore, BlockSeverity: DefaultBlockSeverity}
+}
+
+// PolicyFor resolves the scanning configuration for platform, preferring an override for the
+// exact platform over one for its OS over the recipe-wide settings
+func (s RecipeSecurity) PolicyFor(platform string) SecurityPolicy {
+	policy := DefaultSecurityPolicy()
+	policy.ScanVulnerabilities = s.ScanVulnerabilities
+
+	goos, _, _ := strings.Cut(platform, "-")
+	for _, key := range []string{goos, platform} {
+		override, ok := s.Platforms[key]
+		if !ok {
+			continue
+		}
+		if override.ScanVulnerabilities != nil {
+			policy.ScanVulnerabilities = *override.ScanVulnerabilities
+		}
+		if override.MinScore != nil {
+			policy.MinScore = *override.MinScore
+		}
+		if override.BlockSeverity != "" {
+			policy.BlockSeverity = override.BlockSeverity
+		}
+	}
+	return policy
+}
+
+// OSVHint names an OSV package to query, or a dependency manifest in the source listing them
+type OSVHint struct {
+	Ecosystem string // OSV ecosystem (e.g., "Go", "crates.io")
+	Package   string // Package name in the ecosystem
+	Version   string // Version to query; defaults to the recipe version
+	Manifest  string // go.mod or Cargo.lock path relative to the source root
+}
+
+// GPGKeyLookup names where a maintainer publishes the gpg_key_ids besides the keyservers
+type GPGKeyLookup struct {
+	WKDEmail    string // Email whose domain serves the key via Web Key Directory
+	KeybaseUser string // Keybase username whose public key is fetched from keybase.io
 }
 
 // RecipeBuildStep represents a build or configure step
@@ -56,4 +240,24 @@ type RecipeBuildStep struct {
 	OutOfTree      bool
 	CustomBuild    string
 	CustomInstall  string
+	VerifyCommand  string // Command run against the packaged binary (supports {version}, {platform})
+	VerifyExpect   string // Optional regex the verify command output must match (supports {version})
+	Requires       []ToolRequirement
+	Platforms      map[string]ScriptOverride // Script overrides keyed by platform ("darwin-arm64") or OS ("darwin")
+	MinSize        int64                     // Smallest acceptable packaged artifact in bytes (0 disables the check)
+	MaxSize        int64                     // Largest acceptable packaged artifact in bytes (0 disables the check)
+}
+
+// ScriptOverride replaces a build step's scripts for matching target platforms
+// Empty fields fall back to the step's default scripts
+type ScriptOverride struct {
+	Script        string
+	CustomBuild   string
+	CustomInstall string
+}
+
+// ToolRequirement declares a minimum toolchain version a source build needs
+type ToolRequirement struct {
+	Tool       string // Executable name (e.g., "go", "cargo")
+	MinVersion string // Minimum version (e.g., "1.22")
 }
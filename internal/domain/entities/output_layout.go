@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// OutputLayout controls how packaged artifacts are arranged under the output directory
+// Artifact file names are always <package>-<version>-<platform>.tar.gz; layouts only nest directories
+type OutputLayout string
+
+const (
+	LayoutFlat             OutputLayout = "flat"               // dist/<package>-<version>-<platform>.tar.gz
+	LayoutByPackage        OutputLayout = "by-package"         // dist/<package>/<package>-<version>-<platform>.tar.gz
+	LayoutByPackageVersion OutputLayout = "by-package-version" // dist/<package>/<version>/<package>-<version>-<platform>.tar.gz
+)
+
+// OutputLayouts lists the supported layouts
+var OutputLayouts = []OutputLayout{LayoutFlat, LayoutByPackage, LayoutByPackageVersion}
+
+// IfExistsPolicy decides what a build does when its final tarball already exists
+type IfExistsPolicy string
+
+const (
+	IfExistsOverwrite IfExistsPolicy = "overwrite" // rebuild and replace the tarball
+	IfExistsSkip      IfExistsPolicy = "skip"      // keep the tarball and report success without rebuilding
+	IfExistsError     IfExistsPolicy = "error"     // fail the build
+)
+
+// IfExistsPolicies lists the supported policies
+var IfExistsPolicies = []IfExistsPolicy{IfExistsOverwrite, IfExistsSkip, IfExistsError}
+
+// ArtifactFileName returns the tarball name of a package build: <package>-<version>-<platform>.tar.gz
+func ArtifactFileName(packageName, version, platform string) string {
+	return fmt.Sprintf("%s-%s-%s.tar.gz", packageName, CanonicalVersion(version), platform)
+}
+
+// ArtifactPath returns where a package build's tarball is placed under base
+func (l OutputLayout) ArtifactPath(base, packageName, version, platform string) string {
+	return filepath.Join(l.Dir(base, packageName, version), ArtifactFileName(packageName, version, platform))
+}
+
+// Dir returns the directory holding a package version's artifacts under base
+// The version is used in canonical form, matching artifact file names
+func (l OutputLayout) Dir(base, packageName, version string) string {
+	switch l {
+	case LayoutByPackage:
+		return filepath.Join(base, packageName)
+	case LayoutByPackageVersion:
+		return filepath.Join(base, packageName, CanonicalVersion(version))
+	default:
+		return base
+	}
+}
@@ -1,6 +1,14 @@
 package entities
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrIncompleteSBOM is returned by strict SBOM generation when a binary resolves no dependencies
+var ErrIncompleteSBOM = errors.New("no dependencies resolved for binary")
 
 // SBOM represents a Software Bill of Materials
 type SBOM struct {
@@ -17,6 +25,31 @@ type Component struct {
 	Name    string
 	Version string
 	Hashes  []Hash
+	PURL    string // Package URL (e.g., "pkg:generic/openssl@3.0.13")
+}
+
+// GenericPURL returns a best-effort package URL for a component without a known ecosystem
+// The version is left out when it is empty or "unknown"
+func GenericPURL(name, version string) string {
+	purl := "pkg:generic/" + escapePURLSegment(name)
+	if version != "" && version != "unknown" {
+		purl += "@" + escapePURLSegment(version)
+	}
+	return purl
+}
+
+// escapePURLSegment percent-encodes everything but unreserved characters, as the purl spec requires
+func escapePURLSegment(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
 }
 
 // Hash represents a cryptographic hash of a component
@@ -15,7 +15,8 @@ type Vulnerability struct {
 	Description string
 	Score       float64 // CVSS score (0.0-10.0)
 	Component   string
-	FixedIn     string // Version where vulnerability is fixed (optional)
+	FixedIn     string     // Version where vulnerability is fixed (optional)
+	Source      *Component // SBOM component (the artifact or a bundled library) the vulnerability was found in
 }
 
 // ScanMetadata contains information about the scan execution
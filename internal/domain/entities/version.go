@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CanonicalVersion returns the stored form of a version, without a leading "v" (e.g., 1.28.0)
+// Artifact file names, output directories and release matching all use this form
+func CanonicalVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+// DisplayVersion returns the display form of a version, with a leading "v" (e.g., v1.28.0)
+func DisplayVersion(version string) string {
+	canonical := CanonicalVersion(version)
+	if canonical == "" {
+		return ""
+	}
+	return "v" + canonical
+}
+
+// CompareVersions compares versions numerically component by component, ignoring a leading "v"
+// and anything after a component's leading digits (e.g., "3rc1" -> 3)
+// Returns: 1 if v1 > v2, -1 if v1 < v2, 0 if equal
+func CompareVersions(v1, v2 string) int {
+	parts1 := strings.Split(CanonicalVersion(v1), ".")
+	parts2 := strings.Split(CanonicalVersion(v2), ".")
+
+	for i := 0; i < len(parts1) || i < len(parts2); i++ {
+		var num1, num2 int
+		if i < len(parts1) {
+			num1 = leadingInt(parts1[i])
+		}
+		if i < len(parts2) {
+			num2 = leadingInt(parts2[i])
+		}
+		if num1 > num2 {
+			return 1
+		} else if num1 < num2 {
+			return -1
+		}
+	}
+	return 0
+}
+
+// leadingInt parses the leading digits of s, or returns 0 if there are none
+func leadingInt(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
+
+// ReleaseTag returns the release tag of a package version: <package>-v<version> (e.g., kubectl-v1.28.0)
+func ReleaseTag(packageName, version string) string {
+	return packageName + "-" + DisplayVersion(version)
+}
+
+// ReleaseTagCandidates returns the tags a released package version may carry: ReleaseTag first,
+// then the unprefixed <package>-<version> form written by earlier batch releases
+func ReleaseTagCandidates(packageName, version string) []string {
+	return []string{ReleaseTag(packageName, version), packageName + "-" + CanonicalVersion(version)}
+}
@@ -0,0 +1,28 @@
+package interfaces
+
+import "time"
+
+// Clock provides the current time, allowing deterministic timestamps in tests
+// and reproducible builds
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+// RealClock returns the system time
+type RealClock struct{}
+
+// Now returns the current system time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same time (useful for tests and SOURCE_DATE_EPOCH)
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now returns the fixed time
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
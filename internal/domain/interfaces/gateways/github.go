@@ -36,6 +36,9 @@ type GitHubGateway interface {
 	// CreateRelease creates a new GitHub release
 	CreateRelease(ctx context.Context, owner, repo string, release *GitHubRelease) (*GitHubRelease, error)
 
+	// UpdateRelease updates an existing release (e.g., to publish a draft)
+	UpdateRelease(ctx context.Context, owner, repo string, releaseID int64, release *GitHubRelease) (*GitHubRelease, error)
+
 	// GetRelease retrieves a release by tag name
 	GetRelease(ctx context.Context, owner, repo, tag string) (*GitHubRelease, error)
 
@@ -47,4 +50,7 @@ type GitHubGateway interface {
 
 	// ListReleases lists all releases in a repository
 	ListReleases(ctx context.Context, owner, repo string) ([]*GitHubRelease, error)
+
+	// DeleteRelease deletes a release and its assets (the git tag is kept)
+	DeleteRelease(ctx context.Context, owner, repo string, releaseID int64) error
 }
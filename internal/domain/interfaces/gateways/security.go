@@ -21,7 +21,8 @@ type SecurityGateway interface {
 	// Verification
 	VerifyChecksum(ctx context.Context, filePath, expectedSum string) error
 	VerifyGPGSignature(ctx context.Context, filePath, sigURL string) error
-	ImportGPGKeys(ctx context.Context, keyIDs []string) error
+	VerifyGPGSignedChecksums(ctx context.Context, filePath, checksumsURL, sigURL string) error
+	ImportGPGKeys(ctx context.Context, keyIDs []string, lookup entities.GPGKeyLookup) error
 	ImportGPGKeysFromURL(ctx context.Context, keysURL string) error
 
 	// Cosign/Sigstore verification
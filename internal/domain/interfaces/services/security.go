@@ -19,5 +19,5 @@ type SecurityService interface {
 	// Business logic
 	CalculateSecurityScore(report *entities.SecurityReport) float64
 	FilterVulnerabilities(vulnerabilities []entities.Vulnerability, minSeverity string) []entities.Vulnerability
-	ShouldBlockBuild(report *entities.SecurityReport) bool
+	ShouldBlockBuild(report *entities.SecurityReport, policy entities.SecurityPolicy) bool
 }
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// osvQuery is one package version to look up in OSV
+type osvQuery struct {
+	Ecosystem string
+	Package   string
+	Version   string
+}
+
+// buildOSVQueries expands a recipe's OSV hints into queries: package hints as given (defaulting
+// to version), manifest hints into one query per dependency listed in sourceDir's manifest
+func buildOSVQueries(hints []entities.OSVHint, sourceDir, version string) ([]osvQuery, error) {
+	var queries []osvQuery
+	seen := make(map[osvQuery]bool)
+	add := func(query osvQuery) {
+		if !seen[query] {
+			seen[query] = true
+			queries = append(queries, query)
+		}
+	}
+
+	for _, hint := range hints {
+		if hint.Manifest == "" {
+			if hint.Ecosystem == "" || hint.Package == "" {
+				return nil, fmt.Errorf("osv hint needs an ecosystem and package, or a manifest")
+			}
+			hintVersion := hint.Version
+			if hintVersion == "" {
+				hintVersion = version
+			}
+			add(osvQuery{Ecosystem: hint.Ecosystem, Package: hint.Package, Version: entities.CanonicalVersion(hintVersion)})
+			continue
+		}
+
+		if !filepath.IsLocal(hint.Manifest) {
+			return nil, fmt.Errorf("osv manifest %q must be a relative path inside the source", hint.Manifest)
+		}
+		//nolint:gosec // G304: manifest path is checked to stay inside the source directory
+		data, err := os.ReadFile(filepath.Join(sourceDir, hint.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read osv manifest: %w", err)
+		}
+		var manifestQueries []osvQuery
+		switch filepath.Base(hint.Manifest) {
+		case "go.mod":
+			manifestQueries = parseGoModQueries(data)
+		case "Cargo.lock":
+			manifestQueries = parseCargoLockQueries(data)
+		default:
+			return nil, fmt.Errorf("unsupported osv manifest %q (supported: go.mod, Cargo.lock)", hint.Manifest)
+		}
+		for _, query := range manifestQueries {
+			add(query)
+		}
+	}
+	return queries, nil
+}
+
+// parseGoModQueries returns a Go query for each module in a go.mod's require directives
+func parseGoModQueries(data []byte) []osvQuery {
+	var queries []osvQuery
+	inRequire := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case !inRequire:
+			continue
+		}
+		if len(fields) >= 2 {
+			queries = append(queries, osvQuery{Ecosystem: "Go", Package: fields[0], Version: entities.CanonicalVersion(fields[1])})
+		}
+	}
+	return queries
+}
+
+// parseCargoLockQueries returns a crates.io query for each registry package in a Cargo.lock;
+// workspace crates have no source and are not published, so they are skipped
+func parseCargoLockQueries(data []byte) []osvQuery {
+	var queries []osvQuery
+	var name, version string
+	var fromRegistry bool
+	flush := func() {
+		if name != "" && version != "" && fromRegistry {
+			queries = append(queries, osvQuery{Ecosystem: "crates.io", Package: name, Version: version})
+		}
+		name, version, fromRegistry = "", "", false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.TrimSpace(key) {
+		case "name":
+			name = value
+		case "version":
+			version = value
+		case "source":
+			fromRegistry = strings.HasPrefix(value, "registry+")
+		}
+	}
+	flush()
+	return queries
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+const sampleGoMod = `module github.com/example/tool
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	golang.org/x/net v0.23.0
+	golang.org/x/sys v0.18.0 // indirect
+	// a comment line
+)
+
+replace golang.org/x/net => ../net
+`
+
+// Test a go.mod manifest hint becomes one Go query per required module, next to package hints
+func TestBuildOSVQueries_GoMod(t *testing.T) {
+	sourceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sourceDir, "go.mod"), []byte(sampleGoMod), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	queries, err := buildOSVQueries([]entities.OSVHint{
+		{Ecosystem: "Go", Package: "github.com/example/tool"},
+		{Manifest: "go.mod"},
+	}, sourceDir, "v2.1.0")
+	if err != nil {
+		t.Fatalf("buildOSVQueries() error = %v", err)
+	}
+
+	want := []osvQuery{
+		{Ecosystem: "Go", Package: "github.com/example/tool", Version: "2.1.0"},
+		{Ecosystem: "Go", Package: "github.com/spf13/cobra", Version: "1.8.0"},
+		{Ecosystem: "Go", Package: "golang.org/x/net", Version: "0.23.0"},
+		{Ecosystem: "Go", Package: "golang.org/x/sys", Version: "0.18.0"},
+	}
+	if fmt.Sprint(queries) != fmt.Sprint(want) {
+		t.Errorf("queries = %v, want %v", queries, want)
+	}
+
+	for _, hints := range [][]entities.OSVHint{
+		{{Manifest: "../go.mod"}},
+		{{Manifest: "package.json"}},
+		{{Ecosystem: "Go"}},
+	} {
+		if _, err := buildOSVQueries(hints, sourceDir, "1.0.0"); err == nil {
+			t.Errorf("buildOSVQueries(%+v) should fail", hints)
+		}
+	}
+}
+
+// Test Cargo.lock hints query registry crates only
+func TestParseCargoLockQueries(t *testing.T) {
+	lock := `version = 3
+
+[[package]]
+name = "tool"
+version = "0.1.0"
+
+[[package]]
+name = "serde"
+version = "1.0.197"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+	queries := parseCargoLockQueries([]byte(lock))
+	want := []osvQuery{{Ecosystem: "crates.io", Package: "serde", Version: "1.0.197"}}
+	if fmt.Sprint(queries) != fmt.Sprint(want) {
+		t.Errorf("queries = %v, want %v", queries, want)
+	}
+}
+
+// Test a scan with hints queries each hinted module in its ecosystem instead of the artifact name
+func TestPerformSecurityScan_OSVHints(t *testing.T) {
+	sourceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sourceDir, "go.mod"), []byte(sampleGoMod), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mockGW := &mockSecurityGateway{
+		scanByArtifact: map[string]*entities.SecurityReport{
+			"golang.org/x/net@0.23.0": {Vulnerabilities: []entities.Vulnerability{{ID: "GO-2024-2687", Severity: "MEDIUM"}}},
+		},
+		scanResult: &entities.SecurityReport{},
+	}
+	svc := NewSecurityService(mockGW)
+
+	report, err := svc.PerformSecurityScan(context.Background(), &entities.Artifact{
+		Name: "tool", Version: "1.0.0", Path: sourceDir, OSVHints: []entities.OSVHint{{Manifest: "go.mod"}},
+	})
+	if err != nil {
+		t.Fatalf("PerformSecurityScan() error = %v", err)
+	}
+
+	if len(mockGW.scanned) != 3 {
+		t.Fatalf("scanned %d packages, want one per go.mod module", len(mockGW.scanned))
+	}
+	for _, artifact := range mockGW.scanned {
+		if artifact.Ecosystem != "Go" || artifact.Name == "tool" {
+			t.Errorf("scanned %s (ecosystem %q), want only go.mod modules in Go", artifact.Name, artifact.Ecosystem)
+		}
+	}
+	if len(report.Vulnerabilities) != 1 || report.Vulnerabilities[0].Component != "golang.org/x/net@0.23.0" {
+		t.Errorf("Vulnerabilities = %+v, want GO-2024-2687 on golang.org/x/net@0.23.0", report.Vulnerabilities)
+	}
+	if report.Score != 9.0 {
+		t.Errorf("Score = %v, want 9.0", report.Score)
+	}
+}
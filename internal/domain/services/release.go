@@ -3,6 +3,7 @@ package services
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ochairo/potions/internal/domain/entities"
@@ -17,6 +18,12 @@ const (
 	PlatformLinuxARM64  Platform = "linux-arm64"
 	PlatformDarwinAMD64 Platform = "darwin-x86_64"
 	PlatformDarwinARM64 Platform = "darwin-arm64"
+
+	// Additional Linux architectures; recipes may declare any "<os>-<arch>" key
+	PlatformLinuxARMv7   Platform = "linux-armv7"
+	PlatformLinuxPPC64LE Platform = "linux-ppc64le"
+	PlatformLinuxS390X   Platform = "linux-s390x"
+	PlatformLinuxRISCV64 Platform = "linux-riscv64"
 )
 
 // ReleaseStatus represents the readiness status of a package for release
@@ -37,13 +44,16 @@ const (
 
 // ReleaseValidation contains the validation result for a package release
 type ReleaseValidation struct {
-	Status              ReleaseStatus
-	ExpectedPlatforms   []Platform
-	AvailablePlatforms  []Platform
-	MissingPlatforms    []Platform
-	UnexpectedPlatforms []Platform
-	ExpectedCount       int
-	AvailableCount      int
+	Status                   ReleaseStatus
+	ExpectedPlatforms        []Platform
+	OptionalPlatforms        []Platform // Expected platforms whose absence never blocks a release
+	AvailablePlatforms       []Platform
+	MissingPlatforms         []Platform // Required platforms that are missing
+	OptionalMissingPlatforms []Platform // Optional platforms that are missing
+	UnexpectedPlatforms      []Platform
+	MismatchedVersions       []string // Versions of the package's tarballs that differ from the requested one
+	ExpectedCount            int
+	AvailableCount           int
 }
 
 // IsReady returns true if the package is ready for release
@@ -51,17 +61,44 @@ func (rv *ReleaseValidation) IsReady() bool {
 	return rv.Status == StatusReady
 }
 
-// ErrorMessage returns a human-readable error message if not ready
-func (rv *ReleaseValidation) ErrorMessage(_, _ string) string {
-	// Calculate minimum required to match validation logic
-	minRequired := rv.ExpectedCount / 2
+// requiredCount returns the number of expected platforms that are not optional
+func (rv *ReleaseValidation) requiredCount() int {
+	return rv.ExpectedCount - len(rv.OptionalPlatforms)
+}
+
+// availableRequiredCount returns the number of available platforms that are not optional
+func (rv *ReleaseValidation) availableRequiredCount() int {
+	optional := make(map[Platform]bool, len(rv.OptionalPlatforms))
+	for _, p := range rv.OptionalPlatforms {
+		optional[p] = true
+	}
+	count := 0
+	for _, p := range rv.AvailablePlatforms {
+		if !optional[p] {
+			count++
+		}
+	}
+	return count
+}
+
+// minRequiredPlatforms returns how many required platforms a partial release needs:
+// at least 50% of them (minimum 1, but 2 if 4 or more are required)
+func minRequiredPlatforms(required int) int {
+	if required == 0 {
+		return 0
+	}
+	minRequired := required / 2
 	if minRequired < 1 {
 		minRequired = 1
 	}
-	if rv.ExpectedCount >= 4 && minRequired < 2 {
+	if required >= 4 && minRequired < 2 {
 		minRequired = 2
 	}
+	return minRequired
+}
 
+// ErrorMessage returns a human-readable error message if not ready
+func (rv *ReleaseValidation) ErrorMessage(_, _ string) string {
 	switch rv.Status {
 	case StatusReady:
 		return ""
@@ -69,7 +106,11 @@ func (rv *ReleaseValidation) ErrorMessage(_, _ string) string {
 		return fmt.Sprintf("No artifacts found (expected: %d platforms)", rv.ExpectedCount)
 	case StatusPlatformMismatch:
 		msg := fmt.Sprintf("Insufficient platforms (expected: %d, have: %d, need: minimum %d)",
-			rv.ExpectedCount, rv.AvailableCount, minRequired)
+			rv.ExpectedCount, rv.AvailableCount, minRequiredPlatforms(rv.ExpectedCount))
+		if len(rv.OptionalPlatforms) > 0 {
+			msg = fmt.Sprintf("Insufficient required platforms (required: %d, have: %d, need: minimum %d)",
+				rv.requiredCount(), rv.availableRequiredCount(), minRequiredPlatforms(rv.requiredCount()))
+		}
 		if len(rv.MissingPlatforms) > 0 {
 			msg += fmt.Sprintf("\n   Missing: %s", platformsToString(rv.MissingPlatforms))
 		}
@@ -82,17 +123,31 @@ func (rv *ReleaseValidation) ErrorMessage(_, _ string) string {
 		return msg
 	case StatusUnexpectedPlatforms:
 		return fmt.Sprintf("Unexpected platforms found: %s", platformsToString(rv.UnexpectedPlatforms))
+	case StatusVersionMismatch:
+		msg := fmt.Sprintf("Artifacts found for a different version (found: %s)", strings.Join(rv.MismatchedVersions, ", "))
+		if len(rv.AvailablePlatforms) > 0 {
+			msg += fmt.Sprintf("\n   Available: %s", platformsToString(rv.AvailablePlatforms))
+		}
+		return msg
 	default:
 		return "Unknown status"
 	}
 }
 
 // ReleaseService handles release validation logic
-type ReleaseService struct{}
+type ReleaseService struct {
+	layout entities.OutputLayout
+}
 
-// NewReleaseService creates a new release service
+// NewReleaseService creates a new release service for the flat output layout
 func NewReleaseService() *ReleaseService {
-	return &ReleaseService{}
+	return &ReleaseService{layout: entities.LayoutFlat}
+}
+
+// WithOutputLayout only counts tarballs found in the package directory of the given layout
+func (s *ReleaseService) WithOutputLayout(layout entities.OutputLayout) *ReleaseService {
+	s.layout = layout
+	return s
 }
 
 // ValidateRelease validates if a package is ready for release based on recipe and available artifacts
@@ -106,26 +161,37 @@ func (s *ReleaseService) ValidateRelease(recipe *entities.Recipe, packageName, v
 	// Extract available platforms from artifact paths
 	validation.AvailablePlatforms = s.extractAvailablePlatforms(packageName, version, artifactPaths)
 	validation.AvailableCount = len(validation.AvailablePlatforms)
+	validation.MismatchedVersions = s.extractMismatchedVersions(packageName, version, artifactPaths)
 
-	// Determine missing and unexpected platforms
-	validation.MissingPlatforms = s.findMissingPlatforms(validation.ExpectedPlatforms, validation.AvailablePlatforms)
+	// Optional platforms are released when present but never count toward the threshold
+	validation.OptionalPlatforms = s.extractOptionalPlatforms(recipe)
+	optional := make(map[Platform]bool, len(validation.OptionalPlatforms))
+	for _, p := range validation.OptionalPlatforms {
+		optional[p] = true
+	}
+
+	// Determine missing (split into required and optional) and unexpected platforms
+	for _, p := range s.findMissingPlatforms(validation.ExpectedPlatforms, validation.AvailablePlatforms) {
+		if optional[p] {
+			validation.OptionalMissingPlatforms = append(validation.OptionalMissingPlatforms, p)
+		} else {
+			validation.MissingPlatforms = append(validation.MissingPlatforms, p)
+		}
+	}
 	validation.UnexpectedPlatforms = s.findUnexpectedPlatforms(validation.ExpectedPlatforms, validation.AvailablePlatforms)
 
 	// Determine status
-	// Allow partial releases: require at least 50% of expected platforms (minimum 1, but prefer 2 if 4 expected)
-	minRequired := validation.ExpectedCount / 2
-	if minRequired < 1 {
-		minRequired = 1 // At least one platform
-	}
-	// For packages with 4 platforms, require at least 2
-	if validation.ExpectedCount >= 4 && minRequired < 2 {
-		minRequired = 2
-	}
+	// Allow partial releases: require at least 50% of required platforms (minimum 1, but prefer 2 if 4 expected)
+	minRequired := minRequiredPlatforms(validation.requiredCount())
 
 	switch {
+	case len(validation.MismatchedVersions) > 0 &&
+		(validation.AvailableCount == 0 || validation.availableRequiredCount() < minRequired):
+		// Tarballs exist but at another version: the build produced the wrong (or mixed) versions
+		validation.Status = StatusVersionMismatch
 	case validation.AvailableCount == 0:
 		validation.Status = StatusNoArtifacts
-	case validation.AvailableCount < minRequired:
+	case validation.availableRequiredCount() < minRequired:
 		validation.Status = StatusPlatformMismatch
 	case len(validation.UnexpectedPlatforms) > 0:
 		// Unexpected platforms indicate a mismatch between recipe and build
@@ -143,7 +209,7 @@ func (s *ReleaseService) extractExpectedPlatforms(recipe *entities.Recipe) []Pla
 	var platforms []Platform
 
 	for platformKey := range recipe.Download.Platforms {
-		platform := s.recipePlatformToStandard(platformKey)
+		platform := s.recipePlatformToStandard(recipe.Download.CanonicalPlatform(platformKey))
 		if platform != "" {
 			platforms = append(platforms, platform)
 		}
@@ -152,30 +218,52 @@ func (s *ReleaseService) extractExpectedPlatforms(recipe *entities.Recipe) []Pla
 	return platforms
 }
 
+// extractOptionalPlatforms returns the recipe platforms marked optional
+func (s *ReleaseService) extractOptionalPlatforms(recipe *entities.Recipe) []Platform {
+	var platforms []Platform
+
+	for platformKey, cfg := range recipe.Download.Platforms {
+		if !cfg.Optional {
+			continue
+		}
+		if platform := s.recipePlatformToStandard(recipe.Download.CanonicalPlatform(platformKey)); platform != "" {
+			platforms = append(platforms, platform)
+		}
+	}
+
+	return platforms
+}
+
 // recipePlatformToStandard maps recipe platform names to standard platform identifiers
-// Both recipes and artifacts use the same naming: linux-amd64, linux-arm64, darwin-x86_64, darwin-arm64
+// Recipe keys and artifact suffixes share the same "<os>-<arch>" naming (e.g. linux-amd64,
+// darwin-x86_64, linux-armv7, linux-ppc64le), so any well-formed key is accepted as-is
 func (s *ReleaseService) recipePlatformToStandard(recipePlatform string) Platform {
-	switch recipePlatform {
-	case "linux-amd64":
-		return PlatformLinuxAMD64
-	case "linux-arm64":
-		return PlatformLinuxARM64
-	case "darwin-x86_64":
-		return PlatformDarwinAMD64
-	case "darwin-arm64":
-		return PlatformDarwinARM64
-	default:
+	if !isPlatformName(recipePlatform) {
 		return ""
 	}
+	return Platform(recipePlatform)
+}
+
+// inLayoutDir reports whether path sits where the output layout places packageName's artifacts
+func (s *ReleaseService) inLayoutDir(path, packageName, version string) bool {
+	dir := filepath.Dir(path)
+	switch s.layout {
+	case entities.LayoutByPackage:
+		return filepath.Base(dir) == packageName
+	case entities.LayoutByPackageVersion:
+		return filepath.Base(dir) == version && filepath.Base(filepath.Dir(dir)) == packageName
+	default:
+		return true
+	}
 }
 
 // extractAvailablePlatforms extracts platforms from artifact filenames
-// Expected format: packageName-version-platform.tar.gz
+// Expected format: packageName-version-platform.tar.gz, inside the layout's package directory
 func (s *ReleaseService) extractAvailablePlatforms(packageName, version string, artifactPaths []string) []Platform {
 	platformSet := make(map[Platform]bool)
 
 	// Clean version (remove 'v' prefix if present)
-	versionClean := strings.TrimPrefix(version, "v")
+	versionClean := entities.CanonicalVersion(version)
 
 	// Look for .tar.gz files only (not checksums or metadata)
 	for _, path := range artifactPaths {
@@ -192,28 +280,16 @@ func (s *ReleaseService) extractAvailablePlatforms(packageName, version string,
 			continue
 		}
 
-		// Extract platform from filename by looking at the last segment before .tar.gz
-		// This handles packages with dashes in the name correctly
-		// Format: package-version-platform.tar.gz
-		nameWithoutExt := strings.TrimSuffix(basename, ".tar.gz")
-
-		// Try each known platform as a suffix
-		var matchedPlatform Platform
-		for _, knownPlatform := range []Platform{
-			PlatformLinuxAMD64,
-			PlatformLinuxARM64,
-			PlatformDarwinAMD64,
-			PlatformDarwinARM64,
-		} {
-			suffix := "-" + string(knownPlatform)
-			if strings.HasSuffix(nameWithoutExt, suffix) {
-				matchedPlatform = knownPlatform
-				break
-			}
+		// Nested layouts must hold the tarball in its own package (and version) directory
+		if !s.inLayoutDir(path, packageName, versionClean) {
+			continue
 		}
 
-		if matchedPlatform != "" {
-			platformSet[matchedPlatform] = true
+		// Everything between the package-version prefix and .tar.gz is the platform
+		// This handles packages with dashes in the name correctly
+		platform := strings.TrimSuffix(strings.TrimPrefix(basename, expectedPrefix), ".tar.gz")
+		if isPlatformName(platform) {
+			platformSet[Platform(platform)] = true
 		}
 	}
 
@@ -226,6 +302,52 @@ func (s *ReleaseService) extractAvailablePlatforms(packageName, version string,
 	return platforms
 }
 
+// extractMismatchedVersions returns the distinct versions of packageName's tarballs that differ
+// from the requested version, in the format packageName-otherVersion-platform.tar.gz
+func (s *ReleaseService) extractMismatchedVersions(packageName, version string, artifactPaths []string) []string {
+	versionClean := entities.CanonicalVersion(version)
+	seen := make(map[string]bool)
+	var versions []string
+
+	for _, path := range artifactPaths {
+		basename := filepath.Base(path)
+		if !strings.HasSuffix(basename, ".tar.gz") || !strings.HasPrefix(basename, packageName+"-") {
+			continue
+		}
+
+		// The platform is the final "<os>-<arch>" pair; whatever precedes it is the version
+		rest := strings.TrimSuffix(strings.TrimPrefix(basename, packageName+"-"), ".tar.gz")
+		archSep := strings.LastIndex(rest, "-")
+		if archSep < 0 {
+			continue
+		}
+		osSep := strings.LastIndex(rest[:archSep], "-")
+		if osSep < 0 || !isPlatformName(rest[osSep+1:]) {
+			continue
+		}
+
+		// Versions start with a digit, which keeps "tool-extra-..." from counting as a "tool" version
+		found := entities.CanonicalVersion(rest[:osSep])
+		if found == "" || found == versionClean || found[0] < '0' || found[0] > '9' {
+			continue
+		}
+		if !s.inLayoutDir(path, packageName, found) || seen[found] {
+			continue
+		}
+		seen[found] = true
+		versions = append(versions, found)
+	}
+
+	sort.Strings(versions)
+	return versions
+}
+
+// isPlatformName reports whether name has the "<os>-<arch>" form
+func isPlatformName(name string) bool {
+	osName, arch, found := strings.Cut(name, "-")
+	return found && osName != "" && arch != "" && !strings.ContainsAny(arch, "-/.")
+}
+
 // findMissingPlatforms returns platforms that are expected but not available
 func (s *ReleaseService) findMissingPlatforms(expected, available []Platform) []Platform {
 	availableSet := make(map[Platform]bool)
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ochairo/potions/internal/domain/entities"
@@ -183,6 +185,10 @@ func TestRecipePlatformMapping(t *testing.T) {
 		{"linux-arm64", PlatformLinuxARM64},
 		{"darwin-x86_64", PlatformDarwinAMD64},
 		{"darwin-arm64", PlatformDarwinARM64},
+		{"linux-armv7", PlatformLinuxARMv7},
+		{"linux-ppc64le", PlatformLinuxPPC64LE},
+		{"linux-s390x", PlatformLinuxS390X},
+		{"linux-riscv64", PlatformLinuxRISCV64},
 		{"unknown", ""},
 	}
 
@@ -250,6 +256,25 @@ func TestExtractAvailablePlatforms(t *testing.T) {
 			artifactPaths: []string{},
 			expected:      []Platform{},
 		},
+		{
+			name:        "additional architectures",
+			packageName: "kubectl",
+			version:     "v1.28.0",
+			artifactPaths: []string{
+				"kubectl-1.28.0-linux-ppc64le.tar.gz",
+				"kubectl-1.28.0-linux-armv7.tar.gz",
+			},
+			expected: []Platform{PlatformLinuxPPC64LE, PlatformLinuxARMv7},
+		},
+		{
+			name:        "package name with dashes",
+			packageName: "docker-compose",
+			version:     "2.20.0",
+			artifactPaths: []string{
+				"docker-compose-2.20.0-linux-s390x.tar.gz",
+			},
+			expected: []Platform{PlatformLinuxS390X},
+		},
 		{
 			name:        "wrong package name ignored",
 			packageName: "kubectl",
@@ -292,3 +317,197 @@ func TestExtractAvailablePlatforms(t *testing.T) {
 		})
 	}
 }
+
+// Test platforms are only extracted from tarballs placed in the output layout's package directory
+func TestExtractAvailablePlatforms_OutputLayouts(t *testing.T) {
+	paths := []string{
+		filepath.Join("dist", "tool-1.0.0-linux-amd64.tar.gz"),
+		filepath.Join("dist", "tool", "tool-1.0.0-linux-arm64.tar.gz"),
+		filepath.Join("dist", "tool", "1.0.0", "tool-1.0.0-darwin-arm64.tar.gz"),
+		filepath.Join("dist", "tool", "0.9.0", "tool-1.0.0-darwin-x86_64.tar.gz"),
+	}
+
+	tests := []struct {
+		layout   entities.OutputLayout
+		expected []Platform
+	}{
+		{entities.LayoutFlat, []Platform{PlatformLinuxAMD64, PlatformLinuxARM64, PlatformDarwinARM64, PlatformDarwinAMD64}},
+		{entities.LayoutByPackage, []Platform{PlatformLinuxARM64}},
+		{entities.LayoutByPackageVersion, []Platform{PlatformDarwinARM64}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.layout), func(t *testing.T) {
+			result := NewReleaseService().WithOutputLayout(tt.layout).extractAvailablePlatforms("tool", "v1.0.0", paths)
+			found := make(map[Platform]bool)
+			for _, p := range result {
+				found[p] = true
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("extractAvailablePlatforms() = %v, want %v", result, tt.expected)
+			}
+			for _, p := range tt.expected {
+				if !found[p] {
+					t.Errorf("extractAvailablePlatforms() = %v, missing %v", result, p)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRelease_AdditionalArchitectures(t *testing.T) {
+	recipe := &entities.Recipe{
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":   {},
+				"linux-ppc64le": {},
+			},
+		},
+	}
+
+	service := NewReleaseService()
+
+	t.Run("ppc64le artifact is validated against recipe platforms", func(t *testing.T) {
+		validation := service.ValidateRelease(recipe, "tool", "v1.0.0", []string{
+			"tool-1.0.0-linux-amd64.tar.gz",
+			"tool-1.0.0-linux-ppc64le.tar.gz",
+		})
+		if !validation.IsReady() {
+			t.Fatalf("Status = %v, want %v", validation.Status, StatusReady)
+		}
+		if validation.ExpectedCount != 2 || validation.AvailableCount != 2 {
+			t.Errorf("Expected/Available = %d/%d, want 2/2", validation.ExpectedCount, validation.AvailableCount)
+		}
+	})
+
+	t.Run("undeclared architecture is unexpected", func(t *testing.T) {
+		validation := service.ValidateRelease(recipe, "tool", "v1.0.0", []string{
+			"tool-1.0.0-linux-amd64.tar.gz",
+			"tool-1.0.0-linux-ppc64le.tar.gz",
+			"tool-1.0.0-linux-riscv64.tar.gz",
+		})
+		if validation.Status != StatusUnexpectedPlatforms {
+			t.Fatalf("Status = %v, want %v", validation.Status, StatusUnexpectedPlatforms)
+		}
+		if len(validation.UnexpectedPlatforms) != 1 || validation.UnexpectedPlatforms[0] != PlatformLinuxRISCV64 {
+			t.Errorf("UnexpectedPlatforms = %v, want [%s]", validation.UnexpectedPlatforms, PlatformLinuxRISCV64)
+		}
+	})
+}
+
+// Test optional platforms never block a release and are reported separately when missing
+func TestValidateRelease_OptionalPlatforms(t *testing.T) {
+	recipe := &entities.Recipe{
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":   {},
+				"linux-arm64":   {},
+				"darwin-x86_64": {Optional: true},
+				"darwin-arm64":  {Optional: true},
+			},
+		},
+	}
+
+	service := NewReleaseService()
+
+	t.Run("optional platforms missing stays ready", func(t *testing.T) {
+		validation := service.ValidateRelease(recipe, "tool", "v1.0.0", []string{
+			"tool-1.0.0-linux-amd64.tar.gz",
+			"tool-1.0.0-linux-arm64.tar.gz",
+		})
+		if !validation.IsReady() {
+			t.Fatalf("Status = %v, want %v", validation.Status, StatusReady)
+		}
+		if len(validation.MissingPlatforms) != 0 {
+			t.Errorf("MissingPlatforms = %v, want none", validation.MissingPlatforms)
+		}
+		if len(validation.OptionalMissingPlatforms) != 2 {
+			t.Errorf("OptionalMissingPlatforms = %v, want both darwin platforms", validation.OptionalMissingPlatforms)
+		}
+	})
+
+	t.Run("present optional platforms are included", func(t *testing.T) {
+		validation := service.ValidateRelease(recipe, "tool", "v1.0.0", []string{
+			"tool-1.0.0-linux-amd64.tar.gz",
+			"tool-1.0.0-linux-arm64.tar.gz",
+			"tool-1.0.0-darwin-arm64.tar.gz",
+		})
+		if !validation.IsReady() || validation.AvailableCount != 3 {
+			t.Fatalf("Status/Available = %v/%d, want ready with 3 platforms", validation.Status, validation.AvailableCount)
+		}
+		if len(validation.OptionalMissingPlatforms) != 1 || validation.OptionalMissingPlatforms[0] != PlatformDarwinAMD64 {
+			t.Errorf("OptionalMissingPlatforms = %v, want [%s]", validation.OptionalMissingPlatforms, PlatformDarwinAMD64)
+		}
+	})
+
+	t.Run("required platforms missing is a mismatch", func(t *testing.T) {
+		validation := service.ValidateRelease(recipe, "tool", "v1.0.0", []string{
+			"tool-1.0.0-darwin-x86_64.tar.gz",
+			"tool-1.0.0-darwin-arm64.tar.gz",
+		})
+		if validation.Status != StatusPlatformMismatch {
+			t.Fatalf("Status = %v, want %v (optional builds must not satisfy the threshold)", validation.Status, StatusPlatformMismatch)
+		}
+		if len(validation.MissingPlatforms) != 2 || len(validation.OptionalMissingPlatforms) != 0 {
+			t.Errorf("Missing/OptionalMissing = %v/%v, want both linux platforms required-missing",
+				validation.MissingPlatforms, validation.OptionalMissingPlatforms)
+		}
+		if msg := validation.ErrorMessage("tool", "v1.0.0"); !strings.Contains(msg, "required: 2, have: 0, need: minimum 1") {
+			t.Errorf("ErrorMessage() = %q, want required platform counts", msg)
+		}
+	})
+}
+
+// Test platform_map keys are matched against artifacts under their canonical potions platform
+func TestValidateRelease_PlatformMap(t *testing.T) {
+	recipe := &entities.Recipe{
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":   {},
+				"macos-aarch64": {Optional: true},
+			},
+			PlatformMap: map[string]string{"darwin-arm64": "macos-aarch64"},
+		},
+	}
+
+	validation := NewReleaseService().ValidateRelease(recipe, "tool", "v1.0.0", []string{
+		"tool-1.0.0-linux-amd64.tar.gz",
+		"tool-1.0.0-darwin-arm64.tar.gz",
+	})
+	if !validation.IsReady() || validation.AvailableCount != 2 {
+		t.Fatalf("Status/Available = %v/%d, want ready with 2 platforms", validation.Status, validation.AvailableCount)
+	}
+	if len(validation.UnexpectedPlatforms) != 0 || len(validation.OptionalMissingPlatforms) != 0 {
+		t.Errorf("Unexpected/OptionalMissing = %v/%v, want darwin-arm64 matched through platform_map",
+			validation.UnexpectedPlatforms, validation.OptionalMissingPlatforms)
+	}
+}
+
+// Test tarballs built at a different version are reported as a version mismatch
+func TestValidateRelease_VersionMismatch(t *testing.T) {
+	recipe := &entities.Recipe{
+		Download: entities.RecipeDownload{
+			Platforms: map[string]entities.PlatformConfig{
+				"linux-amd64":  {},
+				"darwin-arm64": {},
+			},
+		},
+	}
+
+	validation := NewReleaseService().ValidateRelease(recipe, "tool", "v1.1.0", []string{
+		"dist/tool-1.0.0-linux-amd64.tar.gz",
+		"dist/tool-1.0.0-darwin-arm64.tar.gz",
+		"dist/tool-1.0.0-darwin-arm64.tar.gz.sha256",
+		"dist/tool-extra-2.0.0-linux-amd64.tar.gz",
+	})
+
+	if validation.Status != StatusVersionMismatch {
+		t.Fatalf("Status = %v, want %v", validation.Status, StatusVersionMismatch)
+	}
+	if strings.Join(validation.MismatchedVersions, ",") != "1.0.0" {
+		t.Errorf("MismatchedVersions = %v, want [1.0.0]", validation.MismatchedVersions)
+	}
+	if msg := validation.ErrorMessage("tool", "v1.1.0"); !strings.Contains(msg, "found: 1.0.0") {
+		t.Errorf("ErrorMessage() = %q, want the mismatched version listed", msg)
+	}
+}
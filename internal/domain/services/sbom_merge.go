@@ -0,0 +1,199 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ochairo/potions/internal/domain/entities"
+)
+
+// cycloneDXDocument is the subset of a CycloneDX JSON SBOM read and written by MergeSBOMs
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies,omitempty"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp,omitempty"`
+	Component *cycloneDXComponent `json:"component,omitempty"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string               `json:"bom-ref,omitempty"`
+	Type       string               `json:"type"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version,omitempty"`
+	PURL       string               `json:"purl,omitempty"`
+	Hashes     []cycloneDXHash      `json:"hashes,omitempty"`
+	Components []cycloneDXComponent `json:"components,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// MergedSBOMName returns the file name of the aggregate SBOM for a release, using the canonical
+// version so it matches the tarball names whether or not version carries a "v"
+func MergedSBOMName(packageName, version string) string {
+	return fmt.Sprintf("%s-%s.sbom.json", packageName, entities.CanonicalVersion(version))
+}
+
+// MergeSBOMs combines per-artifact CycloneDX SBOMs into one release SBOM written to dir
+// Each artifact becomes a top-level application component with its own libraries nested
+// under it; libraries shared by several artifacts are listed once at the top level, and the
+// dependencies section records which artifacts use which libraries
+func (s *SecurityArtifactsService) MergeSBOMs(dir, packageName, version string, sbomPaths []string) (string, error) {
+	if len(sbomPaths) == 0 {
+		return "", fmt.Errorf("no SBOMs to merge")
+	}
+
+	type mergedApp struct {
+		component cycloneDXComponent
+		libraries []cycloneDXComponent
+	}
+
+	paths := append([]string(nil), sbomPaths...)
+	sort.Strings(paths)
+
+	apps := make([]mergedApp, 0, len(paths))
+	usage := make(map[string]int)
+	for _, path := range paths {
+		doc, err := readCycloneDX(path)
+		if err != nil {
+			return "", err
+		}
+
+		app := mergedApp{component: cycloneDXComponent{
+			Type: "application",
+			Name: strings.TrimSuffix(filepath.Base(path), ".sbom.json"),
+		}}
+		if doc.Metadata.Component != nil && doc.Metadata.Component.Name != "" {
+			app.component.Name = doc.Metadata.Component.Name
+			app.component.Version = doc.Metadata.Component.Version
+			app.component.PURL = doc.Metadata.Component.PURL
+			app.component.Hashes = doc.Metadata.Component.Hashes
+		}
+		app.component.BOMRef = app.component.Name
+
+		seen := make(map[string]bool)
+		for _, component := range doc.Components {
+			// The artifact's own file entry carries its hashes rather than describing a dependency
+			if component.Type == "file" && component.Name == app.component.Name {
+				if len(app.component.Hashes) == 0 {
+					app.component.Hashes = component.Hashes
+				}
+				continue
+			}
+
+			ref := libraryRef(component)
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			usage[ref]++
+			app.libraries = append(app.libraries, cycloneDXComponent{
+				BOMRef:  ref,
+				Type:    component.Type,
+				Name:    component.Name,
+				Version: component.Version,
+				PURL:    component.PURL,
+				Hashes:  component.Hashes,
+			})
+		}
+		apps = append(apps, app)
+	}
+
+	merged := cycloneDXDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: s.timestamp(),
+			Component: &cycloneDXComponent{
+				BOMRef:  packageName + "@" + version,
+				Type:    "application",
+				Name:    packageName,
+				Version: version,
+				PURL:    entities.GenericPURL(packageName, version),
+			},
+		},
+		Components: make([]cycloneDXComponent, 0, len(apps)),
+	}
+
+	shared := make(map[string]cycloneDXComponent)
+	for _, app := range apps {
+		component := app.component
+		dependency := cycloneDXDependency{Ref: component.BOMRef}
+		for _, library := range app.libraries {
+			dependency.DependsOn = append(dependency.DependsOn, library.BOMRef)
+			if usage[library.BOMRef] > 1 {
+				shared[library.BOMRef] = library
+				continue
+			}
+			component.Components = append(component.Components, library)
+		}
+		sort.Strings(dependency.DependsOn)
+		merged.Components = append(merged.Components, component)
+		merged.Dependencies = append(merged.Dependencies, dependency)
+	}
+
+	sharedRefs := make([]string, 0, len(shared))
+	for ref := range shared {
+		sharedRefs = append(sharedRefs, ref)
+	}
+	sort.Strings(sharedRefs)
+	for _, ref := range sharedRefs {
+		merged.Components = append(merged.Components, shared[ref])
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal merged SBOM: %w", err)
+	}
+
+	mergedPath := filepath.Join(dir, MergedSBOMName(packageName, version))
+	if err := os.WriteFile(mergedPath, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write merged SBOM: %w", err)
+	}
+	return mergedPath, nil
+}
+
+// readCycloneDX parses a CycloneDX JSON SBOM
+func readCycloneDX(path string) (*cycloneDXDocument, error) {
+	//nolint:gosec // G304: path is an SBOM generated alongside a release artifact
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SBOM: %w", err)
+	}
+
+	var doc cycloneDXDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid SBOM %s: %w", filepath.Base(path), err)
+	}
+	if doc.BOMFormat != "CycloneDX" {
+		return nil, fmt.Errorf("%s is not a CycloneDX SBOM", filepath.Base(path))
+	}
+	return &doc, nil
+}
+
+// libraryRef identifies a component across SBOMs by name and version
+func libraryRef(component cycloneDXComponent) string {
+	if component.Version == "" {
+		return component.Name
+	}
+	return component.Name + "@" + component.Version
+}
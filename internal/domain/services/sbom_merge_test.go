@@ -0,0 +1,96 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ochairo/potions/internal/domain/interfaces"
+)
+
+// writeArtifactSBOM writes a per-artifact CycloneDX SBOM listing the given libraries
+func writeArtifactSBOM(t *testing.T, dir, tarball string, libraries ...string) string {
+	t.Helper()
+	components := []map[string]any{{
+		"type":   "file",
+		"name":   tarball,
+		"hashes": []map[string]string{{"alg": "SHA-256", "content": "sha-of-" + tarball}},
+	}}
+	for _, library := range libraries {
+		components = append(components, map[string]any{"type": "library", "name": library, "version": "1"})
+	}
+	data, err := json.Marshal(map[string]any{
+		"bomFormat":   "CycloneDX",
+		"specVersion": "1.5",
+		"version":     1,
+		"metadata":    map[string]any{"component": map[string]any{"type": "application", "name": tarball}},
+		"components":  components,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, tarball+".sbom.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Test merging artifact SBOMs keeps each tarball as an application and lists shared libraries once
+func TestSecurityArtifactsService_MergeSBOMs(t *testing.T) {
+	dir := t.TempDir()
+	linux := writeArtifactSBOM(t, dir, "tool-1.0.0-linux-amd64.tar.gz", "libc.so.6", "libssl.so.3")
+	darwin := writeArtifactSBOM(t, dir, "tool-1.0.0-darwin-arm64.tar.gz", "libc.so.6", "libz.1.dylib")
+
+	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{})
+	mergedPath, err := service.MergeSBOMs(t.TempDir(), "tool", "1.0.0", []string{linux, darwin})
+	if err != nil {
+		t.Fatalf("MergeSBOMs() error = %v", err)
+	}
+	if filepath.Base(mergedPath) != "tool-1.0.0.sbom.json" {
+		t.Errorf("merged SBOM name = %s, want tool-1.0.0.sbom.json", filepath.Base(mergedPath))
+	}
+	if name := MergedSBOMName("tool", "v1.0.0"); name != "tool-1.0.0.sbom.json" {
+		t.Errorf("MergedSBOMName(v1.0.0) = %s, want the canonical version", name)
+	}
+
+	merged, err := readCycloneDX(mergedPath)
+	if err != nil {
+		t.Fatalf("readCycloneDX() error = %v", err)
+	}
+
+	counts := make(map[string]int)
+	var count func(components []cycloneDXComponent)
+	count = func(components []cycloneDXComponent) {
+		for _, component := range components {
+			counts[component.Type+":"+component.Name]++
+			count(component.Components)
+		}
+	}
+	count(merged.Components)
+
+	want := map[string]int{
+		"application:tool-1.0.0-darwin-arm64.tar.gz": 1,
+		"application:tool-1.0.0-linux-amd64.tar.gz":  1,
+		"library:libc.so.6":                          1,
+		"library:libssl.so.3":                        1,
+		"library:libz.1.dylib":                       1,
+	}
+	if fmt.Sprint(counts) != fmt.Sprint(want) {
+		t.Errorf("component counts = %v, want %v", counts, want)
+	}
+
+	for _, dependency := range merged.Dependencies {
+		if len(dependency.DependsOn) != 2 || dependency.DependsOn[0] != "libc.so.6@1" {
+			t.Errorf("dependency %s = %v, want libc.so.6@1 and its own library", dependency.Ref, dependency.DependsOn)
+		}
+	}
+	if merged.Metadata.Component.PURL != "pkg:generic/tool@1.0.0" {
+		t.Errorf("metadata purl = %q, want pkg:generic/tool@1.0.0", merged.Metadata.Component.PURL)
+	}
+	if merged.Components[0].Hashes[0].Content != "sha-of-tool-1.0.0-darwin-arm64.tar.gz" {
+		t.Errorf("application hashes = %+v, want the tarball's file hash", merged.Components[0].Hashes)
+	}
+}
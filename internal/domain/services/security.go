@@ -23,11 +23,29 @@ func NewSecurityService(gateway gateways.SecurityGateway) services.SecurityServi
 
 // PerformSecurityScan performs vulnerability scanning on an artifact
 func (s *securityService) PerformSecurityScan(ctx context.Context, artifact *entities.Artifact) (*entities.SecurityReport, error) {
+	// Recipe hints name the real dependencies, so nothing is guessed from the name or binary
+	if len(artifact.OSVHints) > 0 {
+		report, err := s.scanOSVHints(ctx, artifact)
+		if err != nil {
+			return nil, fmt.Errorf("security scan failed: %w", err)
+		}
+		report.Score = s.CalculateSecurityScore(report)
+		return report, nil
+	}
+
 	// Delegate to gateway for actual scanning
 	report, err := s.gateway.ScanWithOSV(ctx, artifact)
 	if err != nil {
 		return nil, fmt.Errorf("security scan failed: %w", err)
 	}
+	tagVulnerabilities(report.Vulnerabilities, entities.Component{Type: "application", Name: artifact.Name, Version: artifact.Version})
+
+	// Query OSV per bundled library so each finding points at the component to update
+	libraryVulns, err := s.scanLibraries(ctx, artifact)
+	if err != nil {
+		return nil, fmt.Errorf("security scan failed: %w", err)
+	}
+	report.Vulnerabilities = append(report.Vulnerabilities, libraryVulns...)
 
 	// Calculate security score (pure business logic)
 	report.Score = s.CalculateSecurityScore(report)
@@ -35,6 +53,69 @@ func (s *securityService) PerformSecurityScan(ctx context.Context, artifact *ent
 	return report, nil
 }
 
+// scanLibraries scans the versioned libraries in the artifact's SBOM, tagging each finding with its library
+// The SBOM is best-effort here: without one only the artifact itself is scanned
+func (s *securityService) scanLibraries(ctx context.Context, artifact *entities.Artifact) ([]entities.Vulnerability, error) {
+	if artifact.Path == "" {
+		return nil, nil
+	}
+	sbom, err := s.gateway.GenerateSBOM(ctx, artifact)
+	if err != nil || sbom == nil {
+		return nil, nil
+	}
+
+	var vulnerabilities []entities.Vulnerability
+	seen := make(map[string]bool)
+	for _, component := range sbom.Components {
+		ref := component.Name + "@" + component.Version
+		if component.Type != "library" || component.Version == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		report, err := s.gateway.ScanWithOSV(ctx, &entities.Artifact{Name: component.Name, Version: component.Version, Platform: artifact.Platform})
+		if err != nil {
+			return nil, fmt.Errorf("scan of %s failed: %w", ref, err)
+		}
+		tagVulnerabilities(report.Vulnerabilities, component)
+		vulnerabilities = append(vulnerabilities, report.Vulnerabilities...)
+	}
+	return vulnerabilities, nil
+}
+
+// scanOSVHints queries OSV for each package named by the artifact's hints, tagging findings with their package
+func (s *securityService) scanOSVHints(ctx context.Context, artifact *entities.Artifact) (*entities.SecurityReport, error) {
+	queries, err := buildOSVQueries(artifact.OSVHints, artifact.Path, artifact.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entities.SecurityReport{ScanDate: time.Now().Format(time.RFC3339)}
+	for _, query := range queries {
+		scanned, err := s.gateway.ScanWithOSV(ctx, &entities.Artifact{Name: query.Package, Version: query.Version,
+			Platform: artifact.Platform, Ecosystem: query.Ecosystem})
+		if err != nil {
+			return nil, fmt.Errorf("scan of %s@%s failed: %w", query.Package, query.Version, err)
+		}
+		tagVulnerabilities(scanned.Vulnerabilities, entities.Component{Type: "library", Name: query.Package, Version: query.Version})
+		report.Vulnerabilities = append(report.Vulnerabilities, scanned.Vulnerabilities...)
+		report.Metadata = scanned.Metadata
+	}
+	return report, nil
+}
+
+// tagVulnerabilities records component as the source of each vulnerability
+func tagVulnerabilities(vulnerabilities []entities.Vulnerability, component entities.Component) {
+	for i := range vulnerabilities {
+		source := component
+		vulnerabilities[i].Component = component.Name
+		if component.Version != "" {
+			vulnerabilities[i].Component += "@" + component.Version
+		}
+		vulnerabilities[i].Source = &source
+	}
+}
+
 // GenerateSBOM generates a Software Bill of Materials for an artifact
 func (s *securityService) GenerateSBOM(ctx context.Context, artifact *entities.Artifact) (*entities.SBOM, error) {
 	// Delegate to gateway
@@ -147,18 +228,17 @@ func (s *securityService) FilterVulnerabilities(vulnerabilities []entities.Vulne
 	return filtered
 }
 
-// ShouldBlockBuild determines if a build should be blocked based on security report
+// ShouldBlockBuild determines if a build should be blocked based on security report and the
+// platform's policy thresholds
 // Pure business logic - no I/O
-func (s *securityService) ShouldBlockBuild(report *entities.SecurityReport) bool {
-	// Block if any CRITICAL vulnerabilities
-	for _, vuln := range report.Vulnerabilities {
-		if vuln.Severity == "CRITICAL" {
-			return true
-		}
+func (s *securityService) ShouldBlockBuild(report *entities.SecurityReport, policy entities.SecurityPolicy) bool {
+	// Block if any vulnerabilities at or above the blocking severity
+	if len(s.FilterVulnerabilities(report.Vulnerabilities, policy.BlockSeverity)) > 0 {
+		return true
 	}
 
 	// Block if security score too low
-	if report.Score < 5.0 {
+	if report.Score < policy.MinScore {
 		return true
 	}
 
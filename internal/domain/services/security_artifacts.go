@@ -10,14 +10,19 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/ochairo/potions/internal/domain/entities"
 	"github.com/ochairo/potions/internal/domain/interfaces"
 )
 
 // SecurityArtifactsService handles generation of security artifacts
 type SecurityArtifactsService struct {
-	logger interfaces.Logger
+	logger          interfaces.Logger
+	clock           interfaces.Clock
+	checksumBaseDir string
 }
 
 // NewSecurityArtifactsService creates a new security artifacts service
@@ -25,7 +30,40 @@ func NewSecurityArtifactsService(logger interfaces.Logger) *SecurityArtifactsSer
 	if logger == nil {
 		logger = &interfaces.StdoutLogger{}
 	}
-	return &SecurityArtifactsService{logger: logger}
+	return &SecurityArtifactsService{logger: logger, clock: interfaces.RealClock{}}
+}
+
+// WithClock sets the time source used for SBOM and provenance timestamps
+func (s *SecurityArtifactsService) WithClock(clock interfaces.Clock) *SecurityArtifactsService {
+	if clock != nil {
+		s.clock = clock
+	}
+	return s
+}
+
+// WithChecksumBaseDir names files in .sha256/.sha512 files by their path relative to dir
+// instead of their basename, so "sha256sum -c" run from dir resolves nested layouts
+func (s *SecurityArtifactsService) WithChecksumBaseDir(dir string) *SecurityArtifactsService {
+	s.checksumBaseDir = dir
+	return s
+}
+
+// checksumFileName returns the name recorded for filePath in its checksum file: the slash-separated
+// path relative to the checksum base directory, or the basename when unset or outside it
+func (s *SecurityArtifactsService) checksumFileName(filePath string) string {
+	if s.checksumBaseDir == "" {
+		return filepath.Base(filePath)
+	}
+	rel, err := filepath.Rel(s.checksumBaseDir, filePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Base(filePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// timestamp returns the current time from the configured clock in RFC 3339 format
+func (s *SecurityArtifactsService) timestamp() string {
+	return s.clock.Now().UTC().Format(time.RFC3339)
 }
 
 // SecurityArtifacts represents all security artifacts for a binary
@@ -36,8 +74,20 @@ type SecurityArtifacts struct {
 	ProvenancePath string
 }
 
+// Paths returns the paths of all generated artifacts
+func (a *SecurityArtifacts) Paths() []string {
+	var paths []string
+	for _, p := range []string{a.SHA256Path, a.SHA512Path, a.SBOMPath, a.ProvenancePath} {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
 // GenerateAllArtifacts generates all security artifacts for a tarball
-func (s *SecurityArtifactsService) GenerateAllArtifacts(ctx context.Context, tarballPath string) (*SecurityArtifacts, error) {
+// sourceURL, when set, is recorded as a provenance material
+func (s *SecurityArtifactsService) GenerateAllArtifacts(ctx context.Context, tarballPath, sourceURL string) (*SecurityArtifacts, error) {
 	artifacts := &SecurityArtifacts{}
 
 	// Generate checksums
@@ -65,7 +115,7 @@ func (s *SecurityArtifactsService) GenerateAllArtifacts(ctx context.Context, tar
 
 	// Generate provenance
 	s.logger.Info("generating provenance")
-	provenancePath, err := s.GenerateProvenance(ctx, tarballPath)
+	provenancePath, err := s.GenerateProvenance(ctx, tarballPath, sourceURL)
 	if err != nil {
 		s.logger.Warn("provenance generation failed, continuing", interfaces.F("error", err))
 	} else {
@@ -83,7 +133,7 @@ func (s *SecurityArtifactsService) GenerateSHA256(filePath string) (string, erro
 	}
 
 	checksumPath := filePath + ".sha256"
-	content := fmt.Sprintf("%s  %s\n", hash, filepath.Base(filePath))
+	content := fmt.Sprintf("%s  %s\n", hash, s.checksumFileName(filePath))
 
 	if err := os.WriteFile(checksumPath, []byte(content), 0600); err != nil {
 		return "", fmt.Errorf("failed to write SHA256 file: %w", err)
@@ -100,7 +150,7 @@ func (s *SecurityArtifactsService) GenerateSHA512(filePath string) (string, erro
 	}
 
 	checksumPath := filePath + ".sha512"
-	content := fmt.Sprintf("%s  %s\n", hash, filepath.Base(filePath))
+	content := fmt.Sprintf("%s  %s\n", hash, s.checksumFileName(filePath))
 
 	if err := os.WriteFile(checksumPath, []byte(content), 0600); err != nil {
 		return "", fmt.Errorf("failed to write SHA512 file: %w", err)
@@ -109,6 +159,57 @@ func (s *SecurityArtifactsService) GenerateSHA512(filePath string) (string, erro
 	return checksumPath, nil
 }
 
+// ChecksumManifestName is the file name of the combined checksum manifest
+const ChecksumManifestName = "SHA256SUMS"
+
+// GenerateChecksumManifest writes a combined SHA256SUMS manifest for files into dir
+// Format matches sha256sum output: "<hash>  <filename>" per line, sorted by filename
+func (s *SecurityArtifactsService) GenerateChecksumManifest(dir string, files []string) (string, error) {
+	sorted := append([]string(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return filepath.Base(sorted[i]) < filepath.Base(sorted[j])
+	})
+
+	var b strings.Builder
+	for _, file := range sorted {
+		hash, err := s.computeSHA256(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash %s: %w", filepath.Base(file), err)
+		}
+		fmt.Fprintf(&b, "%s  %s\n", hash, filepath.Base(file))
+	}
+
+	manifestPath := filepath.Join(dir, ChecksumManifestName)
+	if err := os.WriteFile(manifestPath, []byte(b.String()), 0600); err != nil {
+		return "", fmt.Errorf("failed to write checksum manifest: %w", err)
+	}
+
+	return manifestPath, nil
+}
+
+// ParseChecksumManifest parses a SHA256SUMS manifest into a filename → hash map
+// Accepts the binary-mode marker ("<hash> *<filename>") written by sha256sum -b
+func ParseChecksumManifest(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			return nil, fmt.Errorf("invalid checksum manifest line %d: %q", i+1, line)
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+
+	if len(sums) == 0 {
+		return nil, fmt.Errorf("checksum manifest is empty")
+	}
+	return sums, nil
+}
+
 // GenerateSBOM generates a simple Software Bill of Materials
 func (s *SecurityArtifactsService) GenerateSBOM(_ context.Context, filePath string) (string, error) {
 	sbomPath := filePath + ".sbom.json"
@@ -119,10 +220,11 @@ func (s *SecurityArtifactsService) GenerateSBOM(_ context.Context, filePath stri
 		"specVersion": "1.5",
 		"version":     1,
 		"metadata": map[string]interface{}{
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"timestamp": s.timestamp(),
 			"component": map[string]interface{}{
 				"type": "application",
 				"name": filepath.Base(filePath),
+				"purl": entities.GenericPURL(filepath.Base(filePath), ""),
 			},
 		},
 		"components": []map[string]interface{}{
@@ -130,6 +232,7 @@ func (s *SecurityArtifactsService) GenerateSBOM(_ context.Context, filePath stri
 				"type":    "file",
 				"name":    filepath.Base(filePath),
 				"version": "unknown",
+				"purl":    entities.GenericPURL(filepath.Base(filePath), ""),
 				"hashes": []map[string]string{
 					{
 						"alg":     "SHA-256",
@@ -153,27 +256,102 @@ func (s *SecurityArtifactsService) GenerateSBOM(_ context.Context, filePath stri
 }
 
 // GenerateProvenance generates SLSA provenance attestation
-func (s *SecurityArtifactsService) GenerateProvenance(_ context.Context, filePath string) (string, error) {
+// sourceURL, when set, is listed as the first material (the upstream source the package was built from)
+func (s *SecurityArtifactsService) GenerateProvenance(_ context.Context, filePath, sourceURL string) (string, error) {
 	provenancePath := filePath + ".provenance.json"
 
-	// Get file info
-	fileInfo, err := os.Stat(filePath)
+	subject, err := s.provenanceSubject(filePath)
 	if err != nil {
 		return "", err
 	}
 
+	materials := []map[string]interface{}{}
+	if sourceURL != "" {
+		materials = append(materials, map[string]interface{}{"uri": sourceURL})
+	}
+	materials = append(materials, map[string]interface{}{
+		"uri": "pkg:generic/" + filepath.Base(filePath),
+		"digest": map[string]string{
+			"sha256": s.mustComputeSHA256(filePath),
+		},
+	})
+
+	if err := s.writeProvenance(provenancePath, []map[string]interface{}{subject}, materials); err != nil {
+		return "", err
+	}
+	return provenancePath, nil
+}
+
+// ReleaseProvenanceName returns the file name of the provenance covering every asset of a release,
+// using the canonical version like the tarball names
+func ReleaseProvenanceName(packageName, version string) string {
+	return fmt.Sprintf("%s-%s.provenance.json", packageName, entities.CanonicalVersion(version))
+}
+
+// GenerateReleaseProvenance writes one SLSA provenance statement into dir whose subjects are
+// every file of a release with its digests, so a single statement attests the whole release
+func (s *SecurityArtifactsService) GenerateReleaseProvenance(dir, packageName, version string, files []string) (string, error) {
+	if len(files) == 0 {
+		return "", fmt.Errorf("no files to attest")
+	}
+
+	sorted := append([]string(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return filepath.Base(sorted[i]) < filepath.Base(sorted[j])
+	})
+
+	subjects := make([]map[string]interface{}, 0, len(sorted))
+	for _, file := range sorted {
+		subject, err := s.provenanceSubject(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to attest %s: %w", filepath.Base(file), err)
+		}
+		subjects = append(subjects, subject)
+	}
+
+	materials := []map[string]interface{}{
+		{"uri": entities.GenericPURL(packageName, version)},
+	}
+
+	provenancePath := filepath.Join(dir, ReleaseProvenanceName(packageName, version))
+	if err := s.writeProvenance(provenancePath, subjects, materials); err != nil {
+		return "", err
+	}
+	return provenancePath, nil
+}
+
+// provenanceSubject describes filePath as a provenance subject with its digests and size
+func (s *SecurityArtifactsService) provenanceSubject(filePath string) (map[string]interface{}, error) {
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	sha256Hash, err := s.computeSHA256(filePath)
+	if err != nil {
+		return nil, err
+	}
+	sha512Hash, err := s.computeSHA512(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"name": filepath.Base(filePath),
+		"digest": map[string]string{
+			"sha256": sha256Hash,
+			"sha512": sha512Hash,
+		},
+		"size": fileInfo.Size(),
+	}, nil
+}
+
+// writeProvenance writes a SLSA provenance statement for subjects built from materials to path
+func (s *SecurityArtifactsService) writeProvenance(path string, subjects, materials []map[string]interface{}) error {
+	buildTime := s.timestamp()
+
 	// Simple SLSA provenance structure
 	provenance := map[string]interface{}{
-		"_type": "https://in-toto.io/Statement/v0.1",
-		"subject": []map[string]interface{}{
-			{
-				"name": filepath.Base(filePath),
-				"digest": map[string]string{
-					"sha256": s.mustComputeSHA256(filePath),
-					"sha512": s.mustComputeSHA512(filePath),
-				},
-			},
-		},
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"subject":       subjects,
 		"predicateType": "https://slsa.dev/provenance/v0.2",
 		"predicate": map[string]interface{}{
 			"builder": map[string]string{
@@ -181,8 +359,8 @@ func (s *SecurityArtifactsService) GenerateProvenance(_ context.Context, filePat
 			},
 			"buildType": "https://github.com/ochairo/potions@v1",
 			"metadata": map[string]interface{}{
-				"buildStartedOn":  time.Now().UTC().Format(time.RFC3339),
-				"buildFinishedOn": time.Now().UTC().Format(time.RFC3339),
+				"buildStartedOn":  buildTime,
+				"buildFinishedOn": buildTime,
 				"completeness": map[string]bool{
 					"parameters":  true,
 					"environment": false,
@@ -190,34 +368,19 @@ func (s *SecurityArtifactsService) GenerateProvenance(_ context.Context, filePat
 				},
 				"reproducible": false,
 			},
-			"materials": []map[string]interface{}{
-				{
-					"uri": "pkg:generic/" + filepath.Base(filePath),
-					"digest": map[string]string{
-						"sha256": s.mustComputeSHA256(filePath),
-					},
-				},
-			},
+			"materials": materials,
 		},
 	}
 
-	// Add file size
-	if fileInfo != nil {
-		if subject, ok := provenance["subject"].([]map[string]interface{}); ok && len(subject) > 0 {
-			subject[0]["size"] = fileInfo.Size()
-		}
-	}
-
 	data, err := json.MarshalIndent(provenance, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal provenance: %w", err)
+		return fmt.Errorf("failed to marshal provenance: %w", err)
 	}
 
-	if err := os.WriteFile(provenancePath, data, 0600); err != nil {
-		return "", fmt.Errorf("failed to write provenance file: %w", err)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write provenance file: %w", err)
 	}
-
-	return provenancePath, nil
+	return nil
 }
 
 // computeSHA256 computes SHA256 hash of a file
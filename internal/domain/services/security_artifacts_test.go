@@ -2,11 +2,14 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain/interfaces"
 )
@@ -54,6 +57,54 @@ func TestSecurityArtifactsService_GenerateSHA256(t *testing.T) {
 	}
 }
 
+// Test checksum files name nested artifacts relative to the base directory so they resolve from it
+func TestSecurityArtifactsService_GenerateSHA256_RelativeToBaseDir(t *testing.T) {
+	baseDir := t.TempDir()
+	nestedDir := filepath.Join(baseDir, "tool", "1.0.0")
+	if err := os.MkdirAll(nestedDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	tarball := filepath.Join(nestedDir, "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(tarball, []byte("nested artifact"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{}).WithChecksumBaseDir(baseDir)
+	checksumPath, err := service.GenerateSHA256(tarball)
+	if err != nil {
+		t.Fatalf("GenerateSHA256 failed: %v", err)
+	}
+
+	//nolint:gosec // G304: checksumPath is test output file
+	content, err := os.ReadFile(checksumPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 || fields[1] != "tool/1.0.0/tool-1.0.0-linux-amd64.tar.gz" {
+		t.Fatalf("checksum line = %q, want path relative to the base directory", content)
+	}
+
+	// The recorded path resolves from the base directory the way "sha256sum -c" would
+	resolved := filepath.Join(baseDir, filepath.FromSlash(fields[1]))
+	hash, err := service.computeSHA256(resolved)
+	if err != nil {
+		t.Fatalf("recorded path does not resolve: %v", err)
+	}
+	if hash != fields[0] {
+		t.Errorf("hash of resolved file = %s, want %s", hash, fields[0])
+	}
+
+	// Files outside the base directory fall back to their basename
+	outside := filepath.Join(t.TempDir(), "other.tar.gz")
+	if err := os.WriteFile(outside, []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := service.checksumFileName(outside); got != "other.tar.gz" {
+		t.Errorf("checksumFileName(outside) = %q, want basename", got)
+	}
+}
+
 // Test SHA512 generation
 func TestSecurityArtifactsService_GenerateSHA512(t *testing.T) {
 	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{})
@@ -150,7 +201,7 @@ func TestSecurityArtifactsService_GenerateProvenance(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	provenancePath, err := service.GenerateProvenance(context.Background(), testFile)
+	provenancePath, err := service.GenerateProvenance(context.Background(), testFile, "")
 	if err != nil {
 		t.Fatalf("GenerateProvenance failed: %v", err)
 	}
@@ -206,6 +257,109 @@ func TestSecurityArtifactsService_GenerateProvenance(t *testing.T) {
 	}
 }
 
+// Test the upstream source URL is recorded as the first provenance material
+func TestSecurityArtifactsService_GenerateProvenance_SourceURL(t *testing.T) {
+	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{})
+	testFile := filepath.Join(t.TempDir(), "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(testFile, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sourceURL := "https://example.com/tool-1.0.0-linux-amd64.tar.gz"
+	provenancePath, err := service.GenerateProvenance(context.Background(), testFile, sourceURL)
+	if err != nil {
+		t.Fatalf("GenerateProvenance failed: %v", err)
+	}
+
+	//nolint:gosec // G304: provenancePath is test output file
+	content, err := os.ReadFile(provenancePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var provenance struct {
+		Predicate struct {
+			Materials []struct {
+				URI string `json:"uri"`
+			} `json:"materials"`
+		} `json:"predicate"`
+	}
+	if err := json.Unmarshal(content, &provenance); err != nil {
+		t.Fatal(err)
+	}
+
+	materials := provenance.Predicate.Materials
+	if len(materials) != 2 || materials[0].URI != sourceURL || materials[1].URI != "pkg:generic/tool-1.0.0-linux-amd64.tar.gz" {
+		t.Errorf("materials = %+v, want source URL followed by the package", materials)
+	}
+}
+
+// Test a release provenance lists every file as a subject with its sha256
+func TestSecurityArtifactsService_GenerateReleaseProvenance(t *testing.T) {
+	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{})
+	artifactsDir := t.TempDir()
+
+	want := make(map[string]string)
+	var files []string
+	for name, content := range map[string]string{
+		"tool-1.0.0-linux-amd64.tar.gz":        "linux tarball",
+		"tool-1.0.0-darwin-arm64.tar.gz":       "darwin tarball",
+		"tool-1.0.0-linux-amd64.tar.gz.sha256": "checksum",
+		"tool-1.0.0.sbom.json":                 "{}",
+	} {
+		path := filepath.Join(artifactsDir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256([]byte(content))
+		want[name] = hex.EncodeToString(sum[:])
+		files = append(files, path)
+	}
+
+	outDir := t.TempDir()
+	provenancePath, err := service.GenerateReleaseProvenance(outDir, "tool", "1.0.0", files)
+	if err != nil {
+		t.Fatalf("GenerateReleaseProvenance() error = %v", err)
+	}
+	if provenancePath != filepath.Join(outDir, "tool-1.0.0.provenance.json") {
+		t.Errorf("provenance path = %s", provenancePath)
+	}
+	if name := ReleaseProvenanceName("tool", "v1.0.0"); name != "tool-1.0.0.provenance.json" {
+		t.Errorf("ReleaseProvenanceName(v1.0.0) = %s, want the canonical version", name)
+	}
+
+	//nolint:gosec // G304: provenancePath is test output file
+	content, err := os.ReadFile(provenancePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var provenance struct {
+		Subject []struct {
+			Name   string            `json:"name"`
+			Digest map[string]string `json:"digest"`
+		} `json:"subject"`
+	}
+	if err := json.Unmarshal(content, &provenance); err != nil {
+		t.Fatalf("provenance is not valid JSON: %v", err)
+	}
+
+	if len(provenance.Subject) != len(want) {
+		t.Fatalf("got %d subjects, want %d", len(provenance.Subject), len(want))
+	}
+	for _, subject := range provenance.Subject {
+		if subject.Digest["sha256"] != want[subject.Name] {
+			t.Errorf("subject %s sha256 = %q, want %q", subject.Name, subject.Digest["sha256"], want[subject.Name])
+		}
+		delete(want, subject.Name)
+	}
+	if len(want) > 0 {
+		t.Errorf("files missing from subjects: %v", want)
+	}
+
+	if _, err := service.GenerateReleaseProvenance(outDir, "tool", "1.0.0", []string{filepath.Join(artifactsDir, "missing")}); err == nil {
+		t.Error("GenerateReleaseProvenance() should fail for a missing file")
+	}
+}
+
 // Test GenerateAllArtifacts
 func TestSecurityArtifactsService_GenerateAllArtifacts(t *testing.T) {
 	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{})
@@ -218,7 +372,7 @@ func TestSecurityArtifactsService_GenerateAllArtifacts(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	artifacts, err := service.GenerateAllArtifacts(context.Background(), testFile)
+	artifacts, err := service.GenerateAllArtifacts(context.Background(), testFile, "")
 	if err != nil {
 		t.Fatalf("GenerateAllArtifacts failed: %v", err)
 	}
@@ -323,3 +477,143 @@ func TestSecurityArtifactsService_MustComputeHelpers(t *testing.T) {
 		t.Errorf("mustComputeSHA512 should return empty string on error, got: %s", hash512)
 	}
 }
+
+// Test provenance and SBOM timestamps come from the injected clock
+func TestSecurityArtifactsService_FixedClock(t *testing.T) {
+	fixed := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{}).WithClock(interfaces.FixedClock{Time: fixed})
+
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.tar.gz")
+	if err := os.WriteFile(testFile, []byte("reproducible"), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	provenancePath, err := service.GenerateProvenance(context.Background(), testFile, "")
+	if err != nil {
+		t.Fatalf("GenerateProvenance failed: %v", err)
+	}
+
+	//nolint:gosec // G304: provenancePath is test output file
+	data, err := os.ReadFile(provenancePath)
+	if err != nil {
+		t.Fatalf("Failed to read provenance: %v", err)
+	}
+
+	var provenance struct {
+		Predicate struct {
+			Metadata struct {
+				BuildStartedOn  string `json:"buildStartedOn"`
+				BuildFinishedOn string `json:"buildFinishedOn"`
+			} `json:"metadata"`
+		} `json:"predicate"`
+	}
+	if err := json.Unmarshal(data, &provenance); err != nil {
+		t.Fatalf("Invalid provenance JSON: %v", err)
+	}
+
+	want := "2024-03-15T10:30:00Z"
+	if got := provenance.Predicate.Metadata.BuildStartedOn; got != want {
+		t.Errorf("buildStartedOn = %q, want %q", got, want)
+	}
+	if got := provenance.Predicate.Metadata.BuildFinishedOn; got != want {
+		t.Errorf("buildFinishedOn = %q, want %q", got, want)
+	}
+
+	sbomPath, err := service.GenerateSBOM(context.Background(), testFile)
+	if err != nil {
+		t.Fatalf("GenerateSBOM failed: %v", err)
+	}
+
+	//nolint:gosec // G304: sbomPath is test output file
+	sbomData, err := os.ReadFile(sbomPath)
+	if err != nil {
+		t.Fatalf("Failed to read SBOM: %v", err)
+	}
+
+	var sbom struct {
+		Metadata struct {
+			Timestamp string `json:"timestamp"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(sbomData, &sbom); err != nil {
+		t.Fatalf("Invalid SBOM JSON: %v", err)
+	}
+	if sbom.Metadata.Timestamp != want {
+		t.Errorf("SBOM timestamp = %q, want %q", sbom.Metadata.Timestamp, want)
+	}
+
+	// Output is byte-for-byte reproducible with a fixed clock
+	first := string(data)
+	if _, err := service.GenerateProvenance(context.Background(), testFile, ""); err != nil {
+		t.Fatalf("GenerateProvenance failed: %v", err)
+	}
+	//nolint:gosec // G304: provenancePath is test output file
+	second, err := os.ReadFile(provenancePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != string(second) {
+		t.Error("Provenance output differs between runs with a fixed clock")
+	}
+}
+
+// Test combined SHA256SUMS manifest generation round-trips through the parser
+func TestSecurityArtifactsService_GenerateChecksumManifest(t *testing.T) {
+	service := NewSecurityArtifactsService(&interfaces.NoOpLogger{})
+	tmpDir := t.TempDir()
+
+	var files []string
+	for _, name := range []string{"tool-1.0.0-linux-amd64.tar.gz", "tool-1.0.0-darwin-arm64.tar.gz"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+
+	manifestPath, err := service.GenerateChecksumManifest(t.TempDir(), files)
+	if err != nil {
+		t.Fatalf("GenerateChecksumManifest() error = %v", err)
+	}
+	if filepath.Base(manifestPath) != ChecksumManifestName {
+		t.Errorf("manifest name = %s, want %s", filepath.Base(manifestPath), ChecksumManifestName)
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "  tool-1.0.0-darwin-arm64.tar.gz") {
+		t.Errorf("manifest should list files sorted by name, got:\n%s", data)
+	}
+
+	sums, err := ParseChecksumManifest(data)
+	if err != nil {
+		t.Fatalf("ParseChecksumManifest() error = %v", err)
+	}
+	for _, file := range files {
+		want := service.mustComputeSHA256(file)
+		if sums[filepath.Base(file)] != want {
+			t.Errorf("sum for %s = %s, want %s", filepath.Base(file), sums[filepath.Base(file)], want)
+		}
+	}
+}
+
+// Test malformed manifests are rejected
+func TestParseChecksumManifest_Invalid(t *testing.T) {
+	for _, data := range []string{"", "abc  file.tar.gz\n", strings.Repeat("a", 64) + "\n"} {
+		if _, err := ParseChecksumManifest([]byte(data)); err == nil {
+			t.Errorf("ParseChecksumManifest(%q) should fail", data)
+		}
+	}
+
+	sums, err := ParseChecksumManifest([]byte(strings.Repeat("A", 64) + " *file.tar.gz\n"))
+	if err != nil {
+		t.Fatalf("ParseChecksumManifest() binary marker error = %v", err)
+	}
+	if sums["file.tar.gz"] != strings.Repeat("a", 64) {
+		t.Errorf("binary-mode entry not parsed: %v", sums)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -16,9 +17,17 @@ type mockSecurityGateway struct {
 	sbomError      error
 	analysisResult *entities.BinaryAnalysis
 	analysisError  error
+	// scanByArtifact, when set, answers ScanWithOSV per "name@version"
+	scanByArtifact map[string]*entities.SecurityReport
+	// scanned records every artifact passed to ScanWithOSV
+	scanned []*entities.Artifact
 }
 
-func (m *mockSecurityGateway) ScanWithOSV(_ context.Context, _ *entities.Artifact) (*entities.SecurityReport, error) {
+func (m *mockSecurityGateway) ScanWithOSV(_ context.Context, artifact *entities.Artifact) (*entities.SecurityReport, error) {
+	m.scanned = append(m.scanned, artifact)
+	if report, ok := m.scanByArtifact[artifact.Name+"@"+artifact.Version]; ok {
+		return report, nil
+	}
 	return m.scanResult, m.scanError
 }
 
@@ -38,7 +47,11 @@ func (m *mockSecurityGateway) VerifyGPGSignature(_ context.Context, _, _ string)
 	return nil
 }
 
-func (m *mockSecurityGateway) ImportGPGKeys(_ context.Context, _ []string) error {
+func (m *mockSecurityGateway) VerifyGPGSignedChecksums(_ context.Context, _, _, _ string) error {
+	return nil
+}
+
+func (m *mockSecurityGateway) ImportGPGKeys(_ context.Context, _ []string, _ entities.GPGKeyLookup) error {
 	return nil
 }
 
@@ -147,6 +160,7 @@ func TestShouldBlockBuild(t *testing.T) {
 	tests := []struct {
 		name        string
 		report      *entities.SecurityReport
+		policy      *entities.SecurityPolicy // Defaults to entities.DefaultSecurityPolicy
 		shouldBlock bool
 	}{
 		{
@@ -207,12 +221,36 @@ func TestShouldBlockBuild(t *testing.T) {
 			},
 			shouldBlock: false,
 		},
+		{
+			name: "high vulnerability with HIGH block severity - block",
+			report: &entities.SecurityReport{
+				Vulnerabilities: []entities.Vulnerability{
+					{Severity: "HIGH"},
+				},
+				Score: 8.0,
+			},
+			policy:      &entities.SecurityPolicy{MinScore: 5.0, BlockSeverity: "HIGH"},
+			shouldBlock: true,
+		},
+		{
+			name: "score below raised threshold - block",
+			report: &entities.SecurityReport{
+				Vulnerabilities: []entities.Vulnerability{},
+				Score:           7.5,
+			},
+			policy:      &entities.SecurityPolicy{MinScore: 8.0, BlockSeverity: "CRITICAL"},
+			shouldBlock: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			policy := entities.DefaultSecurityPolicy()
+			if tt.policy != nil {
+				policy = *tt.policy
+			}
 			svc := NewSecurityService(nil)
-			blocked := svc.ShouldBlockBuild(tt.report)
+			blocked := svc.ShouldBlockBuild(tt.report, policy)
 			if blocked != tt.shouldBlock {
 				t.Errorf("ShouldBlockBuild() = %v, want %v", blocked, tt.shouldBlock)
 			}
@@ -354,6 +392,43 @@ func TestPerformSecurityScan(t *testing.T) {
 	}
 }
 
+// Test vulnerabilities found in bundled libraries carry the SBOM component they came from
+func TestPerformSecurityScan_PerComponent(t *testing.T) {
+	mockGW := &mockSecurityGateway{
+		scanByArtifact: map[string]*entities.SecurityReport{
+			"tool@1.0.0":    {Vulnerabilities: []entities.Vulnerability{{ID: "GHSA-tool", Severity: "LOW"}}},
+			"openssl@3.0.1": {Vulnerabilities: []entities.Vulnerability{{ID: "CVE-2022-0778", Severity: "HIGH"}, {ID: "CVE-2022-3602", Severity: "CRITICAL"}}},
+			"zlib@1.3.1":    {Vulnerabilities: []entities.Vulnerability{}},
+		},
+		scanError: fmt.Errorf("unexpected OSV query"),
+		sbomResult: &entities.SBOM{Components: []entities.Component{
+			{Type: "application", Name: "tool", Version: "1.0.0"},
+			{Type: "library", Name: "openssl", Version: "3.0.1"},
+			{Type: "library", Name: "zlib", Version: "1.3.1"},
+			{Type: "library", Name: "libc.so.6"}, // unversioned: nothing to query
+		}},
+	}
+	svc := NewSecurityService(mockGW)
+
+	report, err := svc.PerformSecurityScan(context.Background(), &entities.Artifact{Name: "tool", Version: "1.0.0", Path: "/tmp/tool"})
+	if err != nil {
+		t.Fatalf("PerformSecurityScan() error = %v", err)
+	}
+
+	want := map[string]string{"GHSA-tool": "tool@1.0.0", "CVE-2022-0778": "openssl@3.0.1", "CVE-2022-3602": "openssl@3.0.1"}
+	if len(report.Vulnerabilities) != len(want) {
+		t.Fatalf("Vulnerabilities = %+v, want %d", report.Vulnerabilities, len(want))
+	}
+	for _, vuln := range report.Vulnerabilities {
+		if vuln.Component != want[vuln.ID] || vuln.Source == nil || vuln.Source.Name+"@"+vuln.Source.Version != want[vuln.ID] {
+			t.Errorf("%s component = %q (source %+v), want %s", vuln.ID, vuln.Component, vuln.Source, want[vuln.ID])
+		}
+	}
+	if report.Score != 10.0-0.5-2.0-3.0 {
+		t.Errorf("Score = %v, want library findings to count", report.Score)
+	}
+}
+
 // TestGenerateSBOM tests SBOM generation
 func TestGenerateSBOM(t *testing.T) {
 	mockGW := &mockSecurityGateway{
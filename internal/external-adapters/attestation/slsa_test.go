@@ -0,0 +1,110 @@
+package attestation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// slsaV1Statement renders a SLSA v1 statement as produced by the SLSA GitHub generator
+func slsaV1Statement(digest, buildType, builderID string) string {
+	return fmt.Sprintf(`{
+  "_type": "https://in-toto.io/Statement/v1",
+  "subject": [{"name": "tool-1.0.0-linux-amd64.tar.gz", "digest": {"sha256": %q}}],
+  "predicateType": "https://slsa.dev/provenance/v1",
+  "predicate": {
+    "buildDefinition": {
+      "buildType": %q,
+      "externalParameters": {"workflow": {"ref": "refs/tags/v1.0.0", "repository": "https://github.com/ochairo/tool"}},
+      "resolvedDependencies": [{"uri": "git+https://github.com/ochairo/tool@refs/tags/v1.0.0", "digest": {"gitCommit": "0123abcd"}}]
+    },
+    "runDetails": {
+      "builder": {"id": %q},
+      "metadata": {"invocationId": "https://github.com/ochairo/tool/actions/runs/1/attempts/1"}
+    }
+  }
+}`, digest, buildType, builderID)
+}
+
+// writeIntotoJSONL writes statements as one DSSE envelope per line, like a .intoto.jsonl file
+func writeIntotoJSONL(t *testing.T, path string, statements ...string) {
+	t.Helper()
+	var lines []string
+	for _, statement := range statements {
+		payload := base64.StdEncoding.EncodeToString([]byte(statement))
+		lines = append(lines, fmt.Sprintf(`{"payloadType":"application/vnd.in-toto+json","payload":%q,"signatures":[{"sig":"MEUCIQ"}]}`, payload))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test a SLSA v1 statement verifies against the file it names and its builder and build type
+func TestVerifier_VerifySLSAProvenance(t *testing.T) {
+	dir := t.TempDir()
+	artifact := filepath.Join(dir, "tool-1.0.0-linux-amd64.tar.gz")
+	if err := os.WriteFile(artifact, []byte("tarball"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("tarball"))
+	digest := hex.EncodeToString(sum[:])
+
+	const (
+		genericBuildType = "https://github.com/slsa-framework/slsa-github-generator/generic@v1"
+		genericBuilder   = GitHubGeneratorBuilderPrefix + "generator_generic_slsa3.yml@refs/tags/v2.0.0"
+	)
+	otherDigest := strings.Repeat("0", 64)
+
+	tests := []struct {
+		name       string
+		statements []string
+		policy     SLSAPolicy
+		wantErr    string
+	}{
+		{"matching subject on a later line", []string{slsaV1Statement(otherDigest, genericBuildType, genericBuilder),
+			slsaV1Statement(digest, genericBuildType, genericBuilder)}, SLSAPolicy{}, ""},
+		{"explicit builder up to its ref", []string{slsaV1Statement(digest, genericBuildType, genericBuilder)},
+			SLSAPolicy{BuilderID: GitHubGeneratorBuilderPrefix + "generator_generic_slsa3.yml", BuildType: genericBuildType}, ""},
+		{"digest mismatch", []string{slsaV1Statement(otherDigest, genericBuildType, genericBuilder)},
+			SLSAPolicy{}, "no SLSA provenance subject matches sha256:" + digest},
+		{"untrusted builder", []string{slsaV1Statement(digest, genericBuildType, "https://example.com/builder@v1")},
+			SLSAPolicy{}, "is not trusted"},
+		{"unexpected build type", []string{slsaV1Statement(digest, genericBuildType, genericBuilder)},
+			SLSAPolicy{BuildType: "https://example.com/buildtype@v1"}, "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provenance := filepath.Join(t.TempDir(), "multiple.intoto.jsonl")
+			writeIntotoJSONL(t, provenance, tt.statements...)
+
+			statement, err := NewVerifier().VerifySLSAProvenance(context.Background(), artifact, provenance, tt.policy)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("VerifySLSAProvenance() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifySLSAProvenance() error = %v", err)
+			}
+			if statement.Predicate.RunDetails.Builder.ID != genericBuilder {
+				t.Errorf("builder = %q, want %q", statement.Predicate.RunDetails.Builder.ID, genericBuilder)
+			}
+		})
+	}
+
+	// A pretty-printed statement is accepted as well as JSON Lines
+	plain := filepath.Join(dir, "tool.provenance.json")
+	if err := os.WriteFile(plain, []byte(slsaV1Statement(digest, genericBuildType, genericBuilder)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVerifier().VerifySLSAProvenance(context.Background(), artifact, plain, SLSAPolicy{}); err != nil {
+		t.Errorf("VerifySLSAProvenance(plain statement) error = %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package attestation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// inTotoPayloadType is the DSSE payload type of in-toto statements
+const inTotoPayloadType = "application/vnd.in-toto+json"
+
+// Statement is a decoded in-toto statement carrying SLSA provenance
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject is an artifact the statement is about
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance holds the SLSA v0.2 provenance predicate fields shown to users, plus the
+// SLSA v1 buildDefinition and runDetails sections
+type Provenance struct {
+	Builder   Builder    `json:"builder"`
+	BuildType string     `json:"buildType"`
+	Materials []Material `json:"materials"`
+
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition is the SLSA v1 description of how the artifact was built
+type BuildDefinition struct {
+	BuildType            string     `json:"buildType"`
+	ResolvedDependencies []Material `json:"resolvedDependencies"`
+}
+
+// RunDetails is the SLSA v1 description of the build run
+type RunDetails struct {
+	Builder Builder `json:"builder"`
+}
+
+// Builder identifies the entity that produced the artifact
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Material is an input the artifact was built from
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+// dsseEnvelope is a DSSE envelope wrapping a base64 payload
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// envelopeDocument covers the shapes a provenance file may take: a bare statement,
+// a DSSE envelope, or a Sigstore bundle holding a DSSE envelope
+type envelopeDocument struct {
+	PayloadType  string        `json:"payloadType"`
+	Payload      string        `json:"payload"`
+	DSSEEnvelope *dsseEnvelope `json:"dsseEnvelope"`
+}
+
+// LoadStatement reads an in-toto statement from a .provenance.json file, a DSSE envelope,
+// or the first Sigstore bundle of an .attestation.jsonl file
+func LoadStatement(path string) (*Statement, error) {
+	// #nosec G304 -- path is user-provided provenance file for inspection
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provenance: %w", err)
+	}
+	return ParseStatement(data)
+}
+
+// ParseStatement decodes an in-toto statement from any supported provenance form
+func ParseStatement(data []byte) (*Statement, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("provenance is empty")
+	}
+
+	// JSON Lines attestation files hold one bundle per line; the first describes the artifact
+	if !json.Valid(data) {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		if scanner.Scan() {
+			data = bytes.TrimSpace(scanner.Bytes())
+		}
+	}
+
+	var doc envelopeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid provenance format: %w", err)
+	}
+
+	payload := data
+	envelope := doc.DSSEEnvelope
+	if envelope == nil && doc.Payload != "" {
+		envelope = &dsseEnvelope{PayloadType: doc.PayloadType, Payload: doc.Payload}
+	}
+	if envelope != nil {
+		if envelope.PayloadType != inTotoPayloadType {
+			return nil, fmt.Errorf("unsupported DSSE payload type %q", envelope.PayloadType)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DSSE payload encoding: %w", err)
+		}
+		payload = decoded
+	}
+
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("invalid in-toto statement: %w", err)
+	}
+	if statement.Type == "" {
+		return nil, fmt.Errorf("provenance missing _type field")
+	}
+	if len(statement.Subject) == 0 {
+		return nil, fmt.Errorf("provenance missing subject field")
+	}
+	return &statement, nil
+}
+
+// MatchesDigest reports whether any subject carries the given algorithm digest
+func (s *Statement) MatchesDigest(algorithm, digest string) bool {
+	for _, subject := range s.Subject {
+		if subject.Digest[algorithm] == digest {
+			return true
+		}
+	}
+	return false
+}
@@ -53,6 +53,23 @@ func (v *Verifier) VerifyAttestation(_ context.Context, filePath, attestationPat
 	return nil
 }
 
+// VerifyLocalAttestation checks that a local attestation file names filePath's SHA-256 digest
+// as a subject, without gh or network access
+func (v *Verifier) VerifyLocalAttestation(filePath, attestationPath string) error {
+	statement, err := LoadStatement(attestationPath)
+	if err != nil {
+		return err
+	}
+	digest, err := fileSHA256(filePath)
+	if err != nil {
+		return err
+	}
+	if !statement.MatchesDigest("sha256", digest) {
+		return fmt.Errorf("no attestation subject matches sha256:%s", digest)
+	}
+	return nil
+}
+
 // VerifyAttestationWithGH verifies using gh CLI
 func (v *Verifier) VerifyAttestationWithGH(ctx context.Context, filePath, owner, repo string) error {
 	if _, err := exec.LookPath("gh"); err != nil {
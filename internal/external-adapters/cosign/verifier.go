@@ -81,6 +81,41 @@ func (v *Verifier) VerifySignatureWithCertIdentity(ctx context.Context, filePath
 	return nil
 }
 
+// VerifyBundle verifies a blob against a cosign bundle (.bundle), which carries the signature,
+// certificate and transparency log entry in one file
+// An empty certIdentity accepts any GitHub Actions workflow identity
+func (v *Verifier) VerifyBundle(ctx context.Context, filePath, bundlePath, certIdentity string) error {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return fmt.Errorf("cosign not installed: %w (install from https://github.com/sigstore/cosign)", err)
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		return fmt.Errorf("file not found: %w", err)
+	}
+	if _, err := os.Stat(bundlePath); err != nil {
+		return fmt.Errorf("bundle file not found: %w", err)
+	}
+
+	identity := []string{"--certificate-identity-regexp", "^https://github.com/.*/.*/.*@.*$"}
+	if certIdentity != "" {
+		identity = []string{"--certificate-identity", certIdentity}
+	}
+	args := append([]string{"verify-blob",
+		"--bundle", bundlePath,
+		"--certificate-oidc-issuer", "https://token.actions.githubusercontent.com",
+	}, identity...)
+	args = append(args, filePath)
+
+	//nolint:gosec // G204: Fixed cosign subcommand; paths are user-provided files to verify
+	cmd := exec.CommandContext(ctx, "cosign", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cosign verification failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
 // IsCosignInstalled checks if Cosign is available in PATH
 func IsCosignInstalled() bool {
 	_, err := exec.LookPath("cosign")
@@ -0,0 +1,85 @@
+package gpg
+
+import (
+	"crypto/sha1" //nolint:gosec // G505: SHA-1 is mandated by the WKD spec for hashing local parts, not used for security
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxKeyResponseSize bounds a single key lookup response
+const maxKeyResponseSize = 1 << 20
+
+// zbase32Alphabet is the z-base-32 alphabet used for WKD local part hashes
+const zbase32Alphabet = "ybndrfg8ejkmcpqxot1uwisza345h769"
+
+// keybaseUserPattern matches valid Keybase usernames
+var keybaseUserPattern = regexp.MustCompile(`^[A-Za-z0-9_]{2,16}$`)
+
+// KeyLookup names where a maintainer publishes their keys besides the keyservers
+type KeyLookup struct {
+	WKDEmail    string // Email whose domain serves the key via Web Key Directory
+	KeybaseUser string // Keybase username whose public key is fetched from keybase.io
+}
+
+// lookupURLs returns the WKD (advanced, then direct method) and Keybase URLs for lookup
+func (v *Verifier) lookupURLs(lookup KeyLookup) ([]string, error) {
+	var urls []string
+	if lookup.WKDEmail != "" {
+		wkd, err := v.wkdURLs(lookup.WKDEmail)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, wkd...)
+	}
+	if lookup.KeybaseUser != "" {
+		if !keybaseUserPattern.MatchString(lookup.KeybaseUser) {
+			return nil, fmt.Errorf("invalid Keybase username %q", lookup.KeybaseUser)
+		}
+		urls = append(urls, fmt.Sprintf("%s/%s/pgp_keys.asc", v.keybaseURL, lookup.KeybaseUser))
+	}
+	return urls, nil
+}
+
+// wkdURLs derives the Web Key Directory URLs for email
+// See https://datatracker.ietf.org/doc/draft-koch-openpgp-webkey-service/
+func (v *Verifier) wkdURLs(email string) ([]string, error) {
+	local, domain, ok := strings.Cut(strings.TrimSpace(email), "@")
+	if !ok || local == "" || domain == "" || strings.ContainsAny(domain, "/@?#") {
+		return nil, fmt.Errorf("invalid WKD email %q", email)
+	}
+	domain = strings.ToLower(domain)
+
+	//nolint:gosec // G401: SHA-1 is mandated by the WKD spec
+	digest := sha1.Sum([]byte(strings.ToLower(local)))
+	hash := zbase32Encode(digest[:])
+	query := "?l=" + url.QueryEscape(local)
+
+	advancedHost, directHost := "https://openpgpkey."+domain, "https://"+domain
+	if v.wkdBaseURL != "" {
+		advancedHost, directHost = v.wkdBaseURL, v.wkdBaseURL
+	}
+	return []string{
+		fmt.Sprintf("%s/.well-known/openpgpkey/%s/hu/%s%s", advancedHost, domain, hash, query),
+		fmt.Sprintf("%s/.well-known/openpgpkey/hu/%s%s", directHost, hash, query),
+	}, nil
+}
+
+// zbase32Encode encodes data with the z-base-32 alphabet, most significant bits first
+func zbase32Encode(data []byte) string {
+	var out strings.Builder
+	var buffer, bits uint
+	for _, b := range data {
+		buffer = buffer<<8 | uint(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out.WriteByte(zbase32Alphabet[(buffer>>bits)&0x1f])
+		}
+	}
+	if bits > 0 {
+		out.WriteByte(zbase32Alphabet[(buffer<<(5-bits))&0x1f])
+	}
+	return out.String()
+}
@@ -0,0 +1,75 @@
+package gpg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// Signer creates detached GPG signatures using a private key
+type Signer struct {
+	entity *openpgp.Entity
+}
+
+// NewSignerFromFile creates a signer from an armored (or binary) private key file
+// The passphrase is only used if the key is encrypted
+func NewSignerFromFile(keyPath, passphrase string) (*Signer, error) {
+	//nolint:gosec // G304: keyPath is user-provided signing key
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing key: %w", err)
+		}
+	}
+
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			if passphrase == "" {
+				return nil, fmt.Errorf("signing key is encrypted but no passphrase was provided")
+			}
+			if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+			}
+		}
+		return &Signer{entity: entity}, nil
+	}
+
+	return nil, fmt.Errorf("no private key found in %s", keyPath)
+}
+
+// KeyID returns the signing key's fingerprint in uppercase hex
+func (s *Signer) KeyID() string {
+	return fmt.Sprintf("%X", s.entity.PrimaryKey.Fingerprint)
+}
+
+// SignDetachedFile writes an armored detached signature for filePath to sigPath
+func (s *Signer) SignDetachedFile(filePath, sigPath string) error {
+	//nolint:gosec // G304: filePath is a release artifact to sign
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file to sign: %w", err)
+	}
+	//nolint:errcheck // Defer close on read-only file
+	defer f.Close()
+
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, s.entity, f, nil); err != nil {
+		return fmt.Errorf("failed to sign %s: %w", filePath, err)
+	}
+
+	if err := os.WriteFile(sigPath, sig.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	return nil
+}
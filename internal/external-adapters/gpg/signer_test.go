@@ -0,0 +1,125 @@
+package gpg
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// writeArmoredKeys writes the entity's armored private and public keys to tmpDir
+func writeArmoredKeys(t *testing.T, tmpDir string, entity *openpgp.Entity) (string, string) {
+	t.Helper()
+
+	var priv bytes.Buffer
+	w, err := armor.Encode(&priv, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivateWithoutSigning(w, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var pub bytes.Buffer
+	w, err = armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	privPath := filepath.Join(tmpDir, "private.asc")
+	pubPath := filepath.Join(tmpDir, "public.asc")
+	if err := os.WriteFile(privPath, priv.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubPath, pub.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return privPath, pubPath
+}
+
+// Test signing a SHA256SUMS manifest and verifying the detached signature over it
+func TestSigner_SignDetachedFile_Manifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	entity := newTestEntity(t, &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	privPath, pubPath := writeArmoredKeys(t, tmpDir, entity)
+
+	manifestPath := filepath.Join(tmpDir, "SHA256SUMS")
+	manifest := strings.Repeat("a", 64) + "  tool-1.0.0-linux-amd64.tar.gz\n"
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := NewSignerFromFile(privPath, "")
+	if err != nil {
+		t.Fatalf("NewSignerFromFile() error = %v", err)
+	}
+	if !strings.HasSuffix(signer.KeyID(), strings.ToUpper(entity.PrimaryKey.KeyIdString())) {
+		t.Errorf("KeyID() = %s, want fingerprint ending in %s", signer.KeyID(), entity.PrimaryKey.KeyIdString())
+	}
+
+	sigPath := manifestPath + ".asc"
+	if err := signer.SignDetachedFile(manifestPath, sigPath); err != nil {
+		t.Fatalf("SignDetachedFile() error = %v", err)
+	}
+
+	v := NewVerifier()
+	if err := v.ImportKeyFromFile(pubPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifySignatureFromFile(manifestPath, sigPath); err != nil {
+		t.Fatalf("VerifySignatureFromFile() error = %v", err)
+	}
+
+	// Tampering with the manifest must invalidate the signature
+	if err := os.WriteFile(manifestPath, []byte(strings.Repeat("b", 64)+"  tool-1.0.0-linux-amd64.tar.gz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.VerifySignatureFromFile(manifestPath, sigPath); err == nil {
+		t.Error("Expected verification failure for tampered manifest")
+	}
+}
+
+// Test encrypted signing keys require the correct passphrase
+func TestNewSignerFromFile_EncryptedKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	entity := newTestEntity(t, &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err := entity.EncryptPrivateKeys([]byte("correct horse"), nil); err != nil {
+		t.Fatal(err)
+	}
+	privPath, _ := writeArmoredKeys(t, tmpDir, entity)
+
+	if _, err := NewSignerFromFile(privPath, ""); err == nil || !strings.Contains(err.Error(), "no passphrase") {
+		t.Errorf("Expected missing passphrase error, got %v", err)
+	}
+	if _, err := NewSignerFromFile(privPath, "wrong"); err == nil {
+		t.Error("Expected error for wrong passphrase")
+	}
+	if _, err := NewSignerFromFile(privPath, "correct horse"); err != nil {
+		t.Errorf("NewSignerFromFile() with passphrase error = %v", err)
+	}
+}
+
+// Test public-only key files are rejected for signing
+func TestNewSignerFromFile_PublicKeyOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	entity := newTestEntity(t, &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	_, pubPath := writeArmoredKeys(t, tmpDir, entity)
+
+	if _, err := NewSignerFromFile(pubPath, ""); err == nil || !strings.Contains(err.Error(), "no private key") {
+		t.Errorf("Expected no private key error, got %v", err)
+	}
+}
@@ -2,14 +2,19 @@
 package gpg
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
 )
 
 // Verifier implements GPG signature verification using ProtonMail's go-crypto
@@ -17,30 +22,91 @@ import (
 // This is in external-adapters to isolate the external dependency
 type Verifier struct {
 	keyring    openpgp.EntityList
+	keyservers []string
 	httpClient *http.Client
+	// wkdBaseURL and keybaseURL replace the lookup hosts in tests
+	wkdBaseURL string
+	keybaseURL string
+}
+
+// DefaultKeyservers are queried in order for key IDs, after any custom keyservers
+var DefaultKeyservers = []string{
+	"https://keys.openpgp.org",
+	"https://keyserver.ubuntu.com",
+	"https://pgp.mit.edu",
 }
 
 // NewVerifier creates a new GPG verifier
 func NewVerifier() *Verifier {
 	return &Verifier{
-		keyring: make(openpgp.EntityList, 0),
+		keyring:    make(openpgp.EntityList, 0),
+		keyservers: append([]string(nil), DefaultKeyservers...),
+		keybaseURL: "https://keybase.io",
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
-// ImportKeys imports GPG keys from a keyserver with fallbacks
+// WithHTTPClient sets the client used for keyserver and KEYS file requests
+func (v *Verifier) WithHTTPClient(client *http.Client) *Verifier {
+	if client != nil {
+		v.httpClient = client
+	}
+	return v
+}
+
+// WithKeyservers queries the given keyservers, in order, before the defaults
+// Addresses should first be validated with ParseKeyserver
+func (v *Verifier) WithKeyservers(keyservers []string) *Verifier {
+	ordered := append(append([]string(nil), keyservers...), DefaultKeyservers...)
+	seen := make(map[string]bool, len(ordered))
+	v.keyservers = v.keyservers[:0]
+	for _, keyserver := range ordered {
+		if !seen[keyserver] {
+			seen[keyserver] = true
+			v.keyservers = append(v.keyservers, keyserver)
+		}
+	}
+	return v
+}
+
+// ParseKeyserver validates a keyserver address and returns its HTTPS base URL
+// The HKPS form (hkps://host) is HKP over TLS and maps to https://host
+func ParseKeyserver(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid keyserver %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "https":
+	case "hkps":
+		u.Scheme = "https"
+	default:
+		return "", fmt.Errorf("keyserver %q must use https:// or hkps://", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("keyserver %q has no host", raw)
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// ImportKeys imports GPG keys from the first keyserver that has them
 func (v *Verifier) ImportKeys(ctx context.Context, keyIDs []string) error {
+	return v.ImportKeysVia(ctx, keyIDs, KeyLookup{})
+}
+
+// ImportKeysVia imports GPG keys from the lookup's WKD and Keybase sources, falling back to the keyservers
+// Keys from every source must match the requested fingerprint or key ID
+func (v *Verifier) ImportKeysVia(ctx context.Context, keyIDs []string, lookup KeyLookup) error {
 	if len(keyIDs) == 0 {
 		return fmt.Errorf("no key IDs provided")
 	}
 
-	// Multiple keyserver fallbacks for redundancy
-	keyservers := []string{
-		"https://keys.openpgp.org",
-		"https://keyserver.ubuntu.com",
-		"https://pgp.mit.edu",
+	lookupURLs, err := v.lookupURLs(lookup)
+	if err != nil {
+		return err
 	}
 
 	for _, keyID := range keyIDs {
@@ -48,85 +114,83 @@ func (v *Verifier) ImportKeys(ctx context.Context, keyIDs []string) error {
 			continue
 		}
 
-		var lastErr error
-		imported := false
-
-		// Try each keyserver until one succeeds
-		for _, keyserver := range keyservers {
-			// Try different keyserver endpoints
-			urls := []string{
+		// Try the maintainer's own publication points, then each keyserver's endpoints
+		urls := append([]string(nil), lookupURLs...)
+		for _, keyserver := range v.keyservers {
+			urls = append(urls,
 				fmt.Sprintf("%s/vks/v1/by-fingerprint/%s", keyserver, keyID),
-				fmt.Sprintf("%s/pks/lookup?op=get&search=0x%s", keyserver, keyID),
-			}
-
-			for _, url := range urls {
-				req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-				if err != nil {
-					lastErr = err
-					continue
-				}
-
-				resp, err := v.httpClient.Do(req)
-				if err != nil {
-					lastErr = err
-					continue
-				}
-
-				if resp.StatusCode != http.StatusOK {
-					_ = resp.Body.Close()
-					lastErr = fmt.Errorf("keyserver returned status %d", resp.StatusCode)
-					continue
-				}
-
-				// Try to parse the key
-				entities, err := openpgp.ReadArmoredKeyRing(resp.Body)
-				_ = resp.Body.Close()
-
-				if err != nil {
-					lastErr = err
-					continue
-				}
-
-				if len(entities) == 0 {
-					lastErr = fmt.Errorf("no keys found in response")
-					continue
-				}
-
-				// Security: Verify key fingerprint matches requested ID
-				validKey := false
-				for _, entity := range entities {
-					fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
-					// Check both full fingerprint and short form (last 16 chars = 8 bytes)
-					if fingerprint == keyID || (len(fingerprint) >= 16 && fingerprint[len(fingerprint)-16:] == keyID) {
-						// Key found - signature verification will fail if key is expired
-						validKey = true
-					}
-				}
-
-				if !validKey {
-					lastErr = fmt.Errorf("no valid keys found matching fingerprint %s", keyID)
-					continue
-				}
-
-				// Successfully imported and verified
-				v.keyring = append(v.keyring, entities...)
-				imported = true
-				break
-			}
+				fmt.Sprintf("%s/pks/lookup?op=get&options=mr&search=0x%s", keyserver, keyID),
+			)
+		}
 
-			if imported {
-				break
+		var lastErr error
+		imported := false
+		for _, keyURL := range urls {
+			keys, err := v.fetchMatchingKeys(ctx, keyURL, keyID)
+			if err != nil {
+				lastErr = err
+				continue
 			}
+			v.keyring = append(v.keyring, keys...)
+			imported = true
+			break
 		}
 
 		if !imported {
-			return fmt.Errorf("failed to import key %s from all keyservers: %w", keyID, lastErr)
+			return fmt.Errorf("failed to import key %s from all key sources: %w", keyID, lastErr)
 		}
 	}
 
 	return nil
 }
 
+// fetchMatchingKeys downloads the keys at keyURL, armored or binary, and returns them
+// only if the primary key or a subkey matches keyID
+func (v *Verifier) fetchMatchingKeys(ctx context.Context, keyURL, keyID string) (openpgp.EntityList, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", keyURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck // Defer close
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", keyURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxKeyResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// Keyservers and Keybase serve armored keys; WKD serves binary ones
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("no keys found in response")
+	}
+
+	// Security: Verify key (or subkey) fingerprint matches requested ID
+	// Expired or revoked keys are reported when verifying signatures
+	for _, entity := range entities {
+		if entityMatchesKeyID(entity, keyID) {
+			return entities, nil
+		}
+	}
+	return nil, fmt.Errorf("no valid keys found matching fingerprint %s", keyID)
+}
+
 // ImportKeysFromURL imports all GPG keys from a KEYS file URL
 // This is commonly used by projects like Apache, Python, Perl that publish KEYS files
 func (v *Verifier) ImportKeysFromURL(ctx context.Context, keysURL string) error {
@@ -195,6 +259,45 @@ func (v *Verifier) ImportKeyFromFile(keyPath string) error {
 	return nil
 }
 
+// ImportKeysFromKeyring imports the given key IDs from a local keyring file (armored or binary)
+// without touching the network, failing if any key is missing from the keyring
+func (v *Verifier) ImportKeysFromKeyring(keyringPath string, keyIDs []string) error {
+	if len(keyIDs) == 0 {
+		return fmt.Errorf("no key IDs provided")
+	}
+
+	//nolint:gosec // G304: keyringPath is user-provided for GPG key import
+	data, err := os.ReadFile(keyringPath)
+	if err != nil {
+		return fmt.Errorf("failed to read keyring: %w", err)
+	}
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to read keyring: %w", err)
+		}
+	}
+
+	for _, keyID := range keyIDs {
+		if keyID == "" {
+			continue
+		}
+		var matched openpgp.EntityList
+		for _, entity := range keyring {
+			if entityMatchesKeyID(entity, keyID) {
+				matched = append(matched, entity)
+			}
+		}
+		if len(matched) == 0 {
+			return fmt.Errorf("key %s not found in keyring %s", keyID, keyringPath)
+		}
+		v.keyring = append(v.keyring, matched...)
+	}
+
+	return nil
+}
+
 // VerifySignature verifies a detached GPG signature
 func (v *Verifier) VerifySignature(ctx context.Context, filePath, sigURL string) error {
 	if len(v.keyring) == 0 {
@@ -243,19 +346,20 @@ func (v *Verifier) VerifySignature(ctx context.Context, filePath, sigURL string)
 	// Check if signature is armored (starts with -----BEGIN PGP SIGNATURE-----)
 	isArmored := len(sigData) > 27 && string(sigData[:27]) == "-----BEGIN PGP SIGNATURE---"
 
+	var signer *openpgp.Entity
 	var verifyErr error
 	if isArmored {
 		// Use CheckArmoredDetachedSignature for armored signatures
 		sigReader := &sigReader{data: sigData}
-		_, verifyErr = openpgp.CheckArmoredDetachedSignature(v.keyring, f, sigReader, nil)
+		signer, verifyErr = openpgp.CheckArmoredDetachedSignature(v.keyring, f, sigReader, nil)
 	} else {
 		// Use CheckDetachedSignature for binary signatures
 		sigReader := &sigReader{data: sigData}
-		_, verifyErr = openpgp.CheckDetachedSignature(v.keyring, f, sigReader, nil)
+		signer, verifyErr = openpgp.CheckDetachedSignature(v.keyring, f, sigReader, nil)
 	}
 
 	if verifyErr != nil {
-		return fmt.Errorf("signature verification failed: %w", verifyErr)
+		return describeVerifyError(signer, verifyErr)
 	}
 
 	return nil
@@ -296,20 +400,96 @@ func (v *Verifier) VerifySignatureFromFile(filePath, sigPath string) error {
 	}
 
 	// Verify signature using appropriate method
+	var signer *openpgp.Entity
 	var verifyErr error
 	if isArmored {
-		_, verifyErr = openpgp.CheckArmoredDetachedSignature(v.keyring, dataFile, sigFile, nil)
+		signer, verifyErr = openpgp.CheckArmoredDetachedSignature(v.keyring, dataFile, sigFile, nil)
 	} else {
-		_, verifyErr = openpgp.CheckDetachedSignature(v.keyring, dataFile, sigFile, nil)
+		signer, verifyErr = openpgp.CheckDetachedSignature(v.keyring, dataFile, sigFile, nil)
 	}
 
 	if verifyErr != nil {
-		return fmt.Errorf("signature verification failed: %w", verifyErr)
+		return describeVerifyError(signer, verifyErr)
 	}
 
 	return nil
 }
 
+// entityMatchesKeyID reports whether the primary key or any subkey matches keyID
+// keyID may be a full fingerprint or a long key ID (last 16 hex chars)
+func entityMatchesKeyID(entity *openpgp.Entity, keyID string) bool {
+	keyID = strings.ToUpper(strings.TrimPrefix(keyID, "0x"))
+
+	fingerprints := []string{fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)}
+	for _, subkey := range entity.Subkeys {
+		fingerprints = append(fingerprints, fmt.Sprintf("%X", subkey.PublicKey.Fingerprint))
+	}
+
+	for _, fingerprint := range fingerprints {
+		if fingerprint == keyID || (len(fingerprint) >= 16 && fingerprint[len(fingerprint)-16:] == keyID) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeVerifyError adds key details to expiry and revocation failures,
+// which otherwise surface as terse openpgp errors
+func describeVerifyError(signer *openpgp.Entity, err error) error {
+	keyDesc := "signing key"
+	if signer != nil {
+		keyDesc = fmt.Sprintf("signing key %X", signer.PrimaryKey.Fingerprint)
+	}
+
+	switch {
+	case errors.Is(err, pgperrors.ErrKeyExpired):
+		if expiry, ok := keyExpiry(signer); ok {
+			return fmt.Errorf("signature verification failed: %s (or its signing subkey) expired on %s: %w",
+				keyDesc, expiry.UTC().Format(time.RFC3339), err)
+		}
+		return fmt.Errorf("signature verification failed: %s (or its signing subkey) has expired: %w", keyDesc, err)
+	case errors.Is(err, pgperrors.ErrKeyRevoked):
+		return fmt.Errorf("signature verification failed: %s (or its signing subkey) has been revoked: %w", keyDesc, err)
+	case errors.Is(err, pgperrors.ErrSignatureExpired):
+		return fmt.Errorf("signature verification failed: signature by %s has expired: %w", keyDesc, err)
+	case errors.Is(err, pgperrors.ErrUnknownIssuer):
+		return fmt.Errorf("signature verification failed: signing key not found in keyring: %w", err)
+	default:
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+}
+
+// keyExpiry returns the earliest expiration time among the entity's primary key and
+// signing subkeys that has already passed
+func keyExpiry(entity *openpgp.Entity) (time.Time, bool) {
+	if entity == nil {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	var earliest time.Time
+	consider := func(created time.Time, lifetime *uint32) {
+		if lifetime == nil || *lifetime == 0 {
+			return
+		}
+		expiry := created.Add(time.Duration(*lifetime) * time.Second)
+		if expiry.Before(now) && (earliest.IsZero() || expiry.Before(earliest)) {
+			earliest = expiry
+		}
+	}
+
+	if selfSig, _ := entity.PrimarySelfSignature(); selfSig != nil {
+		consider(entity.PrimaryKey.CreationTime, selfSig.KeyLifetimeSecs)
+	}
+	for _, subkey := range entity.Subkeys {
+		if subkey.Sig != nil && subkey.Sig.FlagSign {
+			consider(subkey.PublicKey.CreationTime, subkey.Sig.KeyLifetimeSecs)
+		}
+	}
+
+	return earliest, !earliest.IsZero()
+}
+
 // GetKeyringSize returns the number of keys in the keyring
 func (v *Verifier) GetKeyringSize() int {
 	return len(v.keyring)
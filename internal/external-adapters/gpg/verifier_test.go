@@ -1,7 +1,10 @@
 package gpg
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -9,6 +12,11 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 )
 
 // Test importing key from file (armored format)
@@ -144,6 +152,73 @@ func TestVerifier_ImportKeys_KeyNotFound(t *testing.T) {
 	}
 }
 
+// Test a custom HKPS keyserver is queried before the defaults and its key imported
+func TestVerifier_ImportKeys_CustomKeyserver(t *testing.T) {
+	entity := newTestEntity(t, &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	_, pubPath := writeArmoredKeys(t, t.TempDir(), entity)
+	armored, err := os.ReadFile(pubPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+
+	var requests []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path != "/vks/v1/by-fingerprint/"+fingerprint {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(armored)
+	}))
+	defer server.Close()
+
+	keyserver, err := ParseKeyserver("hkps://" + strings.TrimPrefix(server.URL, "https://") + "/")
+	if err != nil {
+		t.Fatalf("ParseKeyserver() error = %v", err)
+	}
+	if keyserver != server.URL {
+		t.Fatalf("ParseKeyserver() = %q, want %q", keyserver, server.URL)
+	}
+
+	v := NewVerifier().WithKeyservers([]string{keyserver})
+	v.httpClient = server.Client()
+	if v.keyservers[0] != server.URL || len(v.keyservers) != len(DefaultKeyservers)+1 {
+		t.Fatalf("keyservers = %v, want custom keyserver first then defaults", v.keyservers)
+	}
+
+	if err := v.ImportKeys(context.Background(), []string{fingerprint}); err != nil {
+		t.Fatalf("ImportKeys() error = %v", err)
+	}
+	if v.GetKeyringSize() != 1 {
+		t.Errorf("keyring size = %d, want 1", v.GetKeyringSize())
+	}
+	if len(requests) != 1 {
+		t.Errorf("keyserver requests = %v, want a single lookup", requests)
+	}
+}
+
+// Test keyserver addresses must use HTTPS or HKPS
+func TestParseKeyserver(t *testing.T) {
+	tests := []struct {
+		raw, want string
+		wantErr   bool
+	}{
+		{raw: "https://keys.example.com/", want: "https://keys.example.com"},
+		{raw: "hkps://keys.example.com", want: "https://keys.example.com"},
+		{raw: "hkp://keys.example.com", wantErr: true},
+		{raw: "http://keys.example.com", wantErr: true},
+		{raw: "keys.example.com", wantErr: true},
+		{raw: "https://", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseKeyserver(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseKeyserver(%q) = (%q, %v), want (%q, error %v)", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
 // Test VerifySignature without keys imported
 func TestVerifier_VerifySignature_NoKeysImported(t *testing.T) {
 	v := NewVerifier()
@@ -229,3 +304,254 @@ func TestVerifier_ImportKeys_ContextCanceled(t *testing.T) {
 		t.Fatal("Expected error for canceled context, got nil")
 	}
 }
+
+// newTestEntity generates an Ed25519 key pair for signing tests
+func newTestEntity(t *testing.T, config *packet.Config) *openpgp.Entity {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Potions Test", "", "test@example.com", config)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return entity
+}
+
+// writeSignedFile writes data and its armored detached signature to tmpDir
+func writeSignedFile(t *testing.T, tmpDir string, signer *openpgp.Entity, config *packet.Config) (string, string) {
+	t.Helper()
+	data := []byte("potions release artifact\n")
+	filePath := filepath.Join(tmpDir, "artifact.tar.gz")
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var sig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sig, signer, bytes.NewReader(data), config); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	sigPath := filePath + ".asc"
+	if err := os.WriteFile(sigPath, sig.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return filePath, sigPath
+}
+
+// Test verification with an expired key reports a descriptive error
+func TestVerifier_VerifySignatureFromFile_ExpiredKey(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+	config := &packet.Config{
+		Algorithm:       packet.PubKeyAlgoEdDSA,
+		KeyLifetimeSecs: 3600,
+		Time:            func() time.Time { return created },
+	}
+	entity := newTestEntity(t, config)
+	filePath, sigPath := writeSignedFile(t, t.TempDir(), entity, config)
+
+	v := NewVerifier()
+	v.keyring = append(v.keyring, entity)
+
+	err := v.VerifySignatureFromFile(filePath, sigPath)
+	if err == nil {
+		t.Fatal("Expected error for expired key, got nil")
+	}
+	if !errors.Is(err, pgperrors.ErrKeyExpired) {
+		t.Errorf("Expected ErrKeyExpired, got: %v", err)
+	}
+	fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+	if !strings.Contains(err.Error(), fingerprint) || !strings.Contains(err.Error(), "expired on") {
+		t.Errorf("Expected error to name key %s and expiry date, got: %v", fingerprint, err)
+	}
+}
+
+// Test verification with a revoked key reports a descriptive error
+func TestVerifier_VerifySignatureFromFile_RevokedKey(t *testing.T) {
+	config := &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA}
+	entity := newTestEntity(t, config)
+	filePath, sigPath := writeSignedFile(t, t.TempDir(), entity, config)
+
+	if err := entity.RevokeKey(packet.KeyCompromised, "test revocation", config); err != nil {
+		t.Fatalf("Failed to revoke key: %v", err)
+	}
+
+	v := NewVerifier()
+	v.keyring = append(v.keyring, entity)
+
+	err := v.VerifySignatureFromFile(filePath, sigPath)
+	if err == nil {
+		t.Fatal("Expected error for revoked key, got nil")
+	}
+	if !strings.Contains(err.Error(), "has been revoked") {
+		t.Errorf("Expected revocation error, got: %v", err)
+	}
+}
+
+// Test verification of a file signed by a signing subkey
+func TestVerifier_VerifySignatureFromFile_SubkeySigned(t *testing.T) {
+	config := &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA}
+	entity := newTestEntity(t, config)
+	if err := entity.AddSigningSubkey(config); err != nil {
+		t.Fatalf("Failed to add signing subkey: %v", err)
+	}
+	tmpDir := t.TempDir()
+	filePath, sigPath := writeSignedFile(t, tmpDir, entity, config)
+
+	// Import only the public key material, as users would
+	var pub bytes.Buffer
+	w, err := armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(tmpDir, "key.asc")
+	if err := os.WriteFile(keyPath, pub.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVerifier()
+	if err := v.ImportKeyFromFile(keyPath); err != nil {
+		t.Fatalf("ImportKeyFromFile() error = %v", err)
+	}
+
+	if err := v.VerifySignatureFromFile(filePath, sigPath); err != nil {
+		t.Errorf("VerifySignatureFromFile() error = %v, want nil", err)
+	}
+}
+
+// Test key ID matching against primary keys and subkeys
+func TestEntityMatchesKeyID(t *testing.T) {
+	config := &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA}
+	entity := newTestEntity(t, config)
+	if err := entity.AddSigningSubkey(config); err != nil {
+		t.Fatalf("Failed to add signing subkey: %v", err)
+	}
+
+	primary := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+	subkey := fmt.Sprintf("%X", entity.Subkeys[len(entity.Subkeys)-1].PublicKey.Fingerprint)
+
+	tests := []struct {
+		name  string
+		keyID string
+		want  bool
+	}{
+		{"primary fingerprint", primary, true},
+		{"primary long key ID", primary[len(primary)-16:], true},
+		{"subkey fingerprint", subkey, true},
+		{"subkey long key ID lowercase", strings.ToLower(subkey[len(subkey)-16:]), true},
+		{"subkey with 0x prefix", "0x" + subkey[len(subkey)-16:], true},
+		{"unrelated key", "0123456789ABCDEF", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entityMatchesKeyID(entity, tt.keyID); got != tt.want {
+				t.Errorf("entityMatchesKeyID(%q) = %v, want %v", tt.keyID, got, tt.want)
+			}
+		})
+	}
+}
+
+// Test WKD URLs hash the lowercased local part with z-base-32 (spec example address)
+func TestVerifier_WKDURLs(t *testing.T) {
+	urls, err := NewVerifier().wkdURLs("Joe.Doe@Example.ORG")
+	if err != nil {
+		t.Fatalf("wkdURLs() error = %v", err)
+	}
+	want := []string{
+		"https://openpgpkey.example.org/.well-known/openpgpkey/example.org/hu/iy9q119eutrkn8s1mk4r39qejnbu3n5q?l=Joe.Doe",
+		"https://example.org/.well-known/openpgpkey/hu/iy9q119eutrkn8s1mk4r39qejnbu3n5q?l=Joe.Doe",
+	}
+	if strings.Join(urls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("wkdURLs() = %v, want %v", urls, want)
+	}
+	if _, err := NewVerifier().wkdURLs("not-an-email"); err == nil {
+		t.Error("wkdURLs() should reject an address without a domain")
+	}
+}
+
+// Test a binary key published via WKD is imported when its fingerprint matches, before any keyserver
+func TestVerifier_ImportKeysVia_WKD(t *testing.T) {
+	entity := newTestEntity(t, &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	var key bytes.Buffer
+	if err := entity.Serialize(&key); err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		// Only the direct method is set up, as on most small domains
+		if r.URL.Path != "/.well-known/openpgpkey/hu/iy9q119eutrkn8s1mk4r39qejnbu3n5q" || r.URL.Query().Get("l") != "Joe.Doe" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(key.Bytes())
+	}))
+	defer server.Close()
+
+	v := NewVerifier()
+	v.httpClient = server.Client()
+	v.wkdBaseURL = server.URL
+	v.keyservers = nil
+	lookup := KeyLookup{WKDEmail: "Joe.Doe@example.org"}
+
+	if err := v.ImportKeysVia(context.Background(), []string{fingerprint}, lookup); err != nil {
+		t.Fatalf("ImportKeysVia() error = %v", err)
+	}
+	if v.GetKeyringSize() != 1 || !entityMatchesKeyID(v.keyring[0], fingerprint) {
+		t.Errorf("keyring = %d keys, want the WKD key %s", v.GetKeyringSize(), fingerprint)
+	}
+	if len(requests) != 2 {
+		t.Errorf("WKD requests = %v, want advanced then direct method", requests)
+	}
+
+	v.ClearKeyring()
+	err := v.ImportKeysVia(context.Background(), []string{"0123456789ABCDEF0123456789ABCDEF01234567"}, lookup)
+	if err == nil || !strings.Contains(err.Error(), "no valid keys found matching fingerprint") {
+		t.Errorf("ImportKeysVia() error = %v, want a fingerprint mismatch", err)
+	}
+	if v.GetKeyringSize() != 0 {
+		t.Errorf("keyring size = %d, want the mismatched key rejected", v.GetKeyringSize())
+	}
+}
+
+// Test an armored key published on Keybase is imported when its fingerprint matches
+func TestVerifier_ImportKeysVia_Keybase(t *testing.T) {
+	entity := newTestEntity(t, &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	_, pubPath := writeArmoredKeys(t, t.TempDir(), entity)
+	armored, err := os.ReadFile(pubPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/maintainer/pgp_keys.asc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(armored)
+	}))
+	defer server.Close()
+
+	v := NewVerifier()
+	v.httpClient = server.Client()
+	v.keybaseURL = server.URL
+	v.keyservers = nil
+
+	// A long key ID matches as well as the full fingerprint
+	if err := v.ImportKeysVia(context.Background(), []string{fingerprint[len(fingerprint)-16:]}, KeyLookup{KeybaseUser: "maintainer"}); err != nil {
+		t.Fatalf("ImportKeysVia() error = %v", err)
+	}
+	if v.GetKeyringSize() != 1 || !entityMatchesKeyID(v.keyring[0], fingerprint) {
+		t.Errorf("keyring = %d keys, want the Keybase key %s", v.GetKeyringSize(), fingerprint)
+	}
+
+	if err := v.ImportKeysVia(context.Background(), []string{fingerprint}, KeyLookup{KeybaseUser: "../admin"}); err == nil {
+		t.Error("ImportKeysVia() should reject an invalid Keybase username")
+	}
+}
@@ -0,0 +1,48 @@
+// Package toml provides TOML decoding of recipe documents.
+package toml
+
+import (
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Decode parses a TOML document into nested maps; dates and times are kept as strings in their
+// TOML form so they decode like the quoted values of other recipe formats
+func Decode(data []byte) (map[string]any, error) {
+	var doc map[string]any
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, err
+	}
+	return stringifyTimes(doc).(map[string]any), nil
+}
+
+// stringifyTimes replaces the time.Time values under v with their TOML text
+func stringifyTimes(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = stringifyTimes(value)
+		}
+	case []map[string]any:
+		for _, table := range v {
+			stringifyTimes(table)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = stringifyTimes(value)
+		}
+	case time.Time:
+		// The decoder marks offset-less values with these zone names
+		switch v.Location().String() {
+		case "date-local":
+			return v.Format(time.DateOnly)
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
@@ -0,0 +1,48 @@
+package toml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test malformed TOML is rejected with the offending line
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"missing equals", "name \"tool\"\n", "line 1: expected '.' or '='"},
+		{"unterminated string", "name = \"tool\n", "line 1"},
+		{"duplicate key", "name = \"a\"\nname = \"b\"\n", "Key 'name' has already been defined"},
+		{"trailing garbage", "[build]\ntimeout_minutes = 5 minutes\n", "line 2"},
+		{"table over value", "download = \"x\"\n[download.platforms]\n", "line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Decode() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Test dates and times decode to their TOML text, including inside arrays of tables
+func TestDecode_Dates(t *testing.T) {
+	doc, err := Decode([]byte("eol_date = 2026-01-31\nchecked = 2026-01-31T10:00:00Z\n\n[[build.requires]]\nsince = 2025-12-01\n"))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := map[string]any{
+		"eol_date": "2026-01-31",
+		"checked":  "2026-01-31T10:00:00Z",
+		"build": map[string]any{
+			"requires": []map[string]any{{"since": "2025-12-01"}},
+		},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Decode() = %#v, want %#v", doc, want)
+	}
+}
@@ -0,0 +1,56 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is read from the working directory when no config path is given
+const ProjectConfigFile = ".potions.yaml"
+
+// ProjectConfig holds project-wide defaults for flags shared by several commands,
+// keyed by flag name (e.g., "recipes-dir: ./recipes")
+type ProjectConfig struct {
+	RecipesDir string `yaml:"recipes-dir"`
+	Owner      string `yaml:"owner"`
+	Repo       string `yaml:"repo"`
+	OutputDir  string `yaml:"output-dir"`
+}
+
+// LoadProjectConfig reads a project config file, rejecting unknown keys so typos are not ignored
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	//nolint:gosec // G304: path is the user's project config file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	config := &ProjectConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Values returns the config's non-empty settings keyed by flag name
+func (c *ProjectConfig) Values() map[string]string {
+	values := make(map[string]string)
+	for key, value := range map[string]string{
+		"recipes-dir": c.RecipesDir,
+		"owner":       c.Owner,
+		"repo":        c.Repo,
+		"output-dir":  c.OutputDir,
+	} {
+		if value != "" {
+			values[key] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,96 @@
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ochairo/potions/internal/external-adapters/toml"
+)
+
+// RecipeFormat is the file format a recipe is written in
+type RecipeFormat string
+
+// Supported recipe formats
+const (
+	FormatYAML RecipeFormat = "yaml"
+	FormatTOML RecipeFormat = "toml"
+	FormatJSON RecipeFormat = "json"
+)
+
+// RecipeFormats lists supported formats in lookup order: a recipe present in several formats
+// resolves to the first
+var RecipeFormats = []RecipeFormat{FormatYAML, FormatTOML, FormatJSON}
+
+// Extension returns the recipe file extension for the format
+func (f RecipeFormat) Extension() string {
+	if f == FormatYAML {
+		return ".yml"
+	}
+	return "." + string(f)
+}
+
+// ParseRecipeFormat validates a --recipe-format value; empty selects formats by file extension
+func ParseRecipeFormat(value string) (RecipeFormat, error) {
+	if value == "" {
+		return "", nil
+	}
+	if value == "yml" {
+		return FormatYAML, nil
+	}
+	for _, format := range RecipeFormats {
+		if string(format) == value {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown recipe format %q (use yaml, toml or json)", value)
+}
+
+// RecipeFormatForFile returns the format of a recipe file by its extension
+func RecipeFormatForFile(path string) (RecipeFormat, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, format := range RecipeFormats {
+		if ext == format.Extension() {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// decodeRecipe decodes recipe data in the given format into the raw YAML structure
+// TOML and JSON documents are re-encoded as YAML so every format shares one set of field tags
+func decodeRecipe(data []byte, format RecipeFormat) (*yamlRecipe, error) {
+	var doc map[string]any
+	switch format {
+	case FormatYAML, "":
+		var yamlDef yamlRecipe
+		if err := yaml.Unmarshal(data, &yamlDef); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		return &yamlDef, nil
+	case FormatTOML:
+		var err error
+		if doc, err = toml.Decode(data); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML: %w", err)
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown recipe format %q", format)
+	}
+
+	converted, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s recipe: %w", format, err)
+	}
+	var yamlDef yamlRecipe
+	if err := yaml.Unmarshal(converted, &yamlDef); err != nil {
+		return nil, fmt.Errorf("invalid %s recipe: %w", format, err)
+	}
+	return &yamlDef, nil
+}
@@ -0,0 +1,174 @@
+package yaml
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const equivalentYAMLRecipe = `name: tool
+description: "Example tool"
+build_type: custom
+version:
+  source: "github-release:example/tool"
+  extract_pattern: '[0-9]+\.[0-9]+\.[0-9]+'
+  prefer_stable: true
+download:
+  official_binary: true
+  download_url: "https://example.com/v{version}/tool-{os}-{arch}.tar.gz"
+  platforms:
+    linux-amd64:
+      os: linux
+      arch: amd64
+    darwin-arm64:
+      os: darwin
+      arch: arm64
+      target: aarch64-apple-darwin
+      optional: true
+security:
+  verify_signature: true
+  gpg_key_ids: ["ABCDEF0123456789"]
+build:
+  timeout_minutes: 5
+  custom_install: |
+    mkdir -p $PREFIX/bin
+    cp tool $PREFIX/bin/
+  requires:
+    - tool: go
+      min_version: "1.22"
+  min_size: 1024
+dependencies: [zlib, openssl]
+`
+
+const equivalentTOMLRecipe = `# Example tool recipe
+name = "tool"
+description = "Example tool"
+build_type = "custom"
+dependencies = [
+  "zlib",
+  "openssl", # trailing comma allowed
+]
+
+[version]
+source = "github-release:example/tool"
+extract_pattern = '[0-9]+\.[0-9]+\.[0-9]+'
+prefer_stable = true
+
+[download]
+official_binary = true
+download_url = "https://example.com/v{version}/tool-{os}-{arch}.tar.gz"
+platforms.linux-amd64 = { os = "linux", arch = "amd64" }
+
+[download.platforms.darwin-arm64]
+os = "darwin"
+arch = "arm64"
+target = "aarch64-apple-darwin"
+optional = true
+
+[security]
+verify_signature = true
+gpg_key_ids = ["ABCDEF0123456789"]
+
+[build]
+timeout_minutes = 5
+custom_install = """
+mkdir -p $PREFIX/bin
+cp tool $PREFIX/bin/
+"""
+min_size = 1_024
+
+[[build.requires]]
+tool = "go"
+min_version = "1.22"
+`
+
+const equivalentJSONRecipe = `{
+  "name": "tool",
+  "description": "Example tool",
+  "build_type": "custom",
+  "version": {
+    "source": "github-release:example/tool",
+    "extract_pattern": "[0-9]+\\.[0-9]+\\.[0-9]+",
+    "prefer_stable": true
+  },
+  "download": {
+    "official_binary": true,
+    "download_url": "https://example.com/v{version}/tool-{os}-{arch}.tar.gz",
+    "platforms": {
+      "linux-amd64": {"os": "linux", "arch": "amd64"},
+      "darwin-arm64": {"os": "darwin", "arch": "arm64", "target": "aarch64-apple-darwin", "optional": true}
+    }
+  },
+  "security": {"verify_signature": true, "gpg_key_ids": ["ABCDEF0123456789"]},
+  "build": {
+    "timeout_minutes": 5,
+    "custom_install": "mkdir -p $PREFIX/bin\ncp tool $PREFIX/bin/\n",
+    "requires": [{"tool": "go", "min_version": "1.22"}],
+    "min_size": 1024
+  },
+  "dependencies": ["zlib", "openssl"]
+}
+`
+
+// Test the same recipe written in YAML, TOML and JSON parses to identical entities
+func TestRecipeParser_ParseFormat_Equivalent(t *testing.T) {
+	parser := NewRecipeParser()
+	want, err := parser.ParseFormat([]byte(equivalentYAMLRecipe), FormatYAML)
+	if err != nil {
+		t.Fatalf("ParseFormat(yaml) error = %v", err)
+	}
+	if want.Download.Platforms["darwin-arm64"].Custom["target"] != "aarch64-apple-darwin" || want.Build.MinSize != 1024 {
+		t.Fatalf("YAML recipe parsed unexpectedly: %+v", want)
+	}
+
+	for format, data := range map[RecipeFormat]string{FormatTOML: equivalentTOMLRecipe, FormatJSON: equivalentJSONRecipe} {
+		got, err := parser.ParseFormat([]byte(data), format)
+		if err != nil {
+			t.Fatalf("ParseFormat(%s) error = %v", format, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseFormat(%s) =\n%+v\nwant\n%+v", format, got, want)
+		}
+	}
+}
+
+// Test the repository detects recipe formats by extension and honors a format restriction
+func TestRecipeRepository_Formats(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"tool-yaml.yml":  strings.Replace(equivalentYAMLRecipe, "name: tool", "name: tool-yaml", 1),
+		"tool-toml.toml": strings.Replace(equivalentTOMLRecipe, `name = "tool"`, `name = "tool-toml"`, 1),
+		"tool-json.json": strings.Replace(equivalentJSONRecipe, `"name": "tool"`, `"name": "tool-json"`, 1),
+		"notes.txt":      "not a recipe",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := NewRecipeRepository(tmpDir)
+	recipes, err := repo.ListRecipes(context.Background())
+	if err != nil {
+		t.Fatalf("ListRecipes() error = %v", err)
+	}
+	if len(recipes) != 3 {
+		t.Fatalf("ListRecipes() returned %d recipes, want 3", len(recipes))
+	}
+	recipe, err := repo.GetRecipe(context.Background(), "tool-toml")
+	if err != nil || recipe.Name != "tool-toml" {
+		t.Fatalf("GetRecipe(tool-toml) = %v, %v", recipe, err)
+	}
+
+	jsonOnly := NewRecipeRepository(tmpDir).WithFormat(FormatJSON)
+	recipes, err = jsonOnly.ListRecipes(context.Background())
+	if err != nil || len(recipes) != 1 || recipes[0].Name != "tool-json" {
+		t.Fatalf("ListRecipes() with json format = %v, %v; want only tool-json", recipes, err)
+	}
+	if _, err := jsonOnly.GetRecipe(context.Background(), "tool-yaml"); err == nil {
+		t.Error("GetRecipe() should not find a YAML recipe when restricted to JSON")
+	}
+}
@@ -3,10 +3,13 @@ package yaml
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ochairo/potions/internal/domain/entities"
-	"gopkg.in/yaml.v3"
 )
 
 // yamlRecipe represents the raw YAML structure
@@ -20,58 +23,121 @@ type yamlRecipe struct {
 	Configure    yamlBuildStep `yaml:"configure"`
 	Build        yamlBuildStep `yaml:"build"`
 	Dependencies []string      `yaml:"dependencies"`
+	Deprecated   bool          `yaml:"deprecated"`
+	EOLDate      string        `yaml:"eol_date"`
+	LatestAlias  bool          `yaml:"latest_alias"`
 }
 
 type yamlVersion struct {
-	Source          string `yaml:"source"`
-	ExcludePatterns string `yaml:"exclude_patterns"`
-	ExtractPattern  string `yaml:"extract_pattern"`
-	Cleanup         string `yaml:"cleanup"`
+	Source          string            `yaml:"source"`
+	ExcludePatterns string            `yaml:"exclude_patterns"`
+	ExtractPattern  string            `yaml:"extract_pattern"`
+	Cleanup         string            `yaml:"cleanup"`
+	PreferStable    bool              `yaml:"prefer_stable"`
+	Channels        map[string]string `yaml:"channels"`
 }
 
 type yamlDownload struct {
-	OfficialBinary bool                          `yaml:"official_binary"`
-	DownloadURL    string                        `yaml:"download_url"`
-	Mirror         string                        `yaml:"mirror"`
-	Method         string                        `yaml:"method"`
-	GitURL         string                        `yaml:"git_url"`
-	GitTagPrefix   string                        `yaml:"git_tag_prefix"`
-	Platforms      map[string]yamlPlatformConfig `yaml:"platforms"`
+	OfficialBinary         bool                          `yaml:"official_binary"`
+	DownloadURL            string                        `yaml:"download_url"`
+	Mirror                 string                        `yaml:"mirror"`
+	ChecksumURL            string                        `yaml:"checksum_url"`
+	ChecksumAlgorithm      string                        `yaml:"checksum_algorithm"`
+	AllowWeakChecksum      bool                          `yaml:"allow_weak_checksum"`
+	ChecksumFromBody       bool                          `yaml:"checksum_from_release_body"`
+	Method                 string                        `yaml:"method"`
+	GitURL                 string                        `yaml:"git_url"`
+	GitTagPrefix           string                        `yaml:"git_tag_prefix"`
+	AuthTokenEnv           string                        `yaml:"auth_token_env"`
+	AuthScheme             string                        `yaml:"auth_scheme"`
+	ExtractStripComponents int                           `yaml:"extract_strip_components"`
+	InnerArchive           string                        `yaml:"inner_archive"`
+	Platforms              map[string]yamlPlatformConfig `yaml:"platforms"`
+	Headers                map[string]string             `yaml:"headers"`
+	PreRequest             *yamlPreRequest               `yaml:"pre_request"`
+	PlatformMap            map[string]string             `yaml:"platform_map"`
+}
+
+type yamlPreRequest struct {
+	URL          string            `yaml:"url"`
+	ExtractRegex string            `yaml:"extract_regex"`
+	ExtractJSON  string            `yaml:"extract_json"`
+	Headers      map[string]string `yaml:"headers"`
 }
 
 type yamlPlatformConfig struct {
-	OS     string `yaml:"os"`
-	Arch   string `yaml:"arch"`
-	Suffix string `yaml:"suffix"`
+	OS           string `yaml:"os"`
+	Arch         string `yaml:"arch"`
+	Suffix       string `yaml:"suffix"`
+	Optional     bool   `yaml:"optional"`
+	AssetPattern string `yaml:"asset_pattern"`
+	BuildHost    string `yaml:"build_host"`
 	// Inline map captures any additional custom fields (e.g., target, triple)
 	Custom map[string]string `yaml:",inline"`
 }
 
 type yamlSecurity struct {
-	VerifySignature     bool     `yaml:"verify_signature"`
-	ScanVulnerabilities bool     `yaml:"scan_vulnerabilities"`
-	GPGKeyIDs           []string `yaml:"gpg_key_ids"`
-	GPGKeysURL          string   `yaml:"gpg_keys_url"`
-	SignatureURL        string   `yaml:"signature_url"`
+	VerifySignature     bool                            `yaml:"verify_signature"`
+	ScanVulnerabilities bool                            `yaml:"scan_vulnerabilities"`
+	GPGKeyIDs           []string                        `yaml:"gpg_key_ids"`
+	GPGKeysURL          string                          `yaml:"gpg_keys_url"`
+	GPGKeyEmail         string                          `yaml:"gpg_key_email"`
+	GPGKeybaseUser      string                          `yaml:"gpg_keybase_user"`
+	SignatureURL        string                          `yaml:"signature_url"`
+	SignatureExtensions []string                        `yaml:"signature_extensions"`
+	SignedChecksumsURL  string                          `yaml:"signed_checksums_url"`
+	OSV                 []yamlOSVHint                   `yaml:"osv"`
+	Platforms           map[string]yamlSecurityOverride `yaml:"platforms"`
+}
+
+type yamlSecurityOverride struct {
+	ScanVulnerabilities *bool    `yaml:"scan_vulnerabilities"`
+	MinScore            *float64 `yaml:"min_score"`
+	BlockSeverity       string   `yaml:"block_severity"`
+}
+
+type yamlOSVHint struct {
+	Ecosystem string `yaml:"ecosystem"`
+	Package   string `yaml:"package"`
+	Version   string `yaml:"version"`
+	Manifest  string `yaml:"manifest"`
 }
 
 type yamlBuildStep struct {
-	Script         string `yaml:"script"`
-	TimeoutMinutes int    `yaml:"timeout_minutes"`
-	OutOfTree      bool   `yaml:"out_of_tree"`
-	CustomBuild    string `yaml:"custom_build"`
-	CustomInstall  string `yaml:"custom_install"`
+	Script         string                        `yaml:"script"`
+	TimeoutMinutes int                           `yaml:"timeout_minutes"`
+	OutOfTree      bool                          `yaml:"out_of_tree"`
+	CustomBuild    string                        `yaml:"custom_build"`
+	CustomInstall  string                        `yaml:"custom_install"`
+	VerifyCommand  string                        `yaml:"verify_command"`
+	VerifyExpect   string                        `yaml:"verify_expect"`
+	Requires       []yamlToolRequirement         `yaml:"requires"`
+	Platforms      map[string]yamlScriptOverride `yaml:"platforms"`
+	MinSize        int64                         `yaml:"min_size"`
+	MaxSize        int64                         `yaml:"max_size"`
 }
 
-// RecipeParser parses YAML recipe files
+type yamlScriptOverride struct {
+	Script        string `yaml:"script"`
+	CustomBuild   string `yaml:"custom_build"`
+	CustomInstall string `yaml:"custom_install"`
+}
+
+type yamlToolRequirement struct {
+	Tool       string `yaml:"tool"`
+	MinVersion string `yaml:"min_version"`
+}
+
+// RecipeParser parses YAML, TOML and JSON recipe files
 type RecipeParser struct{}
 
-// NewRecipeParser creates a new YAML parser
+// NewRecipeParser creates a new recipe parser
 func NewRecipeParser() *RecipeParser {
 	return &RecipeParser{}
 }
 
-// ParseFile parses a YAML recipe file into a Recipe entity
+// ParseFile parses a recipe file into a Recipe entity, choosing the format by extension
+// (YAML when the extension is not recognized)
 func (p *RecipeParser) ParseFile(filePath string) (*entities.Recipe, error) {
 	//nolint:gosec // G304: filePath is recipe definition path from repository
 	data, err := os.ReadFile(filePath)
@@ -79,14 +145,23 @@ func (p *RecipeParser) ParseFile(filePath string) (*entities.Recipe, error) {
 		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
-	return p.Parse(data)
+	format, ok := RecipeFormatForFile(filePath)
+	if !ok {
+		format = FormatYAML
+	}
+	return p.ParseFormat(data, format)
 }
 
 // Parse parses YAML bytes into a Recipe entity
 func (p *RecipeParser) Parse(data []byte) (*entities.Recipe, error) {
-	var yamlDef yamlRecipe
-	if err := yaml.Unmarshal(data, &yamlDef); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	return p.ParseFormat(data, FormatYAML)
+}
+
+// ParseFormat parses recipe bytes in the given format into a Recipe entity
+func (p *RecipeParser) ParseFormat(data []byte, format RecipeFormat) (*entities.Recipe, error) {
+	yamlDef, err := decodeRecipe(data, format)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate required fields
@@ -94,6 +169,30 @@ func (p *RecipeParser) Parse(data []byte) (*entities.Recipe, error) {
 		return nil, fmt.Errorf("recipe must have a name")
 	}
 
+	var eolDate time.Time
+	if yamlDef.EOLDate != "" {
+		if eolDate, err = time.Parse(time.DateOnly, yamlDef.EOLDate); err != nil {
+			return nil, fmt.Errorf("invalid eol_date %q (want YYYY-MM-DD)", yamlDef.EOLDate)
+		}
+	}
+
+	mappedFrom := make(map[string]string, len(yamlDef.Download.PlatformMap))
+	for _, platform := range slices.Sorted(maps.Keys(yamlDef.Download.PlatformMap)) {
+		key := yamlDef.Download.PlatformMap[platform]
+		if _, ok := yamlDef.Download.Platforms[key]; !ok {
+			return nil, fmt.Errorf("platform_map %s: %q is not a download platform", platform, key)
+		}
+		if other, ok := mappedFrom[key]; ok {
+			return nil, fmt.Errorf("platform_map %s: %q is already mapped from %s", platform, key, other)
+		}
+		mappedFrom[key] = platform
+	}
+
+	security, err := convertSecurity(yamlDef.Security)
+	if err != nil {
+		return nil, fmt.Errorf("recipe %s: %w", yamlDef.Name, err)
+	}
+
 	// Convert to domain entity
 	def := &entities.Recipe{
 		Name:         yamlDef.Name,
@@ -101,10 +200,13 @@ func (p *RecipeParser) Parse(data []byte) (*entities.Recipe, error) {
 		BuildType:    yamlDef.BuildType,
 		Description:  yamlDef.Description,
 		Download:     convertDownload(yamlDef.Download),
-		Security:     convertSecurity(yamlDef.Security),
+		Security:     security,
 		Configure:    convertBuildStep(yamlDef.Configure),
 		Build:        convertBuildStep(yamlDef.Build),
 		Dependencies: yamlDef.Dependencies,
+		Deprecated:   yamlDef.Deprecated,
+		EOLDate:      eolDate,
+		LatestAlias:  yamlDef.LatestAlias,
 	}
 
 	return def, nil
@@ -116,6 +218,8 @@ func convertVersion(yv yamlVersion) entities.VersionConfig {
 		ExcludePatterns: yv.ExcludePatterns,
 		ExtractPattern:  yv.ExtractPattern,
 		Cleanup:         yv.Cleanup,
+		PreferStable:    yv.PreferStable,
+		Channels:        yv.Channels,
 	}
 }
 
@@ -131,32 +235,94 @@ func convertDownload(yd yamlDownload) entities.RecipeDownload {
 		}
 
 		platforms[name] = entities.PlatformConfig{
-			OS:     cfg.OS,
-			Arch:   cfg.Arch,
-			Suffix: cfg.Suffix,
-			Custom: custom,
+			OS:           cfg.OS,
+			Arch:         cfg.Arch,
+			Suffix:       cfg.Suffix,
+			Custom:       custom,
+			Optional:     cfg.Optional,
+			AssetPattern: cfg.AssetPattern,
+			BuildHost:    cfg.BuildHost,
 		}
 	}
 
 	return entities.RecipeDownload{
-		OfficialBinary: yd.OfficialBinary,
-		DownloadURL:    yd.DownloadURL,
-		Mirror:         yd.Mirror,
-		Method:         yd.Method,
-		GitURL:         yd.GitURL,
-		GitTagPrefix:   yd.GitTagPrefix,
-		Platforms:      platforms,
+		OfficialBinary:          yd.OfficialBinary,
+		DownloadURL:             yd.DownloadURL,
+		Mirror:                  yd.Mirror,
+		ChecksumURL:             yd.ChecksumURL,
+		Method:                  yd.Method,
+		GitURL:                  yd.GitURL,
+		GitTagPrefix:            yd.GitTagPrefix,
+		AuthTokenEnv:            yd.AuthTokenEnv,
+		AuthScheme:              yd.AuthScheme,
+		ExtractStripComponents:  yd.ExtractStripComponents,
+		InnerArchive:            yd.InnerArchive,
+		ChecksumAlgorithm:       yd.ChecksumAlgorithm,
+		AllowWeakChecksum:       yd.AllowWeakChecksum,
+		ChecksumFromReleaseBody: yd.ChecksumFromBody,
+		Platforms:               platforms,
+		Headers:                 yd.Headers,
+		PreRequest:              convertPreRequest(yd.PreRequest),
+		PlatformMap:             yd.PlatformMap,
+	}
+}
+
+func convertPreRequest(yp *yamlPreRequest) *entities.DownloadPreRequest {
+	if yp == nil {
+		return nil
+	}
+	return &entities.DownloadPreRequest{
+		URL:          yp.URL,
+		ExtractRegex: yp.ExtractRegex,
+		ExtractJSON:  yp.ExtractJSON,
+		Headers:      yp.Headers,
 	}
 }
 
-func convertSecurity(ys yamlSecurity) entities.RecipeSecurity {
+func convertSecurity(ys yamlSecurity) (entities.RecipeSecurity, error) {
+	var osv []entities.OSVHint
+	for _, hint := range ys.OSV {
+		osv = append(osv, entities.OSVHint{Ecosystem: hint.Ecosystem, Package: hint.Package, Version: hint.Version, Manifest: hint.Manifest})
+	}
+	overrides, err := convertSecurityOverrides(ys.Platforms)
+	if err != nil {
+		return entities.RecipeSecurity{}, err
+	}
 	return entities.RecipeSecurity{
 		VerifySignature:     ys.VerifySignature,
 		ScanVulnerabilities: ys.ScanVulnerabilities,
 		GPGKeyIDs:           ys.GPGKeyIDs,
 		GPGKeysURL:          ys.GPGKeysURL,
+		GPGKeyLookup:        entities.GPGKeyLookup{WKDEmail: ys.GPGKeyEmail, KeybaseUser: ys.GPGKeybaseUser},
 		SignatureURL:        ys.SignatureURL,
+		SignatureExtensions: ys.SignatureExtensions,
+		SignedChecksumsURL:  ys.SignedChecksumsURL,
+		OSV:                 osv,
+		Platforms:           overrides,
+	}, nil
+}
+
+// blockSeverities are the vulnerability severities a security override may block on
+var blockSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+func convertSecurityOverrides(yos map[string]yamlSecurityOverride) (map[string]entities.SecurityOverride, error) {
+	if len(yos) == 0 {
+		return nil, nil
 	}
+	overrides := make(map[string]entities.SecurityOverride, len(yos))
+	for _, platform := range slices.Sorted(maps.Keys(yos)) {
+		yo := yos[platform]
+		severity := strings.ToUpper(yo.BlockSeverity)
+		if severity != "" && !slices.Contains(blockSeverities, severity) {
+			return nil, fmt.Errorf("security.platforms.%s: unknown block_severity %q (valid: critical, high, medium, low)", platform, yo.BlockSeverity)
+		}
+		overrides[platform] = entities.SecurityOverride{
+			ScanVulnerabilities: yo.ScanVulnerabilities,
+			MinScore:            yo.MinScore,
+			BlockSeverity:       severity,
+		}
+	}
+	return overrides, nil
 }
 
 func convertBuildStep(yb yamlBuildStep) entities.RecipeBuildStep {
@@ -166,5 +332,37 @@ func convertBuildStep(yb yamlBuildStep) entities.RecipeBuildStep {
 		OutOfTree:      yb.OutOfTree,
 		CustomBuild:    yb.CustomBuild,
 		CustomInstall:  yb.CustomInstall,
+		VerifyCommand:  yb.VerifyCommand,
+		VerifyExpect:   yb.VerifyExpect,
+		Requires:       convertToolRequirements(yb.Requires),
+		Platforms:      convertScriptOverrides(yb.Platforms),
+		MinSize:        yb.MinSize,
+		MaxSize:        yb.MaxSize,
+	}
+}
+
+func convertScriptOverrides(yos map[string]yamlScriptOverride) map[string]entities.ScriptOverride {
+	if len(yos) == 0 {
+		return nil
+	}
+	overrides := make(map[string]entities.ScriptOverride, len(yos))
+	for platform, yo := range yos {
+		overrides[platform] = entities.ScriptOverride{
+			Script:        yo.Script,
+			CustomBuild:   yo.CustomBuild,
+			CustomInstall: yo.CustomInstall,
+		}
+	}
+	return overrides
+}
+
+func convertToolRequirements(yrs []yamlToolRequirement) []entities.ToolRequirement {
+	if len(yrs) == 0 {
+		return nil
+	}
+	reqs := make([]entities.ToolRequirement, 0, len(yrs))
+	for _, yr := range yrs {
+		reqs = append(reqs, entities.ToolRequirement{Tool: yr.Tool, MinVersion: yr.MinVersion})
 	}
+	return reqs
 }
@@ -1,7 +1,10 @@
 package yaml
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/ochairo/potions/internal/domain/entities"
 )
 
 func TestRecipeParser_Parse_Valid(t *testing.T) {
@@ -175,3 +178,168 @@ func TestRecipeParser_ParseFile_NotFound(t *testing.T) {
 		t.Error("ParseFile() should return error for nonexistent file")
 	}
 }
+
+func TestRecipeParser_Parse_DownloadAuth(t *testing.T) {
+	parser := NewRecipeParser()
+	yamlData := []byte(`name: private-tool
+build_type: custom
+download:
+  download_url: https://example.com/private-tool-{version}.tar.gz
+  auth_token_env: PRIVATE_TOOL_TOKEN
+  auth_scheme: token
+  platforms:
+    linux-amd64:
+      os: linux
+      arch: amd64
+`)
+
+	recipe, err := parser.Parse(yamlData)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if recipe.Download.AuthTokenEnv != "PRIVATE_TOOL_TOKEN" {
+		t.Errorf("Download.AuthTokenEnv = %q, want PRIVATE_TOOL_TOKEN", recipe.Download.AuthTokenEnv)
+	}
+	if recipe.Download.AuthScheme != "token" {
+		t.Errorf("Download.AuthScheme = %q, want token", recipe.Download.AuthScheme)
+	}
+}
+
+func TestRecipeParser_Parse_VerifyCommand(t *testing.T) {
+	parser := NewRecipeParser()
+	yamlData := []byte(`name: tool
+build_type: custom
+download:
+  download_url: https://example.com/tool-{version}.tar.gz
+  platforms:
+    linux-amd64:
+      os: linux
+      arch: amd64
+build:
+  verify_command: tool --version
+  verify_expect: "tool {version}"
+`)
+
+	recipe, err := parser.Parse(yamlData)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if recipe.Build.VerifyCommand != "tool --version" {
+		t.Errorf("Build.VerifyCommand = %q, want %q", recipe.Build.VerifyCommand, "tool --version")
+	}
+	if recipe.Build.VerifyExpect != "tool {version}" {
+		t.Errorf("Build.VerifyExpect = %q, want %q", recipe.Build.VerifyExpect, "tool {version}")
+	}
+}
+
+func TestRecipeParser_Parse_Lifecycle(t *testing.T) {
+	parser := NewRecipeParser()
+	recipe, err := parser.Parse([]byte(`name: legacy-tool
+build_type: custom
+deprecated: true
+eol_date: "2026-06-30"
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !recipe.Deprecated {
+		t.Error("Deprecated = false, want true")
+	}
+	if got := recipe.EOLDate.Format("2006-01-02"); got != "2026-06-30" {
+		t.Errorf("EOLDate = %s, want 2026-06-30", got)
+	}
+
+	if _, err := parser.Parse([]byte("name: tool\neol_date: June 2026\n")); err == nil {
+		t.Error("Parse() should reject an eol_date that is not YYYY-MM-DD")
+	}
+}
+
+func TestRecipeParser_Parse_SecurityPlatformOverrides(t *testing.T) {
+	parser := NewRecipeParser()
+	recipe, err := parser.Parse([]byte(`name: tool
+build_type: custom
+security:
+  scan_vulnerabilities: true
+  platforms:
+    darwin:
+      scan_vulnerabilities: false
+    linux-arm64:
+      min_score: 7.5
+      block_severity: high
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if policy := recipe.Security.PolicyFor("darwin-arm64"); policy.ScanVulnerabilities {
+		t.Error("PolicyFor(darwin-arm64).ScanVulnerabilities = true, want disabled by the darwin override")
+	}
+	want := entities.SecurityPolicy{ScanVulnerabilities: true, MinScore: 7.5, BlockSeverity: "HIGH"}
+	if policy := recipe.Security.PolicyFor("linux-arm64"); policy != want {
+		t.Errorf("PolicyFor(linux-arm64) = %+v, want %+v", policy, want)
+	}
+	if policy := recipe.Security.PolicyFor("linux-amd64"); policy != entities.DefaultSecurityPolicy() {
+		t.Errorf("PolicyFor(linux-amd64) = %+v, want the default policy", policy)
+	}
+
+	_, err = parser.Parse([]byte(`name: tool
+security:
+  platforms:
+    linux-arm64:
+      block_severity: severe
+`))
+	if err == nil || !strings.Contains(err.Error(), `recipe tool: security.platforms.linux-arm64: unknown block_severity "severe"`) {
+		t.Errorf("Parse() error = %v, want unknown block_severity naming the recipe and platform", err)
+	}
+}
+
+func TestRecipeParser_Parse_PlatformMap(t *testing.T) {
+	parser := NewRecipeParser()
+	recipe, err := parser.Parse([]byte(`name: tool
+download:
+  download_url: "https://example.com/tool-{version}-{os}-{arch}.tar.gz"
+  platforms:
+    macos-aarch64: {}
+    linux-x86_64: {}
+  platform_map:
+    darwin-arm64: macos-aarch64
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if key := recipe.Download.PlatformKey("darwin-arm64"); key != "macos-aarch64" {
+		t.Errorf("PlatformKey(darwin-arm64) = %q, want macos-aarch64", key)
+	}
+	if _, ok := recipe.Download.Platform("darwin-arm64"); !ok {
+		t.Error("Platform(darwin-arm64) not found through platform_map")
+	}
+	if platform := recipe.Download.CanonicalPlatform("macos-aarch64"); platform != "darwin-arm64" {
+		t.Errorf("CanonicalPlatform(macos-aarch64) = %q, want darwin-arm64", platform)
+	}
+
+	_, err = parser.Parse([]byte(`name: tool
+download:
+  platforms:
+    macos-aarch64: {}
+  platform_map:
+    darwin-arm64: macos-arm64
+`))
+	if err == nil || !strings.Contains(err.Error(), `"macos-arm64" is not a download platform`) {
+		t.Errorf("Parse() error = %v, want unknown platform_map target", err)
+	}
+
+	_, err = parser.Parse([]byte(`name: tool
+download:
+  platforms:
+    macos-aarch64: {}
+  platform_map:
+    darwin-arm64: macos-aarch64
+    darwin-aarch64: macos-aarch64
+`))
+	if err == nil || !strings.Contains(err.Error(), `platform_map darwin-arm64: "macos-aarch64" is already mapped from darwin-aarch64`) {
+		t.Errorf("Parse() error = %v, want duplicate platform_map target", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -39,13 +40,17 @@ func validateRecipeName(name string) error {
 	return nil
 }
 
-// RecipeRepository implements repositories.RecipeRepository using YAML files
+// RecipeRepository implements repositories.RecipeRepository using YAML, TOML or JSON files
 type RecipeRepository struct {
 	recipesDir string
 	parser     *RecipeParser
+	format     RecipeFormat
+	include    []string
+	exclude    []string
 }
 
-// NewRecipeRepository creates a new YAML-based recipe repository
+// NewRecipeRepository creates a new file-based recipe repository; each recipe's format
+// is detected from its file extension
 func NewRecipeRepository(recipesDir string) *RecipeRepository {
 	return &RecipeRepository{
 		recipesDir: recipesDir,
@@ -53,21 +58,78 @@ func NewRecipeRepository(recipesDir string) *RecipeRepository {
 	}
 }
 
-// GetRecipe retrieves a package recipe by name
-func (r *RecipeRepository) GetRecipe(_ context.Context, name string) (*entities.Recipe, error) {
+// WithNameFilter limits ListRecipes to recipe names matching any include glob
+// (all names when include is empty) and no exclude glob
+func (r *RecipeRepository) WithNameFilter(include, exclude []string) *RecipeRepository {
+	r.include = include
+	r.exclude = exclude
+	return r
+}
+
+// WithFormat restricts the repository to recipe files of one format (all formats when empty)
+func (r *RecipeRepository) WithFormat(format RecipeFormat) *RecipeRepository {
+	r.format = format
+	return r
+}
+
+// formats returns the recipe formats the repository reads, in lookup order
+func (r *RecipeRepository) formats() []RecipeFormat {
+	if r.format != "" {
+		return []RecipeFormat{r.format}
+	}
+	return RecipeFormats
+}
+
+// RecipePath returns the file holding the named recipe
+func (r *RecipeRepository) RecipePath(name string) (string, error) {
 	// SECURITY: Validate recipe name to prevent path traversal
 	if err := validateRecipeName(name); err != nil {
-		return nil, fmt.Errorf("invalid recipe name: %w", err)
+		return "", fmt.Errorf("invalid recipe name: %w", err)
 	}
+	return r.findRecipeFile(name)
+}
 
-	filePath := filepath.Join(r.recipesDir, name+".yml")
+// findRecipeFile returns the first existing file for name across the repository's formats
+func (r *RecipeRepository) findRecipeFile(name string) (string, error) {
+	for _, format := range r.formats() {
+		filePath := filepath.Join(r.recipesDir, name+format.Extension())
+		if _, err := os.Stat(filePath); err == nil {
+			return filePath, nil
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to stat recipe file: %w", err)
+		}
+	}
+	return "", fmt.Errorf("recipe not found: %s", name)
+}
+
+// matchesNameFilter reports whether a recipe name passes the include/exclude globs
+func (r *RecipeRepository) matchesNameFilter(name string) bool {
+	if len(r.include) > 0 && !matchesAnyGlob(name, r.include) {
+		return false
+	}
+	return !matchesAnyGlob(name, r.exclude)
+}
+
+// matchesAnyGlob reports whether name matches any of the glob patterns
+func matchesAnyGlob(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRecipe retrieves a package recipe by name
+func (r *RecipeRepository) GetRecipe(_ context.Context, name string) (*entities.Recipe, error) {
+	filePath, err := r.RecipePath(name)
+	if err != nil {
+		return nil, err
+	}
 
 	// SECURITY: Check file size before reading (prevent memory exhaustion)
 	stat, err := os.Stat(filePath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("recipe not found: %s", name)
-		}
 		return nil, fmt.Errorf("failed to stat recipe file: %w", err)
 	}
 	if stat.Size() > maxRecipeFileSize {
@@ -84,18 +146,41 @@ func (r *RecipeRepository) ListRecipes(_ context.Context) ([]*entities.Recipe, e
 		return nil, fmt.Errorf("failed to read recipes directory: %w", err)
 	}
 
-	recipes := make([]*entities.Recipe, 0)
+	// Collect each recipe name once; a name present in several formats resolves like GetRecipe
+	var names []string
+	seen := make(map[string]bool)
 	for _, entry := range entries {
-		// Skip non-YAML files
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yml") {
+		// Skip files that are not recipes in a format this repository reads
+		if entry.IsDir() {
+			continue
+		}
+		format, ok := RecipeFormatForFile(entry.Name())
+		if !ok || (r.format != "" && format != r.format) {
 			continue
 		}
 
-		filePath := filepath.Join(r.recipesDir, entry.Name())
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	recipes := make([]*entities.Recipe, 0, len(names))
+	for _, name := range names {
+		// Filter by name before parsing so excluded recipes cost nothing
+		if !r.matchesNameFilter(name) {
+			continue
+		}
+
+		filePath, err := r.findRecipeFile(name)
+		if err != nil {
+			return nil, err
+		}
 		def, err := r.parser.ParseFile(filePath)
 		if err != nil {
 			// Log warning but continue processing other files
-			fmt.Fprintf(os.Stderr, "Warning: failed to parse %s: %v\n", entry.Name(), err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse %s: %v\n", filepath.Base(filePath), err)
 			continue
 		}
 
@@ -114,7 +199,7 @@ func (r *RecipeRepository) GetRecipesByPlatform(ctx context.Context, platform st
 
 	filtered := make([]*entities.Recipe, 0)
 	for _, def := range allDefs {
-		if _, hasPlatform := def.Download.Platforms[platform]; hasPlatform {
+		if _, hasPlatform := def.Download.Platform(platform); hasPlatform {
 			filtered = append(filtered, def)
 		}
 	}
@@ -2,8 +2,11 @@ package yaml
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +47,45 @@ func TestRecipeRepository_GetRecipe_NotFound(t *testing.T) {
 		t.Error("GetRecipe() should return error for nonexistent recipe")
 	}
 }
+
+func TestRecipeRepository_ListRecipes_NameFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"k8s-kubectl", "k8s-helm", "k8s-legacy-tool", "curl", "jq"} {
+		content := fmt.Sprintf("name: %s\nbuild_type: official_binary\ndownload:\n  platforms:\n    linux-amd64:\n      os: linux\n      arch: amd64\n", name)
+		if err := os.WriteFile(filepath.Join(tmpDir, name+".yml"), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{"no filter", nil, nil, []string{"curl", "jq", "k8s-helm", "k8s-kubectl", "k8s-legacy-tool"}},
+		{"include only", []string{"k8s-*"}, nil, []string{"k8s-helm", "k8s-kubectl", "k8s-legacy-tool"}},
+		{"include and exclude", []string{"k8s-*"}, []string{"k8s-legacy-*"}, []string{"k8s-helm", "k8s-kubectl"}},
+		{"exclude only", nil, []string{"k8s-*"}, []string{"curl", "jq"}},
+		{"multiple includes", []string{"curl", "jq"}, nil, []string{"curl", "jq"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewRecipeRepository(tmpDir).WithNameFilter(tt.include, tt.exclude)
+			recipes, err := repo.ListRecipes(context.Background())
+			if err != nil {
+				t.Fatalf("ListRecipes() error = %v", err)
+			}
+
+			var got []string
+			for _, recipe := range recipes {
+				got = append(got, recipe.Name)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ListRecipes() names = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
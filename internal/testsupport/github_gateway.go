@@ -0,0 +1,315 @@
+// Package testsupport provides in-memory fakes of domain gateways for tests.
+package testsupport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ochairo/potions/internal/domain/interfaces/gateways"
+)
+
+// UploadedAsset records an asset uploaded to a fake release
+type UploadedAsset struct {
+	ReleaseTag string
+	Name       string
+	Size       int64
+}
+
+// FakeGitHubGateway is a concurrency-safe in-memory implementation of gateways.GitHubGateway
+// Releases and assets live in maps; errors can be injected per operation
+type FakeGitHubGateway struct {
+	mu         sync.Mutex
+	nextID     int64
+	releases   map[int64]*gateways.GitHubRelease
+	order      []int64
+	byUpload   map[string]int64
+	assets     map[int64][]*gateways.GitHubAsset
+	created    []gateways.GitHubRelease
+	deleted    []string
+	uploads    []UploadedAsset
+	calls      []string
+	createErr  error
+	updateErr  error
+	listErr    error
+	uploadErrs map[string]error
+	// downloadBaseURL, when set, prefixes the browser download URL of uploaded assets
+	downloadBaseURL string
+	// createDelay slows CreateRelease so tests can observe concurrent calls
+	createDelay    time.Duration
+	createInFlight int
+	maxCreates     int
+}
+
+var _ gateways.GitHubGateway = (*FakeGitHubGateway)(nil)
+
+// NewFakeGitHubGateway creates a fake gateway pre-populated with published releases for existingTags
+func NewFakeGitHubGateway(existingTags ...string) *FakeGitHubGateway {
+	f := &FakeGitHubGateway{
+		releases:   make(map[int64]*gateways.GitHubRelease),
+		byUpload:   make(map[string]int64),
+		assets:     make(map[int64][]*gateways.GitHubAsset),
+		uploadErrs: make(map[string]error),
+	}
+	for _, tag := range existingTags {
+		f.addRelease(&gateways.GitHubRelease{TagName: tag})
+	}
+	return f
+}
+
+// FailCreate makes subsequent CreateRelease calls return err
+func (f *FakeGitHubGateway) FailCreate(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.createErr = err
+}
+
+// FailUpdate makes subsequent UpdateRelease calls return err
+func (f *FakeGitHubGateway) FailUpdate(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updateErr = err
+}
+
+// FailList makes subsequent ListReleases calls return err
+func (f *FakeGitHubGateway) FailList(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listErr = err
+}
+
+// FailUpload makes uploads of filename return err
+func (f *FakeGitHubGateway) FailUpload(filename string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.uploadErrs[filename] = err
+}
+
+// ServeDownloadsFrom sets uploaded assets' browser download URLs to baseURL/<tag>/<filename>
+func (f *FakeGitHubGateway) ServeDownloadsFrom(baseURL string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.downloadBaseURL = baseURL
+}
+
+// DelayCreate makes subsequent CreateRelease calls sleep for d before creating the release
+func (f *FakeGitHubGateway) DelayCreate(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.createDelay = d
+}
+
+// MaxConcurrentCreates returns the largest number of CreateRelease calls seen in flight at once
+func (f *FakeGitHubGateway) MaxConcurrentCreates() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxCreates
+}
+
+// Calls returns a log of mutating calls, such as "create <tag> draft=true", "upload <name>",
+// "upload <name> failed", "update <tag> draft=false" and "delete <tag>", in call order
+func (f *FakeGitHubGateway) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// CreatedReleases returns copies of the releases created through CreateRelease, in call order
+func (f *FakeGitHubGateway) CreatedReleases() []gateways.GitHubRelease {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]gateways.GitHubRelease(nil), f.created...)
+}
+
+// DeletedReleases returns the tags of releases removed through DeleteRelease, in call order
+func (f *FakeGitHubGateway) DeletedReleases() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.deleted...)
+}
+
+// Uploads returns every successfully uploaded asset, in call order
+func (f *FakeGitHubGateway) Uploads() []UploadedAsset {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]UploadedAsset(nil), f.uploads...)
+}
+
+// UploadsFor returns the assets uploaded to the release tagged tag
+func (f *FakeGitHubGateway) UploadsFor(tag string) []UploadedAsset {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var assets []UploadedAsset
+	for _, upload := range f.uploads {
+		if upload.ReleaseTag == tag {
+			assets = append(assets, upload)
+		}
+	}
+	return assets
+}
+
+// addRelease stores a copy of release under a new ID; callers must hold f.mu or be single-threaded
+func (f *FakeGitHubGateway) addRelease(release *gateways.GitHubRelease) *gateways.GitHubRelease {
+	f.nextID++
+	stored := *release
+	stored.ID = f.nextID
+	stored.UploadURL = fmt.Sprintf("fake://uploads/%d", stored.ID)
+	stored.HTMLURL = fmt.Sprintf("https://github.example/releases/tag/%s", stored.TagName)
+	f.releases[stored.ID] = &stored
+	f.order = append(f.order, stored.ID)
+	f.byUpload[stored.UploadURL] = stored.ID
+	return &stored
+}
+
+// CreateRelease creates a new in-memory release
+func (f *FakeGitHubGateway) CreateRelease(_ context.Context, _, _ string, release *gateways.GitHubRelease) (*gateways.GitHubRelease, error) {
+	f.mu.Lock()
+	f.createInFlight++
+	f.maxCreates = max(f.maxCreates, f.createInFlight)
+	delay := f.createDelay
+	f.mu.Unlock()
+
+	time.Sleep(delay)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.createInFlight--
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	for _, existing := range f.releases {
+		if existing.TagName == release.TagName {
+			return nil, fmt.Errorf("release already exists: %s", release.TagName)
+		}
+	}
+
+	stored := f.addRelease(release)
+	f.created = append(f.created, *stored)
+	f.calls = append(f.calls, fmt.Sprintf("create %s draft=%v", stored.TagName, stored.Draft))
+	created := *stored
+	return &created, nil
+}
+
+// UpdateRelease updates the tag, draft flag, name, body and prerelease flag of a release
+func (f *FakeGitHubGateway) UpdateRelease(_ context.Context, _, _ string, releaseID int64, release *gateways.GitHubRelease) (*gateways.GitHubRelease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.updateErr != nil {
+		return nil, f.updateErr
+	}
+	stored, ok := f.releases[releaseID]
+	if !ok {
+		return nil, fmt.Errorf("release %d not found", releaseID)
+	}
+
+	if release.TagName != "" {
+		stored.TagName = release.TagName
+	}
+	stored.Name = release.Name
+	stored.Body = release.Body
+	stored.Draft = release.Draft
+	stored.Prerelease = release.Prerelease
+	f.calls = append(f.calls, fmt.Sprintf("update %s draft=%v", stored.TagName, stored.Draft))
+	updated := *stored
+	return &updated, nil
+}
+
+// GetRelease retrieves a release by tag name
+func (f *FakeGitHubGateway) GetRelease(_ context.Context, _, _, tag string) (*gateways.GitHubRelease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range f.order {
+		if f.releases[id].TagName == tag {
+			release := *f.releases[id]
+			return &release, nil
+		}
+	}
+	return nil, fmt.Errorf("release not found: %s", tag)
+}
+
+// UploadAsset reads content and records it as an asset of the release owning uploadURL
+func (f *FakeGitHubGateway) UploadAsset(_ context.Context, uploadURL, filename string, content io.Reader) (*gateways.GitHubAsset, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.uploadErrs[filename]; err != nil {
+		f.calls = append(f.calls, fmt.Sprintf("upload %s failed", filename))
+		return nil, err
+	}
+	releaseID, ok := f.byUpload[uploadURL]
+	if !ok {
+		return nil, fmt.Errorf("unknown upload URL: %s", uploadURL)
+	}
+
+	asset := &gateways.GitHubAsset{
+		ID:    int64(len(f.uploads) + 1),
+		Name:  filename,
+		State: "uploaded",
+		Size:  int64(len(data)),
+	}
+	if f.downloadBaseURL != "" {
+		asset.BrowserDownloadURL = fmt.Sprintf("%s/%s/%s", f.downloadBaseURL, f.releases[releaseID].TagName, filename)
+	}
+	f.assets[releaseID] = append(f.assets[releaseID], asset)
+	f.uploads = append(f.uploads, UploadedAsset{
+		ReleaseTag: f.releases[releaseID].TagName,
+		Name:       filename,
+		Size:       asset.Size,
+	})
+	f.calls = append(f.calls, fmt.Sprintf("upload %s", filename))
+	return asset, nil
+}
+
+// ListReleaseAssets lists all assets uploaded to a release
+func (f *FakeGitHubGateway) ListReleaseAssets(_ context.Context, _, _ string, releaseID int64) ([]*gateways.GitHubAsset, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.releases[releaseID]; !ok {
+		return nil, fmt.Errorf("release %d not found", releaseID)
+	}
+	return append([]*gateways.GitHubAsset(nil), f.assets[releaseID]...), nil
+}
+
+// ListReleases lists all releases in creation order
+func (f *FakeGitHubGateway) ListReleases(_ context.Context, _, _ string) ([]*gateways.GitHubRelease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	releases := make([]*gateways.GitHubRelease, 0, len(f.order))
+	for _, id := range f.order {
+		release := *f.releases[id]
+		releases = append(releases, &release)
+	}
+	return releases, nil
+}
+
+// DeleteRelease removes a release and its assets
+func (f *FakeGitHubGateway) DeleteRelease(_ context.Context, _, _ string, releaseID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	release, ok := f.releases[releaseID]
+	if !ok {
+		return fmt.Errorf("release %d not found", releaseID)
+	}
+
+	f.deleted = append(f.deleted, release.TagName)
+	f.calls = append(f.calls, fmt.Sprintf("delete %s", release.TagName))
+	delete(f.releases, releaseID)
+	delete(f.byUpload, release.UploadURL)
+	delete(f.assets, releaseID)
+	for i, id := range f.order {
+		if id == releaseID {
+			f.order = append(f.order[:i], f.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
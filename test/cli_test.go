@@ -57,10 +57,12 @@ func TestCLI_HelpAndVersion(t *testing.T) {
 		"build",
 		"release",
 		"list",
+		"graph",
 		"monitor",
 		"scan",
 		"verify",
 		"validate-release",
+		"validate-recipe",
 	}
 
 	for _, cmd := range commands {